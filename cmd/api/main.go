@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -11,7 +16,24 @@ import (
 
 	"github.com/teusf/billing-system/config"
 	"github.com/teusf/billing-system/internal/infrastructure/database"
+	"github.com/teusf/billing-system/internal/infrastructure/evolution"
+	assinaturaHTTP "github.com/teusf/billing-system/internal/infrastructure/http/assinatura"
+	clienteHTTP "github.com/teusf/billing-system/internal/infrastructure/http/cliente"
+	configuracaoHTTP "github.com/teusf/billing-system/internal/infrastructure/http/configuracao"
+	faturaHTTP "github.com/teusf/billing-system/internal/infrastructure/http/fatura"
+	relatorioHTTP "github.com/teusf/billing-system/internal/infrastructure/http/relatorio"
+	webhookHTTP "github.com/teusf/billing-system/internal/infrastructure/http/webhook"
 	"github.com/teusf/billing-system/internal/infrastructure/logger"
+	assinaturaRepo "github.com/teusf/billing-system/internal/infrastructure/repository/assinatura"
+	clienteRepo "github.com/teusf/billing-system/internal/infrastructure/repository/cliente"
+	configuracaoRepo "github.com/teusf/billing-system/internal/infrastructure/repository/configuracao"
+	eventstoreRepo "github.com/teusf/billing-system/internal/infrastructure/repository/eventstore"
+	faturaRepo "github.com/teusf/billing-system/internal/infrastructure/repository/fatura"
+	mensagemRepo "github.com/teusf/billing-system/internal/infrastructure/repository/mensagem"
+	projecaoRepo "github.com/teusf/billing-system/internal/infrastructure/repository/projecao"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/transaction"
+	"github.com/teusf/billing-system/internal/usecase/entrega"
+	"github.com/teusf/billing-system/internal/usecase/envio"
 )
 
 func main() {
@@ -60,11 +82,61 @@ func main() {
 		w.Write([]byte("OK"))
 	})
 
-	// 6. Inicia o servidor
+	// Recursos
+	clientes := clienteRepo.NewClientePostgres(db)
+	faturas := faturaRepo.NewFaturaPostgres(db)
+	configuracoes := configuracaoRepo.NewConfiguracaoPostgres(db)
+	uow := transaction.NewUnitOfWorkPostgres(db)
+
+	r.Mount("/clientes", clienteHTTP.NewClienteHandler(clientes, log).Routes())
+	r.Mount("/faturas", faturaHTTP.NewFaturaHandler(faturas, clientes, eventstoreRepo.NewEventStorePostgres(db), uow, cfg.APIURLPublica, log).Routes())
+	r.Mount("/assinaturas", assinaturaHTTP.NewAssinaturaHandler(assinaturaRepo.NewAssinaturaPostgres(db), clientes, uow, log).Routes())
+	r.Mount("/configuracoes", configuracaoHTTP.NewConfiguracaoHandler(configuracoes, log).Routes())
+	r.Mount("/relatorios", relatorioHTTP.NewRelatorioHandler(projecaoRepo.NewRecebiveisPostgres(db), projecaoRepo.NewEstatisticasEnvioPostgres(db), log).Routes())
+
+	// Webhooks de provedores: autenticados pela chave compartilhada, não pelo usuário
+	if cfg.EvolutionWebhookKey != "" {
+		r.Mount("/webhooks/evolution", webhookHTTP.NewEvolutionHandler(entrega.NewService(uow), cfg.EvolutionWebhookKey, log).Routes())
+	} else {
+		log.Warn("EVOLUTION_WEBHOOK_KEY not set: delivery and read receipts are disabled")
+	}
+
+	// 6. Workers em background (param junto com o servidor)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var workers sync.WaitGroup
+	if cfg.DispatcherAtivo {
+		mensageiro := evolution.NewClient(cfg.EvolutionAPIURL, cfg.EvolutionAPIKey, cfg.EvolutionInstance)
+		dispatcher := envio.NewDispatcher(mensagemRepo.NewMensagemPostgres(db), uow, mensageiro, log, envio.Opcoes{
+			Workers: cfg.DispatcherWorkers,
+		})
+
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			dispatcher.Run(ctx)
+		}()
+	}
+
+	// 7. Inicia o servidor
 	addr := fmt.Sprintf(":%s", cfg.AppPort)
-	log.Info("Server listening", zap.String("addr", addr))
+	srv := &http.Server{Addr: addr, Handler: r}
+
+	go func() {
+		log.Info("Server listening", zap.String("addr", addr))
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Server failed", zap.Error(err))
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info("Shutting down")
 
-	if err := http.ListenAndServe(addr, r); err != nil {
-		log.Fatal("Server failed", zap.Error(err))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Error("Server shutdown failed", zap.Error(err))
 	}
+	workers.Wait()
 }
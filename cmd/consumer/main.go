@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/config"
+	"github.com/teusf/billing-system/internal/infrastructure/database"
+	"github.com/teusf/billing-system/internal/infrastructure/evolution"
+	"github.com/teusf/billing-system/internal/infrastructure/logger"
+	"github.com/teusf/billing-system/internal/infrastructure/messaging"
+	"github.com/teusf/billing-system/internal/infrastructure/messaging/rabbitmq"
+	mensagemRepo "github.com/teusf/billing-system/internal/infrastructure/repository/mensagem"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/transaction"
+	"github.com/teusf/billing-system/internal/usecase/envio"
+)
+
+func main() {
+	// 1. Carrega Configurações
+	cfg, err := config.LoadConfig(".env")
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 2. Configura Logger
+	isDebug := cfg.AppEnv == "development"
+	log := logger.NewLogger(isDebug)
+	defer log.Sync()
+
+	log.Info("Starting Billing System Consumer", zap.String("env", cfg.AppEnv))
+
+	// 3. Conecta ao banco de dados (as migrations são executadas pela API)
+	db, err := database.NewPostgresConnection(cfg, log)
+	if err != nil {
+		log.Fatal("Could not connect to database", zap.Error(err))
+	}
+	defer db.Close()
+
+	// 4. Conecta ao RabbitMQ e garante a topologia
+	broker, err := rabbitmq.Conectar(rabbitmq.URL(cfg.RabbitMQHost, cfg.RabbitMQPort, cfg.RabbitMQUser, cfg.RabbitMQPassword), 1, log)
+	if err != nil {
+		log.Fatal("Could not connect to RabbitMQ", zap.Error(err))
+	}
+	defer broker.Close()
+
+	if err := broker.Declarar(messaging.TopologiaPadrao()); err != nil {
+		log.Fatal("Failed to declare RabbitMQ topology", zap.Error(err))
+	}
+
+	// 5. Monta o consumidor
+	mensageiro := evolution.NewClient(cfg.EvolutionAPIURL, cfg.EvolutionAPIKey, cfg.EvolutionInstance)
+	processador := envio.NewProcessador(mensagemRepo.NewMensagemPostgres(db), transaction.NewUnitOfWorkPostgres(db), mensageiro, log, envio.Opcoes{})
+	consumidor := messaging.NewConsumidorEnvio(broker, processador, log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// 6. Um canal por worker; se algum cair, encerra tudo para o processo ser reiniciado
+	var workers sync.WaitGroup
+	for i := 0; i < cfg.ConsumidorWorkers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if err := consumidor.Run(ctx); err != nil {
+				log.Error("Consumer stopped", zap.Error(err))
+				stop()
+			}
+		}()
+	}
+
+	log.Info("Consumer running", zap.Int("workers", cfg.ConsumidorWorkers))
+	<-ctx.Done()
+
+	log.Info("Shutting down consumer")
+	workers.Wait()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/config"
+	"github.com/teusf/billing-system/internal/infrastructure/database"
+	"github.com/teusf/billing-system/internal/infrastructure/logger"
+	eventstoreRepo "github.com/teusf/billing-system/internal/infrastructure/repository/eventstore"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/transaction"
+	"github.com/teusf/billing-system/internal/usecase/projecao"
+)
+
+func main() {
+	reconstruir := flag.String("reconstruir", "", "reconstrói a projeção informada a partir do evento zero e encerra")
+	flag.Parse()
+
+	// 1. Carrega Configurações
+	cfg, err := config.LoadConfig(".env")
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 2. Configura Logger
+	isDebug := cfg.AppEnv == "development"
+	log := logger.NewLogger(isDebug)
+	defer log.Sync()
+
+	log.Info("Starting Billing System Projector", zap.String("env", cfg.AppEnv))
+
+	// 3. Conecta ao banco de dados (as migrations são executadas pela API)
+	db, err := database.NewPostgresConnection(cfg, log)
+	if err != nil {
+		log.Fatal("Could not connect to database", zap.Error(err))
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	runner := projecao.NewRunner(
+		eventstoreRepo.NewEventStorePostgres(db),
+		transaction.NewProjecaoUnitOfWorkPostgres(db),
+		projecao.Padrao(),
+		log,
+		projecao.Opcoes{},
+	)
+
+	// 4a. Reconstrução: pode rodar com a API e outros projectors no ar;
+	// a projeção fica reservada até o commit e as consultas veem a versão anterior até lá.
+	if *reconstruir != "" {
+		n, err := runner.Reconstruir(ctx, *reconstruir)
+		if err != nil {
+			log.Fatal("Failed to rebuild projection", zap.String("projecao", *reconstruir), zap.Strings("disponiveis", runner.Nomes()), zap.Error(err))
+		}
+		log.Info("Projection rebuilt", zap.String("projecao", *reconstruir), zap.Int("eventos", n))
+		return
+	}
+
+	// 4b. Acompanha o event store até receber sinal de encerramento.
+	// Várias réplicas podem rodar: cada projeção é processada por uma de cada vez.
+	runner.Run(ctx)
+}
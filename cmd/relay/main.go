@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/config"
+	"github.com/teusf/billing-system/internal/infrastructure/database"
+	"github.com/teusf/billing-system/internal/infrastructure/logger"
+	"github.com/teusf/billing-system/internal/infrastructure/messaging"
+	"github.com/teusf/billing-system/internal/infrastructure/messaging/rabbitmq"
+	eventstoreRepo "github.com/teusf/billing-system/internal/infrastructure/repository/eventstore"
+	"github.com/teusf/billing-system/internal/usecase/outbox"
+)
+
+func main() {
+	// 1. Carrega Configurações
+	cfg, err := config.LoadConfig(".env")
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 2. Configura Logger
+	isDebug := cfg.AppEnv == "development"
+	log := logger.NewLogger(isDebug)
+	defer log.Sync()
+
+	log.Info("Starting Billing System Outbox Relay", zap.String("env", cfg.AppEnv))
+
+	// 3. Conecta ao banco de dados (as migrations são executadas pela API)
+	db, err := database.NewPostgresConnection(cfg, log)
+	if err != nil {
+		log.Fatal("Could not connect to database", zap.Error(err))
+	}
+	defer db.Close()
+
+	// 4. Conecta ao RabbitMQ e garante a topologia
+	broker, err := rabbitmq.Conectar(rabbitmq.URL(cfg.RabbitMQHost, cfg.RabbitMQPort, cfg.RabbitMQUser, cfg.RabbitMQPassword), 1, log)
+	if err != nil {
+		log.Fatal("Could not connect to RabbitMQ", zap.Error(err))
+	}
+	defer broker.Close()
+
+	if err := broker.Declarar(messaging.TopologiaPadrao()); err != nil {
+		log.Fatal("Failed to declare RabbitMQ topology", zap.Error(err))
+	}
+
+	// 5. Publica o outbox até receber sinal de encerramento.
+	// Várias réplicas podem rodar: o advisory lock deixa apenas uma publicando por vez.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	relay := outbox.NewRelay(eventstoreRepo.NewOutboxPostgres(db), messaging.NewPublicador(broker), log, outbox.Opcoes{})
+	relay.Run(ctx)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/config"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/database"
+	"github.com/teusf/billing-system/internal/infrastructure/logger"
+	clienteRepo "github.com/teusf/billing-system/internal/infrastructure/repository/cliente"
+	configuracaoRepo "github.com/teusf/billing-system/internal/infrastructure/repository/configuracao"
+	eventstoreRepo "github.com/teusf/billing-system/internal/infrastructure/repository/eventstore"
+	faturaRepo "github.com/teusf/billing-system/internal/infrastructure/repository/fatura"
+	mensagemRepo "github.com/teusf/billing-system/internal/infrastructure/repository/mensagem"
+	reguaRepo "github.com/teusf/billing-system/internal/infrastructure/repository/regua"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/transaction"
+	"github.com/teusf/billing-system/internal/infrastructure/scheduler"
+	"github.com/teusf/billing-system/internal/usecase/assinatura"
+	"github.com/teusf/billing-system/internal/usecase/lembrete"
+	"github.com/teusf/billing-system/internal/usecase/regua"
+	"github.com/teusf/billing-system/internal/usecase/vencimento"
+)
+
+func main() {
+	// 1. Carrega Configurações
+	cfg, err := config.LoadConfig(".env")
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 2. Configura Logger
+	isDebug := cfg.AppEnv == "development"
+	log := logger.NewLogger(isDebug)
+	defer log.Sync()
+
+	log.Info("Starting Billing System Scheduler", zap.String("env", cfg.AppEnv))
+
+	// 3. Conecta ao banco de dados (as migrations são executadas pela API)
+	db, err := database.NewPostgresConnection(cfg, log)
+	if err != nil {
+		log.Fatal("Could not connect to database", zap.Error(err))
+	}
+	defer db.Close()
+
+	// 4. Monta os casos de uso
+	repos := repository.Repositorios{
+		Clientes:      clienteRepo.NewClientePostgres(db),
+		Faturas:       faturaRepo.NewFaturaPostgres(db),
+		Mensagens:     mensagemRepo.NewMensagemPostgres(db),
+		Configuracoes: configuracaoRepo.NewConfiguracaoPostgres(db),
+		Eventos:       eventstoreRepo.NewEventStorePostgres(db),
+		Regua:         reguaRepo.NewReguaPostgres(db),
+	}
+	uow := transaction.NewUnitOfWorkPostgres(db)
+
+	lembretes := lembrete.NewService(repos, uow, log, cfg.APIURLPublica)
+	vencimentos := vencimento.NewService(repos, uow, log, cfg.CobrancaAoVencer, cfg.APIURLPublica)
+	reguas := regua.NewService(repos, uow, log, cfg.APIURLPublica)
+	assinaturas := assinatura.NewGerador(uow, log, cfg.AntecedenciaAssinaturaDias)
+
+	// 5. Agenda os jobs
+	s := scheduler.NewScheduler(log)
+
+	err = s.Agendar("lembretes", time.Duration(cfg.IntervaloLembreteMinutos)*time.Minute, func() error {
+		n, err := lembretes.Executar()
+		if n > 0 {
+			log.Info("Lembretes enfileirados", zap.Int("quantidade", n))
+		}
+		return err
+	})
+	if err != nil {
+		log.Fatal("Failed to schedule job", zap.Error(err))
+	}
+
+	err = s.Agendar("vencimentos", time.Duration(cfg.IntervaloVencimentoMinutos)*time.Minute, func() error {
+		n, err := vencimentos.Executar()
+		if n > 0 {
+			log.Info("Faturas marcadas como vencidas", zap.Int("quantidade", n))
+		}
+		return err
+	})
+	if err != nil {
+		log.Fatal("Failed to schedule job", zap.Error(err))
+	}
+
+	err = s.Agendar("regua", time.Duration(cfg.IntervaloReguaMinutos)*time.Minute, func() error {
+		n, err := reguas.Executar()
+		if n > 0 {
+			log.Info("Mensagens da regua de cobranca enfileiradas", zap.Int("quantidade", n))
+		}
+		return err
+	})
+	if err != nil {
+		log.Fatal("Failed to schedule job", zap.Error(err))
+	}
+
+	err = s.Agendar("assinaturas", time.Duration(cfg.IntervaloAssinaturaMinutos)*time.Minute, func() error {
+		n, err := assinaturas.Executar()
+		if n > 0 {
+			log.Info("Faturas de assinaturas emitidas", zap.Int("quantidade", n))
+		}
+		return err
+	})
+	if err != nil {
+		log.Fatal("Failed to schedule job", zap.Error(err))
+	}
+
+	s.Start()
+	log.Info("Scheduler running")
+
+	// 6. Aguarda sinal de encerramento
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Info("Shutting down scheduler")
+	s.Stop()
+}
@@ -31,10 +31,33 @@ type Config struct {
 	EvolutionAPIKey   string `mapstructure:"EVOLUTION_API_KEY"`
 	EvolutionInstance string `mapstructure:"EVOLUTION_INSTANCE"`
 
+	// Chave compartilhada do webhook de status (POST /webhooks/evolution), conferida no
+	// header apikey ou no campo apikey do corpo. Vazia = webhook desligado.
+	EvolutionWebhookKey string `mapstructure:"EVOLUTION_WEBHOOK_KEY"`
+
+	// Endereço externo da API (ex.: https://api.exemplo.com.br), usado nos links
+	// enviados aos clientes, como o QR code do Pix. Vazio = mensagens sem links.
+	APIURLPublica string `mapstructure:"API_URL_PUBLICA"`
+
 	// Business Rules
 	LembreteDiasAntes  int    `mapstructure:"LEMBRETE_DIAS_ANTES"`
 	HorarioInicioEnvio string `mapstructure:"HORARIO_INICIO_ENVIO"`
 	HorarioFimEnvio    string `mapstructure:"HORARIO_FIM_ENVIO"`
+
+	// Scheduler
+	IntervaloLembreteMinutos   int  `mapstructure:"INTERVALO_LEMBRETE_MINUTOS"`
+	IntervaloVencimentoMinutos int  `mapstructure:"INTERVALO_VENCIMENTO_MINUTOS"`
+	IntervaloReguaMinutos      int  `mapstructure:"INTERVALO_REGUA_MINUTOS"`
+	CobrancaAoVencer           bool `mapstructure:"COBRANCA_AO_VENCER"`
+	IntervaloAssinaturaMinutos int  `mapstructure:"INTERVALO_ASSINATURA_MINUTOS"`
+	AntecedenciaAssinaturaDias int  `mapstructure:"ANTECEDENCIA_ASSINATURA_DIAS"` // Dias antes do vencimento em que a fatura do ciclo é emitida
+
+	// Dispatcher de mensagens
+	DispatcherAtivo   bool `mapstructure:"DISPATCHER_ATIVO"`
+	DispatcherWorkers int  `mapstructure:"DISPATCHER_WORKERS"`
+
+	// Consumidor da fila de envio (cmd/consumer)
+	ConsumidorWorkers int `mapstructure:"CONSUMIDOR_WORKERS"`
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -46,6 +69,15 @@ func LoadConfig(path string) (*Config, error) {
 	viper.SetDefault("APP_PORT", "8080")
 	viper.SetDefault("LOG_LEVEL", "debug")
 	viper.SetDefault("LEMBRETE_DIAS_ANTES", 3)
+	viper.SetDefault("INTERVALO_LEMBRETE_MINUTOS", 5)
+	viper.SetDefault("INTERVALO_VENCIMENTO_MINUTOS", 60)
+	viper.SetDefault("INTERVALO_REGUA_MINUTOS", 15)
+	viper.SetDefault("COBRANCA_AO_VENCER", true)
+	viper.SetDefault("INTERVALO_ASSINATURA_MINUTOS", 60)
+	viper.SetDefault("ANTECEDENCIA_ASSINATURA_DIAS", 10)
+	viper.SetDefault("DISPATCHER_ATIVO", true)
+	viper.SetDefault("DISPATCHER_WORKERS", 4)
+	viper.SetDefault("CONSUMIDOR_WORKERS", 4)
 
 	viper.AutomaticEnv() // Read from env variables
 
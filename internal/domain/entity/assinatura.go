@@ -0,0 +1,305 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type Periodicidade string
+type StatusAssinatura string
+
+const (
+	PeriodicidadeMensal     Periodicidade = "mensal"
+	PeriodicidadeTrimestral Periodicidade = "trimestral"
+	PeriodicidadeSemestral  Periodicidade = "semestral"
+	PeriodicidadeAnual      Periodicidade = "anual"
+
+	StatusAssinaturaAtiva     StatusAssinatura = "ativa"
+	StatusAssinaturaPausada   StatusAssinatura = "pausada"   // Não gera faturas; os ciclos da pausa não são cobrados
+	StatusAssinaturaCancelada StatusAssinatura = "cancelada" // Encerrada pelo tenant
+	StatusAssinaturaEncerrada StatusAssinatura = "encerrada" // Chegou à data de fim
+)
+
+var (
+	ErrPeriodicidadeInvalida     = errors.New("periodicidade deve ser mensal, trimestral, semestral ou anual")
+	ErrDiaVencimentoInvalido     = errors.New("dia de vencimento deve estar entre 1 e 31")
+	ErrDescricaoObrigatoria      = errors.New("descricao da assinatura e obrigatoria")
+	ErrFimAntesDoInicio          = errors.New("data de fim deve ser posterior a data de inicio")
+	ErrAssinaturaNaoAtiva        = errors.New("assinatura nao esta ativa")
+	ErrAssinaturaNaoPausada      = errors.New("assinatura nao esta pausada")
+	ErrAssinaturaFinalizada      = errors.New("assinatura ja foi cancelada ou encerrada")
+	ErrAssinaturaSemCiclosAtivos = errors.New("assinatura nao tem ciclos a faturar ate a data de fim")
+	ErrAssinaturaNaoEncontrada   = errors.New("assinatura nao encontrada")
+)
+
+// Meses é a duração de um ciclo da periodicidade (0 se inválida)
+func (p Periodicidade) Meses() int {
+	switch p {
+	case PeriodicidadeMensal:
+		return 1
+	case PeriodicidadeTrimestral:
+		return 3
+	case PeriodicidadeSemestral:
+		return 6
+	case PeriodicidadeAnual:
+		return 12
+	}
+	return 0
+}
+
+// Assinatura é a cobrança recorrente de um cliente: a cada ciclo, o gerador
+// (usecase/assinatura) emite uma fatura com o valor e a descrição dela.
+// O estado fica na tabela assinaturas; as mudanças também viram eventos no stream dela.
+type Assinatura struct {
+	BaseEntity
+	ClienteID     string
+	Valor         Dinheiro
+	Descricao     string
+	Periodicidade Periodicidade
+	DiaVencimento int // Em meses mais curtos, vence no último dia (31 vira 30, 28 ou 29)
+	DataInicio    time.Time
+	DataFim       *time.Time // Nil = sem prazo
+	Status        StatusAssinatura
+
+	// ProximoCiclo é o índice do próximo ciclo a faturar, contado a partir do mês de DataInicio.
+	// Guardar o índice, e não a data, evita que o dia 31 "encolha" depois de fevereiro.
+	ProximoCiclo int
+
+	// Versao é a última versão do stream de eventos já persistida (0 = assinatura nova)
+	Versao  int
+	eventos []*Event
+}
+
+// Payloads dos eventos do agregado Assinatura
+
+type AssinaturaCriadaData struct {
+	AssinaturaID  string        `json:"assinatura_id"`
+	ClienteID     string        `json:"cliente_id"`
+	Valor         Dinheiro      `json:"valor"`
+	Descricao     string        `json:"descricao"`
+	Periodicidade Periodicidade `json:"periodicidade"`
+	DiaVencimento int           `json:"dia_vencimento"`
+	DataInicio    time.Time     `json:"data_inicio"`
+	DataFim       *time.Time    `json:"data_fim,omitempty"`
+}
+
+type AssinaturaFaturaGeradaData struct {
+	AssinaturaID string    `json:"assinatura_id"`
+	ClienteID    string    `json:"cliente_id"`
+	FaturaID     string    `json:"fatura_id"`
+	Periodo      string    `json:"periodo"`
+	Vencimento   time.Time `json:"vencimento"`
+}
+
+// AssinaturaStatusData é o payload de pausa, retomada, cancelamento e encerramento.
+// ProximoVencimento só vai na pausa e na retomada: é o ciclo que será faturado ao retomar.
+type AssinaturaStatusData struct {
+	AssinaturaID      string     `json:"assinatura_id"`
+	ClienteID         string     `json:"cliente_id"`
+	ProximoVencimento *time.Time `json:"proximo_vencimento,omitempty"`
+}
+
+// NewAssinatura cria a assinatura ativa. O primeiro ciclo é o primeiro vencimento a partir
+// de hoje e do início: uma assinatura com início no passado não cobra os meses anteriores.
+func NewAssinatura(clienteID string, valor Dinheiro, descricao string, periodicidade Periodicidade, diaVencimento int, inicio time.Time, fim *time.Time) (*Assinatura, error) {
+	if valor.Moeda() != MoedaBRL {
+		return nil, ErrMoedaNaoSuportada
+	}
+
+	a := &Assinatura{
+		BaseEntity:    NewBase(),
+		ClienteID:     clienteID,
+		Valor:         valor,
+		Descricao:     descricao,
+		Periodicidade: periodicidade,
+		DiaVencimento: diaVencimento,
+		DataInicio:    inicioDoDia(inicio),
+		DataFim:       fim,
+		Status:        StatusAssinaturaAtiva,
+	}
+	if fim != nil {
+		f := inicioDoDia(*fim)
+		a.DataFim = &f
+	}
+
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	a.ProximoCiclo = a.primeiroCicloDesde(time.Now())
+	if a.semCiclos() {
+		return nil, ErrAssinaturaSemCiclosAtivos
+	}
+
+	a.registrar(EventAssinaturaCriada, AssinaturaCriadaData{
+		AssinaturaID:  a.ID,
+		ClienteID:     a.ClienteID,
+		Valor:         a.Valor,
+		Descricao:     a.Descricao,
+		Periodicidade: a.Periodicidade,
+		DiaVencimento: a.DiaVencimento,
+		DataInicio:    a.DataInicio,
+		DataFim:       a.DataFim,
+	})
+
+	return a, nil
+}
+
+func (a *Assinatura) Validate() error {
+	if !a.Valor.IsPositivo() {
+		return ErrValorInvalido
+	}
+	if a.Descricao == "" {
+		return ErrDescricaoObrigatoria
+	}
+	if a.Periodicidade.Meses() == 0 {
+		return ErrPeriodicidadeInvalida
+	}
+	if a.DiaVencimento < 1 || a.DiaVencimento > 31 {
+		return ErrDiaVencimentoInvalido
+	}
+	if a.DataFim != nil && !a.DataFim.After(a.DataInicio) {
+		return ErrFimAntesDoInicio
+	}
+	return nil
+}
+
+// Vencimento é a data de vencimento do ciclo n
+func (a *Assinatura) Vencimento(ciclo int) time.Time {
+	// Dia 1 do mês do ciclo; time.Date normaliza meses acima de 12
+	mes := time.Date(a.DataInicio.Year(), a.DataInicio.Month()+time.Month(ciclo*a.Periodicidade.Meses()), 1, 0, 0, 0, 0, a.DataInicio.Location())
+	ultimoDia := mes.AddDate(0, 1, -1).Day()
+
+	dia := a.DiaVencimento
+	if dia > ultimoDia {
+		dia = ultimoDia
+	}
+	return mes.AddDate(0, 0, dia-1)
+}
+
+// ProximoVencimento é o vencimento do próximo ciclo a faturar
+func (a *Assinatura) ProximoVencimento() time.Time {
+	return a.Vencimento(a.ProximoCiclo)
+}
+
+// Periodo identifica o ciclo pelo mês do vencimento (AAAA-MM). Como os ciclos têm
+// pelo menos um mês, o período é único na assinatura e serve de chave de idempotência.
+func (a *Assinatura) Periodo(ciclo int) string {
+	return a.Vencimento(ciclo).Format("2006-01")
+}
+
+// RegistrarFatura avança para o próximo ciclo depois que a fatura do atual foi emitida.
+// Se não houver mais ciclos até a data de fim, a assinatura é encerrada.
+func (a *Assinatura) RegistrarFatura(faturaID string) error {
+	if a.Status != StatusAssinaturaAtiva {
+		return ErrAssinaturaNaoAtiva
+	}
+
+	a.registrar(EventAssinaturaFaturaGerada, AssinaturaFaturaGeradaData{
+		AssinaturaID: a.ID,
+		ClienteID:    a.ClienteID,
+		FaturaID:     faturaID,
+		Periodo:      a.Periodo(a.ProximoCiclo),
+		Vencimento:   a.ProximoVencimento(),
+	})
+
+	a.ProximoCiclo++
+	a.encerrarSeAcabou()
+	a.Touch()
+	return nil
+}
+
+// Pausar suspende a geração de faturas
+func (a *Assinatura) Pausar() error {
+	if a.Status != StatusAssinaturaAtiva {
+		return ErrAssinaturaNaoAtiva
+	}
+
+	a.Status = StatusAssinaturaPausada
+	a.registrar(EventAssinaturaPausada, a.dadosStatus())
+	a.Touch()
+	return nil
+}
+
+// Retomar reativa a assinatura a partir do primeiro vencimento desde agora:
+// os ciclos que venceram durante a pausa não são cobrados.
+func (a *Assinatura) Retomar(agora time.Time) error {
+	if a.Status != StatusAssinaturaPausada {
+		return ErrAssinaturaNaoPausada
+	}
+
+	if proximo := a.primeiroCicloDesde(agora); proximo > a.ProximoCiclo {
+		a.ProximoCiclo = proximo
+	}
+	a.Status = StatusAssinaturaAtiva
+	a.registrar(EventAssinaturaRetomada, a.dadosStatus())
+	a.encerrarSeAcabou()
+	a.Touch()
+	return nil
+}
+
+// Cancelar encerra a assinatura; as faturas já emitidas não são afetadas
+func (a *Assinatura) Cancelar() error {
+	if a.Status == StatusAssinaturaCancelada || a.Status == StatusAssinaturaEncerrada {
+		return ErrAssinaturaFinalizada
+	}
+
+	a.Status = StatusAssinaturaCancelada
+	a.registrar(EventAssinaturaCancelada, AssinaturaStatusData{AssinaturaID: a.ID, ClienteID: a.ClienteID})
+	a.Touch()
+	return nil
+}
+
+// EventosPendentes retorna os eventos ainda não gravados no event store
+func (a *Assinatura) EventosPendentes() []*Event {
+	return a.eventos
+}
+
+// ConfirmarEventos é chamado pelo repositório depois de gravar os eventos pendentes
+func (a *Assinatura) ConfirmarEventos() {
+	a.Versao += len(a.eventos)
+	a.eventos = nil
+}
+
+func (a *Assinatura) encerrarSeAcabou() {
+	if a.Status == StatusAssinaturaAtiva && a.semCiclos() {
+		a.Status = StatusAssinaturaEncerrada
+		a.registrar(EventAssinaturaEncerrada, AssinaturaStatusData{AssinaturaID: a.ID, ClienteID: a.ClienteID})
+	}
+}
+
+func (a *Assinatura) semCiclos() bool {
+	return a.DataFim != nil && a.ProximoVencimento().After(*a.DataFim)
+}
+
+// primeiroCicloDesde é o primeiro ciclo que vence no dia de desde ou depois, sem voltar antes do início
+func (a *Assinatura) primeiroCicloDesde(desde time.Time) int {
+	dia := inicioDoDia(desde.In(a.DataInicio.Location()))
+	if dia.Before(a.DataInicio) {
+		dia = a.DataInicio
+	}
+
+	ciclo := 0
+	for a.Vencimento(ciclo).Before(dia) {
+		ciclo++
+	}
+	return ciclo
+}
+
+func (a *Assinatura) dadosStatus() AssinaturaStatusData {
+	proximo := a.ProximoVencimento()
+	return AssinaturaStatusData{AssinaturaID: a.ID, ClienteID: a.ClienteID, ProximoVencimento: &proximo}
+}
+
+// registrar guarda o evento como pendente de persistência. Diferente da fatura, a assinatura
+// não é reconstruída do stream: o estado já foi alterado pelo comando que chama registrar.
+func (a *Assinatura) registrar(eventType string, data interface{}) {
+	// Os payloads são structs simples: a serialização não tem como falhar
+	raw, _ := json.Marshal(data)
+	a.eventos = append(a.eventos, NewEvent(eventType, a.ID, AggregateAssinatura, raw, nil, 0))
+}
+
+func inicioDoDia(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAssinatura(t *testing.T) {
+	t.Run("should validate the fields", func(t *testing.T) {
+		inicio := time.Now()
+
+		_, err := NewAssinatura("cli-1", BRL(0), "Retainer", PeriodicidadeMensal, 10, inicio, nil)
+		assert.ErrorIs(t, err, ErrValorInvalido)
+		_, err = NewAssinatura("cli-1", BRL(1000), "", PeriodicidadeMensal, 10, inicio, nil)
+		assert.ErrorIs(t, err, ErrDescricaoObrigatoria)
+		_, err = NewAssinatura("cli-1", BRL(1000), "Retainer", "quinzenal", 10, inicio, nil)
+		assert.ErrorIs(t, err, ErrPeriodicidadeInvalida)
+		_, err = NewAssinatura("cli-1", BRL(1000), "Retainer", PeriodicidadeMensal, 32, inicio, nil)
+		assert.ErrorIs(t, err, ErrDiaVencimentoInvalido)
+
+		fim := inicio.AddDate(0, 0, -1)
+		_, err = NewAssinatura("cli-1", BRL(1000), "Retainer", PeriodicidadeMensal, 10, inicio, &fim)
+		assert.ErrorIs(t, err, ErrFimAntesDoInicio)
+	})
+
+	t.Run("should not bill cycles before today", func(t *testing.T) {
+		hoje := inicioDoDia(time.Now())
+		a, err := NewAssinatura("cli-1", BRL(1000), "Retainer", PeriodicidadeMensal, 1, hoje.AddDate(-1, 0, 0), nil)
+		assert.NoError(t, err)
+		assert.False(t, a.ProximoVencimento().Before(hoje))
+		assert.True(t, a.ProximoVencimento().Before(hoje.AddDate(0, 1, 1)))
+
+		assert.Len(t, a.EventosPendentes(), 1)
+		assert.Equal(t, EventAssinaturaCriada, a.EventosPendentes()[0].EventType)
+	})
+}
+
+func TestAssinatura_Vencimento(t *testing.T) {
+	ano := time.Now().Year() + 1
+
+	t.Run("should fall back to the last day of shorter months", func(t *testing.T) {
+		a, err := NewAssinatura("cli-1", BRL(1000), "Retainer", PeriodicidadeMensal, 31, time.Date(ano, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 0, a.ProximoCiclo)
+		assert.Equal(t, time.Date(ano, 1, 31, 0, 0, 0, 0, time.UTC), a.Vencimento(0))
+		assert.Equal(t, time.Date(ano, 3, 0, 0, 0, 0, 0, time.UTC), a.Vencimento(1)) // Último dia de fevereiro
+		assert.Equal(t, time.Date(ano, 3, 31, 0, 0, 0, 0, time.UTC), a.Vencimento(2))
+		assert.Equal(t, fmt.Sprintf("%d-03", ano), a.Periodo(2))
+	})
+
+	t.Run("should skip to the next month when the day already passed at the start", func(t *testing.T) {
+		a, _ := NewAssinatura("cli-1", BRL(1000), "Retainer", PeriodicidadeTrimestral, 5, time.Date(ano, 1, 20, 0, 0, 0, 0, time.UTC), nil)
+		assert.Equal(t, time.Date(ano, 4, 5, 0, 0, 0, 0, time.UTC), a.ProximoVencimento())
+		assert.Equal(t, time.Date(ano, 7, 5, 0, 0, 0, 0, time.UTC), a.Vencimento(a.ProximoCiclo+1))
+	})
+}
+
+func TestAssinatura_Ciclos(t *testing.T) {
+	ano := time.Now().Year() + 1
+
+	t.Run("should advance and close at the end date", func(t *testing.T) {
+		fim := time.Date(ano, 2, 15, 0, 0, 0, 0, time.UTC)
+		a, _ := NewAssinatura("cli-1", BRL(1000), "Retainer", PeriodicidadeMensal, 10, time.Date(ano, 1, 1, 0, 0, 0, 0, time.UTC), &fim)
+
+		assert.NoError(t, a.RegistrarFatura("fat-1"))
+		assert.Equal(t, StatusAssinaturaAtiva, a.Status)
+		assert.NoError(t, a.RegistrarFatura("fat-2"))
+		assert.Equal(t, StatusAssinaturaEncerrada, a.Status)
+
+		tipos := []string{}
+		for _, e := range a.EventosPendentes() {
+			tipos = append(tipos, e.EventType)
+		}
+		assert.Equal(t, []string{EventAssinaturaCriada, EventAssinaturaFaturaGerada, EventAssinaturaFaturaGerada, EventAssinaturaEncerrada}, tipos)
+
+		assert.ErrorIs(t, a.RegistrarFatura("fat-3"), ErrAssinaturaNaoAtiva)
+		assert.ErrorIs(t, a.Cancelar(), ErrAssinaturaFinalizada)
+	})
+
+	t.Run("should not bill the cycles skipped while paused", func(t *testing.T) {
+		a, _ := NewAssinatura("cli-1", BRL(1000), "Retainer", PeriodicidadeMensal, 10, time.Date(ano, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+
+		assert.NoError(t, a.Pausar())
+		assert.ErrorIs(t, a.Pausar(), ErrAssinaturaNaoAtiva)
+		assert.ErrorIs(t, a.RegistrarFatura("fat-1"), ErrAssinaturaNaoAtiva)
+
+		assert.NoError(t, a.Retomar(time.Date(ano, 3, 20, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, StatusAssinaturaAtiva, a.Status)
+		assert.Equal(t, time.Date(ano, 4, 10, 0, 0, 0, 0, time.UTC), a.ProximoVencimento())
+		assert.ErrorIs(t, a.Retomar(time.Now()), ErrAssinaturaNaoPausada)
+
+		eventos := a.EventosPendentes()
+		assert.Equal(t, EventAssinaturaPausada, eventos[1].EventType)
+		assert.Equal(t, EventAssinaturaRetomada, eventos[2].EventType)
+		payload, err := Eventos.Decodificar(eventos[2])
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(ano, 4, 10, 0, 0, 0, 0, time.UTC), *payload.(*AssinaturaStatusData).ProximoVencimento)
+	})
+
+	t.Run("should cancel active and paused subscriptions", func(t *testing.T) {
+		a, _ := NewAssinatura("cli-1", BRL(1000), "Retainer", PeriodicidadeMensal, 10, time.Date(ano, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+		assert.NoError(t, a.Pausar())
+		assert.NoError(t, a.Cancelar())
+		assert.Equal(t, StatusAssinaturaCancelada, a.Status)
+		assert.Equal(t, EventAssinaturaCancelada, a.EventosPendentes()[2].EventType)
+
+		a.ConfirmarEventos()
+		assert.Equal(t, 3, a.Versao)
+		assert.Empty(t, a.EventosPendentes())
+	})
+}
@@ -0,0 +1,180 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBoletoNaoConfigurado         = errors.New("boleto nao configurado para o tenant")
+	ErrBancoBoletoInvalido          = errors.New("codigo do banco do boleto deve ter 3 digitos")
+	ErrConvenioBoletoInvalido       = errors.New("convenio do boleto deve ter 7 digitos")
+	ErrCarteiraBoletoInvalida       = errors.New("carteira do boleto deve ter 2 digitos")
+	ErrVencimentoBoletoForaDoLimite = errors.New("vencimento fora do intervalo aceito pelo boleto")
+	ErrValorBoletoForaDoLimite      = errors.New("valor excede o limite do boleto")
+)
+
+// ConfigBoleto são os dados da conta de cobrança do tenant no banco. Sem banco, o tenant não emite boleto.
+type ConfigBoleto struct {
+	Banco    string // Código de compensação (001 = Banco do Brasil)
+	Convenio string // Número do convênio de cobrança, 7 dígitos
+	Carteira string // Carteira de cobrança, 2 dígitos (ex.: 17)
+}
+
+func (c ConfigBoleto) Configurado() bool {
+	return c.Banco != ""
+}
+
+func (c ConfigBoleto) Validate() error {
+	if !c.Configurado() {
+		return nil
+	}
+	if len(c.Banco) != 3 || !soDigitos(c.Banco) {
+		return ErrBancoBoletoInvalido
+	}
+	if len(c.Convenio) != 7 || !soDigitos(c.Convenio) {
+		return ErrConvenioBoletoInvalido
+	}
+	if len(c.Carteira) != 2 || !soDigitos(c.Carteira) {
+		return ErrCarteiraBoletoInvalida
+	}
+	return nil
+}
+
+// Layout FEBRABAN do código de barras (44 posições):
+// banco(3) moeda(1) DV(1) fator de vencimento(4) valor(10) campo livre(25)
+const (
+	codigoMoedaBoleto        = "9" // Real
+	tamanhoValorBoleto       = 10
+	tamanhoComplementoBoleto = 10
+	fatorMinimo              = 1000
+	fatorCiclo               = 9000 // O fator vai de 1000 a 9999 e recomeça em 1000
+)
+
+// Data base do fator de vencimento. O fator chegou a 9999 em 21/02/2025 e voltou a 1000 no dia seguinte.
+var dataBaseFator = time.Date(1997, 10, 7, 0, 0, 0, 0, time.UTC)
+
+// Boleto é a cobrança bancária de uma fatura, calculada localmente (sem registro no banco)
+type Boleto struct {
+	Banco           string
+	NossoNumero     string // Convênio + complemento: identifica a fatura no retorno do banco
+	Valor           Dinheiro
+	Vencimento      time.Time
+	FatorVencimento string
+	CodigoBarras    string // 44 dígitos; é o conteúdo do código de barras Interleaved 2 of 5
+	LinhaDigitavel  string // 47 dígitos formatados em cinco campos, para digitação no internet banking
+}
+
+// NewBoleto monta o boleto da fatura. O campo livre segue o layout de convênio de 7 posições
+// (zeros(6) + nosso número(17) + carteira(2)); o nosso número usa os dígitos do número da fatura.
+func NewBoleto(config ConfigBoleto, fatura *Fatura, valor Dinheiro, vencimento time.Time) (*Boleto, error) {
+	if !config.Configurado() {
+		return nil, ErrBoletoNaoConfigurado
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if valor.Moeda() != "" && valor.Moeda() != MoedaBRL {
+		return nil, ErrMoedaNaoSuportada
+	}
+	if valor.Centavos() < 0 || len(fmt.Sprint(valor.Centavos())) > tamanhoValorBoleto {
+		return nil, ErrValorBoletoForaDoLimite
+	}
+
+	fator, err := FatorVencimento(vencimento)
+	if err != nil {
+		return nil, err
+	}
+
+	nossoNumero := config.Convenio + complementoNossoNumero(fatura.Numero)
+	campoLivre := "000000" + nossoNumero + config.Carteira
+	valorCampo := fmt.Sprintf("%0*d", tamanhoValorBoleto, valor.Centavos())
+
+	semDV := config.Banco + codigoMoedaBoleto + fator + valorCampo + campoLivre
+	codigo := semDV[:4] + dvModulo11(semDV) + semDV[4:]
+
+	return &Boleto{
+		Banco:           config.Banco,
+		NossoNumero:     nossoNumero,
+		Valor:           valor,
+		Vencimento:      vencimento,
+		FatorVencimento: fator,
+		CodigoBarras:    codigo,
+		LinhaDigitavel:  LinhaDigitavel(codigo),
+	}, nil
+}
+
+// FatorVencimento é o número de dias entre a data base e o vencimento, no ciclo de 1000 a 9999
+func FatorVencimento(vencimento time.Time) (string, error) {
+	dia := time.Date(vencimento.Year(), vencimento.Month(), vencimento.Day(), 0, 0, 0, 0, time.UTC)
+	dias := int(dia.Sub(dataBaseFator).Hours() / 24)
+	if dias < fatorMinimo {
+		return "", ErrVencimentoBoletoForaDoLimite
+	}
+	return fmt.Sprintf("%04d", (dias-fatorMinimo)%fatorCiclo+fatorMinimo), nil
+}
+
+// LinhaDigitavel converte o código de barras na representação numérica do boleto:
+// três campos do campo livre com DV módulo 10, o DV geral e fator + valor.
+func LinhaDigitavel(codigo string) string {
+	campoLivre := codigo[19:]
+
+	campo1 := codigo[:4] + campoLivre[:5]
+	campo1 += dvModulo10(campo1)
+	campo2 := campoLivre[5:15]
+	campo2 += dvModulo10(campo2)
+	campo3 := campoLivre[15:]
+	campo3 += dvModulo10(campo3)
+
+	return fmt.Sprintf("%s.%s %s.%s %s.%s %s %s",
+		campo1[:5], campo1[5:],
+		campo2[:5], campo2[5:],
+		campo3[:5], campo3[5:],
+		codigo[4:5],
+		codigo[5:19],
+	)
+}
+
+// complementoNossoNumero usa os últimos dígitos do número da fatura: FAT-2026-000042 vira 2026000042
+func complementoNossoNumero(numero string) string {
+	digitos := naoDigito.ReplaceAllString(numero, "")
+	if len(digitos) > tamanhoComplementoBoleto {
+		digitos = digitos[len(digitos)-tamanhoComplementoBoleto:]
+	}
+	return strings.Repeat("0", tamanhoComplementoBoleto-len(digitos)) + digitos
+}
+
+// dvModulo10 multiplica os dígitos por 2, 1, 2... da direita para a esquerda, somando os
+// algarismos dos produtos. O DV é o que falta para a próxima dezena.
+func dvModulo10(s string) string {
+	soma, peso := 0, 2
+	for i := len(s) - 1; i >= 0; i-- {
+		p := int(s[i]-'0') * peso
+		soma += p/10 + p%10
+		peso = 3 - peso
+	}
+	return fmt.Sprint((10 - soma%10) % 10)
+}
+
+// dvModulo11 é o DV geral do código de barras: pesos de 2 a 9 da direita para a esquerda.
+// Resto que daria DV 0, 10 ou 11 vira 1, como manda o layout FEBRABAN.
+func dvModulo11(s string) string {
+	soma, peso := 0, 2
+	for i := len(s) - 1; i >= 0; i-- {
+		soma += int(s[i]-'0') * peso
+		peso++
+		if peso > 9 {
+			peso = 2
+		}
+	}
+	dv := 11 - soma%11
+	if dv == 0 || dv == 10 || dv == 11 {
+		dv = 1
+	}
+	return fmt.Sprint(dv)
+}
+
+var naoDigito = regexp.MustCompile(`[^0-9]`)
@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFatorVencimento(t *testing.T) {
+	casos := map[string]time.Time{
+		"1000": time.Date(2000, 7, 3, 0, 0, 0, 0, time.UTC),
+		"9999": time.Date(2025, 2, 21, 0, 0, 0, 0, time.UTC),
+		"1001": time.Date(2025, 2, 23, 0, 0, 0, 0, time.UTC), // Segundo ciclo: 22/02/2025 voltou a 1000
+		"1626": time.Date(2026, 11, 10, 23, 59, 0, 0, time.UTC),
+	}
+	for fator, data := range casos {
+		f, err := FatorVencimento(data)
+		assert.NoError(t, err)
+		assert.Equal(t, fator, f, data.String())
+	}
+
+	_, err := FatorVencimento(time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrVencimentoBoletoForaDoLimite)
+}
+
+func TestLinhaDigitavel(t *testing.T) {
+	// Exemplo publicado pelo Banco do Brasil
+	codigo := "00193373700000001000500940144816060680935031"
+	assert.Equal(t, "00190.50095 40144.816069 06809.350314 3 37370000000100", LinhaDigitavel(codigo))
+}
+
+func TestNewBoleto(t *testing.T) {
+	config := ConfigBoleto{Banco: "001", Convenio: "1234567", Carteira: "17"}
+	f, _ := NewFatura("cli-1", "FAT-2026-000042", BRL(15050), time.Now().AddDate(0, 0, 5), "Servico")
+
+	t.Run("should compute the barcode and the linha digitavel", func(t *testing.T) {
+		vencimento := time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC)
+		b, err := NewBoleto(config, f, BRL(15050), vencimento)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "12345672026000042", b.NossoNumero)
+		assert.Equal(t, "1626", b.FatorVencimento)
+		assert.Equal(t, "00193162600000150500000001234567202600004217", b.CodigoBarras)
+		assert.Len(t, b.CodigoBarras, 44)
+		assert.Equal(t, "00190.00009 01234.567202 26000.042171 3 16260000015050", b.LinhaDigitavel)
+
+		// O DV geral confere com o restante do código
+		assert.Equal(t, b.CodigoBarras[4:5], dvModulo11(b.CodigoBarras[:4]+b.CodigoBarras[5:]))
+	})
+
+	t.Run("should reject invalid configurations", func(t *testing.T) {
+		_, err := NewBoleto(ConfigBoleto{}, f, BRL(100), time.Now())
+		assert.ErrorIs(t, err, ErrBoletoNaoConfigurado)
+
+		assert.ErrorIs(t, ConfigBoleto{Banco: "1", Convenio: "1234567", Carteira: "17"}.Validate(), ErrBancoBoletoInvalido)
+		assert.ErrorIs(t, ConfigBoleto{Banco: "001", Convenio: "12A4567", Carteira: "17"}.Validate(), ErrConvenioBoletoInvalido)
+		assert.ErrorIs(t, ConfigBoleto{Banco: "001", Convenio: "1234567", Carteira: "7"}.Validate(), ErrCarteiraBoletoInvalida)
+	})
+
+	t.Run("should reject values that do not fit the barcode", func(t *testing.T) {
+		_, err := NewBoleto(config, f, BRL(10000000000), time.Now())
+		assert.ErrorIs(t, err, ErrValorBoletoForaDoLimite)
+	})
+}
+
+func TestComplementoNossoNumero(t *testing.T) {
+	assert.Equal(t, "0000000042", complementoNossoNumero("42"))
+	assert.Equal(t, "2026000042", complementoNossoNumero("NF/2026/000042"))
+	assert.Equal(t, "2345678901", complementoNossoNumero("ABC-12345678901"))
+}
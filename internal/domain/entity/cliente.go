@@ -9,14 +9,18 @@ var (
 	ErrNomeCurto        = errors.New("nome deve ter pelo menos 3 digitos")
 	ErrWhatsAppInvalido = errors.New("whatsapp deve conter apenas numeros e ter entre 10 e 15 digitos")
 	ErrEmailInvalido    = errors.New("email invalido")
+
+	// ErrClienteNaoEncontrado indica um cliente referenciado (ex.: na criação da fatura) que não existe
+	ErrClienteNaoEncontrado = errors.New("cliente nao encontrado")
 )
 
 type Cliente struct {
 	BaseEntity
-	Nome     string
-	WhatsApp string
-	Email    string
-	Ativo    bool
+	UsuarioID string // Tenant dono do cliente (opcional)
+	Nome      string
+	WhatsApp  string
+	Email     string
+	Ativo     bool
 }
 
 func NewCliente(nome, whatsapp, email string) (*Cliente, error) {
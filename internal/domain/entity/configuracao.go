@@ -18,10 +18,16 @@ type Configuracao struct {
 	DiasAntesLembrete    int
 	TemplateLembrete     string
 	TemplateCobranca     string
+	TemplateConfirmacao  string // Confirmação de pagamento; vazio = comprovante padrão
 	WhatsAppFinanceiro   string
 	EnvioAutomaticoAtivo bool
 	HorarioInicioEnvio   string // HH:MM
 	HorarioFimEnvio      string // HH:MM
+	Numeracao            FormatoNumeracao
+	Encargos             EncargosAtraso // Multa e juros das faturas em atraso (padrão: sem encargos)
+	Pix                  ConfigPix      // Dados de recebimento Pix; vazio = tenant não cobra por Pix
+	Boleto               ConfigBoleto   // Conta de cobrança no banco; vazio = tenant não emite boleto
+	Regua                ReguaCobranca  // Etapas de cobrança; vazia = lembrete único e cobrança no vencimento
 }
 
 func NewConfiguracao(usuarioID string) (*Configuracao, error) {
@@ -32,6 +38,7 @@ func NewConfiguracao(usuarioID string) (*Configuracao, error) {
 		EnvioAutomaticoAtivo: true,
 		HorarioInicioEnvio:   "08:00",
 		HorarioFimEnvio:      "18:00",
+		Numeracao:            FormatoNumeracaoPadrao(),
 	}
 
 	if err := c.Validate(); err != nil {
@@ -55,7 +62,23 @@ func (c *Configuracao) Validate() error {
 		return ErrFormatoHoraInvalido
 	}
 
-	return nil
+	if err := c.Numeracao.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Encargos.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Pix.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Boleto.Validate(); err != nil {
+		return err
+	}
+
+	return c.Regua.Validate()
 }
 
 func (c *Configuracao) EstaDentroHorarioEnvio(agora time.Time) bool {
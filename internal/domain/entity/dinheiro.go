@@ -0,0 +1,441 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Moeda string
+
+const MoedaBRL Moeda = "BRL"
+
+var (
+	ErrValorMonetarioInvalido = errors.New("valor monetario invalido")
+	ErrMoedasDiferentes       = errors.New("operacao entre moedas diferentes")
+	ErrMoedaNaoSuportada      = errors.New("moeda nao suportada")
+	ErrDivisaoInvalida        = errors.New("divisao deve ter ao menos uma parte com peso positivo")
+	ErrEstouroMonetario       = errors.New("valor monetario fora do limite")
+)
+
+// Dinheiro é um valor monetário exato: inteiro em centavos (unidade mínima) mais o código da moeda.
+// Nunca passa por float: somas, divisões e rateios não perdem nem criam centavos.
+// O valor zero (Dinheiro{}) não tem moeda e pode ser somado a qualquer uma.
+type Dinheiro struct {
+	centavos int64
+	moeda    Moeda
+}
+
+func NewDinheiro(centavos int64, moeda Moeda) Dinheiro {
+	return Dinheiro{centavos: centavos, moeda: moeda}
+}
+
+// BRL cria um valor em reais a partir dos centavos: BRL(123456) = R$ 1.234,56
+func BRL(centavos int64) Dinheiro {
+	return NewDinheiro(centavos, MoedaBRL)
+}
+
+func (d Dinheiro) Centavos() int64 {
+	return d.centavos
+}
+
+func (d Dinheiro) Moeda() Moeda {
+	return d.moeda
+}
+
+func (d Dinheiro) IsZero() bool {
+	return d.centavos == 0
+}
+
+func (d Dinheiro) IsPositivo() bool {
+	return d.centavos > 0
+}
+
+func (d Dinheiro) IsNegativo() bool {
+	return d.centavos < 0
+}
+
+// moedaComum retorna a moeda do resultado de uma operação entre d e o
+func (d Dinheiro) moedaComum(o Dinheiro) (Moeda, error) {
+	switch {
+	case d.moeda == o.moeda || o.moeda == "":
+		return d.moeda, nil
+	case d.moeda == "":
+		return o.moeda, nil
+	}
+	return "", fmt.Errorf("%w: %s e %s", ErrMoedasDiferentes, d.moeda, o.moeda)
+}
+
+func (d Dinheiro) Somar(o Dinheiro) (Dinheiro, error) {
+	moeda, err := d.moedaComum(o)
+	if err != nil {
+		return Dinheiro{}, err
+	}
+	soma := d.centavos + o.centavos
+	// Estouro quando as parcelas têm o mesmo sinal e o resultado troca de sinal
+	if (d.centavos > 0 && o.centavos > 0 && soma < 0) || (d.centavos < 0 && o.centavos < 0 && soma >= 0) {
+		return Dinheiro{}, ErrEstouroMonetario
+	}
+	return NewDinheiro(soma, moeda), nil
+}
+
+func (d Dinheiro) Subtrair(o Dinheiro) (Dinheiro, error) {
+	if o.centavos == math.MinInt64 {
+		return Dinheiro{}, ErrEstouroMonetario
+	}
+	return d.Somar(o.Negativo())
+}
+
+func (d Dinheiro) Negativo() Dinheiro {
+	return NewDinheiro(-d.centavos, d.moeda)
+}
+
+// Multiplicar multiplica por uma quantidade inteira (ex.: preço unitário x quantidade)
+func (d Dinheiro) Multiplicar(n int64) (Dinheiro, error) {
+	r := new(big.Int).Mul(big.NewInt(d.centavos), big.NewInt(n))
+	if !r.IsInt64() {
+		return Dinheiro{}, ErrEstouroMonetario
+	}
+	return NewDinheiro(r.Int64(), d.moeda), nil
+}
+
+// Comparar retorna -1, 0 ou 1 conforme d seja menor, igual ou maior que o
+func (d Dinheiro) Comparar(o Dinheiro) (int, error) {
+	if _, err := d.moedaComum(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case d.centavos < o.centavos:
+		return -1, nil
+	case d.centavos > o.centavos:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Dividir reparte o valor em partes iguais. Os centavos que sobram da divisão
+// vão um para cada uma das primeiras partes, então a soma das partes é sempre o total:
+// R$ 100,00 em 3 = 33,34 + 33,33 + 33,33.
+func (d Dinheiro) Dividir(partes int) ([]Dinheiro, error) {
+	if partes <= 0 {
+		return nil, ErrDivisaoInvalida
+	}
+	pesos := make([]int64, partes)
+	for i := range pesos {
+		pesos[i] = 1
+	}
+	return d.Alocar(pesos...)
+}
+
+// Alocar reparte o valor proporcionalmente aos pesos (ex.: rateio 70/30).
+// Cada parte recebe o piso da sua proporção e os centavos restantes vão para as partes
+// com maior resto (maior fração perdida), desempatando pela ordem. A soma das partes é o total.
+func (d Dinheiro) Alocar(pesos ...int64) ([]Dinheiro, error) {
+	total := new(big.Int)
+	for _, p := range pesos {
+		if p < 0 {
+			return nil, ErrDivisaoInvalida
+		}
+		total.Add(total, big.NewInt(p))
+	}
+	if total.Sign() == 0 {
+		return nil, ErrDivisaoInvalida
+	}
+
+	// Reparte o valor absoluto e devolve o sinal no final, para que o arredondamento
+	// de valores negativos seja o espelho dos positivos
+	valor := new(big.Int).Abs(big.NewInt(d.centavos))
+
+	type parte struct {
+		indice int
+		resto  *big.Int
+	}
+	partes := make([]Dinheiro, len(pesos))
+	restos := make([]parte, len(pesos))
+	distribuido := new(big.Int)
+
+	for i, p := range pesos {
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(valor, big.NewInt(p)), total, new(big.Int))
+		partes[i] = NewDinheiro(q.Int64(), d.moeda)
+		restos[i] = parte{indice: i, resto: r}
+		distribuido.Add(distribuido, q)
+	}
+
+	sort.SliceStable(restos, func(a, b int) bool {
+		return restos[a].resto.Cmp(restos[b].resto) > 0
+	})
+	sobra := new(big.Int).Sub(valor, distribuido).Int64()
+	for i := int64(0); i < sobra; i++ {
+		partes[restos[i].indice].centavos++
+	}
+
+	if d.centavos < 0 {
+		for i := range partes {
+			partes[i].centavos = -partes[i].centavos
+		}
+	}
+	return partes, nil
+}
+
+// Formatar escreve o valor no formato brasileiro, sem símbolo: 1.234,56
+func (d Dinheiro) Formatar() string {
+	inteiro, centavos := d.partes()
+
+	var b strings.Builder
+	if d.centavos < 0 {
+		b.WriteByte('-')
+	}
+	for i, c := range inteiro {
+		if i > 0 && (len(inteiro)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+	b.WriteByte(',')
+	b.WriteString(centavos)
+	return b.String()
+}
+
+// String formata com o símbolo da moeda: R$ 1.234,56 (ou USD 1.234,56 para outras moedas)
+func (d Dinheiro) String() string {
+	simbolo := string(d.moeda)
+	if d.moeda == MoedaBRL || d.moeda == "" {
+		simbolo = "R$"
+	}
+	return simbolo + " " + d.Formatar()
+}
+
+// Decimal escreve o valor com ponto decimal e sem separador de milhar (1234.56),
+// o formato das colunas DECIMAL e do JSON
+func (d Dinheiro) Decimal() string {
+	inteiro, centavos := d.partes()
+	if d.centavos < 0 {
+		return "-" + inteiro + "." + centavos
+	}
+	return inteiro + "." + centavos
+}
+
+// partes retorna os dígitos da parte inteira e os dois dígitos dos centavos, sem sinal
+func (d Dinheiro) partes() (string, string) {
+	abs := new(big.Int).Abs(big.NewInt(d.centavos)).String()
+	for len(abs) < 3 {
+		abs = "0" + abs
+	}
+	return abs[:len(abs)-2], abs[len(abs)-2:]
+}
+
+// ParseBRL lê um valor no formato brasileiro: "1.234,56", "1234,56", "R$ 1.234,56", "-10,5" ou "100".
+// O separador de milhar é opcional, mas quando usado precisa agrupar de 3 em 3.
+// Mais de duas casas decimais é erro: o valor nunca é arredondado.
+func ParseBRL(s string) (Dinheiro, error) {
+	texto := strings.TrimSpace(s)
+	texto = strings.TrimSpace(strings.TrimPrefix(texto, "R$"))
+
+	negativo := strings.HasPrefix(texto, "-")
+	texto = strings.TrimPrefix(texto, "-")
+
+	inteiro, fracao, temFracao := strings.Cut(texto, ",")
+	if strings.Contains(inteiro, ".") {
+		grupos := strings.Split(inteiro, ".")
+		if len(grupos[0]) == 0 || len(grupos[0]) > 3 {
+			return Dinheiro{}, fmt.Errorf("%w: %q", ErrValorMonetarioInvalido, s)
+		}
+		for _, g := range grupos[1:] {
+			if len(g) != 3 {
+				return Dinheiro{}, fmt.Errorf("%w: %q", ErrValorMonetarioInvalido, s)
+			}
+		}
+		inteiro = strings.Join(grupos, "")
+	}
+	if temFracao && fracao == "" {
+		return Dinheiro{}, fmt.Errorf("%w: %q", ErrValorMonetarioInvalido, s)
+	}
+
+	centavos, err := centavosDe(inteiro, fracao, negativo)
+	if err != nil {
+		return Dinheiro{}, fmt.Errorf("%w: %q", err, s)
+	}
+	return BRL(centavos), nil
+}
+
+// ParseDecimal lê um valor com ponto decimal e sem separador de milhar ("1234.56"),
+// como vem das colunas DECIMAL e dos números JSON
+func ParseDecimal(s string, moeda Moeda) (Dinheiro, error) {
+	texto := strings.TrimSpace(s)
+
+	negativo := strings.HasPrefix(texto, "-")
+	texto = strings.TrimPrefix(texto, "-")
+
+	inteiro, fracao, temFracao := strings.Cut(texto, ".")
+	if temFracao && fracao == "" {
+		return Dinheiro{}, fmt.Errorf("%w: %q", ErrValorMonetarioInvalido, s)
+	}
+
+	centavos, err := centavosDe(inteiro, fracao, negativo)
+	if err != nil {
+		return Dinheiro{}, fmt.Errorf("%w: %q", err, s)
+	}
+	return NewDinheiro(centavos, moeda), nil
+}
+
+// centavosDe junta a parte inteira e até duas casas decimais, só dígitos, em centavos
+func centavosDe(inteiro, fracao string, negativo bool) (int64, error) {
+	if inteiro == "" || len(fracao) > 2 || !soDigitos(inteiro) || !soDigitos(fracao) {
+		return 0, ErrValorMonetarioInvalido
+	}
+	for len(fracao) < 2 {
+		fracao += "0"
+	}
+
+	centavos, err := strconv.ParseInt(inteiro+fracao, 10, 64)
+	if err != nil {
+		return 0, ErrEstouroMonetario
+	}
+	if negativo {
+		centavos = -centavos
+	}
+	return centavos, nil
+}
+
+func soDigitos(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalJSON escreve o valor como número com duas casas (150.50). A moeda não vai junto:
+// quem expõe o valor informa a moeda em um campo próprio quando ela importa.
+func (d Dinheiro) MarshalJSON() ([]byte, error) {
+	return []byte(d.Decimal()), nil
+}
+
+// UnmarshalJSON aceita número (150.5) ou texto no formato brasileiro ("1.234,56").
+// O número é lido a partir do texto, sem passar por float64. A moeda lida é sempre BRL.
+func (d *Dinheiro) UnmarshalJSON(data []byte) error {
+	texto := string(data)
+	if texto == "null" {
+		return nil
+	}
+
+	var (
+		v   Dinheiro
+		err error
+	)
+	if strings.HasPrefix(texto, `"`) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		v, err = ParseBRL(s)
+	} else {
+		v, err = ParseDecimal(texto, MoedaBRL)
+	}
+	if err != nil {
+		return err
+	}
+
+	*d = v
+	return nil
+}
+
+// Value grava o valor em colunas DECIMAL
+func (d Dinheiro) Value() (driver.Value, error) {
+	return d.Decimal(), nil
+}
+
+// Scan lê colunas DECIMAL. As colunas não guardam a moeda: o valor lido é sempre BRL,
+// a única moeda em que o sistema fatura (ver NewFatura).
+func (d *Dinheiro) Scan(src interface{}) error {
+	var texto string
+	switch v := src.(type) {
+	case []byte:
+		texto = string(v)
+	case string:
+		texto = v
+	case int64:
+		texto = strconv.FormatInt(v, 10)
+	default:
+		return fmt.Errorf("%w: tipo %T", ErrValorMonetarioInvalido, src)
+	}
+
+	v, err := ParseDecimal(texto, MoedaBRL)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Percentual é uma taxa exata em centésimos de ponto percentual: Percentual(525) = 5,25%.
+// No JSON e nas colunas DECIMAL aparece como o percentual com duas casas (5.25).
+type Percentual int64
+
+// Porcentagem calcula p sobre o valor, arredondando para o centavo mais próximo
+// (meio centavo arredonda para longe do zero)
+func (d Dinheiro) Porcentagem(p Percentual) (Dinheiro, error) {
+	return d.MultiplicarFracao(int64(p), 10000)
+}
+
+// MultiplicarFracao calcula valor * numerador / denominador em uma única divisão,
+// com o mesmo arredondamento de Porcentagem. Serve para taxas proporcionais,
+// como juros ao mês aplicados por dia (taxa * dias / 30).
+func (d Dinheiro) MultiplicarFracao(numerador, denominador int64) (Dinheiro, error) {
+	if denominador <= 0 {
+		return Dinheiro{}, ErrDivisaoInvalida
+	}
+
+	produto := new(big.Int).Mul(big.NewInt(d.centavos), big.NewInt(numerador))
+	q, r := new(big.Int).QuoRem(produto, big.NewInt(denominador), new(big.Int))
+
+	// Resto de meio denominador ou mais arredonda para longe do zero
+	dobro := new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2))
+	if dobro.Cmp(big.NewInt(denominador)) >= 0 {
+		q.Add(q, big.NewInt(int64(produto.Sign())))
+	}
+	if !q.IsInt64() {
+		return Dinheiro{}, ErrEstouroMonetario
+	}
+	return NewDinheiro(q.Int64(), d.moeda), nil
+}
+
+func (p Percentual) String() string {
+	return NewDinheiro(int64(p), "").Formatar() + "%"
+}
+
+func (p Percentual) MarshalJSON() ([]byte, error) {
+	return []byte(NewDinheiro(int64(p), "").Decimal()), nil
+}
+
+func (p *Percentual) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	v, err := ParseDecimal(string(data), "")
+	if err != nil {
+		return err
+	}
+	*p = Percentual(v.Centavos())
+	return nil
+}
+
+func (p Percentual) Value() (driver.Value, error) {
+	return NewDinheiro(int64(p), "").Decimal(), nil
+}
+
+func (p *Percentual) Scan(src interface{}) error {
+	var d Dinheiro
+	if err := d.Scan(src); err != nil {
+		return err
+	}
+	*p = Percentual(d.Centavos())
+	return nil
+}
@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDinheiro_ParseBRL(t *testing.T) {
+	t.Run("should parse brazilian formatted values exactly", func(t *testing.T) {
+		casos := map[string]int64{
+			"1.234,56":      123456,
+			"1234,56":       123456,
+			"R$ 1.234,56":   123456,
+			"0,10":          10,
+			"10,5":          1050,
+			"100":           10000,
+			"-10,05":        -1005,
+			"1.000.000,01":  100000001,
+			" R$ 2.500,00 ": 250000,
+		}
+		for texto, centavos := range casos {
+			d, err := ParseBRL(texto)
+			assert.NoError(t, err, texto)
+			assert.Equal(t, BRL(centavos), d, texto)
+		}
+	})
+
+	t.Run("should reject malformed values", func(t *testing.T) {
+		for _, texto := range []string{"", "abc", "1,234", "12.34,56", "1.2345,00", "10,", ",50", "1.234.56", "R$"} {
+			_, err := ParseBRL(texto)
+			assert.ErrorIs(t, err, ErrValorMonetarioInvalido, texto)
+		}
+	})
+
+	t.Run("should round trip through Formatar", func(t *testing.T) {
+		for _, centavos := range []int64{0, 1, 99, 100, 123456, 100000001, -5, -123456} {
+			d, err := ParseBRL(BRL(centavos).Formatar())
+			assert.NoError(t, err)
+			assert.Equal(t, centavos, d.Centavos())
+		}
+	})
+}
+
+func TestDinheiro_Formatar(t *testing.T) {
+	assert.Equal(t, "1.234,56", BRL(123456).Formatar())
+	assert.Equal(t, "0,05", BRL(5).Formatar())
+	assert.Equal(t, "-1.000,00", BRL(-100000).Formatar())
+	assert.Equal(t, "R$ 150,50", BRL(15050).String())
+	assert.Equal(t, "USD 10,00", NewDinheiro(1000, "USD").String())
+	assert.Equal(t, "1234.56", BRL(123456).Decimal())
+}
+
+func TestDinheiro_Aritmetica(t *testing.T) {
+	t.Run("should not drift when summing cents", func(t *testing.T) {
+		total := Dinheiro{}
+		for i := 0; i < 10; i++ {
+			total, _ = total.Somar(BRL(10))
+		}
+		assert.Equal(t, BRL(100), total)
+	})
+
+	t.Run("should refuse to mix currencies", func(t *testing.T) {
+		_, err := BRL(100).Somar(NewDinheiro(100, "USD"))
+		assert.ErrorIs(t, err, ErrMoedasDiferentes)
+	})
+
+	t.Run("should split without losing cents", func(t *testing.T) {
+		partes, err := BRL(10000).Dividir(3)
+		assert.NoError(t, err)
+		assert.Equal(t, []Dinheiro{BRL(3334), BRL(3333), BRL(3333)}, partes)
+
+		partes, _ = BRL(-10000).Dividir(3)
+		assert.Equal(t, []Dinheiro{BRL(-3334), BRL(-3333), BRL(-3333)}, partes)
+
+		_, err = BRL(100).Dividir(0)
+		assert.ErrorIs(t, err, ErrDivisaoInvalida)
+	})
+
+	t.Run("should allocate leftover cents to the largest remainders", func(t *testing.T) {
+		// 0,05 em 30/70: 1,5 e 3,5 centavos; o empate fica com a primeira parte
+		partes, err := BRL(5).Alocar(30, 70)
+		assert.NoError(t, err)
+		assert.Equal(t, []Dinheiro{BRL(2), BRL(3)}, partes)
+
+		// 1,00 em 1/1/4: 16,67 + 16,67 + 66,67 -> sobra 1 centavo para a maior fração
+		partes, _ = BRL(100).Alocar(1, 1, 4)
+		assert.Equal(t, []Dinheiro{BRL(17), BRL(17), BRL(66)}, partes)
+
+		soma := Dinheiro{}
+		for _, p := range partes {
+			soma, _ = soma.Somar(p)
+		}
+		assert.Equal(t, BRL(100), soma)
+	})
+}
+
+func TestDinheiro_JSON(t *testing.T) {
+	t.Run("should encode as a number with two decimals", func(t *testing.T) {
+		data, err := json.Marshal(map[string]Dinheiro{"valor": BRL(15050)})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"valor":150.50}`, string(data))
+	})
+
+	t.Run("should decode numbers and brazilian strings without float rounding", func(t *testing.T) {
+		var v struct {
+			A Dinheiro `json:"a"`
+			B Dinheiro `json:"b"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(`{"a":0.29,"b":"1.234,56"}`), &v))
+		assert.Equal(t, BRL(29), v.A)
+		assert.Equal(t, BRL(123456), v.B)
+	})
+
+	t.Run("should reject fractions of a cent", func(t *testing.T) {
+		var d Dinheiro
+		assert.ErrorIs(t, json.Unmarshal([]byte(`10.005`), &d), ErrValorMonetarioInvalido)
+	})
+}
+
+func TestDinheiro_Porcentagem(t *testing.T) {
+	v, err := BRL(12345).Porcentagem(250) // 2,5% de 123,45 = 3,08625
+	assert.NoError(t, err)
+	assert.Equal(t, BRL(309), v)
+
+	v, _ = BRL(-12345).Porcentagem(250)
+	assert.Equal(t, BRL(-309), v)
+
+	var p Percentual
+	assert.NoError(t, json.Unmarshal([]byte(`5.25`), &p))
+	assert.Equal(t, Percentual(525), p)
+	assert.Equal(t, "5,25%", p.String())
+}
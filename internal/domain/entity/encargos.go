@@ -0,0 +1,162 @@
+package entity
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var (
+	ErrMultaInvalida    = errors.New("multa deve estar entre 0 e 100%")
+	ErrJurosInvalidos   = errors.New("juros ao mes devem estar entre 0 e 100%")
+	ErrCarenciaInvalida = errors.New("dias de carencia devem estar entre 0 e 90")
+)
+
+// EncargosAtraso são as regras de multa e juros de mora de um tenant.
+// O valor zero não cobra encargos.
+type EncargosAtraso struct {
+	Multa        Percentual // Cobrada uma única vez sobre o valor em aberto no vencimento (ex.: 2%)
+	JurosAoMes   Percentual // Juros simples pro rata die: JurosAoMes / 30 do valor em aberto em cada dia de atraso (ex.: 1% a.m.)
+	DiasCarencia int        // Dias após o vencimento em que o pagamento ainda sai sem encargos
+}
+
+func (e EncargosAtraso) Validate() error {
+	if e.Multa < 0 || e.Multa > 10000 {
+		return ErrMultaInvalida
+	}
+	if e.JurosAoMes < 0 || e.JurosAoMes > 10000 {
+		return ErrJurosInvalidos
+	}
+	if e.DiasCarencia < 0 || e.DiasCarencia > 90 {
+		return ErrCarenciaInvalida
+	}
+	return nil
+}
+
+// ValorDevido é o quanto a fatura vale em uma data, com os encargos separados.
+// Saldo é o que falta pagar: Total menos os pagamentos ativos.
+type ValorDevido struct {
+	Principal  Dinheiro
+	Multa      Dinheiro
+	Juros      Dinheiro
+	Total      Dinheiro
+	Pago       Dinheiro
+	Saldo      Dinheiro
+	DiasAtraso int
+}
+
+// ValorDevido calcula o valor atualizado da fatura para pagamento na data em.
+// Os dias de atraso contam por data de calendário a partir do vencimento. Dentro da carência
+// não há encargos; passada a carência, multa e juros valem desde o vencimento.
+// Os encargos incidem só sobre o que estava em aberto: a multa sobre o valor não pago até
+// o vencimento e os juros, dia a dia, sobre o valor ainda não pago naquele dia.
+// Faturas pagas ou canceladas não devem nada.
+func (f *Fatura) ValorDevido(em time.Time, encargos EncargosAtraso) (ValorDevido, error) {
+	zero := NewDinheiro(0, f.Valor.Moeda())
+	if f.Status == StatusPaga || f.Status == StatusCancelada {
+		return ValorDevido{Principal: zero, Multa: zero, Juros: zero, Total: zero, Pago: zero, Saldo: zero}, nil
+	}
+
+	devido := ValorDevido{Principal: f.Valor, Multa: zero, Juros: zero, Total: f.Valor, Pago: f.TotalPago()}
+	devido.DiasAtraso = diasEntre(f.DataVencimento, em)
+	if devido.DiasAtraso < 0 {
+		devido.DiasAtraso = 0
+	}
+	if devido.DiasAtraso > encargos.DiasCarencia {
+		if err := devido.cobrarEncargos(f, encargos); err != nil {
+			return ValorDevido{}, err
+		}
+	}
+
+	saldo, err := devido.Total.Subtrair(devido.Pago)
+	if err != nil {
+		return ValorDevido{}, err
+	}
+	if saldo.IsNegativo() {
+		saldo = zero
+	}
+	devido.Saldo = saldo
+	return devido, nil
+}
+
+// cobrarEncargos calcula multa e juros desde o vencimento e os soma ao total.
+// Os pagamentos abatem primeiro o valor da fatura, na ordem em que foram feitos; o que passa
+// dele paga encargos. Pagamentos feitos até o fim da carência contam como feitos no vencimento.
+func (devido *ValorDevido) cobrarEncargos(f *Fatura, encargos EncargosAtraso) error {
+	var pagamentos []Pagamento
+	for _, p := range f.Pagamentos {
+		if !p.Estornado() {
+			pagamentos = append(pagamentos, p)
+		}
+	}
+	sort.SliceStable(pagamentos, func(i, j int) bool {
+		return pagamentos[i].PagoEm.Before(pagamentos[j].PagoEm)
+	})
+
+	// Juros simples: soma do valor em aberto em cada dia (centavos x dias), multiplicada pela
+	// taxa diária em uma só divisão, para arredondar uma vez
+	var err error
+	emAberto := f.Valor
+	emAbertoPorDia := NewDinheiro(0, f.Valor.Moeda())
+	multaCalculada := false
+	dia := 0
+
+	cobrarAte := func(ate int) error {
+		if !multaCalculada && ate > 0 {
+			if devido.Multa, err = emAberto.Porcentagem(encargos.Multa); err != nil {
+				return err
+			}
+			multaCalculada = true
+		}
+		trecho, err := emAberto.Multiplicar(int64(ate - dia))
+		if err != nil {
+			return err
+		}
+		if emAbertoPorDia, err = emAbertoPorDia.Somar(trecho); err != nil {
+			return err
+		}
+		dia = ate
+		return nil
+	}
+
+	for _, p := range pagamentos {
+		pagoNoDia := diasEntre(f.DataVencimento, p.PagoEm)
+		if pagoNoDia <= encargos.DiasCarencia {
+			pagoNoDia = 0
+		}
+		if pagoNoDia > devido.DiasAtraso {
+			pagoNoDia = devido.DiasAtraso
+		}
+		if err := cobrarAte(pagoNoDia); err != nil {
+			return err
+		}
+
+		if emAberto, err = emAberto.Subtrair(p.Valor); err != nil {
+			return err
+		}
+		if emAberto.IsNegativo() {
+			emAberto = NewDinheiro(0, f.Valor.Moeda())
+		}
+	}
+	if err := cobrarAte(devido.DiasAtraso); err != nil {
+		return err
+	}
+
+	if devido.Juros, err = emAbertoPorDia.MultiplicarFracao(int64(encargos.JurosAoMes), 10000*30); err != nil {
+		return err
+	}
+
+	if devido.Total, err = f.Valor.Somar(devido.Multa); err != nil {
+		return err
+	}
+	devido.Total, err = devido.Total.Somar(devido.Juros)
+	return err
+}
+
+// diasEntre conta os dias de calendário de de até ate, no fuso de ate
+func diasEntre(de, ate time.Time) int {
+	de = de.In(ate.Location())
+	inicio := time.Date(de.Year(), de.Month(), de.Day(), 0, 0, 0, 0, time.UTC)
+	fim := time.Date(ate.Year(), ate.Month(), ate.Day(), 0, 0, 0, 0, time.UTC)
+	return int(fim.Sub(inicio).Hours() / 24)
+}
@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func faturaVencidaHa(t *testing.T, dias int) *Fatura {
+	t.Helper()
+
+	f, err := NewFatura("cust-123", "FAT-2026-000001", BRL(10000), time.Now().AddDate(0, 0, 1), "Atrasada")
+	assert.NoError(t, err)
+	f.DataVencimento = time.Now().AddDate(0, 0, -dias)
+	return f
+}
+
+func TestFatura_ValorDevido(t *testing.T) {
+	encargos := EncargosAtraso{Multa: 200, JurosAoMes: 100, DiasCarencia: 3}
+
+	t.Run("should charge nothing before the due date", func(t *testing.T) {
+		f, _ := NewFatura("cust-123", "FAT-2026-000002", BRL(10000), time.Now().AddDate(0, 0, 5), "Em dia")
+
+		d, err := f.ValorDevido(time.Now(), encargos)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, d.DiasAtraso)
+		assert.Equal(t, BRL(10000), d.Total)
+	})
+
+	t.Run("should charge nothing within the grace period", func(t *testing.T) {
+		d, err := faturaVencidaHa(t, 3).ValorDevido(time.Now(), encargos)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, d.DiasAtraso)
+		assert.True(t, d.Multa.IsZero())
+		assert.True(t, d.Juros.IsZero())
+		assert.Equal(t, BRL(10000), d.Total)
+	})
+
+	t.Run("should charge fine and pro rata interest since the due date", func(t *testing.T) {
+		d, err := faturaVencidaHa(t, 10).ValorDevido(time.Now(), encargos)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, d.DiasAtraso)
+		assert.Equal(t, BRL(10000), d.Principal)
+		assert.Equal(t, BRL(200), d.Multa) // 2% de 100,00
+		assert.Equal(t, BRL(33), d.Juros)  // 1% a.m. por 10 dias = 0,333...
+		assert.Equal(t, BRL(10233), d.Total)
+	})
+
+	t.Run("should owe nothing once paid or cancelled", func(t *testing.T) {
+		f := faturaVencidaHa(t, 10)
+		assert.NoError(t, f.Cancelar())
+
+		d, err := f.ValorDevido(time.Now(), encargos)
+		assert.NoError(t, err)
+		assert.True(t, d.Total.IsZero())
+	})
+}
+
+func TestFatura_ValorDevidoComPagamentosParciais(t *testing.T) {
+	encargos := EncargosAtraso{Multa: 200, JurosAoMes: 100}
+
+	pagar := func(t *testing.T, f *Fatura, centavos int64, pagoEm time.Time, encargos EncargosAtraso) {
+		t.Helper()
+		_, err := f.RegistrarPagamento(BRL(centavos), MetodoPix, pagoEm, "", encargos)
+		assert.NoError(t, err)
+	}
+
+	t.Run("should charge only on what was unpaid at the due date", func(t *testing.T) {
+		f := faturaVencidaHa(t, 10)
+		pagar(t, f, 6000, f.DataVencimento.AddDate(0, 0, -1), encargos)
+
+		d, err := f.ValorDevido(time.Now(), encargos)
+		assert.NoError(t, err)
+		assert.Equal(t, BRL(80), d.Multa) // 2% de 40,00
+		assert.Equal(t, BRL(13), d.Juros) // 1% a.m. de 40,00 por 10 dias = 0,133...
+		assert.Equal(t, BRL(10093), d.Total)
+		assert.Equal(t, BRL(4093), d.Saldo)
+	})
+
+	t.Run("should stop the interest on the part paid late", func(t *testing.T) {
+		f := faturaVencidaHa(t, 10)
+		pagar(t, f, 5000, f.DataVencimento.AddDate(0, 0, 4), encargos)
+
+		d, err := f.ValorDevido(time.Now(), encargos)
+		assert.NoError(t, err)
+		assert.Equal(t, BRL(200), d.Multa) // Os 100,00 estavam em aberto no vencimento
+		assert.Equal(t, BRL(23), d.Juros)  // 100,00 por 4 dias + 50,00 por 6 dias = 0,233...
+		assert.Equal(t, BRL(5223), d.Saldo)
+	})
+
+	t.Run("should treat payments within the grace period as paid on the due date", func(t *testing.T) {
+		comCarencia := EncargosAtraso{Multa: 200, JurosAoMes: 100, DiasCarencia: 3}
+		f := faturaVencidaHa(t, 10)
+		pagar(t, f, 6000, f.DataVencimento.AddDate(0, 0, 2), comCarencia)
+
+		d, err := f.ValorDevido(time.Now(), comCarencia)
+		assert.NoError(t, err)
+		assert.Equal(t, BRL(80), d.Multa)
+		assert.Equal(t, BRL(13), d.Juros)
+	})
+}
+
+func TestFatura_MarcarComoPagaComEncargos(t *testing.T) {
+	f := faturaVencidaHa(t, 10)
+	assert.NoError(t, f.MarcarComoVencida(time.Now()))
+
+	assert.NoError(t, f.MarcarComoPaga(EncargosAtraso{Multa: 200, JurosAoMes: 100}))
+
+	eventos := f.EventosPendentes()
+	paga := eventos[len(eventos)-1]
+	assert.Equal(t, EventFaturaPaga, paga.EventType)
+
+	var d FaturaPagaData
+	assert.NoError(t, json.Unmarshal(paga.EventData, &d))
+	assert.Equal(t, BRL(10233), d.ValorPago)
+	assert.Equal(t, BRL(200), d.Multa)
+	assert.Equal(t, BRL(33), d.Juros)
+}
+
+func TestEncargosAtraso_Validate(t *testing.T) {
+	assert.NoError(t, EncargosAtraso{}.Validate())
+	assert.Equal(t, ErrMultaInvalida, EncargosAtraso{Multa: -1}.Validate())
+	assert.Equal(t, ErrJurosInvalidos, EncargosAtraso{JurosAoMes: 10001}.Validate())
+	assert.Equal(t, ErrCarenciaInvalida, EncargosAtraso{DiasCarencia: 91}.Validate())
+}
@@ -2,7 +2,42 @@ package entity
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de agregado
+const (
+	AggregateFatura     = "Fatura"
+	AggregateMensagem   = "Mensagem"
+	AggregateAssinatura = "Assinatura"
+)
+
+// Tipos de evento
+const (
+	EventFaturaCriada              = "FaturaCriada"
+	EventFaturaPagamentoRegistrado = "FaturaPagamentoRegistrado"
+	EventFaturaPagamentoEstornado  = "FaturaPagamentoEstornado"
+	EventFaturaPaga                = "FaturaPaga"
+	EventFaturaCancelada           = "FaturaCancelada"
+	EventFaturaVencida             = "FaturaVencida"
+	EventFaturaLembreteEnviado     = "FaturaLembreteEnviado"
+
+	EventMensagemEnfileirada   = "MensagemEnfileirada"
+	EventMensagemEnviada       = "MensagemEnviada"
+	EventMensagemFalhou        = "MensagemFalhou" // Falha temporária, haverá nova tentativa
+	EventMensagemMovidaParaDLQ = "MensagemMovidaParaDLQ"
+	EventMensagemEntregue      = "MensagemEntregue" // Informado pelo provedor (webhook)
+	EventMensagemLida          = "MensagemLida"     // Informado pelo provedor (webhook)
+
+	EventAssinaturaCriada       = "AssinaturaCriada"
+	EventAssinaturaFaturaGerada = "AssinaturaFaturaGerada"
+	EventAssinaturaPausada      = "AssinaturaPausada"
+	EventAssinaturaRetomada     = "AssinaturaRetomada"
+	EventAssinaturaCancelada    = "AssinaturaCancelada"
+	EventAssinaturaEncerrada    = "AssinaturaEncerrada"
 )
 
 type Event struct {
@@ -13,12 +48,15 @@ type Event struct {
 	EventData     json.RawMessage `json:"event_data"`
 	Metadata      json.RawMessage `json:"metadata,omitempty"`
 	Timestamp     time.Time       `json:"timestamp"`
-	Version       int             `json:"version"`
+	Version       int             `json:"version"`            // Posição no stream do agregado (atribuída no Append)
+	SchemaVersion int             `json:"schema_version"`     // Versão do formato de EventData (ver RegistroEventos)
+	Position      int64           `json:"position,omitempty"` // Ordem global no event store (atribuída ao gravar)
 }
 
 // NewEvent cria uma nova instância de evento
 func NewEvent(eventType, aggregateID, aggregateType string, data, metadata json.RawMessage, version int) *Event {
 	return &Event{
+		ID:            uuid.New().String(),
 		EventType:     eventType,
 		AggregateID:   aggregateID,
 		AggregateType: aggregateType,
@@ -26,5 +64,100 @@ func NewEvent(eventType, aggregateID, aggregateType string, data, metadata json.
 		Metadata:      metadata,
 		Timestamp:     time.Now(),
 		Version:       version,
+		SchemaVersion: Eventos.VersaoAtual(eventType),
+	}
+}
+
+// MensagemEnfileiradaData é o payload do evento MensagemEnfileirada,
+// consumido pelo worker de envio
+type MensagemEnfileiradaData struct {
+	MensagemID string       `json:"mensagem_id"`
+	FaturaID   string       `json:"fatura_id"`
+	ClienteID  string       `json:"cliente_id"`
+	Tipo       TipoMensagem `json:"tipo"`
+}
+
+// NewMensagemEnfileiradaEvent cria o evento que dispara o envio de uma mensagem
+func NewMensagemEnfileiradaEvent(m *Mensagem) (*Event, error) {
+	data, err := json.Marshal(MensagemEnfileiradaData{
+		MensagemID: m.ID,
+		FaturaID:   m.FaturaID,
+		ClienteID:  m.ClienteID,
+		Tipo:       m.Tipo,
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	return NewEvent(EventMensagemEnfileirada, m.ID, AggregateMensagem, data, nil, 1), nil
+}
+
+// MensagemEnvioData é o payload dos eventos de resultado do envio
+// (MensagemEnviada, MensagemFalhou e MensagemMovidaParaDLQ)
+type MensagemEnvioData struct {
+	MensagemID string       `json:"mensagem_id"`
+	FaturaID   string       `json:"fatura_id"`
+	ClienteID  string       `json:"cliente_id"`
+	Tipo       TipoMensagem `json:"tipo"`
+	Tentativas int          `json:"tentativas"`
+	Motivo     string       `json:"motivo,omitempty"`
+}
+
+// NewMensagemEnvioEvent cria um evento de resultado do envio com o estado atual da mensagem.
+// A versão é atribuída no Append.
+func NewMensagemEnvioEvent(eventType string, m *Mensagem) (*Event, error) {
+	data := MensagemEnvioData{
+		MensagemID: m.ID,
+		FaturaID:   m.FaturaID,
+		ClienteID:  m.ClienteID,
+		Tipo:       m.Tipo,
+		Tentativas: m.TentativasEnvio,
+	}
+	if eventType != EventMensagemEnviada {
+		data.Motivo = m.ErroMensagem
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEvent(eventType, m.ID, AggregateMensagem, raw, nil, 0), nil
+}
+
+// MensagemEntregaData é o payload dos eventos informados pelo provedor depois do envio
+// (MensagemEntregue e MensagemLida). Em é o horário do status no provedor.
+type MensagemEntregaData struct {
+	MensagemID string       `json:"mensagem_id"`
+	FaturaID   string       `json:"fatura_id"`
+	ClienteID  string       `json:"cliente_id"`
+	Tipo       TipoMensagem `json:"tipo"`
+	IDProvedor string       `json:"id_provedor"`
+	Em         time.Time    `json:"em"`
+}
+
+// NewMensagemEntregaEvent cria o evento do status atual da mensagem (entregue ou lida).
+// A versão é atribuída no Append.
+func NewMensagemEntregaEvent(m *Mensagem) (*Event, error) {
+	eventType, em := EventMensagemEntregue, m.EntregueEm
+	if m.Status == StatusMensagemLida {
+		eventType, em = EventMensagemLida, m.LidaEm
+	}
+	if em == nil {
+		return nil, fmt.Errorf("mensagem %s sem status do provedor", m.ID)
+	}
+
+	raw, err := json.Marshal(MensagemEntregaData{
+		MensagemID: m.ID,
+		FaturaID:   m.FaturaID,
+		ClienteID:  m.ClienteID,
+		Tipo:       m.Tipo,
+		IDProvedor: m.IDProvedor,
+		Em:         *em,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEvent(eventType, m.ID, AggregateMensagem, raw, nil, 0), nil
 }
@@ -0,0 +1,180 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Upcaster converte o payload de um evento da versão N do schema para a N+1.
+// Trabalha sobre os campos crus para não depender dos structs de versões antigas.
+type Upcaster func(campos map[string]json.RawMessage) error
+
+// tipoEvento descreve um tipo de evento registrado
+type tipoEvento struct {
+	payload   reflect.Type // Struct do payload na versão atual
+	upcasters []Upcaster   // upcasters[i] converte da versão i+1 para i+2
+}
+
+func (t *tipoEvento) versaoAtual() int {
+	return len(t.upcasters) + 1
+}
+
+// RegistroEventos associa cada tipo de evento ao struct do seu payload e aos upcasters
+// que trazem payloads antigos para a versão atual. O histórico nunca é reescrito:
+// os eventos ficam gravados na versão em que foram emitidos e são atualizados na leitura.
+type RegistroEventos struct {
+	mu    sync.RWMutex
+	tipos map[string]*tipoEvento
+}
+
+func NewRegistroEventos() *RegistroEventos {
+	return &RegistroEventos{tipos: map[string]*tipoEvento{}}
+}
+
+// Registrar associa o tipo de evento ao struct do payload atual. A versão atual do schema
+// é len(upcasters)+1: um tipo sem upcasters está na versão 1, e cada mudança de formato
+// acrescenta um upcaster no fim da lista. Registrar o mesmo tipo duas vezes é erro de programação.
+func (r *RegistroEventos) Registrar(eventType string, payload interface{}, upcasters ...Upcaster) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tipos[eventType]; ok {
+		panic(fmt.Sprintf("evento %s registrado duas vezes", eventType))
+	}
+
+	t := reflect.TypeOf(payload)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	r.tipos[eventType] = &tipoEvento{payload: t, upcasters: upcasters}
+}
+
+// VersaoAtual retorna a versão do schema em que novos eventos do tipo são gravados
+// (1 para tipos não registrados)
+func (r *RegistroEventos) VersaoAtual(eventType string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if t, ok := r.tipos[eventType]; ok {
+		return t.versaoAtual()
+	}
+	return 1
+}
+
+// Atualizar aplica os upcasters pendentes, deixando EventData e SchemaVersion na versão atual.
+// Eventos de tipos não registrados ficam como estão.
+func (r *RegistroEventos) Atualizar(e *Event) error {
+	r.mu.RLock()
+	t, ok := r.tipos[e.EventType]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	versao := e.SchemaVersion
+	if versao <= 0 {
+		versao = 1
+	}
+	if versao > t.versaoAtual() {
+		return fmt.Errorf("evento %s na versao %d, mais nova que a suportada (%d)", e.EventType, versao, t.versaoAtual())
+	}
+	if versao == t.versaoAtual() {
+		e.SchemaVersion = versao
+		return nil
+	}
+
+	campos := map[string]json.RawMessage{}
+	if err := json.Unmarshal(e.EventData, &campos); err != nil {
+		return fmt.Errorf("erro ao ler payload do evento %s: %w", e.EventType, err)
+	}
+
+	for ; versao < t.versaoAtual(); versao++ {
+		if err := t.upcasters[versao-1](campos); err != nil {
+			return fmt.Errorf("erro ao converter evento %s da versao %d: %w", e.EventType, versao, err)
+		}
+	}
+
+	data, err := json.Marshal(campos)
+	if err != nil {
+		return err
+	}
+
+	e.EventData = data
+	e.SchemaVersion = versao
+	return nil
+}
+
+// Decodificar retorna o payload do evento, na versão atual, como ponteiro para o struct registrado.
+// O evento recebido não é alterado.
+func (r *RegistroEventos) Decodificar(e *Event) (interface{}, error) {
+	r.mu.RLock()
+	t, ok := r.tipos[e.EventType]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("evento nao registrado: %s", e.EventType)
+	}
+
+	atual := *e
+	if err := r.Atualizar(&atual); err != nil {
+		return nil, err
+	}
+
+	payload := reflect.New(t.payload).Interface()
+	if err := json.Unmarshal(atual.EventData, payload); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar evento %s: %w", e.EventType, err)
+	}
+	return payload, nil
+}
+
+// RenomearCampos cria um upcaster que troca o nome dos campos (antigo -> novo)
+func RenomearCampos(nomes map[string]string) Upcaster {
+	return func(campos map[string]json.RawMessage) error {
+		for antigo, novo := range nomes {
+			if v, ok := campos[antigo]; ok {
+				campos[novo] = v
+				delete(campos, antigo)
+			}
+		}
+		return nil
+	}
+}
+
+// Eventos é o registro usado pelo event store e pelos agregados.
+// Ao mudar o formato de um payload, altere o struct e acrescente o upcaster aqui.
+var Eventos = registroPadrao()
+
+func registroPadrao() *RegistroEventos {
+	r := NewRegistroEventos()
+
+	r.Registrar(EventFaturaCriada, FaturaCriadaData{})
+	r.Registrar(EventFaturaPaga, FaturaPagaData{},
+		// v2: valor -> valor_pago, data_pagamento -> pago_em
+		RenomearCampos(map[string]string{"valor": "valor_pago", "data_pagamento": "pago_em"}),
+	)
+	r.Registrar(EventFaturaPagamentoRegistrado, FaturaPagamentoRegistradoData{})
+	r.Registrar(EventFaturaPagamentoEstornado, FaturaPagamentoEstornadoData{})
+	r.Registrar(EventFaturaCancelada, FaturaCanceladaData{})
+	r.Registrar(EventFaturaVencida, FaturaVencidaData{})
+	r.Registrar(EventFaturaLembreteEnviado, FaturaLembreteEnviadoData{})
+
+	r.Registrar(EventMensagemEnfileirada, MensagemEnfileiradaData{})
+	r.Registrar(EventMensagemEnviada, MensagemEnvioData{})
+	r.Registrar(EventMensagemFalhou, MensagemEnvioData{})
+	r.Registrar(EventMensagemMovidaParaDLQ, MensagemEnvioData{})
+	r.Registrar(EventMensagemEntregue, MensagemEntregaData{})
+	r.Registrar(EventMensagemLida, MensagemEntregaData{})
+
+	r.Registrar(EventAssinaturaCriada, AssinaturaCriadaData{})
+	r.Registrar(EventAssinaturaFaturaGerada, AssinaturaFaturaGeradaData{})
+	r.Registrar(EventAssinaturaPausada, AssinaturaStatusData{})
+	r.Registrar(EventAssinaturaRetomada, AssinaturaStatusData{})
+	r.Registrar(EventAssinaturaCancelada, AssinaturaStatusData{})
+	r.Registrar(EventAssinaturaEncerrada, AssinaturaStatusData{})
+
+	return r
+}
@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// faturaPagaV1 é um FaturaPaga gravado antes da renomeação dos campos
+func faturaPagaV1(faturaID string, pagoEm time.Time) *Event {
+	data := []byte(`{"fatura_id":"` + faturaID + `","cliente_id":"cli-1","valor":150.5,"data_pagamento":"` + pagoEm.Format(time.RFC3339) + `"}`)
+	e := NewEvent(EventFaturaPaga, faturaID, AggregateFatura, data, nil, 2)
+	e.SchemaVersion = 1
+	return e
+}
+
+func TestRegistroEventos_Atualizar(t *testing.T) {
+	t.Run("should upcast an old payload to the current version", func(t *testing.T) {
+		pagoEm := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+		e := faturaPagaV1("fat-1", pagoEm)
+
+		assert.NoError(t, Eventos.Atualizar(e))
+		assert.Equal(t, 2, e.SchemaVersion)
+
+		var d FaturaPagaData
+		assert.NoError(t, json.Unmarshal(e.EventData, &d))
+		assert.Equal(t, BRL(15050), d.ValorPago)
+		assert.True(t, pagoEm.Equal(d.PagoEm))
+		assert.NotContains(t, string(e.EventData), `"valor"`)
+	})
+
+	t.Run("should keep events already in the current version", func(t *testing.T) {
+		f, _ := NewFatura("cli-1", "FAT-2026-000001", BRL(10000), time.Now().AddDate(0, 0, 5), "Servico")
+		f.MarcarComoPaga(EncargosAtraso{})
+		e := f.EventosPendentes()[2] // FaturaPaga, depois do pagamento registrado
+		assert.Equal(t, 2, e.SchemaVersion)
+
+		antes := string(e.EventData)
+		assert.NoError(t, Eventos.Atualizar(e))
+		assert.Equal(t, antes, string(e.EventData))
+	})
+
+	t.Run("should reject a version newer than supported", func(t *testing.T) {
+		e := faturaPagaV1("fat-1", time.Now())
+		e.SchemaVersion = 3
+		assert.Error(t, Eventos.Atualizar(e))
+	})
+
+	t.Run("should ignore unregistered event types", func(t *testing.T) {
+		e := NewEvent("PedidoCriado", "ped-1", "Pedido", []byte(`{"valor":1}`), nil, 1)
+		assert.Equal(t, 1, e.SchemaVersion)
+		assert.NoError(t, Eventos.Atualizar(e))
+		assert.JSONEq(t, `{"valor":1}`, string(e.EventData))
+	})
+}
+
+func TestRegistroEventos_Decodificar(t *testing.T) {
+	e := faturaPagaV1("fat-1", time.Now())
+
+	payload, err := Eventos.Decodificar(e)
+	assert.NoError(t, err)
+
+	d, ok := payload.(*FaturaPagaData)
+	assert.True(t, ok)
+	assert.Equal(t, BRL(15050), d.ValorPago)
+
+	// O evento original não é alterado
+	assert.Equal(t, 1, e.SchemaVersion)
+
+	_, err = Eventos.Decodificar(NewEvent("PedidoCriado", "ped-1", "Pedido", []byte(`{}`), nil, 1))
+	assert.Error(t, err)
+}
+
+func TestRegistroEventos_Registrar(t *testing.T) {
+	r := NewRegistroEventos()
+	r.Registrar("Teste", struct{}{},
+		RenomearCampos(map[string]string{"a": "b"}),
+		RenomearCampos(map[string]string{"b": "c"}),
+	)
+	assert.Equal(t, 3, r.VersaoAtual("Teste"))
+	assert.Equal(t, 1, r.VersaoAtual("Outro"))
+
+	// Upcasters são aplicados em sequência
+	e := &Event{EventType: "Teste", EventData: []byte(`{"a":1}`), SchemaVersion: 1}
+	assert.NoError(t, r.Atualizar(e))
+	assert.JSONEq(t, `{"c":1}`, string(e.EventData))
+
+	assert.Panics(t, func() { r.Registrar("Teste", struct{}{}) })
+}
+
+func TestReconstruirFatura_SchemaAntigo(t *testing.T) {
+	f, _ := NewFatura("cli-1", "FAT-2026-000002", BRL(15050), time.Now().AddDate(0, 0, 5), "Servico")
+	criada := f.EventosPendentes()[0]
+	criada.Version = 1
+
+	pagoEm := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	reconstruida, err := ReconstruirFatura([]*Event{criada, faturaPagaV1(f.ID, pagoEm)})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPaga, reconstruida.Status)
+	assert.True(t, pagoEm.Equal(*reconstruida.DataPagamento))
+	assert.Equal(t, 2, reconstruida.Versao)
+}
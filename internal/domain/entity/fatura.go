@@ -2,22 +2,22 @@ package entity
 
 import (
 	"errors"
-	"fmt"
-	"math/rand"
 	"time"
 )
 
 type StatusFatura string
 
 const (
-	StatusPendente  StatusFatura = "pendente"
-	StatusPaga      StatusFatura = "paga"
-	StatusVencida   StatusFatura = "vencida"
-	StatusCancelada StatusFatura = "cancelada"
+	StatusPendente         StatusFatura = "pendente"
+	StatusParcialmentePaga StatusFatura = "parcialmente_paga" // Tem pagamentos, mas ainda não cobrem o valor devido, e não venceu
+	StatusPaga             StatusFatura = "paga"
+	StatusVencida          StatusFatura = "vencida" // Venceu sem ser liquidada, com ou sem pagamentos parciais
+	StatusCancelada        StatusFatura = "cancelada"
 )
 
 var (
 	ErrValorInvalido        = errors.New("valor deve ser maior que zero")
+	ErrNumeroObrigatorio    = errors.New("numero da fatura e obrigatorio")
 	ErrVencimentoPassado    = errors.New("data de vencimento deve ser futura")
 	ErrFaturaJaPaga         = errors.New("fatura ja esta paga")
 	ErrFaturaJaCancelada    = errors.New("fatura ja esta cancelada")
@@ -28,25 +28,46 @@ var (
 type Fatura struct {
 	BaseEntity
 	ClienteID       string
+	UsuarioID       string // Tenant do cliente na criação, o mesmo da numeração; não vai para os eventos
 	Numero          string
 	Descricao       string
-	Valor           float64
+	Valor           Dinheiro     // Total a pagar; com itens, tem que bater com Totais().Total
+	Itens           []ItemFatura // Opcional: faturas antigas têm só o valor
+	Pagamentos      []Pagamento  // Inclusive os estornados; ver TotalPago
 	DataVencimento  time.Time
-	DataPagamento   *time.Time
+	DataPagamento   *time.Time // Quando a fatura foi liquidada
 	Status          StatusFatura
 	LembreteEnviado bool
+
+	// Versao é a última versão do stream de eventos já persistida (0 = fatura nova)
+	Versao  int
+	eventos []*Event // Eventos gerados e ainda não gravados no event store
 }
 
-func NewFatura(clienteID string, valor float64, dataVencimento time.Time, descricao string) (*Fatura, error) {
-	f := &Fatura{
-		BaseEntity:      NewBase(),
-		ClienteID:       clienteID,
-		Numero:          GerarNumeroFatura(),
-		Valor:           valor,
-		DataVencimento:  dataVencimento,
-		Descricao:       descricao,
-		Status:          StatusPendente,
-		LembreteEnviado: false,
+// NewFatura cria a fatura registrando o evento FaturaCriada.
+// Todas as mudanças de estado passam por eventos (ver fatura_eventos.go),
+// o que permite reconstruir a fatura a partir do stream.
+// O numero vem da numeração sequencial do tenant (usecase/numeracao), reservado
+// na mesma transação em que a fatura é gravada.
+func NewFatura(clienteID, numero string, valor Dinheiro, dataVencimento time.Time, descricao string, itens ...ItemFatura) (*Fatura, error) {
+	// Os eventos e as colunas de valor não guardam a moeda, então só faturamos em reais.
+	// A checagem vem antes do evento porque, depois dele, o valor já foi lido como BRL.
+	if valor.Moeda() != MoedaBRL {
+		return nil, ErrMoedaNaoSuportada
+	}
+
+	f := &Fatura{BaseEntity: NewBase()}
+	err := f.registrar(EventFaturaCriada, FaturaCriadaData{
+		FaturaID:       f.ID,
+		ClienteID:      clienteID,
+		Numero:         numero,
+		Descricao:      descricao,
+		Valor:          valor,
+		DataVencimento: dataVencimento,
+		Itens:          itens,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	if err := f.Validate(); err != nil {
@@ -58,10 +79,18 @@ func NewFatura(clienteID string, valor float64, dataVencimento time.Time, descri
 
 func (f *Fatura) Validate() error {
 	// Valor tem que ser positivo
-	if f.Valor <= 0 {
+	if !f.Valor.IsPositivo() {
 		return ErrValorInvalido
 	}
 
+	if f.Numero == "" {
+		return ErrNumeroObrigatorio
+	}
+
+	if err := f.validarItens(); err != nil {
+		return err
+	}
+
 	// Verifica se é uma NOVA fatura sendo criada agora
 	isNewInvoice := time.Since(f.CreatedAt) < time.Second
 
@@ -73,7 +102,9 @@ func (f *Fatura) Validate() error {
 	return nil
 }
 
-func (f *Fatura) MarcarComoPaga() error {
+// MarcarComoPaga liquida a fatura agora, registrando um pagamento manual do saldo devedor
+// com a multa e os juros das regras do tenant (ver ValorDevido)
+func (f *Fatura) MarcarComoPaga(encargos EncargosAtraso) error {
 	if f.Status == StatusPaga {
 		return ErrFaturaJaPaga
 	}
@@ -81,18 +112,29 @@ func (f *Fatura) MarcarComoPaga() error {
 		return ErrPagarFaturaCancelada
 	}
 
-	now := time.Now()
-	f.Status = StatusPaga
-	f.DataPagamento = &now
-	f.Touch()
-	return nil
+	agora := time.Now()
+	devido, err := f.ValorDevido(agora, encargos)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.RegistrarPagamento(devido.Saldo, MetodoManual, agora, "", encargos)
+	return err
 }
 
-func (f *Fatura) MarcarComoVencida() {
-	if f.Status == StatusPendente && f.DataVencimento.Before(time.Now()) {
-		f.Status = StatusVencida
-		f.Touch()
+// MarcarComoVencida vence a fatura se o vencimento é anterior a agora, o horário de
+// referência de quem chama (o mesmo usado para buscar as faturas vencidas)
+func (f *Fatura) MarcarComoVencida(agora time.Time) error {
+	if (f.Status != StatusPendente && f.Status != StatusParcialmentePaga) || !f.DataVencimento.Before(agora) {
+		return nil
 	}
+	return f.registrar(EventFaturaVencida, FaturaVencidaData{
+		FaturaID:       f.ID,
+		ClienteID:      f.ClienteID,
+		Numero:         f.Numero,
+		Valor:          f.Valor,
+		DataVencimento: f.DataVencimento,
+	})
 }
 
 func (f *Fatura) Cancelar() error {
@@ -102,15 +144,22 @@ func (f *Fatura) Cancelar() error {
 	if f.Status == StatusCancelada {
 		return ErrFaturaJaCancelada
 	}
+	if f.TotalPago().IsPositivo() {
+		return ErrCancelarFaturaComPagamentos
+	}
 
-	f.Status = StatusCancelada
-	f.Touch()
-	return nil
+	return f.registrar(EventFaturaCancelada, FaturaCanceladaData{
+		FaturaID:  f.ID,
+		ClienteID: f.ClienteID,
+		Valor:     f.Valor,
+	})
 }
 
-func (f *Fatura) MarcarLembreteEnviado() {
-	f.LembreteEnviado = true
-	f.Touch()
+func (f *Fatura) MarcarLembreteEnviado() error {
+	if f.LembreteEnviado {
+		return nil
+	}
+	return f.registrar(EventFaturaLembreteEnviado, FaturaLembreteEnviadoData{FaturaID: f.ID})
 }
 
 func (f *Fatura) DiasAteVencimento() int {
@@ -132,10 +181,3 @@ func (f *Fatura) DeveEnviarLembrete(diasAntes int) bool {
 
 	return estaNaJanelaDeEnvio
 }
-
-// GerarNumeroFatura gera um ID legível: FAT-YYYYMMDD-Random
-func GerarNumeroFatura() string {
-	now := time.Now()
-	randNum := rand.Intn(999999)
-	return fmt.Sprintf("FAT-%s-%06d", now.Format("20060102"), randNum)
-}
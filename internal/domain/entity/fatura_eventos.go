@@ -0,0 +1,220 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrStreamVazio    = errors.New("stream da fatura nao possui eventos")
+	ErrStreamInvalido = errors.New("stream da fatura deve comecar com FaturaCriada")
+)
+
+// Payloads dos eventos do agregado Fatura. ClienteID e Valor vão em todos
+// para que projeções não precisem consultar a tabela faturas.
+// Dinheiro é gravado como número JSON (150.50), o mesmo formato dos eventos
+// anteriores a ele, que guardavam float64: o histórico é lido sem upcaster.
+
+type FaturaCriadaData struct {
+	FaturaID       string       `json:"fatura_id"`
+	ClienteID      string       `json:"cliente_id"`
+	Numero         string       `json:"numero"`
+	Descricao      string       `json:"descricao"`
+	Valor          Dinheiro     `json:"valor"`
+	DataVencimento time.Time    `json:"data_vencimento"`
+	Itens          []ItemFatura `json:"itens,omitempty"` // Ausente nos eventos anteriores aos itens
+}
+
+// FaturaPagaData está na versão 2 do schema (ver registroPadrao em event_registro.go).
+// ValorPago inclui Multa e Juros, que são zero nos eventos anteriores aos encargos.
+type FaturaPagaData struct {
+	FaturaID  string    `json:"fatura_id"`
+	ClienteID string    `json:"cliente_id"`
+	ValorPago Dinheiro  `json:"valor_pago"`
+	Multa     Dinheiro  `json:"multa"`
+	Juros     Dinheiro  `json:"juros"`
+	PagoEm    time.Time `json:"pago_em"`
+}
+
+type FaturaPagamentoRegistradoData struct {
+	FaturaID          string          `json:"fatura_id"`
+	ClienteID         string          `json:"cliente_id"`
+	PagamentoID       string          `json:"pagamento_id"`
+	Valor             Dinheiro        `json:"valor"`
+	Metodo            MetodoPagamento `json:"metodo"`
+	PagoEm            time.Time       `json:"pago_em"`
+	ReferenciaExterna string          `json:"referencia_externa,omitempty"`
+}
+
+// FaturaPagamentoEstornadoData leva o status para o qual a fatura volta: ele depende
+// do vencimento no momento do estorno, que não dá para recalcular ao reaplicar o stream.
+type FaturaPagamentoEstornadoData struct {
+	FaturaID    string       `json:"fatura_id"`
+	ClienteID   string       `json:"cliente_id"`
+	PagamentoID string       `json:"pagamento_id"`
+	Valor       Dinheiro     `json:"valor"`
+	Motivo      string       `json:"motivo,omitempty"`
+	EstornadoEm time.Time    `json:"estornado_em"`
+	Status      StatusFatura `json:"status"`
+}
+
+type FaturaCanceladaData struct {
+	FaturaID  string   `json:"fatura_id"`
+	ClienteID string   `json:"cliente_id"`
+	Valor     Dinheiro `json:"valor"`
+}
+
+type FaturaVencidaData struct {
+	FaturaID       string    `json:"fatura_id"`
+	ClienteID      string    `json:"cliente_id"`
+	Numero         string    `json:"numero"`
+	Valor          Dinheiro  `json:"valor"`
+	DataVencimento time.Time `json:"data_vencimento"`
+}
+
+type FaturaLembreteEnviadoData struct {
+	FaturaID string `json:"fatura_id"`
+}
+
+// registrar aplica o evento na fatura e o guarda como pendente de persistência.
+// Um evento que não pôde ser aplicado não é guardado: o stream gravado nunca
+// diverge do estado da fatura.
+func (f *Fatura) registrar(eventType string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar evento %s: %w", eventType, err)
+	}
+
+	event := NewEvent(eventType, f.ID, AggregateFatura, raw, nil, 0)
+	if err := f.aplicar(event); err != nil {
+		return fmt.Errorf("erro ao aplicar evento %s: %w", eventType, err)
+	}
+	f.eventos = append(f.eventos, event)
+	return nil
+}
+
+// aplicar muda o estado da fatura a partir de um evento.
+// É o único lugar que altera o estado, seja num comando novo ou ao reconstruir o stream.
+// O payload passa pelo registro de eventos, então eventos gravados em versões
+// antigas do schema chegam aqui já no formato atual.
+func (f *Fatura) aplicar(e *Event) error {
+	payload, err := Eventos.Decodificar(e)
+	if err != nil {
+		return err
+	}
+
+	switch d := payload.(type) {
+	case *FaturaCriadaData:
+		f.ID = e.AggregateID
+		f.ClienteID = d.ClienteID
+		f.Numero = d.Numero
+		f.Descricao = d.Descricao
+		f.Valor = d.Valor
+		f.Itens = d.Itens
+		f.DataVencimento = d.DataVencimento
+		f.Status = StatusPendente
+		f.CreatedAt = e.Timestamp
+
+	case *FaturaPagamentoRegistradoData:
+		f.Pagamentos = append(f.Pagamentos, Pagamento{
+			ID:                d.PagamentoID,
+			FaturaID:          e.AggregateID,
+			Valor:             d.Valor,
+			Metodo:            d.Metodo,
+			PagoEm:            d.PagoEm,
+			ReferenciaExterna: d.ReferenciaExterna,
+		})
+		// Uma fatura vencida continua vencida: o pagamento parcial não tira o atraso
+		if f.Status == StatusPendente {
+			f.Status = StatusParcialmentePaga
+		}
+
+	case *FaturaPagamentoEstornadoData:
+		// Copia antes de alterar: o slice pode ser compartilhado com outra cópia da fatura
+		pagamentos := append([]Pagamento(nil), f.Pagamentos...)
+		for i := range pagamentos {
+			if pagamentos[i].ID == d.PagamentoID {
+				estornadoEm := d.EstornadoEm
+				pagamentos[i].EstornadoEm = &estornadoEm
+				pagamentos[i].MotivoEstorno = d.Motivo
+			}
+		}
+		f.Pagamentos = pagamentos
+		f.Status = d.Status
+		f.DataPagamento = nil
+
+	case *FaturaPagaData:
+		pagamento := d.PagoEm
+		if pagamento.IsZero() {
+			pagamento = e.Timestamp
+		}
+		f.Status = StatusPaga
+		f.DataPagamento = &pagamento
+
+	case *FaturaCanceladaData:
+		f.Status = StatusCancelada
+
+	case *FaturaVencidaData:
+		f.Status = StatusVencida
+
+	case *FaturaLembreteEnviadoData:
+		f.LembreteEnviado = true
+
+	default:
+		return fmt.Errorf("evento desconhecido para fatura: %s", e.EventType)
+	}
+
+	f.UpdatedAt = e.Timestamp
+	if e.Version > 0 {
+		f.Versao = e.Version
+	}
+	return nil
+}
+
+// EventosPendentes retorna os eventos gerados desde a última persistência
+func (f *Fatura) EventosPendentes() []*Event {
+	return f.eventos
+}
+
+// ConfirmarEventos é chamado pelo repositório depois de gravar os eventos pendentes
+func (f *Fatura) ConfirmarEventos() {
+	if n := len(f.eventos); n > 0 {
+		f.Versao = f.eventos[n-1].Version
+	}
+	f.eventos = nil
+}
+
+// ReconstruirFatura refaz a fatura aplicando o stream completo, em ordem de versão
+func ReconstruirFatura(eventos []*Event) (*Fatura, error) {
+	if len(eventos) == 0 {
+		return nil, ErrStreamVazio
+	}
+	if eventos[0].EventType != EventFaturaCriada {
+		return nil, ErrStreamInvalido
+	}
+
+	f := &Fatura{}
+	for _, e := range eventos {
+		if err := f.aplicar(e); err != nil {
+			return nil, fmt.Errorf("erro ao aplicar evento %s (versao %d): %w", e.EventType, e.Version, err)
+		}
+	}
+
+	return f, nil
+}
+
+// ReconstruirFaturaEm mostra a fatura como ela estava no instante informado,
+// considerando apenas os eventos registrados até ele
+func ReconstruirFaturaEm(eventos []*Event, em time.Time) (*Fatura, error) {
+	var ate []*Event
+	for _, e := range eventos {
+		if e.Timestamp.After(em) {
+			break
+		}
+		ate = append(ate, e)
+	}
+
+	return ReconstruirFatura(ate)
+}
@@ -0,0 +1,135 @@
+package entity
+
+import "errors"
+
+var (
+	ErrDescricaoItemObrigatoria = errors.New("descricao do item e obrigatoria")
+	ErrQuantidadeInvalida       = errors.New("quantidade do item deve ser maior que zero")
+	ErrPrecoUnitarioInvalido    = errors.New("preco unitario do item deve ser maior que zero")
+	ErrDescontoInvalido         = errors.New("desconto do item deve estar entre zero e o valor bruto do item")
+	ErrAliquotaInvalida         = errors.New("aliquota do item deve estar entre 0 e 100%")
+	ErrTotalDivergente          = errors.New("valor da fatura diverge do total dos itens")
+)
+
+// ItemFatura é uma linha da fatura. Os itens são definidos na criação e viajam
+// no evento FaturaCriada, por isso as tags JSON.
+type ItemFatura struct {
+	Descricao       string     `json:"descricao"`
+	Quantidade      int64      `json:"quantidade"`
+	PrecoUnitario   Dinheiro   `json:"preco_unitario"`
+	Desconto        Dinheiro   `json:"desconto"`         // Valor absoluto abatido do item
+	AliquotaImposto Percentual `json:"aliquota_imposto"` // Incide sobre o valor já com desconto
+}
+
+// TotaisFatura decompõe o valor de um item ou da fatura inteira:
+// Total = Subtotal - Descontos + Impostos
+type TotaisFatura struct {
+	Subtotal  Dinheiro
+	Descontos Dinheiro
+	Impostos  Dinheiro
+	Total     Dinheiro
+}
+
+func (i ItemFatura) Validate() error {
+	if i.Descricao == "" {
+		return ErrDescricaoItemObrigatoria
+	}
+	if i.Quantidade <= 0 {
+		return ErrQuantidadeInvalida
+	}
+	if !i.PrecoUnitario.IsPositivo() {
+		return ErrPrecoUnitarioInvalido
+	}
+	if i.AliquotaImposto < 0 || i.AliquotaImposto > 10000 {
+		return ErrAliquotaInvalida
+	}
+	return nil
+}
+
+// Totais calcula o item. O imposto é arredondado por item, como sai na nota,
+// e a fatura soma os valores já arredondados.
+func (i ItemFatura) Totais() (TotaisFatura, error) {
+	bruto, err := i.PrecoUnitario.Multiplicar(i.Quantidade)
+	if err != nil {
+		return TotaisFatura{}, err
+	}
+
+	maior, err := i.Desconto.Comparar(bruto)
+	if err != nil {
+		return TotaisFatura{}, err
+	}
+	if i.Desconto.IsNegativo() || maior > 0 {
+		return TotaisFatura{}, ErrDescontoInvalido
+	}
+
+	base, err := bruto.Subtrair(i.Desconto)
+	if err != nil {
+		return TotaisFatura{}, err
+	}
+	imposto, err := base.Porcentagem(i.AliquotaImposto)
+	if err != nil {
+		return TotaisFatura{}, err
+	}
+	total, err := base.Somar(imposto)
+	if err != nil {
+		return TotaisFatura{}, err
+	}
+
+	return TotaisFatura{Subtotal: bruto, Descontos: i.Desconto, Impostos: imposto, Total: total}, nil
+}
+
+// somar acumula os totais de um item
+func (t TotaisFatura) somar(o TotaisFatura) (TotaisFatura, error) {
+	var err error
+	if t.Subtotal, err = t.Subtotal.Somar(o.Subtotal); err != nil {
+		return TotaisFatura{}, err
+	}
+	if t.Descontos, err = t.Descontos.Somar(o.Descontos); err != nil {
+		return TotaisFatura{}, err
+	}
+	if t.Impostos, err = t.Impostos.Somar(o.Impostos); err != nil {
+		return TotaisFatura{}, err
+	}
+	if t.Total, err = t.Total.Somar(o.Total); err != nil {
+		return TotaisFatura{}, err
+	}
+	return t, nil
+}
+
+// Totais soma os itens da fatura. Uma fatura sem itens (criada só com o valor)
+// tem o valor inteiro como subtotal, sem descontos nem impostos.
+func (f *Fatura) Totais() (TotaisFatura, error) {
+	if len(f.Itens) == 0 {
+		return TotaisFatura{Subtotal: f.Valor, Descontos: BRL(0), Impostos: BRL(0), Total: f.Valor}, nil
+	}
+
+	totais := TotaisFatura{}
+	for _, item := range f.Itens {
+		t, err := item.Totais()
+		if err != nil {
+			return TotaisFatura{}, err
+		}
+		if totais, err = totais.somar(t); err != nil {
+			return TotaisFatura{}, err
+		}
+	}
+	return totais, nil
+}
+
+// validarItens confere cada item e se o valor da fatura bate com o total calculado
+func (f *Fatura) validarItens() error {
+	for _, item := range f.Itens {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+	}
+
+	totais, err := f.Totais()
+	if err != nil {
+		return err
+	}
+	if totais.Total.Centavos() != f.Valor.Centavos() {
+		return ErrTotalDivergente
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemFatura_Totais(t *testing.T) {
+	t.Run("should apply discount before tax and round tax per item", func(t *testing.T) {
+		item := ItemFatura{Descricao: "Hora tecnica", Quantidade: 3, PrecoUnitario: BRL(3333), Desconto: BRL(999), AliquotaImposto: 500}
+
+		totais, err := item.Totais()
+		assert.NoError(t, err)
+		assert.Equal(t, BRL(9999), totais.Subtotal)
+		assert.Equal(t, BRL(999), totais.Descontos)
+		assert.Equal(t, BRL(450), totais.Impostos) // 5% de 90,00
+		assert.Equal(t, BRL(9450), totais.Total)
+	})
+
+	t.Run("should round half a cent away from zero", func(t *testing.T) {
+		item := ItemFatura{Descricao: "Licenca", Quantidade: 1, PrecoUnitario: BRL(10), AliquotaImposto: 500}
+		totais, _ := item.Totais()
+		assert.Equal(t, BRL(1), totais.Impostos) // 0,005 -> 0,01
+	})
+
+	t.Run("should reject a discount above the gross value", func(t *testing.T) {
+		item := ItemFatura{Descricao: "Setup", Quantidade: 1, PrecoUnitario: BRL(1000), Desconto: BRL(1001)}
+		_, err := item.Totais()
+		assert.Equal(t, ErrDescontoInvalido, err)
+	})
+}
+
+func TestFatura_Itens(t *testing.T) {
+	vencimento := time.Now().AddDate(0, 0, 5)
+	itens := []ItemFatura{
+		{Descricao: "Consultoria", Quantidade: 10, PrecoUnitario: BRL(15000), Desconto: BRL(10000), AliquotaImposto: 200},
+		{Descricao: "Hospedagem", Quantidade: 1, PrecoUnitario: BRL(4990)},
+	}
+
+	t.Run("should compute subtotal, discounts, taxes and total", func(t *testing.T) {
+		f, err := NewFatura("cust-123", "FAT-2026-000001", BRL(147790), vencimento, "Marco", itens...)
+		assert.NoError(t, err)
+
+		totais, err := f.Totais()
+		assert.NoError(t, err)
+		assert.Equal(t, BRL(154990), totais.Subtotal)
+		assert.Equal(t, BRL(10000), totais.Descontos)
+		assert.Equal(t, BRL(2800), totais.Impostos)
+		assert.Equal(t, BRL(147790), totais.Total)
+	})
+
+	t.Run("should reject a valor that disagrees with the items", func(t *testing.T) {
+		f, err := NewFatura("cust-123", "FAT-2026-000002", BRL(147789), vencimento, "Marco", itens...)
+		assert.Nil(t, f)
+		assert.Equal(t, ErrTotalDivergente, err)
+	})
+
+	t.Run("should reject invalid items", func(t *testing.T) {
+		_, err := NewFatura("cust-123", "FAT-2026-000003", BRL(100), vencimento, "", ItemFatura{Descricao: "X", Quantidade: 0, PrecoUnitario: BRL(100)})
+		assert.Equal(t, ErrQuantidadeInvalida, err)
+	})
+
+	t.Run("should keep the items when rebuilt from the stream", func(t *testing.T) {
+		f, _ := NewFatura("cust-123", "FAT-2026-000004", BRL(147790), vencimento, "Marco", itens...)
+
+		r, err := ReconstruirFatura(f.EventosPendentes())
+		assert.NoError(t, err)
+		assert.Len(t, r.Itens, 2)
+		assert.Equal(t, f.Itens, r.Itens)
+	})
+
+	t.Run("should treat a fatura without items as a single subtotal", func(t *testing.T) {
+		f, _ := NewFatura("cust-123", "FAT-2026-000005", BRL(5000), vencimento, "Avulsa")
+		totais, _ := f.Totais()
+		assert.Equal(t, BRL(5000), totais.Subtotal)
+		assert.Equal(t, BRL(5000), totais.Total)
+		assert.True(t, totais.Impostos.IsZero())
+	})
+}
@@ -10,7 +10,7 @@ import (
 func TestNewFatura(t *testing.T) {
 	t.Run("should create valid fatura", func(t *testing.T) {
 		vencimento := time.Now().AddDate(0, 0, 5) // 5 dias no futuro
-		f, err := NewFatura("cust-123", 100.50, vencimento, "Consultoria")
+		f, err := NewFatura("cust-123", "FAT-2026-000001", BRL(10050), vencimento, "Consultoria")
 
 		assert.NoError(t, err)
 		assert.NotNil(t, f)
@@ -22,7 +22,7 @@ func TestNewFatura(t *testing.T) {
 
 	t.Run("should validate invalid value", func(t *testing.T) {
 		vencimento := time.Now().AddDate(0, 0, 5)
-		f, err := NewFatura("cust-123", 0, vencimento, "Invalid")
+		f, err := NewFatura("cust-123", "FAT-2026-000002", BRL(0), vencimento, "Invalid")
 		assert.Error(t, err)
 		assert.Nil(t, f)
 		assert.Equal(t, ErrValorInvalido, err)
@@ -30,26 +30,39 @@ func TestNewFatura(t *testing.T) {
 
 	t.Run("should validate past due date", func(t *testing.T) {
 		vencimento := time.Now().AddDate(0, 0, -1)
-		f, err := NewFatura("cust-123", 100, vencimento, "Late")
+		f, err := NewFatura("cust-123", "FAT-2026-000003", BRL(10000), vencimento, "Late")
 		assert.Error(t, err)
 		assert.Nil(t, f)
 		assert.Equal(t, ErrVencimentoPassado, err)
 	})
+
+	t.Run("should require a numero", func(t *testing.T) {
+		f, err := NewFatura("cust-123", "", BRL(10000), time.Now().AddDate(0, 0, 5), "Sem numero")
+		assert.Nil(t, f)
+		assert.Equal(t, ErrNumeroObrigatorio, err)
+	})
+
+	t.Run("should reject currencies other than BRL", func(t *testing.T) {
+		vencimento := time.Now().AddDate(0, 0, 5)
+		f, err := NewFatura("cust-123", "FAT-2026-000008", NewDinheiro(10000, "USD"), vencimento, "Exterior")
+		assert.Nil(t, f)
+		assert.Equal(t, ErrMoedaNaoSuportada, err)
+	})
 }
 
 func TestFatura_StateTransitions(t *testing.T) {
 	vencimento := time.Now().AddDate(0, 0, 5)
-	f, _ := NewFatura("cust-123", 100, vencimento, "Test")
+	f, _ := NewFatura("cust-123", "FAT-2026-000004", BRL(10000), vencimento, "Test")
 
 	t.Run("should mark as paid", func(t *testing.T) {
-		err := f.MarcarComoPaga()
+		err := f.MarcarComoPaga(EncargosAtraso{})
 		assert.NoError(t, err)
 		assert.Equal(t, StatusPaga, f.Status)
 		assert.NotNil(t, f.DataPagamento)
 	})
 
 	t.Run("should fail to pay already paid", func(t *testing.T) {
-		err := f.MarcarComoPaga()
+		err := f.MarcarComoPaga(EncargosAtraso{})
 		assert.Equal(t, ErrFaturaJaPaga, err)
 	})
 
@@ -61,20 +74,36 @@ func TestFatura_StateTransitions(t *testing.T) {
 
 func TestFatura_Cancelamento(t *testing.T) {
 	vencimento := time.Now().AddDate(0, 0, 5)
-	f, _ := NewFatura("cust-123", 100, vencimento, "Test")
+	f, _ := NewFatura("cust-123", "FAT-2026-000005", BRL(10000), vencimento, "Test")
 
 	err := f.Cancelar()
 	assert.NoError(t, err)
 	assert.Equal(t, StatusCancelada, f.Status)
 
-	err = f.MarcarComoPaga()
+	err = f.MarcarComoPaga(EncargosAtraso{})
 	assert.Equal(t, ErrPagarFaturaCancelada, err)
 }
 
+func TestFatura_MarcarComoVencida(t *testing.T) {
+	vencimento := time.Now().AddDate(0, 0, 5)
+
+	t.Run("should keep pending before the reference time passes the due date", func(t *testing.T) {
+		f, _ := NewFatura("cust-123", "FAT-2026-000008", BRL(10000), vencimento, "Test")
+		assert.NoError(t, f.MarcarComoVencida(vencimento.Add(-time.Minute)))
+		assert.Equal(t, StatusPendente, f.Status)
+	})
+
+	t.Run("should use the reference time instead of the clock", func(t *testing.T) {
+		f, _ := NewFatura("cust-123", "FAT-2026-000009", BRL(10000), vencimento, "Test")
+		assert.NoError(t, f.MarcarComoVencida(vencimento.Add(time.Minute)))
+		assert.Equal(t, StatusVencida, f.Status)
+	})
+}
+
 func TestFatura_Lembrete(t *testing.T) {
 	// Fatura vence em 2 dias (dentro da janela de 3 dias)
 	vencimento := time.Now().AddDate(0, 0, 2)
-	f, _ := NewFatura("cust-123", 100, vencimento, "Test")
+	f, _ := NewFatura("cust-123", "FAT-2026-000006", BRL(10000), vencimento, "Test")
 
 	// Configurado para avisar 3 dias antes
 	// Vence em 2 dias. 2 <= 3. Deve enviar.
@@ -82,14 +111,87 @@ func TestFatura_Lembrete(t *testing.T) {
 	shouldSend := f.DeveEnviarLembrete(3)
 	assert.True(t, shouldSend)
 
-	f.MarcarLembreteEnviado()
+	assert.NoError(t, f.MarcarLembreteEnviado())
 	assert.True(t, f.LembreteEnviado)
 	assert.False(t, f.DeveEnviarLembrete(3))
 }
 
-func TestGerarNumeroFatura(t *testing.T) {
-	num := GerarNumeroFatura()
-	assert.Contains(t, num, "FAT-2") // Check year start
-	assert.Len(t, num, 19)           // FAT-YYYYMMDD-XXXXXX (4+8+1+6 = 19 length) formula: FAT + - + 8 chars date + - + 6 digits = 3+1+8+1+6 = 19
-	// FAT-20240315-123456
+func TestFatura_Eventos(t *testing.T) {
+	vencimento := time.Now().AddDate(0, 0, 5)
+	f, _ := NewFatura("cust-123", "FAT-2026-000007", BRL(10000), vencimento, "Test")
+
+	assert.NoError(t, f.MarcarLembreteEnviado())
+	assert.NoError(t, f.MarcarLembreteEnviado()) // Idempotente: não gera segundo evento
+	assert.NoError(t, f.MarcarComoPaga(EncargosAtraso{}))
+	assert.Error(t, f.Cancelar()) // Transição rejeitada não gera evento
+
+	// A baixa manual registra o pagamento do saldo e liquida a fatura
+	eventos := f.EventosPendentes()
+	assert.Len(t, eventos, 4)
+	assert.Equal(t, EventFaturaCriada, eventos[0].EventType)
+	assert.Equal(t, EventFaturaLembreteEnviado, eventos[1].EventType)
+	assert.Equal(t, EventFaturaPagamentoRegistrado, eventos[2].EventType)
+	assert.Equal(t, EventFaturaPaga, eventos[3].EventType)
+	for _, e := range eventos {
+		assert.Equal(t, f.ID, e.AggregateID)
+		assert.Equal(t, AggregateFatura, e.AggregateType)
+	}
+
+	// Simula a gravação no event store, que atribui as versões
+	for i, e := range eventos {
+		e.Version = i + 1
+	}
+	f.ConfirmarEventos()
+	assert.Equal(t, 4, f.Versao)
+	assert.Empty(t, f.EventosPendentes())
+
+	t.Run("should rebuild from stream", func(t *testing.T) {
+		r, err := ReconstruirFatura(eventos)
+		assert.NoError(t, err)
+		assert.Equal(t, f.ID, r.ID)
+		assert.Equal(t, f.Numero, r.Numero)
+		assert.Equal(t, f.Valor, r.Valor)
+		assert.Equal(t, StatusPaga, r.Status)
+		assert.True(t, r.LembreteEnviado)
+		assert.Equal(t, f.DataPagamento.Unix(), r.DataPagamento.Unix())
+		assert.Len(t, r.Pagamentos, 1)
+		assert.Equal(t, 4, r.Versao)
+	})
+
+	t.Run("should rebuild as of a past date", func(t *testing.T) {
+		eventos[2].Timestamp = time.Now().Add(time.Hour) // Pagamento "no futuro"
+		eventos[3].Timestamp = eventos[2].Timestamp
+
+		r, err := ReconstruirFaturaEm(eventos, time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, StatusPendente, r.Status)
+		assert.True(t, r.LembreteEnviado)
+		assert.Equal(t, 2, r.Versao)
+
+		_, err = ReconstruirFaturaEm(eventos, time.Now().AddDate(0, 0, -1))
+		assert.Equal(t, ErrStreamVazio, err)
+	})
+
+	t.Run("should reject stream without FaturaCriada", func(t *testing.T) {
+		_, err := ReconstruirFatura(eventos[1:])
+		assert.Equal(t, ErrStreamInvalido, err)
+	})
+}
+
+func TestFatura_EventoNaoAplicado(t *testing.T) {
+	f, _ := NewFatura("cust-123", "FAT-2026-000008", BRL(10000), time.Now().AddDate(0, 0, 5), "Test")
+	pendentes := len(f.EventosPendentes())
+
+	t.Run("should not keep an event the fatura could not apply", func(t *testing.T) {
+		err := f.registrar("FaturaDesconhecida", FaturaLembreteEnviadoData{FaturaID: f.ID})
+		assert.Error(t, err)
+		assert.Len(t, f.EventosPendentes(), pendentes)
+	})
+
+	t.Run("should report payloads that cannot be serialized", func(t *testing.T) {
+		err := f.registrar(EventFaturaLembreteEnviado, make(chan int))
+		assert.Error(t, err)
+		assert.False(t, f.LembreteEnviado)
+		assert.Len(t, f.EventosPendentes(), pendentes)
+	})
 }
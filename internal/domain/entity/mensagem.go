@@ -12,12 +12,18 @@ const (
 	StatusMensagemPendente StatusMensagem = "pendente"
 	StatusMensagemEnviada  StatusMensagem = "enviada"
 	StatusMensagemFalha    StatusMensagem = "falha"
+	StatusMensagemDLQ      StatusMensagem = "dlq"      // Esgotou as tentativas ou falha definitiva
+	StatusMensagemEntregue StatusMensagem = "entregue" // Chegou ao aparelho do cliente (webhook do provedor)
+	StatusMensagemLida     StatusMensagem = "lida"     // Aberta pelo cliente (webhook do provedor)
 
 	TipoMensagemLembrete    TipoMensagem = "lembrete"
 	TipoMensagemConfirmacao TipoMensagem = "confirmacao"
 	TipoMensagemCobranca    TipoMensagem = "cobranca"
 )
 
+// MaxTentativasEnvio é o número de tentativas antes de a mensagem ir para a DLQ
+const MaxTentativasEnvio = 5
+
 var (
 	ErrWhatsAppVazio = errors.New("whatsapp nao pode ser vazio")
 	ErrConteudoVazio = errors.New("conteudo nao pode ser vazio")
@@ -34,6 +40,12 @@ type Mensagem struct {
 	TentativasEnvio int
 	ErroMensagem    string
 	EnviadoEm       *time.Time
+
+	ProximaTentativaEm *time.Time // Nil = pode ser enviada imediatamente
+
+	IDProvedor string     // ID da mensagem no provedor, usado para correlacionar os webhooks de status
+	EntregueEm *time.Time // Informado pelo provedor
+	LidaEm     *time.Time // Informado pelo provedor
 }
 
 func NewMensagem(faturaID, clienteID, whatsapp, conteudo string, tipo TipoMensagem) (*Mensagem, error) {
@@ -55,6 +67,22 @@ func NewMensagem(faturaID, clienteID, whatsapp, conteudo string, tipo TipoMensag
 	return m, nil
 }
 
+// NewMensagemNaDLQ registra uma mensagem que não tem como ser enviada (ex.: cliente sem
+// WhatsApp, conteúdo que não pôde ser montado) já na DLQ, com o motivo. Ela fica visível
+// para o operador em vez de impedir a operação que a gerou.
+func NewMensagemNaDLQ(faturaID, clienteID, whatsapp, conteudo string, tipo TipoMensagem, motivo string) *Mensagem {
+	return &Mensagem{
+		BaseEntity:   NewBase(),
+		FaturaID:     faturaID,
+		ClienteID:    clienteID,
+		WhatsApp:     whatsapp,
+		Tipo:         tipo,
+		Conteudo:     conteudo,
+		Status:       StatusMensagemDLQ,
+		ErroMensagem: motivo,
+	}
+}
+
 func (m *Mensagem) Validate() error {
 	if m.WhatsApp == "" {
 		return ErrWhatsAppVazio
@@ -65,10 +93,12 @@ func (m *Mensagem) Validate() error {
 	return nil
 }
 
-func (m *Mensagem) MarcarComoEnviada() {
+// MarcarComoEnviada registra o envio com o ID devolvido pelo provedor
+func (m *Mensagem) MarcarComoEnviada(idProvedor string) {
 	now := time.Now()
 	m.Status = StatusMensagemEnviada
 	m.EnviadoEm = &now
+	m.IDProvedor = idProvedor
 	m.TentativasEnvio++ // Conta como uma tentativa bem sucedida
 	m.Touch()
 }
@@ -81,9 +111,72 @@ func (m *Mensagem) MarcarComoFalha(erro string) {
 }
 
 func (m *Mensagem) PodeRetentar() bool {
-	return m.TentativasEnvio < 5
+	return m.TentativasEnvio < MaxTentativasEnvio
 }
 
 func (m *Mensagem) DeveIrParaDLQ() bool {
-	return m.TentativasEnvio >= 5 && m.Status != StatusMensagemEnviada
+	return m.TentativasEnvio >= MaxTentativasEnvio && m.Status != StatusMensagemEnviada
+}
+
+// AgendarNovaTentativa aplica backoff exponencial a partir da quantidade de tentativas:
+// base, 2*base, 4*base... limitado a max.
+func (m *Mensagem) AgendarNovaTentativa(agora time.Time, base, max time.Duration) {
+	espera := base
+	for i := 1; i < m.TentativasEnvio && espera < max; i++ {
+		espera *= 2
+	}
+	if espera > max {
+		espera = max
+	}
+
+	proxima := agora.Add(espera)
+	m.ProximaTentativaEm = &proxima
+	m.Touch()
+}
+
+// MoverParaDLQ encerra a mensagem sem novas tentativas
+func (m *Mensagem) MoverParaDLQ() {
+	m.Status = StatusMensagemDLQ
+	m.ProximaTentativaEm = nil
+	m.Touch()
+}
+
+// MarcarComoEntregue registra a entrega informada pelo provedor. Retorna false se não há
+// o que mudar: mensagem ainda não enviada, já entregue ou já lida (callback repetido ou fora de ordem).
+func (m *Mensagem) MarcarComoEntregue(em time.Time) bool {
+	if m.Status != StatusMensagemEnviada {
+		return false
+	}
+
+	m.Status = StatusMensagemEntregue
+	m.EntregueEm = &em
+	m.Touch()
+	return true
+}
+
+// MarcarComoLida registra a leitura informada pelo provedor. Uma mensagem lida foi entregue:
+// se o aviso de entrega não chegou, a entrega fica com o mesmo horário da leitura.
+// Retorna false se a mensagem ainda não foi enviada ou já estava lida.
+func (m *Mensagem) MarcarComoLida(em time.Time) bool {
+	if m.Status != StatusMensagemEnviada && m.Status != StatusMensagemEntregue {
+		return false
+	}
+
+	if m.EntregueEm == nil {
+		m.EntregueEm = &em
+	}
+	m.Status = StatusMensagemLida
+	m.LidaEm = &em
+	m.Touch()
+	return true
+}
+
+// EntregaPendente é um status que o provedor informou antes de o envio ser gravado:
+// o webhook pode chegar antes do commit que guarda o IDProvedor na mensagem.
+// Fica guardado até a gravação do envio, que o aplica.
+type EntregaPendente struct {
+	IDProvedor string
+	Status     StatusMensagem // StatusMensagemEntregue ou StatusMensagemLida
+	Em         time.Time      // Horário do status no provedor
+	RecebidoEm time.Time
 }
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -28,11 +29,47 @@ func TestMensagem_Lifecycle(t *testing.T) {
 	m, _ := NewMensagem("fat-1", "cli-1", "5511999998888", "Olá", TipoMensagemLembrete)
 
 	t.Run("should mark as sent", func(t *testing.T) {
-		m.MarcarComoEnviada()
+		m.MarcarComoEnviada("3EB0C767D26A")
 		assert.Equal(t, StatusMensagemEnviada, m.Status)
 		assert.NotNil(t, m.EnviadoEm)
 		assert.Equal(t, 1, m.TentativasEnvio)
+		assert.Equal(t, "3EB0C767D26A", m.IDProvedor)
 	})
+
+	entregueEm := time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+	lidaEm := entregueEm.Add(time.Hour)
+
+	t.Run("should mark as delivered once", func(t *testing.T) {
+		assert.True(t, m.MarcarComoEntregue(entregueEm))
+		assert.Equal(t, StatusMensagemEntregue, m.Status)
+		assert.Equal(t, entregueEm, *m.EntregueEm)
+
+		assert.False(t, m.MarcarComoEntregue(lidaEm))
+		assert.Equal(t, entregueEm, *m.EntregueEm)
+	})
+
+	t.Run("should mark as read once", func(t *testing.T) {
+		assert.True(t, m.MarcarComoLida(lidaEm))
+		assert.Equal(t, StatusMensagemLida, m.Status)
+		assert.Equal(t, lidaEm, *m.LidaEm)
+		assert.Equal(t, entregueEm, *m.EntregueEm)
+
+		// Callbacks repetidos ou atrasados não fazem o status voltar
+		assert.False(t, m.MarcarComoLida(lidaEm.Add(time.Hour)))
+		assert.False(t, m.MarcarComoEntregue(lidaEm))
+		assert.Equal(t, StatusMensagemLida, m.Status)
+	})
+}
+
+func TestMensagem_LidaSemAvisoDeEntrega(t *testing.T) {
+	m, _ := NewMensagem("fat-1", "cli-1", "5511999998888", "Olá", TipoMensagemLembrete)
+	lidaEm := time.Date(2026, 3, 5, 11, 0, 0, 0, time.UTC)
+
+	assert.False(t, m.MarcarComoLida(lidaEm)) // Ainda não enviada
+
+	m.MarcarComoEnviada("3EB0C767D26A")
+	assert.True(t, m.MarcarComoLida(lidaEm))
+	assert.Equal(t, lidaEm, *m.EntregueEm)
 }
 
 func TestMensagem_RetryLogic(t *testing.T) {
@@ -58,3 +95,41 @@ func TestMensagem_RetryLogic(t *testing.T) {
 	assert.Equal(t, StatusMensagemFalha, m.Status)
 	assert.Equal(t, "timeout final", m.ErroMensagem)
 }
+
+func TestMensagem_Backoff(t *testing.T) {
+	m, _ := NewMensagem("fat-1", "cli-1", "5511999998888", "M", TipoMensagemLembrete)
+	agora := time.Date(2026, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	esperado := []time.Duration{
+		30 * time.Second,
+		60 * time.Second,
+		120 * time.Second,
+		240 * time.Second,
+		5 * time.Minute, // Limitado ao máximo
+	}
+
+	for _, espera := range esperado {
+		m.MarcarComoFalha("timeout")
+		m.AgendarNovaTentativa(agora, 30*time.Second, 5*time.Minute)
+		assert.Equal(t, agora.Add(espera), *m.ProximaTentativaEm)
+	}
+}
+
+func TestMensagem_MoverParaDLQ(t *testing.T) {
+	m, _ := NewMensagem("fat-1", "cli-1", "5511999998888", "M", TipoMensagemLembrete)
+	m.MarcarComoFalha("numero nao possui whatsapp")
+	m.AgendarNovaTentativa(time.Now(), time.Second, time.Minute)
+
+	m.MoverParaDLQ()
+	assert.Equal(t, StatusMensagemDLQ, m.Status)
+	assert.Nil(t, m.ProximaTentativaEm)
+	assert.Equal(t, "numero nao possui whatsapp", m.ErroMensagem)
+}
+
+func TestNewMensagemNaDLQ(t *testing.T) {
+	m := NewMensagemNaDLQ("fat-1", "cli-1", "", "Pagamento recebido", TipoMensagemConfirmacao, ErrWhatsAppVazio.Error())
+	assert.NotEmpty(t, m.ID)
+	assert.Equal(t, StatusMensagemDLQ, m.Status)
+	assert.Equal(t, ErrWhatsAppVazio.Error(), m.ErroMensagem)
+	assert.Equal(t, 0, m.TentativasEnvio)
+}
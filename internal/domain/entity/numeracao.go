@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPrefixoNumeracaoInvalido = errors.New("prefixo da numeracao deve ter de 1 a 10 letras maiusculas ou numeros")
+	ErrDigitosNumeracaoInvalido = errors.New("digitos da numeracao devem estar entre 1 e 12")
+)
+
+var prefixoNumeracaoRegex = regexp.MustCompile(`^[A-Z0-9]{1,10}$`)
+
+// FormatoNumeracao define como o número sequencial da fatura vira texto: PREFIXO-AAAA-000001.
+// O prefixo também identifica a série: cada tenant tem uma sequência por prefixo e,
+// quando o ano faz parte do número, a sequência recomeça a cada ano.
+type FormatoNumeracao struct {
+	Prefixo    string
+	IncluirAno bool
+	Digitos    int // Largura mínima do sequencial, completada com zeros à esquerda
+}
+
+func FormatoNumeracaoPadrao() FormatoNumeracao {
+	return FormatoNumeracao{Prefixo: "FAT", IncluirAno: true, Digitos: 6}
+}
+
+func (f FormatoNumeracao) Validate() error {
+	if !prefixoNumeracaoRegex.MatchString(f.Prefixo) {
+		return ErrPrefixoNumeracaoInvalido
+	}
+	if f.Digitos < 1 || f.Digitos > 12 {
+		return ErrDigitosNumeracaoInvalido
+	}
+	return nil
+}
+
+// Periodo identifica a sequência dentro da série: o ano, se ele faz parte do número, ou 0
+func (f FormatoNumeracao) Periodo(em time.Time) int {
+	if f.IncluirAno {
+		return em.Year()
+	}
+	return 0
+}
+
+// Formatar monta o número da fatura a partir do sequencial reservado para o período de em
+func (f FormatoNumeracao) Formatar(sequencial int64, em time.Time) string {
+	partes := []string{f.Prefixo}
+	if f.IncluirAno {
+		partes = append(partes, fmt.Sprintf("%04d", em.Year()))
+	}
+	partes = append(partes, fmt.Sprintf("%0*d", f.Digitos, sequencial))
+	return strings.Join(partes, "-")
+}
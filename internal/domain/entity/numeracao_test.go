@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatoNumeracao(t *testing.T) {
+	em := time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	t.Run("should format with prefix, year and zero padding", func(t *testing.T) {
+		f := FormatoNumeracaoPadrao()
+		assert.Equal(t, "FAT-2026-000042", f.Formatar(42, em))
+		assert.Equal(t, 2026, f.Periodo(em))
+	})
+
+	t.Run("should omit the year and keep a single sequence", func(t *testing.T) {
+		f := FormatoNumeracao{Prefixo: "NF", Digitos: 4}
+		assert.Equal(t, "NF-0007", f.Formatar(7, em))
+		assert.Equal(t, "NF-12345", f.Formatar(12345, em)) // Não trunca quando passa da largura
+		assert.Equal(t, 0, f.Periodo(em))
+	})
+
+	t.Run("should validate prefix and digits", func(t *testing.T) {
+		assert.NoError(t, FormatoNumeracaoPadrao().Validate())
+		assert.Equal(t, ErrPrefixoNumeracaoInvalido, FormatoNumeracao{Prefixo: "fat", Digitos: 6}.Validate())
+		assert.Equal(t, ErrPrefixoNumeracaoInvalido, FormatoNumeracao{Prefixo: "", Digitos: 6}.Validate())
+		assert.Equal(t, ErrDigitosNumeracaoInvalido, FormatoNumeracao{Prefixo: "FAT", Digitos: 0}.Validate())
+	})
+}
@@ -0,0 +1,224 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MetodoPagamento string
+
+const (
+	MetodoPix           MetodoPagamento = "pix"
+	MetodoBoleto        MetodoPagamento = "boleto"
+	MetodoCartao        MetodoPagamento = "cartao"
+	MetodoTransferencia MetodoPagamento = "transferencia"
+	MetodoDinheiro      MetodoPagamento = "dinheiro"
+	MetodoManual        MetodoPagamento = "manual" // Baixa manual, sem o meio informado
+)
+
+var (
+	ErrValorPagamentoInvalido      = errors.New("valor do pagamento deve ser maior que zero")
+	ErrMetodoPagamentoInvalido     = errors.New("metodo de pagamento invalido")
+	ErrDataPagamentoFutura         = errors.New("data do pagamento nao pode ser futura")
+	ErrPagamentoExcedeSaldo        = errors.New("valor do pagamento excede o saldo devedor da fatura")
+	ErrPagamentoDuplicado          = errors.New("ja existe pagamento com essa referencia externa")
+	ErrPagamentoNaoEncontrado      = errors.New("pagamento nao encontrado")
+	ErrPagamentoJaEstornado        = errors.New("pagamento ja foi estornado")
+	ErrCancelarFaturaComPagamentos = errors.New("nao e possivel cancelar uma fatura com pagamentos; estorne-os antes")
+)
+
+func (m MetodoPagamento) Valido() bool {
+	switch m {
+	case MetodoPix, MetodoBoleto, MetodoCartao, MetodoTransferencia, MetodoDinheiro, MetodoManual:
+		return true
+	}
+	return false
+}
+
+// Pagamento é um recebimento registrado contra a fatura. Uma fatura pode ter vários;
+// ela é liquidada quando a soma dos pagamentos ativos cobre o valor devido.
+// Pagamentos fazem parte do agregado Fatura e viajam nos eventos dele.
+type Pagamento struct {
+	ID                string
+	FaturaID          string
+	Valor             Dinheiro
+	Metodo            MetodoPagamento
+	PagoEm            time.Time
+	ReferenciaExterna string // Identificador no meio de pagamento (txid do Pix, nosso número do boleto...)
+	EstornadoEm       *time.Time
+	MotivoEstorno     string
+}
+
+func (p Pagamento) Estornado() bool {
+	return p.EstornadoEm != nil
+}
+
+// TotalPago soma os pagamentos que não foram estornados
+func (f *Fatura) TotalPago() Dinheiro {
+	total := NewDinheiro(0, f.Valor.Moeda())
+	for _, p := range f.Pagamentos {
+		if p.Estornado() {
+			continue
+		}
+		// Todos os pagamentos são em reais, como a fatura: a soma não tem como falhar
+		total, _ = total.Somar(p.Valor)
+	}
+	return total
+}
+
+// RegistrarPagamento lança um recebimento na fatura. O valor não pode passar do saldo devedor
+// em pagoEm (valor atualizado com os encargos, menos o que já foi pago); quando o cobre,
+// a fatura é liquidada no mesmo comando. Uma referência externa repetida é recusada,
+// o que torna seguro reprocessar a confirmação de um meio de pagamento.
+func (f *Fatura) RegistrarPagamento(valor Dinheiro, metodo MetodoPagamento, pagoEm time.Time, referencia string, encargos EncargosAtraso) (*Pagamento, error) {
+	if f.Status == StatusPaga {
+		return nil, ErrFaturaJaPaga
+	}
+	if f.Status == StatusCancelada {
+		return nil, ErrPagarFaturaCancelada
+	}
+	if !valor.IsPositivo() {
+		return nil, ErrValorPagamentoInvalido
+	}
+	if valor.Moeda() != MoedaBRL {
+		return nil, ErrMoedaNaoSuportada
+	}
+	if !metodo.Valido() {
+		return nil, ErrMetodoPagamentoInvalido
+	}
+
+	agora := time.Now()
+	if pagoEm.IsZero() {
+		pagoEm = agora
+	}
+	if pagoEm.After(agora) {
+		return nil, ErrDataPagamentoFutura
+	}
+
+	if referencia != "" {
+		for _, p := range f.Pagamentos {
+			if p.ReferenciaExterna == referencia {
+				return nil, ErrPagamentoDuplicado
+			}
+		}
+	}
+
+	devido, err := f.ValorDevido(pagoEm, encargos)
+	if err != nil {
+		return nil, err
+	}
+	comparacao, err := valor.Comparar(devido.Saldo)
+	if err != nil {
+		return nil, err
+	}
+	if comparacao > 0 {
+		return nil, ErrPagamentoExcedeSaldo
+	}
+
+	id := uuid.New().String()
+	err = f.registrar(EventFaturaPagamentoRegistrado, FaturaPagamentoRegistradoData{
+		FaturaID:          f.ID,
+		ClienteID:         f.ClienteID,
+		PagamentoID:       id,
+		Valor:             valor,
+		Metodo:            metodo,
+		PagoEm:            pagoEm,
+		ReferenciaExterna: referencia,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if comparacao == 0 {
+		err := f.registrar(EventFaturaPaga, FaturaPagaData{
+			FaturaID:  f.ID,
+			ClienteID: f.ClienteID,
+			ValorPago: f.TotalPago(),
+			Multa:     devido.Multa,
+			Juros:     devido.Juros,
+			PagoEm:    pagoEm,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f.pagamento(id), nil
+}
+
+// EstornarPagamento devolve um pagamento. A fatura volta a dever o valor estornado:
+// fica vencida se o vencimento já passou, ou parcialmente paga/pendente conforme restem pagamentos.
+func (f *Fatura) EstornarPagamento(pagamentoID, motivo string) error {
+	p := f.pagamento(pagamentoID)
+	if p == nil {
+		return ErrPagamentoNaoEncontrado
+	}
+	if p.Estornado() {
+		return ErrPagamentoJaEstornado
+	}
+
+	agora := time.Now()
+	restante, err := f.TotalPago().Subtrair(p.Valor)
+	if err != nil {
+		return err
+	}
+
+	status := StatusPendente
+	switch {
+	case f.DataVencimento.Before(agora):
+		status = StatusVencida
+	case restante.IsPositivo():
+		status = StatusParcialmentePaga
+	}
+
+	return f.registrar(EventFaturaPagamentoEstornado, FaturaPagamentoEstornadoData{
+		FaturaID:    f.ID,
+		ClienteID:   f.ClienteID,
+		PagamentoID: p.ID,
+		Valor:       p.Valor,
+		Motivo:      motivo,
+		EstornadoEm: agora,
+		Status:      status,
+	})
+}
+
+// PagamentoQueLiquidou é o pagamento que completou o valor devido de uma fatura paga:
+// o mais recente não estornado até DataPagamento, que recebe a data dele.
+// Retorna nil se a fatura não está paga.
+func (f *Fatura) PagamentoQueLiquidou() *Pagamento {
+	if f.Status != StatusPaga || f.DataPagamento == nil {
+		return nil
+	}
+
+	var liquidou *Pagamento
+	for i := range f.Pagamentos {
+		p := &f.Pagamentos[i]
+		if p.Estornado() || p.PagoEm.After(*f.DataPagamento) {
+			continue
+		}
+		if liquidou == nil || !p.PagoEm.Before(liquidou.PagoEm) {
+			liquidou = p
+		}
+	}
+	return liquidou
+}
+
+func (f *Fatura) pagamento(id string) *Pagamento {
+	for i := range f.Pagamentos {
+		if f.Pagamentos[i].ID == id {
+			return &f.Pagamentos[i]
+		}
+	}
+	return nil
+}
+
+// ConfirmacaoPagamento registra que o pagamento que liquidou a fatura já gerou
+// a mensagem de confirmação para o cliente
+type ConfirmacaoPagamento struct {
+	PagamentoID string
+	FaturaID    string
+	MensagemID  string
+	EnviadaEm   time.Time
+}
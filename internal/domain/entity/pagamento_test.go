@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFatura_Pagamentos(t *testing.T) {
+	f, _ := NewFatura("cust-123", "FAT-2026-000001", BRL(10000), time.Now().AddDate(0, 0, 5), "Parcelada")
+
+	t.Run("should leave the fatura partially paid", func(t *testing.T) {
+		p, err := f.RegistrarPagamento(BRL(4000), MetodoPix, time.Time{}, "E1", EncargosAtraso{})
+		assert.NoError(t, err)
+		assert.Equal(t, f.ID, p.FaturaID)
+		assert.Equal(t, StatusParcialmentePaga, f.Status)
+		assert.Equal(t, BRL(4000), f.TotalPago())
+		assert.Nil(t, f.DataPagamento)
+		assert.Nil(t, f.PagamentoQueLiquidou())
+	})
+
+	t.Run("should validate the payment", func(t *testing.T) {
+		_, err := f.RegistrarPagamento(BRL(0), MetodoPix, time.Time{}, "", EncargosAtraso{})
+		assert.Equal(t, ErrValorPagamentoInvalido, err)
+
+		_, err = f.RegistrarPagamento(BRL(100), "cheque", time.Time{}, "", EncargosAtraso{})
+		assert.Equal(t, ErrMetodoPagamentoInvalido, err)
+
+		_, err = f.RegistrarPagamento(BRL(100), MetodoPix, time.Now().Add(time.Hour), "", EncargosAtraso{})
+		assert.Equal(t, ErrDataPagamentoFutura, err)
+
+		_, err = f.RegistrarPagamento(BRL(100), MetodoPix, time.Time{}, "E1", EncargosAtraso{})
+		assert.Equal(t, ErrPagamentoDuplicado, err)
+
+		_, err = f.RegistrarPagamento(BRL(6001), MetodoPix, time.Time{}, "", EncargosAtraso{})
+		assert.Equal(t, ErrPagamentoExcedeSaldo, err)
+	})
+
+	t.Run("should not cancel while payments are active", func(t *testing.T) {
+		assert.Equal(t, ErrCancelarFaturaComPagamentos, f.Cancelar())
+	})
+
+	t.Run("should settle when payments cover the balance", func(t *testing.T) {
+		_, err := f.RegistrarPagamento(BRL(6000), MetodoBoleto, time.Time{}, "", EncargosAtraso{})
+		assert.NoError(t, err)
+		assert.Equal(t, StatusPaga, f.Status)
+		assert.NotNil(t, f.DataPagamento)
+		assert.Equal(t, f.Pagamentos[1].ID, f.PagamentoQueLiquidou().ID)
+
+		_, err = f.RegistrarPagamento(BRL(1), MetodoPix, time.Time{}, "", EncargosAtraso{})
+		assert.Equal(t, ErrFaturaJaPaga, err)
+	})
+
+	t.Run("should reopen the fatura on refund", func(t *testing.T) {
+		assert.NoError(t, f.EstornarPagamento(f.Pagamentos[1].ID, "chargeback"))
+		assert.Equal(t, StatusParcialmentePaga, f.Status)
+		assert.Nil(t, f.DataPagamento)
+		assert.Equal(t, BRL(4000), f.TotalPago())
+
+		assert.Equal(t, ErrPagamentoJaEstornado, f.EstornarPagamento(f.Pagamentos[1].ID, ""))
+		assert.Equal(t, ErrPagamentoNaoEncontrado, f.EstornarPagamento("outro", ""))
+
+		// Sem pagamentos ativos, volta a pendente
+		assert.NoError(t, f.EstornarPagamento(f.Pagamentos[0].ID, ""))
+		assert.Equal(t, StatusPendente, f.Status)
+		assert.NoError(t, f.Cancelar())
+	})
+
+	t.Run("should rebuild payments from the stream", func(t *testing.T) {
+		r, err := ReconstruirFatura(f.EventosPendentes())
+		assert.NoError(t, err)
+		assert.Len(t, r.Pagamentos, 2)
+		assert.True(t, r.Pagamentos[0].Estornado())
+		assert.Equal(t, "chargeback", r.Pagamentos[1].MotivoEstorno)
+		assert.Equal(t, StatusCancelada, r.Status)
+	})
+}
+
+func TestFatura_PagamentoQueLiquidou(t *testing.T) {
+	f, _ := NewFatura("cust-123", "FAT-2026-000001", BRL(10000), time.Now().AddDate(0, 0, 5), "Parcelada")
+	agora := time.Now()
+
+	// O pagamento que liquida é retroativo: fica antes do parcial na ordem por data
+	_, err := f.RegistrarPagamento(BRL(4000), MetodoPix, agora.Add(-time.Hour), "", EncargosAtraso{})
+	assert.NoError(t, err)
+	liquidou, err := f.RegistrarPagamento(BRL(6000), MetodoBoleto, agora.Add(-2*time.Hour), "", EncargosAtraso{})
+	assert.NoError(t, err)
+
+	f.Pagamentos = []Pagamento{f.Pagamentos[1], f.Pagamentos[0]}
+	assert.Equal(t, liquidou.ID, f.PagamentoQueLiquidou().ID)
+}
+
+func TestFatura_PagamentoParcialEmAtraso(t *testing.T) {
+	t.Run("should keep an overdue fatura overdue after a partial payment", func(t *testing.T) {
+		f := faturaVencidaHa(t, 10)
+		assert.NoError(t, f.MarcarComoVencida(time.Now()))
+
+		p, err := f.RegistrarPagamento(BRL(4000), MetodoPix, time.Time{}, "", EncargosAtraso{})
+		assert.NoError(t, err)
+		assert.Equal(t, StatusVencida, f.Status)
+
+		assert.NoError(t, f.EstornarPagamento(p.ID, ""))
+		assert.Equal(t, StatusVencida, f.Status)
+	})
+
+	t.Run("should let a partially paid fatura become overdue", func(t *testing.T) {
+		f, _ := NewFatura("cust-123", "FAT-2026-000002", BRL(10000), time.Now().AddDate(0, 0, 5), "Parcelada")
+		_, err := f.RegistrarPagamento(BRL(4000), MetodoPix, time.Time{}, "", EncargosAtraso{})
+		assert.NoError(t, err)
+		assert.Equal(t, StatusParcialmentePaga, f.Status)
+
+		assert.NoError(t, f.MarcarComoVencida(f.DataVencimento.AddDate(0, 0, 1)))
+		assert.Equal(t, StatusVencida, f.Status)
+
+		r, err := ReconstruirFatura(f.EventosPendentes())
+		assert.NoError(t, err)
+		assert.Equal(t, StatusVencida, r.Status)
+	})
+}
+
+func TestFatura_PagamentoComEncargos(t *testing.T) {
+	f := faturaVencidaHa(t, 10)
+	encargos := EncargosAtraso{Multa: 200, JurosAoMes: 100}
+
+	// Devido: 102,33. O saldo inclui os encargos
+	_, err := f.RegistrarPagamento(BRL(10000), MetodoPix, time.Time{}, "", encargos)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusParcialmentePaga, f.Status)
+
+	devido, err := f.ValorDevido(time.Now(), encargos)
+	assert.NoError(t, err)
+	assert.Equal(t, BRL(233), devido.Saldo)
+
+	_, err = f.RegistrarPagamento(BRL(233), MetodoPix, time.Time{}, "", encargos)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPaga, f.Status)
+}
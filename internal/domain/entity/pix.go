@@ -0,0 +1,300 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type TipoPix string
+
+const (
+	PixEstatico TipoPix = "estatico" // Chave no próprio payload; pode ser pago mais de uma vez
+	PixDinamico TipoPix = "dinamico" // Aponta para a cobrança criada no PSP (CobrancaPix); uso único
+)
+
+var (
+	ErrPixNaoConfigurado    = errors.New("pix nao configurado para o tenant")
+	ErrChavePixInvalida     = errors.New("chave pix deve ter ate 77 caracteres")
+	ErrNomeRecebedorPix     = errors.New("nome do recebedor pix deve ter de 1 a 25 caracteres")
+	ErrCidadePix            = errors.New("cidade do recebedor pix deve ter de 1 a 15 caracteres")
+	ErrLocationPix          = errors.New("location da cobranca pix deve ter de 1 a 77 caracteres, sem o https://")
+	ErrPixDinamicoSemCob    = errors.New("pix dinamico exige a cobranca criada no psp para a fatura")
+	ErrTipoPixInvalido      = errors.New("tipo de pix deve ser estatico ou dinamico")
+	ErrValorPixForaDoLimite = errors.New("valor excede o limite do pix")
+	ErrCampoPixExtenso      = errors.New("campo do pix excede 99 caracteres")
+)
+
+// ConfigPix são os dados de recebimento Pix de um tenant. Sem chave, o tenant não recebe por Pix.
+type ConfigPix struct {
+	Chave         string // CPF/CNPJ, e-mail, telefone (+55...) ou chave aleatória
+	NomeRecebedor string // Até 25 caracteres; acentos são removidos no payload
+	Cidade        string // Até 15 caracteres
+}
+
+func (c ConfigPix) Configurado() bool {
+	return c.Chave != ""
+}
+
+func (c ConfigPix) Validate() error {
+	if !c.Configurado() {
+		return nil
+	}
+	if _, err := contaEstatica(c.Chave); err != nil {
+		return ErrChavePixInvalida
+	}
+	if n := len(textoPix(c.NomeRecebedor)); n == 0 || n > 25 {
+		return ErrNomeRecebedorPix
+	}
+	if n := len(textoPix(c.Cidade)); n == 0 || n > 15 {
+		return ErrCidadePix
+	}
+	return nil
+}
+
+// CobrancaPix é a cobrança imediata (cob) que o PSP do tenant criou para a fatura.
+// Location é o endereço que o PSP devolveu na criação, sem o https://: o Pix dinâmico
+// aponta para ele, e é o PSP quem informa ao pagador o valor e o txid da cobrança.
+type CobrancaPix struct {
+	FaturaID     string
+	Location     string
+	RegistradaEm time.Time
+}
+
+func NewCobrancaPix(faturaID, location string) (*CobrancaPix, error) {
+	c := &CobrancaPix{FaturaID: faturaID, Location: strings.TrimSpace(location), RegistradaEm: time.Now()}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate confere que a location cabe, junto com o GUI, no campo 26 do BR Code
+func (c CobrancaPix) Validate() error {
+	if c.Location == "" || strings.Contains(c.Location, "://") {
+		return ErrLocationPix
+	}
+	if _, err := contaDinamica(c.Location); err != nil {
+		return ErrLocationPix
+	}
+	return nil
+}
+
+// Campos do BR Code (Manual de Padrões para Iniciação do Pix, BACEN)
+const (
+	emvFormato         = "00"
+	emvIniciacao       = "01"
+	emvContaPix        = "26"
+	emvCategoria       = "52"
+	emvMoeda           = "53"
+	emvValor           = "54"
+	emvPais            = "58"
+	emvNome            = "59"
+	emvCidade          = "60"
+	emvDadosAdicionais = "62"
+	emvCRC             = "63"
+	emvPixGUI          = "00"
+	emvPixChave        = "01"
+	emvPixURL          = "25"
+	emvAdicionaisTxID  = "05"
+	gui                = "br.gov.bcb.pix"
+	codigoMoedaReal    = "986"
+	iniciacaoUnica     = "12" // QR de uso único: exigido no dinâmico
+	txidDinamico       = "***"
+	tamanhoMaximoTxID  = 25
+	tamanhoMaximoCampo = 99
+	tamanhoMaximoValor = 13
+)
+
+// BRCode é o Pix "copia e cola" de uma cobrança
+type BRCode struct {
+	Tipo     TipoPix
+	Config   ConfigPix
+	TxID     string   // Identificador da cobrança no payload estático
+	Location string   // Cobrança no PSP, para o dinâmico
+	Valor    Dinheiro // Zero deixa o valor em aberto para o pagador
+}
+
+// NewBRCode monta o Pix da fatura usando o número dela como txid.
+// O dinâmico exige a cobrança criada no PSP para a fatura.
+func NewBRCode(config ConfigPix, tipo TipoPix, fatura *Fatura, valor Dinheiro, cob *CobrancaPix) (BRCode, error) {
+	if !config.Configurado() {
+		return BRCode{}, ErrPixNaoConfigurado
+	}
+	if err := config.Validate(); err != nil {
+		return BRCode{}, err
+	}
+	if tipo != PixEstatico && tipo != PixDinamico {
+		return BRCode{}, ErrTipoPixInvalido
+	}
+	if tipo == PixDinamico && cob == nil {
+		return BRCode{}, ErrPixDinamicoSemCob
+	}
+	if valor.Moeda() != "" && valor.Moeda() != MoedaBRL {
+		return BRCode{}, ErrMoedaNaoSuportada
+	}
+	if len(valor.Decimal()) > tamanhoMaximoValor {
+		return BRCode{}, ErrValorPixForaDoLimite
+	}
+
+	code := BRCode{Tipo: tipo, Config: config, TxID: TxIDFatura(fatura.Numero), Valor: valor}
+	if tipo == PixDinamico {
+		if err := cob.Validate(); err != nil {
+			return BRCode{}, err
+		}
+		code.Location = cob.Location
+	}
+	return code, nil
+}
+
+// TxIDFatura converte o número da fatura no txid do Pix, que só aceita letras e dígitos:
+// FAT-2026-000042 vira FAT2026000042
+func TxIDFatura(numero string) string {
+	txid := txidInvalido.ReplaceAllString(numero, "")
+	if len(txid) > tamanhoMaximoTxID {
+		txid = txid[len(txid)-tamanhoMaximoTxID:] // O fim do número é o sequencial
+	}
+	return txid
+}
+
+var txidInvalido = regexp.MustCompile(`[^A-Za-z0-9]`)
+
+// Payload serializa o BR Code no formato EMV (ID + tamanho + valor), terminando no CRC16.
+// Falha se algum campo passar de 99 caracteres, o que só acontece com um BRCode montado
+// sem NewBRCode.
+func (b BRCode) Payload() (string, error) {
+	var conta, txid string
+	var err error
+	if b.Tipo == PixDinamico {
+		conta, err = contaDinamica(b.Location)
+		// No dinâmico, o txid fica na cobrança do PSP e o campo recebe ***
+		txid = txidDinamico
+	} else {
+		conta, err = contaEstatica(b.Config.Chave)
+		txid = b.TxID
+		if txid == "" {
+			txid = txidDinamico
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	adicionais, err := emv(emvAdicionaisTxID, txid)
+	if err != nil {
+		return "", err
+	}
+
+	campos := []string{emvFormato, "01"}
+	if b.Tipo == PixDinamico {
+		campos = append(campos, emvIniciacao, iniciacaoUnica)
+	}
+	campos = append(campos, emvContaPix, conta, emvCategoria, "0000", emvMoeda, codigoMoedaReal)
+	if b.Valor.IsPositivo() {
+		campos = append(campos, emvValor, b.Valor.Decimal())
+	}
+	campos = append(campos,
+		emvPais, "BR",
+		emvNome, textoPix(b.Config.NomeRecebedor),
+		emvCidade, textoPix(b.Config.Cidade),
+		emvDadosAdicionais, adicionais,
+	)
+
+	payload, err := emv(campos...)
+	if err != nil {
+		return "", err
+	}
+
+	// O CRC cobre o payload inteiro, inclusive o ID e o tamanho do próprio campo
+	payload += emvCRC + "04"
+	return payload + fmt.Sprintf("%04X", crc16(payload)), nil
+}
+
+// contaEstatica é o conteúdo do campo 26 do Pix estático: GUI e chave
+func contaEstatica(chave string) (string, error) {
+	return contaPix(emvPixChave, chave)
+}
+
+// contaDinamica é o conteúdo do campo 26 do Pix dinâmico: GUI e location da cobrança
+func contaDinamica(location string) (string, error) {
+	return contaPix(emvPixURL, location)
+}
+
+// contaPix confere também o tamanho do campo 26 inteiro, que é limitado como os demais
+func contaPix(id, valor string) (string, error) {
+	conta, err := emv(emvPixGUI, gui, id, valor)
+	if err != nil {
+		return "", err
+	}
+	if len(conta) > tamanhoMaximoCampo {
+		return "", ErrCampoPixExtenso
+	}
+	return conta, nil
+}
+
+// emv codifica os campos dados em pares (ID, valor), na ordem
+func emv(campos ...string) (string, error) {
+	var b strings.Builder
+	for i := 0; i+1 < len(campos); i += 2 {
+		campo, err := campoEMV(campos[i], campos[i+1])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(campo)
+	}
+	return b.String(), nil
+}
+
+// campoEMV codifica um campo TLV. O tamanho tem dois dígitos: um valor maior que 99
+// caracteres não cabe e, cortado, geraria um payload corrompido com CRC válido.
+func campoEMV(id, valor string) (string, error) {
+	if len(valor) > tamanhoMaximoCampo {
+		return "", fmt.Errorf("%w: campo %s com %d caracteres", ErrCampoPixExtenso, id, len(valor))
+	}
+	return fmt.Sprintf("%s%02d%s", id, len(valor), valor), nil
+}
+
+// crc16 é o CRC-16/CCITT-FALSE (polinômio 0x1021, valor inicial 0xFFFF) exigido pelo BR Code
+func crc16(s string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+var acentosPix = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e", "ë", "e",
+	"í", "i", "î", "i", "ì", "i", "ï", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ò", "o", "ö", "o",
+	"ú", "u", "û", "u", "ù", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "Ê", "E", "È", "E", "Ë", "E",
+	"Í", "I", "Î", "I", "Ì", "I", "Ï", "I",
+	"Ó", "O", "Ô", "O", "Õ", "O", "Ò", "O", "Ö", "O",
+	"Ú", "U", "Û", "U", "Ù", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+)
+
+// textoPix deixa nome e cidade em ASCII, como os leitores de QR dos bancos esperam
+func textoPix(s string) string {
+	s = acentosPix.Replace(strings.TrimSpace(s))
+	var b strings.Builder
+	for _, r := range s {
+		if r >= 0x20 && r < 0x7F {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRC16(t *testing.T) {
+	// Valor de verificação do CRC-16/CCITT-FALSE
+	assert.Equal(t, uint16(0x29B1), crc16("123456789"))
+}
+
+func TestBRCode_Payload(t *testing.T) {
+	config := ConfigPix{Chave: "123e4567-e12b-12d1-a456-426655440000", NomeRecebedor: "Fulano de Tal", Cidade: "BRASILIA"}
+
+	t.Run("should match the example from the BACEN manual", func(t *testing.T) {
+		code := BRCode{Tipo: PixEstatico, Config: config}
+		payload, err := code.Payload()
+		assert.NoError(t, err)
+		assert.Equal(t,
+			"00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D",
+			payload)
+	})
+
+	f, _ := NewFatura("cust-123", "FAT-2026-000042", BRL(15050), time.Now().AddDate(0, 0, 5), "Consultoria")
+
+	t.Run("should use the fatura numero as txid and include the amount", func(t *testing.T) {
+		code, err := NewBRCode(config, PixEstatico, f, BRL(15050), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "FAT2026000042", code.TxID)
+
+		payload, err := code.Payload()
+		assert.NoError(t, err)
+		assert.Contains(t, payload, "5406150.50")
+		assert.Contains(t, payload, "62170513FAT2026000042")
+
+		// Os 4 últimos caracteres são o CRC de todo o resto
+		corpo := payload[:len(payload)-4]
+		assert.True(t, strings.HasSuffix(corpo, "6304"))
+		assert.Equal(t, fmt.Sprintf("%04X", crc16(corpo)), payload[len(payload)-4:])
+	})
+
+	t.Run("should point dynamic codes to the location returned by the PSP", func(t *testing.T) {
+		cob, err := NewCobrancaPix(f.ID, "pix.psp.com.br/qr/v2/9d36b84fc70b478fb95c12729b90ca25")
+		assert.NoError(t, err)
+
+		code, err := NewBRCode(config, PixDinamico, f, BRL(15050), cob)
+		assert.NoError(t, err)
+
+		payload, err := code.Payload()
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(payload, "000201010212"))
+		assert.Contains(t, payload, "26750014br.gov.bcb.pix2553pix.psp.com.br/qr/v2/9d36b84fc70b478fb95c12729b90ca25")
+		assert.Contains(t, payload, "62070503***")
+		assert.NotContains(t, payload, config.Chave)
+		assert.NotContains(t, payload, code.TxID)
+	})
+
+	t.Run("should reject locations that do not fit the merchant account field", func(t *testing.T) {
+		// GUI (18) + ID e tamanho da location (4) + 77 = 99, o máximo do campo 26
+		_, err := NewCobrancaPix(f.ID, strings.Repeat("a", 77))
+		assert.NoError(t, err)
+
+		_, err = NewCobrancaPix(f.ID, strings.Repeat("a", 78))
+		assert.Equal(t, ErrLocationPix, err)
+
+		_, err = NewCobrancaPix(f.ID, "https://pix.psp.com.br/qr/v2/abc")
+		assert.Equal(t, ErrLocationPix, err)
+
+		_, err = NewCobrancaPix(f.ID, " ")
+		assert.Equal(t, ErrLocationPix, err)
+	})
+
+	t.Run("should fail instead of truncating oversized fields", func(t *testing.T) {
+		code := BRCode{Tipo: PixDinamico, Config: config, Location: strings.Repeat("a", 78)}
+		_, err := code.Payload()
+		assert.ErrorIs(t, err, ErrCampoPixExtenso)
+
+		code = BRCode{Tipo: PixEstatico, Config: ConfigPix{Chave: strings.Repeat("a", 100), NomeRecebedor: "Fulano", Cidade: "BRASILIA"}}
+		_, err = code.Payload()
+		assert.ErrorIs(t, err, ErrCampoPixExtenso)
+	})
+
+	t.Run("should strip accents from name and city", func(t *testing.T) {
+		acentuado := ConfigPix{Chave: "+5511999998888", NomeRecebedor: "João Conceição", Cidade: "São Paulo"}
+		code, err := NewBRCode(acentuado, PixEstatico, f, BRL(100), nil)
+		assert.NoError(t, err)
+		payload, err := code.Payload()
+		assert.NoError(t, err)
+		assert.Contains(t, payload, "5914Joao Conceicao6009Sao Paulo")
+	})
+
+	t.Run("should validate the configuration", func(t *testing.T) {
+		_, err := NewBRCode(ConfigPix{}, PixEstatico, f, BRL(100), nil)
+		assert.Equal(t, ErrPixNaoConfigurado, err)
+
+		_, err = NewBRCode(config, PixDinamico, f, BRL(100), nil)
+		assert.Equal(t, ErrPixDinamicoSemCob, err)
+
+		_, err = NewBRCode(config, "boleto", f, BRL(100), nil)
+		assert.Equal(t, ErrTipoPixInvalido, err)
+
+		longo := config
+		longo.NomeRecebedor = strings.Repeat("a", 26)
+		assert.Equal(t, ErrNomeRecebedorPix, longo.Validate())
+
+		chaveLonga := config
+		chaveLonga.Chave = strings.Repeat("a", 78)
+		assert.Equal(t, ErrChavePixInvalida, chaveLonga.Validate())
+	})
+}
+
+func TestTxIDFatura(t *testing.T) {
+	assert.Equal(t, "FAT2026000042", TxIDFatura("FAT-2026-000042"))
+	assert.Equal(t, "2026000000000000000000042", TxIDFatura("PREFIXOLONGO-2026-000000000000000000042"))
+}
@@ -0,0 +1,89 @@
+package entity
+
+import "time"
+
+// Read models mantidos pelas projeções a partir do event store.
+// Não são fonte de verdade: podem ser apagados e reconstruídos reaplicando os eventos.
+
+// RecebivelFatura é a situação de uma fatura dentro da projeção de recebíveis.
+// A projeção precisa dela para saber de qual total tirar o valor quando a fatura muda de status.
+type RecebivelFatura struct {
+	FaturaID  string
+	ClienteID string
+	Valor     Dinheiro
+	Recebido  Dinheiro // Soma dos pagamentos ativos; na fatura paga, inclui multa e juros
+	Status    StatusFatura
+}
+
+// RecebiveisCliente resume as contas a receber de um cliente
+type RecebiveisCliente struct {
+	ClienteID          string
+	QuantidadeEmAberto int // Faturas pendentes ou parcialmente pagas
+	ValorEmAberto      Dinheiro
+	QuantidadeVencida  int
+	ValorVencido       Dinheiro
+	QuantidadePaga     int
+	ValorRecebido      Dinheiro // Tudo o que já entrou, inclusive pagamentos parciais
+	AtualizadoEm       time.Time
+}
+
+// Somar aplica no resumo a entrada (sinal = 1) ou saída (sinal = -1) de uma fatura.
+// O que foi recebido conta em ValorRecebido e o que falta receber conta no total do status.
+// Canceladas não entram em nenhum total.
+func (r *RecebiveisCliente) Somar(f RecebivelFatura, sinal int) error {
+	var (
+		quantidade *int
+		aberto     *Dinheiro
+	)
+	switch f.Status {
+	case StatusPendente, StatusParcialmentePaga:
+		quantidade, aberto = &r.QuantidadeEmAberto, &r.ValorEmAberto
+	case StatusVencida:
+		quantidade, aberto = &r.QuantidadeVencida, &r.ValorVencido
+	case StatusPaga:
+		quantidade = &r.QuantidadePaga
+	default:
+		return nil
+	}
+
+	recebido := f.Recebido
+	if sinal < 0 {
+		recebido = recebido.Negativo()
+	}
+	totalRecebido, err := r.ValorRecebido.Somar(recebido)
+	if err != nil {
+		return err
+	}
+
+	if aberto != nil {
+		saldo, err := f.Valor.Subtrair(f.Recebido)
+		if err != nil {
+			return err
+		}
+		if saldo.IsNegativo() {
+			saldo = Dinheiro{}
+		}
+		if sinal < 0 {
+			saldo = saldo.Negativo()
+		}
+		totalAberto, err := aberto.Somar(saldo)
+		if err != nil {
+			return err
+		}
+		*aberto = totalAberto
+	}
+
+	*quantidade += sinal
+	r.ValorRecebido = totalRecebido
+	return nil
+}
+
+// EstatisticasEnvio conta os resultados de envio de mensagens de um tipo em um dia
+type EstatisticasEnvio struct {
+	Dia          time.Time // Data do evento no calendário local, guardada como meia-noite UTC
+	Tipo         TipoMensagem
+	Enfileiradas int
+	Enviadas     int
+	Falhas       int // Tentativas que falharam e foram reagendadas
+	DLQ          int
+}
@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	MaxEtapasRegua     = 10
+	DiasMinimosEtapa   = -30 // Até 30 dias antes do vencimento
+	DiasMaximosEtapa   = 90  // Até 90 dias depois do vencimento
+	tamanhoMaxTemplate = 1000
+)
+
+var (
+	ErrReguaMuitasEtapas    = errors.New("regua de cobranca aceita no maximo 10 etapas")
+	ErrDiasEtapaInvalidos   = errors.New("dias da etapa devem estar entre -30 e 90")
+	ErrEtapaDuplicada       = errors.New("regua de cobranca tem duas etapas no mesmo dia")
+	ErrTipoEtapaInvalido    = errors.New("tipo da etapa deve ser lembrete ou cobranca")
+	ErrTemplateEtapaExtenso = errors.New("template da etapa deve ter no maximo 1000 caracteres")
+)
+
+// EtapaCobranca é um envio da régua, posicionado em dias relativos ao vencimento:
+// -5 é cinco dias antes, 0 é o próprio dia e 3 é três dias depois (D-5, D0, D+3).
+type EtapaCobranca struct {
+	Dias     int          `json:"dias"`
+	Tipo     TipoMensagem `json:"tipo"`
+	Template string       `json:"template,omitempty"` // Vazio = texto padrão da etapa
+}
+
+// Nome identifica a etapa nos logs e nas respostas (D-5, D0, D+3)
+func (e EtapaCobranca) Nome() string {
+	if e.Dias == 0 {
+		return "D0"
+	}
+	return fmt.Sprintf("D%+d", e.Dias)
+}
+
+func (e EtapaCobranca) Validate() error {
+	if e.Dias < DiasMinimosEtapa || e.Dias > DiasMaximosEtapa {
+		return ErrDiasEtapaInvalidos
+	}
+	if e.Tipo != TipoMensagemLembrete && e.Tipo != TipoMensagemCobranca {
+		return ErrTipoEtapaInvalido
+	}
+	if len([]rune(e.Template)) > tamanhoMaxTemplate {
+		return ErrTemplateEtapaExtenso
+	}
+	return nil
+}
+
+// ReguaCobranca são as etapas de cobrança do tenant. Vazia = o tenant usa o lembrete
+// único (DiasAntesLembrete) e a cobrança no vencimento, como antes da régua.
+type ReguaCobranca []EtapaCobranca
+
+func (r ReguaCobranca) Validate() error {
+	if len(r) > MaxEtapasRegua {
+		return ErrReguaMuitasEtapas
+	}
+
+	dias := map[int]bool{}
+	for _, e := range r {
+		if err := e.Validate(); err != nil {
+			return err
+		}
+		if dias[e.Dias] {
+			return ErrEtapaDuplicada
+		}
+		dias[e.Dias] = true
+	}
+	return nil
+}
+
+// EtapaDevida é a etapa mais recente cuja data já chegou para uma fatura com o vencimento
+// informado. Etapas anteriores que não rodaram (fatura emitida depois delas, job parado)
+// são puladas: o cliente recebe só a mensagem que faz sentido hoje, não todas de uma vez.
+func (r ReguaCobranca) EtapaDevida(vencimento, agora time.Time) (EtapaCobranca, bool) {
+	decorridos := diasEntre(vencimento, agora)
+
+	var devida EtapaCobranca
+	encontrada := false
+	for _, e := range r {
+		if e.Dias <= decorridos && (!encontrada || e.Dias > devida.Dias) {
+			devida = e
+			encontrada = true
+		}
+	}
+	return devida, encontrada
+}
+
+// Value grava a régua como JSON (coluna JSONB)
+func (r ReguaCobranca) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+func (r *ReguaCobranca) Scan(src interface{}) error {
+	var raw []byte
+	switch v := src.(type) {
+	case nil:
+		*r = nil
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("tipo %T nao suportado para regua de cobranca", src)
+	}
+
+	var etapas ReguaCobranca
+	if err := json.Unmarshal(raw, &etapas); err != nil {
+		return fmt.Errorf("regua de cobranca invalida: %w", err)
+	}
+	if len(etapas) == 0 {
+		etapas = nil
+	}
+	*r = etapas
+	return nil
+}
+
+// EtapaExecutada registra que uma etapa da régua já gerou a mensagem de uma fatura
+type EtapaExecutada struct {
+	FaturaID    string
+	Dias        int
+	MensagemID  string
+	ExecutadaEm time.Time
+}
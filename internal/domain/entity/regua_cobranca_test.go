@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReguaCobranca_Validate(t *testing.T) {
+	regua := ReguaCobranca{
+		{Dias: -5, Tipo: TipoMensagemLembrete},
+		{Dias: 0, Tipo: TipoMensagemLembrete, Template: "Sua fatura vence hoje"},
+		{Dias: 3, Tipo: TipoMensagemCobranca},
+	}
+	assert.NoError(t, regua.Validate())
+	assert.NoError(t, ReguaCobranca(nil).Validate())
+
+	t.Run("should reject invalid steps", func(t *testing.T) {
+		assert.Equal(t, ErrDiasEtapaInvalidos, ReguaCobranca{{Dias: -31, Tipo: TipoMensagemLembrete}}.Validate())
+		assert.Equal(t, ErrDiasEtapaInvalidos, ReguaCobranca{{Dias: 91, Tipo: TipoMensagemCobranca}}.Validate())
+		assert.Equal(t, ErrTipoEtapaInvalido, ReguaCobranca{{Dias: 1, Tipo: TipoMensagemConfirmacao}}.Validate())
+		assert.Equal(t, ErrEtapaDuplicada, ReguaCobranca{{Dias: 1, Tipo: TipoMensagemCobranca}, {Dias: 1, Tipo: TipoMensagemLembrete}}.Validate())
+		assert.Equal(t, ErrTemplateEtapaExtenso, ReguaCobranca{{Dias: 1, Tipo: TipoMensagemCobranca, Template: strings.Repeat("a", 1001)}}.Validate())
+
+		longa := ReguaCobranca{}
+		for i := 0; i <= MaxEtapasRegua; i++ {
+			longa = append(longa, EtapaCobranca{Dias: i, Tipo: TipoMensagemCobranca})
+		}
+		assert.Equal(t, ErrReguaMuitasEtapas, longa.Validate())
+	})
+
+	t.Run("should be validated with the configuration", func(t *testing.T) {
+		c, _ := NewConfiguracao("user-1")
+		c.Regua = ReguaCobranca{{Dias: 0, Tipo: "sms"}}
+		assert.Equal(t, ErrTipoEtapaInvalido, c.Validate())
+	})
+
+	t.Run("should name the steps by day", func(t *testing.T) {
+		assert.Equal(t, []string{"D-5", "D0", "D+3"}, []string{regua[0].Nome(), regua[1].Nome(), regua[2].Nome()})
+	})
+}
+
+func TestReguaCobranca_EtapaDevida(t *testing.T) {
+	regua := ReguaCobranca{
+		{Dias: 3, Tipo: TipoMensagemCobranca},
+		{Dias: -5, Tipo: TipoMensagemLembrete},
+		{Dias: 0, Tipo: TipoMensagemLembrete},
+	}
+	vencimento := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should return nothing before the first step", func(t *testing.T) {
+		_, ok := regua.EtapaDevida(vencimento, time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC))
+		assert.False(t, ok)
+	})
+
+	t.Run("should return the latest step already reached", func(t *testing.T) {
+		casos := map[time.Time]int{
+			time.Date(2026, 3, 5, 8, 0, 0, 0, time.UTC):  -5,
+			time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC):  -5,
+			time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC): 0,
+			time.Date(2026, 3, 13, 8, 0, 0, 0, time.UTC): 3,
+			time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC):  3,
+		}
+		for agora, dias := range casos {
+			etapa, ok := regua.EtapaDevida(vencimento, agora)
+			assert.True(t, ok)
+			assert.Equal(t, dias, etapa.Dias, agora.String())
+		}
+	})
+}
+
+func TestReguaCobranca_Scan(t *testing.T) {
+	regua := ReguaCobranca{{Dias: -1, Tipo: TipoMensagemLembrete, Template: "Vence amanha"}}
+
+	raw, err := regua.Value()
+	assert.NoError(t, err)
+
+	var lida ReguaCobranca
+	assert.NoError(t, lida.Scan([]byte(raw.(string))))
+	assert.Equal(t, regua, lida)
+
+	assert.NoError(t, lida.Scan([]byte("[]")))
+	assert.Nil(t, lida)
+
+	vazia, _ := ReguaCobranca(nil).Value()
+	assert.Equal(t, "[]", vazia)
+}
@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+)
+
+// Mensageiro é a porta de saída para envio de mensagens de WhatsApp.
+// A implementação concreta (ex: Evolution API) fica na infraestrutura.
+type Mensageiro interface {
+	// EnviarTexto envia uma mensagem de texto e retorna o ID da mensagem no provedor
+	EnviarTexto(ctx context.Context, whatsapp, texto string) (string, error)
+}
+
+// ErroEnvio descreve uma falha de envio em termos de domínio.
+// Motivo é o texto gravado via Mensagem.MarcarComoFalha.
+type ErroEnvio struct {
+	Motivo     string
+	Retentavel bool // Falhas temporárias (timeout, 5xx, 429) podem ser retentadas
+	Err        error
+}
+
+func (e *ErroEnvio) Error() string {
+	if e.Err != nil {
+		return e.Motivo + ": " + e.Err.Error()
+	}
+	return e.Motivo
+}
+
+func (e *ErroEnvio) Unwrap() error {
+	return e.Err
+}
+
+// MotivoFalha extrai o motivo a ser registrado na mensagem
+func MotivoFalha(err error) string {
+	var erroEnvio *ErroEnvio
+	if errors.As(err, &erroEnvio) {
+		return erroEnvio.Motivo
+	}
+	return err.Error()
+}
+
+// EhRetentavel indica se vale a pena tentar o envio novamente.
+// Erros desconhecidos são tratados como temporários.
+func EhRetentavel(err error) bool {
+	var erroEnvio *ErroEnvio
+	if errors.As(err, &erroEnvio) {
+		return erroEnvio.Retentavel
+	}
+	return true
+}
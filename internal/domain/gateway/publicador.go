@@ -0,0 +1,14 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+)
+
+// PublicadorEventos é a porta de saída para divulgar eventos de domínio
+// a outros processos (ex: RabbitMQ).
+type PublicadorEventos interface {
+	// Publicar envia os eventos na ordem recebida
+	Publicar(ctx context.Context, eventos ...*entity.Event) error
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+)
+
+type AssinaturaRepository interface {
+	Save(assinatura *entity.Assinatura) error
+	// FindByID trava a assinatura até o fim da transação (FOR UPDATE no Postgres)
+	FindByID(id string) (*entity.Assinatura, error)
+	FindByClienteID(clienteID string) ([]*entity.Assinatura, error)
+
+	// FindParaFaturar retorna até limite assinaturas ativas cujo próximo vencimento é até ate
+	FindParaFaturar(ate time.Time, limite int) ([]*entity.Assinatura, error)
+
+	Update(assinatura *entity.Assinatura) error
+
+	// FaturaDoPeriodo retorna o id da fatura que faturou o período ("" se nenhuma)
+	FaturaDoPeriodo(assinaturaID, periodo string) (string, error)
+
+	// RegistrarPeriodo marca o período da assinatura como faturado pela fatura informada.
+	// Retorna false se o período já tinha fatura: é a garantia de que um ciclo nunca
+	// é faturado duas vezes, mesmo com o gerador rodando de novo ou em paralelo.
+	RegistrarPeriodo(assinaturaID, periodo, faturaID string) (bool, error)
+}
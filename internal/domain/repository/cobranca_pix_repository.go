@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/teusf/billing-system/internal/domain/entity"
+
+// CobrancaPixRepository guarda as cobranças Pix criadas no PSP para as faturas
+type CobrancaPixRepository interface {
+	// FindByFaturaID retorna a cobrança registrada para a fatura, ou nil
+	FindByFaturaID(faturaID string) (*entity.CobrancaPix, error)
+
+	// Save registra a cobrança da fatura, substituindo a anterior (ex.: cobrança recriada
+	// no PSP com um novo valor)
+	Save(cobranca *entity.CobrancaPix) error
+}
@@ -5,5 +5,6 @@ import "github.com/teusf/billing-system/internal/domain/entity"
 type ConfiguracaoRepository interface {
 	Save(config *entity.Configuracao) error
 	FindByUsuarioID(usuarioID string) (*entity.Configuracao, error)
+	FindAll() ([]*entity.Configuracao, error)
 	Update(config *entity.Configuracao) error
 }
@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/teusf/billing-system/internal/domain/entity"
+
+// ConfirmacaoRepository guarda quais pagamentos já tiveram a confirmação enviada ao cliente
+type ConfirmacaoRepository interface {
+	// FindByFaturaID retorna as confirmações da fatura, da mais antiga para a mais recente
+	FindByFaturaID(faturaID string) ([]*entity.ConfirmacaoPagamento, error)
+
+	// Registrar marca o pagamento como confirmado. Retorna false se ele já estava registrado:
+	// é a garantia de que um pagamento nunca gera duas confirmações.
+	Registrar(confirmacao *entity.ConfirmacaoPagamento) (bool, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+)
+
+// EntregaPendenteRepository guarda os status de entrega recebidos antes de o envio ser gravado
+type EntregaPendenteRepository interface {
+	// Travar serializa, até o fim da transação, o webhook e a gravação do envio do mesmo
+	// ID do provedor: ou o webhook encontra a mensagem, ou o envio encontra o status guardado.
+	Travar(idProvedor string) error
+
+	// Guardar registra o status. O mesmo status repetido para o mesmo ID não altera nada.
+	Guardar(entrega *entity.EntregaPendente) error
+
+	// Retirar remove e retorna os status guardados do ID, do mais antigo para o mais recente
+	Retirar(idProvedor string) ([]*entity.EntregaPendente, error)
+
+	// ExpurgarAntesDe remove os status recebidos antes de limite e retorna quantos removeu
+	ExpurgarAntesDe(limite time.Time) (int, error)
+}
@@ -1,7 +1,29 @@
 package repository
 
-// EventStore define o contrato para armazernar eventos de domínio.
-// Por enquanto interface genérica, será refinada na implementação.
+import (
+	"errors"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+)
+
+// ExpectedVersionAny desliga a checagem de concorrência no Append:
+// os eventos entram no fim do stream, qualquer que seja a versão atual.
+const ExpectedVersionAny = -1
+
+// ErrConflitoVersao indica que o stream mudou desde que o chamador o leu
+var ErrConflitoVersao = errors.New("conflito de versao no stream do agregado")
+
+// EventStore define o contrato para armazenar eventos de domínio.
+// Cada agregado tem um stream com versões 1, 2, 3...; Position é a ordem global.
 type EventStore interface {
-	Append(event interface{}) error
+	// Append grava os eventos no fim do stream, atribuindo Version a cada um.
+	// expectedVersion é a última versão que o chamador conhece (0 = stream novo);
+	// se o stream estiver em outra versão retorna ErrConflitoVersao.
+	Append(aggregateID string, expectedVersion int, events ...*entity.Event) error
+
+	// LoadStream retorna os eventos do agregado com Version >= fromVersion, em ordem
+	LoadStream(aggregateID string, fromVersion int) ([]*entity.Event, error)
+
+	// ReadAll retorna até limite eventos com Position > fromPosition, na ordem global
+	ReadAll(fromPosition int64, limite int) ([]*entity.Event, error)
 }
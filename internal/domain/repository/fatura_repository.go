@@ -1,12 +1,19 @@
 package repository
 
-import "github.com/teusf/billing-system/internal/domain/entity"
+import (
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+)
 
 type FaturaRepository interface {
 	Save(fatura *entity.Fatura) error
 	FindByID(id string) (*entity.Fatura, error)
 	FindByClienteID(clienteID string) ([]*entity.Fatura, error)
 	FindPendentes() ([]*entity.Fatura, error)
+	FindPendentesByUsuarioID(usuarioID string) ([]*entity.Fatura, error)
+	FindEmAbertoByUsuarioID(usuarioID string) ([]*entity.Fatura, error) // Pendentes, vencidas e parcialmente pagas
 	FindVencendoEm(dias int) ([]*entity.Fatura, error)
+	FindPendentesVencidas(ate time.Time, limite int) ([]*entity.Fatura, error)
 	Update(fatura *entity.Fatura) error
 }
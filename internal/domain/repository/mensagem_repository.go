@@ -1,11 +1,22 @@
 package repository
 
-import "github.com/teusf/billing-system/internal/domain/entity"
+import (
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+)
 
 type MensagemRepository interface {
 	Save(mensagem *entity.Mensagem) error
 	FindByID(id string) (*entity.Mensagem, error)
+
+	// FindByIDProvedor busca a mensagem pelo ID devolvido pelo provedor no envio.
+	// Dentro de uma transação, a linha fica travada até o fim dela: webhooks
+	// simultâneos da mesma mensagem são aplicados um de cada vez.
+	FindByIDProvedor(idProvedor string) (*entity.Mensagem, error)
 	FindByStatus(status entity.StatusMensagem) ([]*entity.Mensagem, error)
 	FindParaDLQ() ([]*entity.Mensagem, error)
+	ReivindicarParaEnvio(agora time.Time, lease time.Duration, limite int) ([]*entity.Mensagem, error)
+	ReivindicarPorID(id string, agora time.Time, lease time.Duration) (*entity.Mensagem, error)
 	Update(mensagem *entity.Mensagem) error
 }
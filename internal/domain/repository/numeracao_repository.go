@@ -0,0 +1,9 @@
+package repository
+
+// NumeracaoRepository controla as sequências de números de fatura de cada tenant
+type NumeracaoRepository interface {
+	// Proximo reserva o próximo número da série no período (1 na primeira chamada).
+	// Deve rodar na transação que grava a fatura: a sequência fica travada até o commit
+	// e, se a transação for desfeita, o número volta para a próxima fatura, sem buracos.
+	Proximo(usuarioID, serie string, periodo int) (int64, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/teusf/billing-system/internal/domain/entity"
+
+// Outbox dá acesso aos eventos gravados que ainda não foram publicados.
+type Outbox interface {
+	// ProcessarPendentes entrega a fn até limite eventos não publicados, na ordem em que foram gravados,
+	// e marca como publicados os primeiros n que fn retornar. Apenas um processamento roda por vez;
+	// se outro estiver em andamento, retorna sem chamar fn.
+	ProcessarPendentes(limite int, fn func(eventos []*entity.Event) (n int, err error)) error
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+)
+
+// ErrProjecaoOcupada indica que outra instância está processando a mesma projeção
+var ErrProjecaoOcupada = errors.New("projecao em processamento por outra instancia")
+
+// CheckpointRepository guarda até onde cada projeção já aplicou o event store
+type CheckpointRepository interface {
+	// Carregar retorna a Position do último evento aplicado (0 se a projeção nunca rodou)
+	Carregar(projecao string) (int64, error)
+	Salvar(projecao string, position int64) error
+}
+
+// RecebiveisRepository guarda o read model de contas a receber por cliente
+type RecebiveisRepository interface {
+	FindFatura(faturaID string) (*entity.RecebivelFatura, error)
+	SaveFatura(fatura *entity.RecebivelFatura) error
+	FindByClienteID(clienteID string) (*entity.RecebiveisCliente, error)
+	SaveCliente(recebiveis *entity.RecebiveisCliente) error
+	Limpar() error
+}
+
+// EstatisticasEnvioRepository guarda os contadores diários de envio de mensagens
+type EstatisticasEnvioRepository interface {
+	// Incrementar soma os contadores de delta aos do mesmo dia e tipo
+	Incrementar(delta *entity.EstatisticasEnvio) error
+	// FindPeriodo retorna as estatísticas dos dias entre de e ate (inclusive), por dia e tipo
+	FindPeriodo(de, ate time.Time) ([]*entity.EstatisticasEnvio, error)
+	Limpar() error
+}
+
+// LeituraRepositorios agrupa o checkpoint e os read models alterados por uma projeção
+type LeituraRepositorios struct {
+	Checkpoints       CheckpointRepository
+	Recebiveis        RecebiveisRepository
+	EstatisticasEnvio EstatisticasEnvioRepository
+}
+
+// ProjecaoUnitOfWork executa fn em uma transação reservada à projeção:
+// o read model e o checkpoint são gravados juntos, e duas instâncias nunca
+// processam a mesma projeção ao mesmo tempo. Se a projeção já estiver
+// reservada, retorna ErrProjecaoOcupada sem chamar fn.
+type ProjecaoUnitOfWork interface {
+	Executar(projecao string, fn func(repos LeituraRepositorios) error) error
+}
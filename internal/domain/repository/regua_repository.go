@@ -0,0 +1,16 @@
+package repository
+
+import "github.com/teusf/billing-system/internal/domain/entity"
+
+// ReguaRepository guarda quais etapas da régua de cobrança já rodaram em cada fatura
+type ReguaRepository interface {
+	// FindByFaturaID retorna as etapas executadas da fatura, da mais antiga para a mais recente
+	FindByFaturaID(faturaID string) ([]*entity.EtapaExecutada, error)
+
+	// Executada informa se a etapa (dias relativos ao vencimento) já rodou na fatura
+	Executada(faturaID string, dias int) (bool, error)
+
+	// Registrar marca a etapa como executada. Retorna false se ela já estava registrada:
+	// é a garantia de que uma etapa nunca manda duas mensagens para a mesma fatura.
+	Registrar(etapa *entity.EtapaExecutada) (bool, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+// Repositorios agrupa os repositórios que participam de uma mesma transação
+type Repositorios struct {
+	Clientes          ClienteRepository
+	Faturas           FaturaRepository
+	Mensagens         MensagemRepository
+	Configuracoes     ConfiguracaoRepository
+	Eventos           EventStore
+	Numeracao         NumeracaoRepository
+	Assinaturas       AssinaturaRepository
+	Regua             ReguaRepository
+	Confirmacoes      ConfirmacaoRepository
+	EntregasPendentes EntregaPendenteRepository
+	CobrancasPix      CobrancaPixRepository
+}
+
+// UnitOfWork executa fn dentro de uma transação.
+// Se fn retornar erro, nada do que foi feito pelos repositórios é persistido.
+type UnitOfWork interface {
+	Executar(fn func(repos Repositorios) error) error
+}
@@ -0,0 +1,5 @@
+-- Vincula o cliente ao usuario (tenant) dono da carteira.
+-- A configuracao do tenant (configuracoes.usuario_id) passa a valer para as faturas dos seus clientes.
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS usuario_id VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_clientes_usuario_id ON clientes(usuario_id);
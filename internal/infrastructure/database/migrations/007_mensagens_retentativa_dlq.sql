@@ -0,0 +1,9 @@
+-- Controle de retentativa do dispatcher: a mensagem só pode ser reivindicada a partir deste instante.
+-- Também funciona como "lease": ao reivindicar, o worker empurra o valor para frente.
+ALTER TABLE mensagens ADD COLUMN IF NOT EXISTS proxima_tentativa_em TIMESTAMP;
+
+-- Novo status para mensagens que esgotaram as tentativas (dead-letter)
+ALTER TABLE mensagens DROP CONSTRAINT IF EXISTS mensagens_status_check;
+ALTER TABLE mensagens ADD CONSTRAINT mensagens_status_check CHECK (status IN ('pendente', 'enviada', 'falha', 'dlq'));
+
+CREATE INDEX IF NOT EXISTS idx_mensagens_envio ON mensagens(status, proxima_tentativa_em);
@@ -0,0 +1,9 @@
+-- Outbox: a tabela events passa a ser a fila de publicação dos eventos de domínio.
+-- position dá a ordem global; transacao_id permite ao relay ignorar eventos de
+-- transações ainda abertas (que podem ter pego uma position menor).
+ALTER TABLE events ADD COLUMN IF NOT EXISTS position BIGSERIAL;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS transacao_id XID8 NOT NULL DEFAULT pg_current_xact_id();
+ALTER TABLE events ADD COLUMN IF NOT EXISTS published_at TIMESTAMP;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_position ON events(position);
+CREATE INDEX IF NOT EXISTS idx_events_nao_publicados ON events(position) WHERE published_at IS NULL;
@@ -0,0 +1,16 @@
+-- Streams por agregado: (aggregate_id, version) passa a ser único.
+-- Eventos antigos foram gravados sempre com version 1; renumera na ordem global antes da constraint.
+UPDATE events e
+SET version = r.rn
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY aggregate_id ORDER BY position) AS rn
+    FROM events
+) r
+WHERE e.id = r.id AND e.version <> r.rn;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'events_aggregate_version_unique') THEN
+        ALTER TABLE events ADD CONSTRAINT events_aggregate_version_unique UNIQUE (aggregate_id, version);
+    END IF;
+END $$;
@@ -0,0 +1,83 @@
+-- Fatura passa a ter o estado reconstruível pelo stream de eventos.
+-- faturas.versao guarda a versão do stream refletida na linha (controle otimista).
+ALTER TABLE faturas ADD COLUMN IF NOT EXISTS versao INTEGER NOT NULL DEFAULT 0;
+
+-- Faturas anteriores ao event sourcing: o stream precisa começar com FaturaCriada.
+-- Streams parciais (ex: só FaturaVencida) são deslocados para abrir espaço na versão 1.
+CREATE TEMP TABLE faturas_sem_criacao ON COMMIT DROP AS
+SELECT f.id
+FROM faturas f
+WHERE NOT EXISTS (
+    SELECT 1 FROM events e
+    WHERE e.aggregate_id = f.id::text AND e.event_type = 'FaturaCriada'
+);
+
+UPDATE events SET version = version + 1000000
+WHERE aggregate_id IN (SELECT id::text FROM faturas_sem_criacao);
+
+UPDATE events SET version = version - 1000000 + 1
+WHERE version > 1000000 AND aggregate_id IN (SELECT id::text FROM faturas_sem_criacao);
+
+-- Os eventos retroativos já nascem publicados: descrevem o passado, não são novidade para consumidores
+INSERT INTO events (id, event_type, aggregate_id, aggregate_type, event_data, metadata, timestamp, version, published_at)
+SELECT
+    gen_random_uuid(),
+    'FaturaCriada',
+    f.id::text,
+    'Fatura',
+    jsonb_build_object(
+        'fatura_id', f.id,
+        'cliente_id', f.cliente_id,
+        'numero', f.numero,
+        'descricao', COALESCE(f.descricao, ''),
+        'valor', f.valor,
+        'data_vencimento', to_char(f.data_vencimento, 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')
+    ),
+    '{"migracao": "010"}'::jsonb,
+    f.created_at,
+    1,
+    NOW()
+FROM faturas f
+WHERE f.id IN (SELECT id FROM faturas_sem_criacao);
+
+-- Faturas sem nenhum evento de estado recebem um evento com o status atual
+INSERT INTO events (id, event_type, aggregate_id, aggregate_type, event_data, metadata, timestamp, version, published_at)
+SELECT
+    gen_random_uuid(),
+    CASE f.status WHEN 'paga' THEN 'FaturaPaga' WHEN 'cancelada' THEN 'FaturaCancelada' ELSE 'FaturaVencida' END,
+    f.id::text,
+    'Fatura',
+    jsonb_build_object(
+        'fatura_id', f.id,
+        'cliente_id', f.cliente_id,
+        'numero', f.numero,
+        'valor', f.valor,
+        'data_vencimento', to_char(f.data_vencimento, 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"'),
+        'data_pagamento', to_char(COALESCE(f.data_pagamento, f.updated_at), 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')
+    ),
+    '{"migracao": "010"}'::jsonb,
+    f.updated_at,
+    2,
+    NOW()
+FROM faturas f
+WHERE f.status <> 'pendente'
+AND f.id IN (SELECT id FROM faturas_sem_criacao)
+AND NOT EXISTS (SELECT 1 FROM events e WHERE e.aggregate_id = f.id::text AND e.version > 1);
+
+INSERT INTO events (id, event_type, aggregate_id, aggregate_type, event_data, metadata, timestamp, version, published_at)
+SELECT
+    gen_random_uuid(),
+    'FaturaLembreteEnviado',
+    f.id::text,
+    'Fatura',
+    jsonb_build_object('fatura_id', f.id),
+    '{"migracao": "010"}'::jsonb,
+    f.updated_at,
+    (SELECT MAX(e.version) + 1 FROM events e WHERE e.aggregate_id = f.id::text),
+    NOW()
+FROM faturas f
+WHERE f.lembrete_enviado
+AND f.id IN (SELECT id FROM faturas_sem_criacao);
+
+UPDATE faturas f
+SET versao = COALESCE((SELECT MAX(e.version) FROM events e WHERE e.aggregate_id = f.id::text), 0);
@@ -0,0 +1,38 @@
+-- Projeções: read models alimentados pelo event store.
+-- Cada projeção guarda a position do último evento aplicado; as tabelas proj_*
+-- podem ser apagadas e reconstruídas a qualquer momento a partir dos eventos.
+CREATE TABLE IF NOT EXISTS projecao_checkpoints (
+    projecao VARCHAR(100) PRIMARY KEY,
+    position BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Recebíveis: situação de cada fatura (para saber de qual total tirar na transição) e totais por cliente
+CREATE TABLE IF NOT EXISTS proj_recebiveis_faturas (
+    fatura_id UUID PRIMARY KEY,
+    cliente_id UUID NOT NULL,
+    valor DECIMAL(10, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS proj_recebiveis_clientes (
+    cliente_id UUID PRIMARY KEY,
+    quantidade_em_aberto INTEGER NOT NULL DEFAULT 0,
+    valor_em_aberto DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    quantidade_vencida INTEGER NOT NULL DEFAULT 0,
+    valor_vencido DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    quantidade_paga INTEGER NOT NULL DEFAULT 0,
+    valor_recebido DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Estatísticas de envio de mensagens por dia e tipo
+CREATE TABLE IF NOT EXISTS proj_estatisticas_envio (
+    dia DATE NOT NULL,
+    tipo VARCHAR(20) NOT NULL,
+    enfileiradas INTEGER NOT NULL DEFAULT 0,
+    enviadas INTEGER NOT NULL DEFAULT 0,
+    falhas INTEGER NOT NULL DEFAULT 0,
+    dlq INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (dia, tipo)
+);
@@ -0,0 +1,3 @@
+-- Versão do formato do payload (event_data). Eventos antigos ficam gravados como foram
+-- emitidos e são convertidos para a versão atual na leitura (upcasters do RegistroEventos).
+ALTER TABLE events ADD COLUMN IF NOT EXISTS schema_version INTEGER NOT NULL DEFAULT 1;
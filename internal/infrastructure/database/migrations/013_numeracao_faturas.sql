@@ -0,0 +1,28 @@
+-- Numeração sequencial de faturas por tenant e série (o prefixo do formato).
+-- O contador é incrementado na transação que insere a fatura; como o UPDATE trava a linha
+-- até o commit e é desfeito no rollback, a sequência não tem buracos.
+-- periodo = ano quando o formato inclui o ano (a sequência recomeça a cada ano), ou 0.
+CREATE TABLE IF NOT EXISTS numeracao_faturas (
+    usuario_id VARCHAR(100) NOT NULL, -- '' para clientes sem tenant
+    serie VARCHAR(10) NOT NULL,
+    periodo INTEGER NOT NULL,
+    ultimo BIGINT NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (usuario_id, serie, periodo)
+);
+
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS numeracao_prefixo VARCHAR(10) NOT NULL DEFAULT 'FAT';
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS numeracao_incluir_ano BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS numeracao_digitos INTEGER NOT NULL DEFAULT 6;
+
+-- Tenants diferentes podem ter o mesmo número: a unicidade passa a ser por tenant.
+-- Os números antigos (FAT-AAAAMMDD-NNNNNN) não colidem com o novo formato (FAT-AAAA-NNNNNN).
+ALTER TABLE faturas ADD COLUMN IF NOT EXISTS usuario_id VARCHAR(100) NOT NULL DEFAULT '';
+
+UPDATE faturas f
+SET usuario_id = COALESCE(c.usuario_id, '')
+FROM clientes c
+WHERE c.id = f.cliente_id;
+
+ALTER TABLE faturas DROP CONSTRAINT IF EXISTS faturas_numero_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_faturas_usuario_numero ON faturas(usuario_id, numero);
@@ -0,0 +1,13 @@
+-- Itens da fatura. Subtotal, descontos, impostos e total são calculados a partir deles
+-- (faturas.valor guarda o total, que é validado contra os itens na criação).
+-- Faturas sem itens continuam valendo: o valor inteiro é o subtotal.
+CREATE TABLE IF NOT EXISTS fatura_itens (
+    fatura_id UUID NOT NULL REFERENCES faturas(id) ON DELETE CASCADE,
+    posicao INTEGER NOT NULL, -- Ordem do item na fatura, a partir de 1
+    descricao TEXT NOT NULL,
+    quantidade BIGINT NOT NULL CHECK (quantidade > 0),
+    preco_unitario DECIMAL(12, 2) NOT NULL CHECK (preco_unitario > 0),
+    desconto DECIMAL(12, 2) NOT NULL DEFAULT 0 CHECK (desconto >= 0),
+    aliquota_imposto DECIMAL(5, 2) NOT NULL DEFAULT 0 CHECK (aliquota_imposto BETWEEN 0 AND 100),
+    PRIMARY KEY (fatura_id, posicao)
+);
@@ -0,0 +1,5 @@
+-- Multa e juros de mora cobrados nas faturas em atraso, por tenant.
+-- Percentuais com duas casas (2.00 = 2%); o padrão é não cobrar encargos.
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS multa DECIMAL(5, 2) NOT NULL DEFAULT 0;
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS juros_ao_mes DECIMAL(5, 2) NOT NULL DEFAULT 0;
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS dias_carencia INTEGER NOT NULL DEFAULT 0;
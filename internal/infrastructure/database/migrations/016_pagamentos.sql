@@ -0,0 +1,26 @@
+-- Pagamentos da fatura. Uma fatura pode receber vários; ela é liquidada quando
+-- eles cobrem o valor devido. Estornos ficam na própria linha (estornado_em).
+ALTER TABLE faturas DROP CONSTRAINT IF EXISTS faturas_status_check;
+ALTER TABLE faturas ADD CONSTRAINT faturas_status_check
+    CHECK (status IN ('pendente', 'parcialmente_paga', 'paga', 'vencida', 'cancelada'));
+
+CREATE TABLE IF NOT EXISTS pagamentos (
+    id UUID PRIMARY KEY,
+    fatura_id UUID NOT NULL REFERENCES faturas(id) ON DELETE CASCADE,
+    valor DECIMAL(12, 2) NOT NULL CHECK (valor > 0),
+    metodo VARCHAR(20) NOT NULL,
+    pago_em TIMESTAMP NOT NULL,
+    referencia_externa VARCHAR(100) NOT NULL DEFAULT '', -- Vazia quando o meio de pagamento não informa
+    estornado_em TIMESTAMP,
+    motivo_estorno TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_pagamentos_fatura_id ON pagamentos(fatura_id);
+
+-- A mesma confirmação do meio de pagamento não pode ser lançada duas vezes na fatura
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pagamentos_referencia
+    ON pagamentos(fatura_id, referencia_externa) WHERE referencia_externa <> '';
+
+-- Recebíveis: o recebido passa a ser acumulado por pagamento
+ALTER TABLE proj_recebiveis_faturas ADD COLUMN IF NOT EXISTS recebido DECIMAL(12, 2) NOT NULL DEFAULT 0;
+UPDATE proj_recebiveis_faturas SET recebido = valor WHERE status = 'paga';
@@ -0,0 +1,6 @@
+-- Dados de recebimento Pix do tenant, usados no BR Code das faturas.
+-- Sem chave, o tenant não cobra por Pix. pix_url_cobranca habilita o Pix dinâmico.
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS pix_chave VARCHAR(77) NOT NULL DEFAULT '';
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS pix_nome_recebedor VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS pix_cidade VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS pix_url_cobranca VARCHAR(77) NOT NULL DEFAULT '';
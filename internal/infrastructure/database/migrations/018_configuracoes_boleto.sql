@@ -0,0 +1,5 @@
+-- Conta de cobrança do tenant, usada no código de barras e na linha digitável dos boletos.
+-- Sem banco, o tenant não emite boleto.
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS boleto_banco CHAR(3) NOT NULL DEFAULT '';
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS boleto_convenio VARCHAR(7) NOT NULL DEFAULT '';
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS boleto_carteira VARCHAR(2) NOT NULL DEFAULT '';
@@ -0,0 +1,31 @@
+-- Assinaturas: cobrança recorrente que gera uma fatura por ciclo.
+-- proximo_vencimento é derivado de proximo_ciclo e fica gravado para o gerador filtrar por ele.
+CREATE TABLE IF NOT EXISTS assinaturas (
+    id UUID PRIMARY KEY,
+    cliente_id UUID NOT NULL REFERENCES clientes(id),
+    valor DECIMAL(12, 2) NOT NULL CHECK (valor > 0),
+    descricao TEXT NOT NULL,
+    periodicidade VARCHAR(20) NOT NULL CHECK (periodicidade IN ('mensal', 'trimestral', 'semestral', 'anual')),
+    dia_vencimento INTEGER NOT NULL CHECK (dia_vencimento BETWEEN 1 AND 31),
+    data_inicio TIMESTAMP NOT NULL,
+    data_fim TIMESTAMP,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('ativa', 'pausada', 'cancelada', 'encerrada')),
+    proximo_ciclo INTEGER NOT NULL DEFAULT 0,
+    proximo_vencimento TIMESTAMP NOT NULL,
+    versao INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_assinaturas_cliente_id ON assinaturas(cliente_id);
+CREATE INDEX IF NOT EXISTS idx_assinaturas_a_faturar ON assinaturas(proximo_vencimento) WHERE status = 'ativa';
+
+-- Um período (AAAA-MM do vencimento) de cada assinatura tem no máximo uma fatura.
+-- A linha é gravada na transação que cria a fatura: re-execuções do gerador não duplicam.
+CREATE TABLE IF NOT EXISTS assinatura_periodos (
+    assinatura_id UUID NOT NULL REFERENCES assinaturas(id) ON DELETE CASCADE,
+    periodo CHAR(7) NOT NULL,
+    fatura_id UUID NOT NULL REFERENCES faturas(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (assinatura_id, periodo)
+);
@@ -0,0 +1,13 @@
+-- Régua de cobrança do tenant: lista JSON de etapas {dias, tipo, template}.
+-- Vazia, o tenant segue com o lembrete único e a cobrança no vencimento.
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS regua_cobranca JSONB NOT NULL DEFAULT '[]';
+
+-- Etapas da régua já executadas em cada fatura (dias relativos ao vencimento: -5, 0, 3...).
+-- A linha é gravada na transação que enfileira a mensagem: a etapa nunca envia duas vezes.
+CREATE TABLE IF NOT EXISTS fatura_etapas_cobranca (
+    fatura_id UUID NOT NULL REFERENCES faturas(id),
+    dias INTEGER NOT NULL,
+    mensagem_id UUID NOT NULL REFERENCES mensagens(id),
+    executada_em TIMESTAMP NOT NULL,
+    PRIMARY KEY (fatura_id, dias)
+);
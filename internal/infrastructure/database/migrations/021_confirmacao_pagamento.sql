@@ -0,0 +1,14 @@
+-- Template da confirmação de pagamento enviada ao cliente. Vazio = comprovante padrão.
+ALTER TABLE configuracoes ADD COLUMN IF NOT EXISTS template_confirmacao TEXT NOT NULL DEFAULT '';
+
+-- Confirmações de pagamento já enfileiradas, uma por pagamento que liquidou a fatura.
+-- A linha é gravada na transação do pagamento, antes da mensagem: a chave em pagamento_id
+-- impede a segunda confirmação, e a FK da mensagem só é conferida no commit.
+CREATE TABLE IF NOT EXISTS confirmacoes_pagamento (
+    pagamento_id UUID PRIMARY KEY REFERENCES pagamentos(id),
+    fatura_id UUID NOT NULL REFERENCES faturas(id),
+    mensagem_id UUID NOT NULL REFERENCES mensagens(id) DEFERRABLE INITIALLY DEFERRED,
+    enviada_em TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_confirmacoes_pagamento_fatura_id ON confirmacoes_pagamento(fatura_id);
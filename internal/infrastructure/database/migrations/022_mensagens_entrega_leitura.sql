@@ -0,0 +1,12 @@
+-- ID da mensagem no provedor (Evolution), devolvido no envio. Os webhooks de status
+-- chegam com ele; vazio nas mensagens ainda não enviadas e nas anteriores a esta migration.
+ALTER TABLE mensagens ADD COLUMN IF NOT EXISTS id_provedor VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE mensagens ADD COLUMN IF NOT EXISTS entregue_em TIMESTAMP;
+ALTER TABLE mensagens ADD COLUMN IF NOT EXISTS lida_em TIMESTAMP;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mensagens_id_provedor ON mensagens(id_provedor) WHERE id_provedor <> '';
+
+-- Status informados pelo provedor depois do envio
+ALTER TABLE mensagens DROP CONSTRAINT IF EXISTS mensagens_status_check;
+ALTER TABLE mensagens ADD CONSTRAINT mensagens_status_check
+    CHECK (status IN ('pendente', 'enviada', 'falha', 'dlq', 'entregue', 'lida'));
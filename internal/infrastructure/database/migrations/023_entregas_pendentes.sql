@@ -0,0 +1,12 @@
+-- Status de entrega que chegam pelo webhook antes de o envio ser gravado (o provedor pode
+-- responder ao envio e chamar o webhook antes do commit do id_provedor). São aplicados
+-- na gravação do envio; os que nenhum envio reclama são expurgados depois de um tempo.
+CREATE TABLE IF NOT EXISTS entregas_pendentes (
+    id_provedor VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('entregue', 'lida')),
+    em TIMESTAMP NOT NULL,
+    recebido_em TIMESTAMP NOT NULL,
+    PRIMARY KEY (id_provedor, status)
+);
+
+CREATE INDEX IF NOT EXISTS idx_entregas_pendentes_recebido_em ON entregas_pendentes(recebido_em);
@@ -0,0 +1,10 @@
+-- Cobranças Pix (cob) criadas no PSP do tenant para as faturas. O Pix dinâmico aponta para
+-- a location que o PSP devolveu na criação da cobrança. A URL base por tenant deixa de
+-- existir: acrescentar o txid a ela gerava um endereço que o PSP nunca criou.
+CREATE TABLE IF NOT EXISTS cobrancas_pix (
+    fatura_id UUID PRIMARY KEY REFERENCES faturas(id) ON DELETE CASCADE,
+    location VARCHAR(77) NOT NULL,
+    registrada_em TIMESTAMP NOT NULL
+);
+
+ALTER TABLE configuracoes DROP COLUMN IF EXISTS pix_url_cobranca;
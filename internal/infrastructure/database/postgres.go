@@ -93,7 +93,10 @@ func createDatabase(cfg *config.Config, logger *zap.Logger) error {
 	return nil
 }
 
-// RunMigrations executa os arquivos .sql na ordem correta
+// RunMigrations executa os arquivos .sql na ordem correta.
+// Cada arquivo é aplicado uma única vez e registrado em schema_migrations. Num banco
+// anterior ao controle, todos rodam uma última vez: as migrations dessa época podem
+// ser repetidas.
 func RunMigrations(db *sql.DB, migrationsPath string, logger *zap.Logger) error {
 	files, err := os.ReadDir(migrationsPath)
 	if err != nil {
@@ -105,6 +108,15 @@ func RunMigrations(db *sql.DB, migrationsPath string, logger *zap.Logger) error
 		return files[i].Name() < files[j].Name()
 	})
 
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		return fmt.Errorf("erro ao criar tabela schema_migrations: %w", err)
+	}
+
 	for _, file := range files {
 		if !strings.HasSuffix(file.Name(), ".sql") {
 			continue
@@ -112,6 +124,14 @@ func RunMigrations(db *sql.DB, migrationsPath string, logger *zap.Logger) error
 
 		logger.Info("Verificando migration", zap.String("file", file.Name()))
 
+		var aplicada bool
+		if err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", file.Name()).Scan(&aplicada); err != nil {
+			return fmt.Errorf("erro ao verificar migration %s: %w", file.Name(), err)
+		}
+		if aplicada {
+			continue
+		}
+
 		content, err := os.ReadFile(filepath.Join(migrationsPath, file.Name()))
 		if err != nil {
 			return fmt.Errorf("erro ao ler arquivo %s: %w", file.Name(), err)
@@ -120,9 +140,24 @@ func RunMigrations(db *sql.DB, migrationsPath string, logger *zap.Logger) error
 		// Divide o arquivo em comandos separados se necessário, mas para este projeto
 		// assumimos que cada arquivo pode rodar inteiro (separado por ;)
 		// ou é um comando só. Executar tudo de uma vez costuma funcionar para DDL simples.
-		if _, err := db.Exec(string(content)); err != nil {
+		// A migration e o registro em schema_migrations são gravados na mesma transação.
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("erro ao iniciar transacao da migration %s: %w", file.Name(), err)
+		}
+		if _, err := tx.Exec(string(content)); err != nil {
+			tx.Rollback()
 			return fmt.Errorf("erro ao executar migration %s: %w", file.Name(), err)
 		}
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", file.Name()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("erro ao registrar migration %s: %w", file.Name(), err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("erro ao commitar migration %s: %w", file.Name(), err)
+		}
+
+		logger.Info("Migration aplicada", zap.String("file", file.Name()))
 	}
 
 	logger.Info("Todas as migrations verificadas/executadas com sucesso")
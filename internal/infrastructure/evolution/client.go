@@ -0,0 +1,163 @@
+package evolution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/gateway"
+)
+
+var _ gateway.Mensageiro = (*Client)(nil)
+
+// Client fala com a Evolution API (WhatsApp) usando a API key global
+type Client struct {
+	baseURL    string
+	apiKey     string
+	instance   string
+	httpClient *http.Client
+}
+
+func NewClient(baseURL, apiKey, instance string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		instance:   instance,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type sendTextRequest struct {
+	Number string `json:"number"`
+	Text   string `json:"text"`
+}
+
+type sendTextResponse struct {
+	Key struct {
+		RemoteJID string `json:"remoteJid"`
+		FromMe    bool   `json:"fromMe"`
+		ID        string `json:"id"`
+	} `json:"key"`
+	Status string `json:"status"`
+}
+
+// errorResponse é o formato de erro da Evolution API.
+// response.message pode ser string, lista de strings ou lista de objetos (ex: número inexistente).
+type errorResponse struct {
+	Status   int    `json:"status"`
+	Error    string `json:"error"`
+	Response struct {
+		Message json.RawMessage `json:"message"`
+	} `json:"response"`
+}
+
+// EnviarTexto chama POST /message/sendText/{instance}
+func (c *Client) EnviarTexto(ctx context.Context, whatsapp, texto string) (string, error) {
+	body, err := json.Marshal(sendTextRequest{Number: whatsapp, Text: texto})
+	if err != nil {
+		return "", &gateway.ErroEnvio{Motivo: "erro ao montar requisicao", Err: err}
+	}
+
+	endpoint := fmt.Sprintf("%s/message/sendText/%s", c.baseURL, url.PathEscape(c.instance))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", &gateway.ErroEnvio{Motivo: "erro ao montar requisicao", Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("apikey", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", &gateway.ErroEnvio{Motivo: "falha de conexao com evolution api", Retentavel: true, Err: err}
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", &gateway.ErroEnvio{Motivo: "falha ao ler resposta da evolution api", Retentavel: true, Err: err}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", mapearErro(resp.StatusCode, raw)
+	}
+
+	var out sendTextResponse
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return "", &gateway.ErroEnvio{Motivo: "resposta invalida da evolution api", Err: err}
+	}
+	if out.Key.ID == "" {
+		return "", &gateway.ErroEnvio{Motivo: "resposta da evolution api sem id da mensagem"}
+	}
+
+	return out.Key.ID, nil
+}
+
+// mapearErro traduz status HTTP e corpo de erro da Evolution em motivos de falha do domínio
+func mapearErro(status int, raw []byte) error {
+	detalhe := extrairDetalhe(raw)
+	err := fmt.Errorf("status %d: %s", status, detalhe)
+
+	switch {
+	case status == http.StatusBadRequest && numeroInexistente(raw):
+		return &gateway.ErroEnvio{Motivo: "numero nao possui whatsapp", Err: err}
+	case status == http.StatusBadRequest:
+		return &gateway.ErroEnvio{Motivo: "requisicao rejeitada pela evolution api", Err: err}
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return &gateway.ErroEnvio{Motivo: "evolution api nao autorizada (verifique a api key)", Err: err}
+	case status == http.StatusNotFound:
+		return &gateway.ErroEnvio{Motivo: "instancia da evolution api nao encontrada", Err: err}
+	case status == http.StatusTooManyRequests:
+		return &gateway.ErroEnvio{Motivo: "limite de envios da evolution api atingido", Retentavel: true, Err: err}
+	case status >= 500:
+		return &gateway.ErroEnvio{Motivo: "evolution api indisponivel", Retentavel: true, Err: err}
+	default:
+		return &gateway.ErroEnvio{Motivo: "erro inesperado da evolution api", Err: err}
+	}
+}
+
+func extrairDetalhe(raw []byte) string {
+	var e errorResponse
+	if err := json.Unmarshal(raw, &e); err != nil || len(e.Response.Message) == 0 {
+		return strings.TrimSpace(string(raw))
+	}
+
+	var texto string
+	if err := json.Unmarshal(e.Response.Message, &texto); err == nil {
+		return texto
+	}
+
+	var lista []string
+	if err := json.Unmarshal(e.Response.Message, &lista); err == nil {
+		return strings.Join(lista, "; ")
+	}
+
+	return string(e.Response.Message)
+}
+
+// numeroInexistente detecta a resposta [{"exists": false, ...}] da Evolution
+func numeroInexistente(raw []byte) bool {
+	var e errorResponse
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return false
+	}
+
+	var itens []struct {
+		Exists *bool `json:"exists"`
+	}
+	if err := json.Unmarshal(e.Response.Message, &itens); err != nil {
+		return false
+	}
+
+	for _, item := range itens {
+		if item.Exists != nil && !*item.Exists {
+			return true
+		}
+	}
+	return false
+}
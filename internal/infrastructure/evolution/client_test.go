@@ -0,0 +1,112 @@
+package evolution
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/gateway"
+	"github.com/teusf/billing-system/internal/infrastructure/evolution/evolutiontest"
+)
+
+func TestClient_EnviarTexto(t *testing.T) {
+	srv := evolutiontest.NewServer("chave", "instance1")
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "chave", "instance1")
+
+	id, err := c.EnviarTexto(context.Background(), "5511999998888", "Ola")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, id)
+
+	recebidas := srv.Recebidas()
+	assert.Len(t, recebidas, 1)
+	assert.Equal(t, "5511999998888", recebidas[0].Number)
+	assert.Equal(t, "Ola", recebidas[0].Text)
+	assert.Equal(t, id, recebidas[0].ID)
+}
+
+func TestClient_MapeamentoDeErros(t *testing.T) {
+	srv := evolutiontest.NewServer("chave", "instance1")
+	defer srv.Close()
+
+	casos := []struct {
+		nome       string
+		client     *Client
+		forcar     []evolutiontest.Resposta
+		motivo     string
+		retentavel bool
+	}{
+		{
+			nome:   "api key invalida",
+			client: NewClient(srv.URL, "errada", "instance1"),
+			motivo: "evolution api nao autorizada (verifique a api key)",
+		},
+		{
+			nome:   "instancia inexistente",
+			client: NewClient(srv.URL, "chave", "outra"),
+			motivo: "instancia da evolution api nao encontrada",
+		},
+		{
+			nome:   "numero sem whatsapp",
+			client: NewClient(srv.URL, "chave", "instance1"),
+			forcar: []evolutiontest.Resposta{evolutiontest.NumeroInexistente("5511999998888")},
+			motivo: "numero nao possui whatsapp",
+		},
+		{
+			nome:       "rate limit",
+			client:     NewClient(srv.URL, "chave", "instance1"),
+			forcar:     []evolutiontest.Resposta{{Status: http.StatusTooManyRequests, Body: `{}`}},
+			motivo:     "limite de envios da evolution api atingido",
+			retentavel: true,
+		},
+		{
+			nome:       "erro interno",
+			client:     NewClient(srv.URL, "chave", "instance1"),
+			forcar:     []evolutiontest.Resposta{{Status: http.StatusBadGateway, Body: `bad gateway`}},
+			motivo:     "evolution api indisponivel",
+			retentavel: true,
+		},
+		{
+			nome:       "servidor fora do ar",
+			client:     NewClient("http://127.0.0.1:1", "chave", "instance1"),
+			motivo:     "falha de conexao com evolution api",
+			retentavel: true,
+		},
+	}
+
+	for _, tc := range casos {
+		t.Run(tc.nome, func(t *testing.T) {
+			srv.FalharCom(tc.forcar...)
+
+			_, err := tc.client.EnviarTexto(context.Background(), "5511999998888", "Ola")
+			assert.Error(t, err)
+			assert.Equal(t, tc.motivo, gateway.MotivoFalha(err))
+			assert.Equal(t, tc.retentavel, gateway.EhRetentavel(err))
+
+			// O motivo é o que vai para a mensagem
+			msg, _ := entity.NewMensagem("fat-1", "cli-1", "5511999998888", "Ola", entity.TipoMensagemLembrete)
+			msg.MarcarComoFalha(gateway.MotivoFalha(err))
+			assert.Equal(t, tc.motivo, msg.ErroMensagem)
+		})
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	srv := evolutiontest.NewServer("chave", "instance1")
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "chave", "instance1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+
+	_, err := c.EnviarTexto(ctx, "5511999998888", "Ola")
+	assert.Error(t, err)
+	assert.True(t, gateway.EhRetentavel(err))
+}
@@ -0,0 +1,123 @@
+// Package evolutiontest fornece um servidor fake da Evolution API baseado em httptest,
+// para testar o adapter e os workers de envio sem acesso à rede.
+package evolutiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// MensagemRecebida é uma mensagem aceita pelo servidor fake
+type MensagemRecebida struct {
+	ID     string
+	Number string
+	Text   string
+}
+
+// Resposta permite forçar o status e o corpo da próxima resposta
+type Resposta struct {
+	Status int
+	Body   string
+}
+
+type Server struct {
+	*httptest.Server
+
+	APIKey   string
+	Instance string
+
+	mu        sync.Mutex
+	recebidas []MensagemRecebida
+	forcadas  []Resposta
+	seq       int
+}
+
+// NewServer sobe o fake aceitando apenas a apiKey e a instância informadas
+func NewServer(apiKey, instance string) *Server {
+	s := &Server{APIKey: apiKey, Instance: instance}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// FalharCom enfileira respostas que serão devolvidas antes do comportamento normal
+func (s *Server) FalharCom(respostas ...Resposta) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forcadas = append(s.forcadas, respostas...)
+}
+
+// Recebidas retorna as mensagens aceitas até agora
+func (s *Server) Recebidas() []MensagemRecebida {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]MensagemRecebida(nil), s.recebidas...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.forcadas) > 0 {
+		resp := s.forcadas[0]
+		s.forcadas = s.forcadas[1:]
+		writeRaw(w, resp.Status, resp.Body)
+		return
+	}
+
+	if r.Header.Get("apikey") != s.APIKey {
+		writeError(w, http.StatusUnauthorized, "Unauthorized", `"Unauthorized"`)
+		return
+	}
+
+	prefix := "/message/sendText/"
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.URL.Path, prefix) {
+		writeError(w, http.StatusNotFound, "Not Found", `["Cannot `+r.Method+` `+r.URL.Path+`"]`)
+		return
+	}
+
+	instance := strings.TrimPrefix(r.URL.Path, prefix)
+	if instance != s.Instance {
+		writeError(w, http.StatusNotFound, "Not Found", fmt.Sprintf(`["The \"%s\" instance does not exist"]`, instance))
+		return
+	}
+
+	var body struct {
+		Number string `json:"number"`
+		Text   string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Number == "" || body.Text == "" {
+		writeError(w, http.StatusBadRequest, "Bad Request", `["number and text are required"]`)
+		return
+	}
+
+	s.seq++
+	id := fmt.Sprintf("FAKE%06d", s.seq)
+	s.recebidas = append(s.recebidas, MensagemRecebida{ID: id, Number: body.Number, Text: body.Text})
+
+	writeRaw(w, http.StatusCreated, fmt.Sprintf(
+		`{"key":{"remoteJid":"%s@s.whatsapp.net","fromMe":true,"id":"%s"},"message":{"conversation":%q},"status":"PENDING"}`,
+		body.Number, id, body.Text,
+	))
+}
+
+// NumeroInexistente é o corpo devolvido pela Evolution quando o número não tem WhatsApp
+func NumeroInexistente(numero string) Resposta {
+	return Resposta{
+		Status: http.StatusBadRequest,
+		Body:   fmt.Sprintf(`{"status":400,"error":"Bad Request","response":{"message":[{"exists":false,"jid":"%s@s.whatsapp.net","number":"%s"}]}}`, numero, numero),
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, errText, message string) {
+	writeRaw(w, status, fmt.Sprintf(`{"status":%d,"error":"%s","response":{"message":%s}}`, status, errText, message))
+}
+
+func writeRaw(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
@@ -0,0 +1,242 @@
+package assinatura
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/http/shared"
+)
+
+type AssinaturaHandler struct {
+	repo        repository.AssinaturaRepository
+	clienteRepo repository.ClienteRepository
+	uow         repository.UnitOfWork // Escritas gravam a assinatura e os eventos juntos
+	logger      *zap.Logger
+}
+
+func NewAssinaturaHandler(repo repository.AssinaturaRepository, clienteRepo repository.ClienteRepository, uow repository.UnitOfWork, logger *zap.Logger) *AssinaturaHandler {
+	return &AssinaturaHandler{repo: repo, clienteRepo: clienteRepo, uow: uow, logger: logger}
+}
+
+// Routes monta as rotas do recurso /assinaturas
+func (h *AssinaturaHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/", h.Create)
+	r.Get("/", h.ListByCliente)
+	r.Get("/{id}", h.Get)
+	r.Post("/{id}/pausar", h.Pausar)
+	r.Post("/{id}/retomar", h.Retomar)
+	r.Post("/{id}/cancelar", h.Cancelar)
+
+	return r
+}
+
+type assinaturaRequest struct {
+	ClienteID     string               `json:"cliente_id"`
+	Valor         entity.Dinheiro      `json:"valor"`
+	Descricao     string               `json:"descricao"`
+	Periodicidade entity.Periodicidade `json:"periodicidade"`
+	DiaVencimento int                  `json:"dia_vencimento"`
+	DataInicio    *time.Time           `json:"data_inicio"` // Omitida = hoje
+	DataFim       *time.Time           `json:"data_fim"`
+}
+
+type assinaturaResponse struct {
+	ID                string                  `json:"id"`
+	ClienteID         string                  `json:"cliente_id"`
+	Valor             entity.Dinheiro         `json:"valor"`
+	Descricao         string                  `json:"descricao"`
+	Periodicidade     entity.Periodicidade    `json:"periodicidade"`
+	DiaVencimento     int                     `json:"dia_vencimento"`
+	DataInicio        time.Time               `json:"data_inicio"`
+	DataFim           *time.Time              `json:"data_fim,omitempty"`
+	Status            entity.StatusAssinatura `json:"status"`
+	ProximoVencimento *time.Time              `json:"proximo_vencimento,omitempty"` // Só enquanto há ciclos a faturar
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+}
+
+func toResponse(a *entity.Assinatura) assinaturaResponse {
+	resp := assinaturaResponse{
+		ID:            a.ID,
+		ClienteID:     a.ClienteID,
+		Valor:         a.Valor,
+		Descricao:     a.Descricao,
+		Periodicidade: a.Periodicidade,
+		DiaVencimento: a.DiaVencimento,
+		DataInicio:    a.DataInicio,
+		DataFim:       a.DataFim,
+		Status:        a.Status,
+		CreatedAt:     a.CreatedAt,
+		UpdatedAt:     a.UpdatedAt,
+	}
+	if a.Status == entity.StatusAssinaturaAtiva || a.Status == entity.StatusAssinaturaPausada {
+		proximo := a.ProximoVencimento()
+		resp.ProximoVencimento = &proximo
+	}
+	return resp
+}
+
+func (h *AssinaturaHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req assinaturaRequest
+	if err := shared.DecodeJSON(r, &req); err != nil {
+		shared.WriteError(w, http.StatusBadRequest, "json_invalido", err.Error())
+		return
+	}
+
+	if !h.clienteExiste(w, req.ClienteID) {
+		return
+	}
+
+	inicio := time.Now()
+	if req.DataInicio != nil {
+		inicio = *req.DataInicio
+	}
+
+	assinatura, err := entity.NewAssinatura(req.ClienteID, req.Valor, req.Descricao, req.Periodicidade, req.DiaVencimento, inicio, req.DataFim)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	err = h.uow.Executar(func(repos repository.Repositorios) error {
+		return repos.Assinaturas.Save(assinatura)
+	})
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusCreated, toResponse(assinatura))
+}
+
+func (h *AssinaturaHandler) ListByCliente(w http.ResponseWriter, r *http.Request) {
+	clienteID := r.URL.Query().Get("cliente_id")
+	if clienteID == "" {
+		shared.WriteError(w, http.StatusBadRequest, "cliente_id_obrigatorio", "informe o parametro cliente_id")
+		return
+	}
+	if _, err := uuid.Parse(clienteID); err != nil {
+		shared.WriteJSON(w, http.StatusOK, []assinaturaResponse{})
+		return
+	}
+
+	assinaturas, err := h.repo.FindByClienteID(clienteID)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	resp := make([]assinaturaResponse, 0, len(assinaturas))
+	for _, a := range assinaturas {
+		resp = append(resp, toResponse(a))
+	}
+
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *AssinaturaHandler) Get(w http.ResponseWriter, r *http.Request) {
+	assinatura, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toResponse(assinatura))
+}
+
+func (h *AssinaturaHandler) Pausar(w http.ResponseWriter, r *http.Request) {
+	h.alterarStatus(w, r, (*entity.Assinatura).Pausar)
+}
+
+func (h *AssinaturaHandler) Retomar(w http.ResponseWriter, r *http.Request) {
+	h.alterarStatus(w, r, func(a *entity.Assinatura) error {
+		return a.Retomar(time.Now())
+	})
+}
+
+func (h *AssinaturaHandler) Cancelar(w http.ResponseWriter, r *http.Request) {
+	h.alterarStatus(w, r, (*entity.Assinatura).Cancelar)
+}
+
+// alterarStatus aplica a transição e persiste a assinatura com os eventos dela na mesma
+// transação. A assinatura é lida (e travada) dentro da transação, então a transição parte
+// do estado atual mesmo com o gerador de faturas avançando o ciclo em paralelo.
+// Transições inválidas são mapeadas para 409 pelo shared.HandleError.
+func (h *AssinaturaHandler) alterarStatus(w http.ResponseWriter, r *http.Request, acao func(*entity.Assinatura) error) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		shared.WriteError(w, http.StatusNotFound, "assinatura_nao_encontrada", "assinatura nao encontrada")
+		return
+	}
+
+	var assinatura *entity.Assinatura
+	err := h.uow.Executar(func(repos repository.Repositorios) error {
+		var err error
+		assinatura, err = repos.Assinaturas.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if assinatura == nil {
+			return entity.ErrAssinaturaNaoEncontrada
+		}
+
+		if err := acao(assinatura); err != nil {
+			return err
+		}
+		return repos.Assinaturas.Update(assinatura)
+	})
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toResponse(assinatura))
+}
+
+// load busca a assinatura do path e já responde 404 caso não exista
+func (h *AssinaturaHandler) load(w http.ResponseWriter, r *http.Request) (*entity.Assinatura, bool) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		shared.WriteError(w, http.StatusNotFound, "assinatura_nao_encontrada", "assinatura nao encontrada")
+		return nil, false
+	}
+
+	assinatura, err := h.repo.FindByID(id)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return nil, false
+	}
+	if assinatura == nil {
+		shared.WriteError(w, http.StatusNotFound, "assinatura_nao_encontrada", "assinatura nao encontrada")
+		return nil, false
+	}
+
+	return assinatura, true
+}
+
+// clienteExiste responde 422 se o cliente informado no corpo não existir
+func (h *AssinaturaHandler) clienteExiste(w http.ResponseWriter, clienteID string) bool {
+	if _, err := uuid.Parse(clienteID); err != nil {
+		shared.WriteError(w, http.StatusUnprocessableEntity, "cliente_nao_encontrado", "cliente nao encontrado")
+		return false
+	}
+
+	cliente, err := h.clienteRepo.FindByID(clienteID)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return false
+	}
+	if cliente == nil {
+		shared.WriteError(w, http.StatusUnprocessableEntity, "cliente_nao_encontrado", "cliente nao encontrado")
+		return false
+	}
+
+	return true
+}
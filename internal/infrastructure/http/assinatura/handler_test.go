@@ -0,0 +1,202 @@
+package assinatura
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/http/shared"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func do(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeError(rec *httptest.ResponseRecorder) shared.ErrorResponse {
+	var e shared.ErrorResponse
+	json.Unmarshal(rec.Body.Bytes(), &e)
+	return e
+}
+
+func TestAssinaturaHandler_Lifecycle(t *testing.T) {
+	store := memory.NewStore()
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "c1@test.com")
+	store.Clientes.Save(client)
+
+	h := NewAssinaturaHandler(store.Assinaturas, store.Clientes, store.UnitOfWork(), zap.NewNop()).Routes()
+
+	// 1. Create
+	body := fmt.Sprintf(`{"cliente_id":"%s","valor":"1.500,00","descricao":"Retainer","periodicidade":"mensal","dia_vencimento":10}`, client.ID)
+	rec := do(h, http.MethodPost, "/", body)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var a assinaturaResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &a))
+	assert.Equal(t, entity.StatusAssinaturaAtiva, a.Status)
+	assert.Equal(t, entity.BRL(150000), a.Valor)
+	assert.NotNil(t, a.ProximoVencimento)
+	assert.Equal(t, 10, a.ProximoVencimento.Day())
+
+	// 2. Leitura
+	rec = do(h, http.MethodGet, "/"+a.ID, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = do(h, http.MethodGet, "/?cliente_id="+client.ID, "")
+	var lista []assinaturaResponse
+	json.Unmarshal(rec.Body.Bytes(), &lista)
+	assert.Len(t, lista, 1)
+
+	// 3. Transições
+	assert.Equal(t, http.StatusOK, do(h, http.MethodPost, "/"+a.ID+"/pausar", "").Code)
+	rec = do(h, http.MethodPost, "/"+a.ID+"/pausar", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "assinatura_nao_ativa", decodeError(rec).Code)
+
+	assert.Equal(t, http.StatusOK, do(h, http.MethodPost, "/"+a.ID+"/retomar", "").Code)
+	assert.Equal(t, http.StatusOK, do(h, http.MethodPost, "/"+a.ID+"/cancelar", "").Code)
+
+	rec = do(h, http.MethodPost, "/"+a.ID+"/cancelar", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "assinatura_finalizada", decodeError(rec).Code)
+
+	eventos, _ := store.Eventos.LoadStream(a.ID, 1)
+	tipos := []string{}
+	for _, e := range eventos {
+		tipos = append(tipos, e.EventType)
+	}
+	assert.Equal(t, []string{entity.EventAssinaturaCriada, entity.EventAssinaturaPausada, entity.EventAssinaturaRetomada, entity.EventAssinaturaCancelada}, tipos)
+}
+
+func TestAssinaturaHandler_Errors(t *testing.T) {
+	store := memory.NewStore()
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "c1@test.com")
+	store.Clientes.Save(client)
+
+	h := NewAssinaturaHandler(store.Assinaturas, store.Clientes, store.UnitOfWork(), zap.NewNop()).Routes()
+
+	t.Run("should reject an unknown cliente", func(t *testing.T) {
+		body := `{"cliente_id":"00000000-0000-0000-0000-000000000000","valor":100,"descricao":"Retainer","periodicidade":"mensal","dia_vencimento":10}`
+		rec := do(h, http.MethodPost, "/", body)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "cliente_nao_encontrado", decodeError(rec).Code)
+	})
+
+	t.Run("should reject an invalid periodicidade", func(t *testing.T) {
+		body := fmt.Sprintf(`{"cliente_id":"%s","valor":100,"descricao":"Retainer","periodicidade":"quinzenal","dia_vencimento":10}`, client.ID)
+		rec := do(h, http.MethodPost, "/", body)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "periodicidade_invalida", decodeError(rec).Code)
+	})
+
+	t.Run("should return 404 for unknown subscriptions", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/nao-existe", "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "assinatura_nao_encontrada", decodeError(rec).Code)
+	})
+
+	t.Run("should require cliente_id to list", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should return 404 when changing unknown subscriptions", func(t *testing.T) {
+		rec := do(h, http.MethodPost, "/"+uuid.New().String()+"/pausar", "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "assinatura_nao_encontrada", decodeError(rec).Code)
+	})
+}
+
+// uowConcorrente roda antes uma alteração concorrente, que chega entre a requisição e a transação
+type uowConcorrente struct {
+	repository.UnitOfWork
+	antes func()
+}
+
+func (u *uowConcorrente) Executar(fn func(repos repository.Repositorios) error) error {
+	if u.antes != nil {
+		u.antes()
+		u.antes = nil
+	}
+	return u.UnitOfWork.Executar(fn)
+}
+
+func TestAssinaturaHandler_LeituraNaTransacao(t *testing.T) {
+	store := memory.NewStore()
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "c1@test.com")
+	store.Clientes.Save(client)
+
+	a, _ := entity.NewAssinatura(client.ID, entity.BRL(10000), "Retainer", entity.PeriodicidadeMensal, 10, time.Now(), nil)
+	store.Assinaturas.Save(a)
+
+	uow := &uowConcorrente{UnitOfWork: store.UnitOfWork()}
+	h := NewAssinaturaHandler(store.Assinaturas, store.Clientes, uow, zap.NewNop()).Routes()
+
+	t.Run("should apply the transition to the state read in the transaction", func(t *testing.T) {
+		uow.antes = func() {
+			atual, _ := store.Assinaturas.FindByID(a.ID)
+			assert.NoError(t, atual.Pausar())
+			assert.NoError(t, store.Assinaturas.Update(atual))
+		}
+
+		rec := do(h, http.MethodPost, "/"+a.ID+"/retomar", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		atual, _ := store.Assinaturas.FindByID(a.ID)
+		assert.Equal(t, entity.StatusAssinaturaAtiva, atual.Status)
+	})
+}
+
+// uowContada conta as transações e pode falhar no commit
+type uowContada struct {
+	repository.UnitOfWork
+	transacoes int
+	falha      error
+}
+
+func (u *uowContada) Executar(fn func(repos repository.Repositorios) error) error {
+	u.transacoes++
+	if err := u.UnitOfWork.Executar(fn); err != nil {
+		return err
+	}
+	return u.falha
+}
+
+func TestAssinaturaHandler_Transacao(t *testing.T) {
+	store := memory.NewStore()
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "c1@test.com")
+	store.Clientes.Save(client)
+
+	uow := &uowContada{UnitOfWork: store.UnitOfWork()}
+	h := NewAssinaturaHandler(store.Assinaturas, store.Clientes, uow, zap.NewNop()).Routes()
+
+	body := fmt.Sprintf(`{"cliente_id":"%s","valor":100,"descricao":"Retainer","periodicidade":"mensal","dia_vencimento":10}`, client.ID)
+	rec := do(h, http.MethodPost, "/", body)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var a assinaturaResponse
+	json.Unmarshal(rec.Body.Bytes(), &a)
+
+	t.Run("should write each change in a transaction", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, do(h, http.MethodPost, "/"+a.ID+"/pausar", "").Code)
+		assert.Equal(t, 2, uow.transacoes)
+	})
+
+	t.Run("should fail when the transaction fails", func(t *testing.T) {
+		uow.falha = errors.New("falha ao gravar evento")
+		assert.Equal(t, http.StatusInternalServerError, do(h, http.MethodPost, "/"+a.ID+"/retomar", "").Code)
+	})
+}
@@ -0,0 +1,227 @@
+package cliente
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/http/shared"
+)
+
+type ClienteHandler struct {
+	repo   repository.ClienteRepository
+	logger *zap.Logger
+}
+
+func NewClienteHandler(repo repository.ClienteRepository, logger *zap.Logger) *ClienteHandler {
+	return &ClienteHandler{repo: repo, logger: logger}
+}
+
+// Routes monta as rotas do recurso /clientes
+func (h *ClienteHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/", h.Create)
+	r.Get("/", h.List)
+	r.Get("/{id}", h.Get)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+	r.Post("/{id}/ativar", h.Ativar)
+	r.Post("/{id}/desativar", h.Desativar)
+
+	return r
+}
+
+type clienteRequest struct {
+	UsuarioID string `json:"usuario_id"`
+	Nome      string `json:"nome"`
+	WhatsApp  string `json:"whatsapp"`
+	Email     string `json:"email"`
+}
+
+type clienteResponse struct {
+	ID        string    `json:"id"`
+	UsuarioID string    `json:"usuario_id,omitempty"`
+	Nome      string    `json:"nome"`
+	WhatsApp  string    `json:"whatsapp"`
+	Email     string    `json:"email"`
+	Ativo     bool      `json:"ativo"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func toResponse(c *entity.Cliente) clienteResponse {
+	return clienteResponse{
+		ID:        c.ID,
+		UsuarioID: c.UsuarioID,
+		Nome:      c.Nome,
+		WhatsApp:  c.WhatsApp,
+		Email:     c.Email,
+		Ativo:     c.Ativo,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+func (h *ClienteHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req clienteRequest
+	if err := shared.DecodeJSON(r, &req); err != nil {
+		shared.WriteError(w, http.StatusBadRequest, "json_invalido", err.Error())
+		return
+	}
+
+	cliente, err := entity.NewCliente(req.Nome, req.WhatsApp, req.Email)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+	cliente.UsuarioID = req.UsuarioID
+
+	if h.whatsAppEmUso(w, cliente.WhatsApp, "") {
+		return
+	}
+
+	if err := h.repo.Save(cliente); err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusCreated, toResponse(cliente))
+}
+
+func (h *ClienteHandler) List(w http.ResponseWriter, r *http.Request) {
+	clientes, err := h.repo.FindAll()
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	resp := make([]clienteResponse, 0, len(clientes))
+	for _, c := range clientes {
+		resp = append(resp, toResponse(c))
+	}
+
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *ClienteHandler) Get(w http.ResponseWriter, r *http.Request) {
+	cliente, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toResponse(cliente))
+}
+
+func (h *ClienteHandler) Update(w http.ResponseWriter, r *http.Request) {
+	cliente, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	var req clienteRequest
+	if err := shared.DecodeJSON(r, &req); err != nil {
+		shared.WriteError(w, http.StatusBadRequest, "json_invalido", err.Error())
+		return
+	}
+
+	cliente.UsuarioID = req.UsuarioID
+	cliente.Nome = req.Nome
+	cliente.WhatsApp = req.WhatsApp
+	cliente.Email = req.Email
+
+	if err := cliente.Validate(); err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	if h.whatsAppEmUso(w, cliente.WhatsApp, cliente.ID) {
+		return
+	}
+
+	cliente.Touch()
+	if err := h.repo.Update(cliente); err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toResponse(cliente))
+}
+
+func (h *ClienteHandler) Ativar(w http.ResponseWriter, r *http.Request) {
+	h.alterarStatus(w, r, (*entity.Cliente).Ativar)
+}
+
+func (h *ClienteHandler) Desativar(w http.ResponseWriter, r *http.Request) {
+	h.alterarStatus(w, r, (*entity.Cliente).Desativar)
+}
+
+func (h *ClienteHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	cliente, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(cliente.ID); err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ClienteHandler) alterarStatus(w http.ResponseWriter, r *http.Request, acao func(*entity.Cliente)) {
+	cliente, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	acao(cliente)
+	if err := h.repo.Update(cliente); err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toResponse(cliente))
+}
+
+// load busca o cliente do path e já responde 404 caso não exista
+func (h *ClienteHandler) load(w http.ResponseWriter, r *http.Request) (*entity.Cliente, bool) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		shared.WriteError(w, http.StatusNotFound, "cliente_nao_encontrado", "cliente nao encontrado")
+		return nil, false
+	}
+
+	cliente, err := h.repo.FindByID(id)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return nil, false
+	}
+	if cliente == nil {
+		shared.WriteError(w, http.StatusNotFound, "cliente_nao_encontrado", "cliente nao encontrado")
+		return nil, false
+	}
+
+	return cliente, true
+}
+
+// whatsAppEmUso responde 409 se o número já pertence a outro cliente
+func (h *ClienteHandler) whatsAppEmUso(w http.ResponseWriter, whatsapp, ignorarID string) bool {
+	existente, err := h.repo.FindByWhatsApp(whatsapp)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return true
+	}
+	if existente != nil && existente.ID != ignorarID {
+		shared.WriteError(w, http.StatusConflict, "whatsapp_duplicado", "whatsapp ja cadastrado para outro cliente")
+		return true
+	}
+
+	return false
+}
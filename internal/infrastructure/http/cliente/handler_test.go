@@ -0,0 +1,155 @@
+package cliente
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/http/shared"
+)
+
+// memRepo é um ClienteRepository em memória para testar os handlers sem banco
+type memRepo struct {
+	clientes map[string]*entity.Cliente
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{clientes: map[string]*entity.Cliente{}}
+}
+
+func (m *memRepo) Save(c *entity.Cliente) error {
+	m.clientes[c.ID] = c
+	return nil
+}
+
+func (m *memRepo) FindByID(id string) (*entity.Cliente, error) {
+	return m.clientes[id], nil
+}
+
+func (m *memRepo) FindByWhatsApp(whatsapp string) (*entity.Cliente, error) {
+	for _, c := range m.clientes {
+		if c.WhatsApp == whatsapp {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memRepo) FindAll() ([]*entity.Cliente, error) {
+	var all []*entity.Cliente
+	for _, c := range m.clientes {
+		all = append(all, c)
+	}
+	return all, nil
+}
+
+func (m *memRepo) Update(c *entity.Cliente) error {
+	m.clientes[c.ID] = c
+	return nil
+}
+
+func (m *memRepo) Delete(id string) error {
+	delete(m.clientes, id)
+	return nil
+}
+
+func do(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestClienteHandler_CRUD(t *testing.T) {
+	repo := newMemRepo()
+	h := NewClienteHandler(repo, zap.NewNop()).Routes()
+
+	// 1. Create
+	rec := do(h, http.MethodPost, "/", `{"nome":"Cliente 1","whatsapp":"5511999998888","email":"c1@test.com"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var created clienteResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.ID)
+	assert.True(t, created.Ativo)
+
+	// 2. Get
+	rec = do(h, http.MethodGet, "/"+created.ID, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 3. Update
+	rec = do(h, http.MethodPut, "/"+created.ID, `{"nome":"Jane Doe","whatsapp":"5511999998888","email":""}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Jane Doe", repo.clientes[created.ID].Nome)
+
+	// 4. Desativar / Ativar
+	rec = do(h, http.MethodPost, "/"+created.ID+"/desativar", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, repo.clientes[created.ID].Ativo)
+
+	rec = do(h, http.MethodPost, "/"+created.ID+"/ativar", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, repo.clientes[created.ID].Ativo)
+
+	// 5. List
+	rec = do(h, http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var list []clienteResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Len(t, list, 1)
+
+	// 6. Delete
+	rec = do(h, http.MethodDelete, "/"+created.ID, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = do(h, http.MethodGet, "/"+created.ID, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestClienteHandler_Erros(t *testing.T) {
+	repo := newMemRepo()
+	h := NewClienteHandler(repo, zap.NewNop()).Routes()
+
+	decode := func(rec *httptest.ResponseRecorder) shared.ErrorResponse {
+		var e shared.ErrorResponse
+		json.Unmarshal(rec.Body.Bytes(), &e)
+		return e
+	}
+
+	t.Run("should map domain errors to 422", func(t *testing.T) {
+		rec := do(h, http.MethodPost, "/", `{"nome":"Jo","whatsapp":"5511999998888"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "nome_curto", decode(rec).Code)
+
+		rec = do(h, http.MethodPost, "/", `{"nome":"John Doe","whatsapp":"123"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "whatsapp_invalido", decode(rec).Code)
+	})
+
+	t.Run("should reject duplicated whatsapp", func(t *testing.T) {
+		rec := do(h, http.MethodPost, "/", `{"nome":"John Doe","whatsapp":"5511999997777"}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		rec = do(h, http.MethodPost, "/", `{"nome":"Jane Doe","whatsapp":"5511999997777"}`)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "whatsapp_duplicado", decode(rec).Code)
+	})
+
+	t.Run("should reject invalid json", func(t *testing.T) {
+		rec := do(h, http.MethodPost, "/", `{"nome":`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "json_invalido", decode(rec).Code)
+	})
+
+	t.Run("should return 404 for unknown id", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/nao-existe", "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "cliente_nao_encontrado", decode(rec).Code)
+	})
+}
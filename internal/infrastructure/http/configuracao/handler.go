@@ -0,0 +1,274 @@
+package configuracao
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/http/shared"
+	"github.com/teusf/billing-system/internal/usecase/template"
+)
+
+type ConfiguracaoHandler struct {
+	repo   repository.ConfiguracaoRepository
+	logger *zap.Logger
+}
+
+func NewConfiguracaoHandler(repo repository.ConfiguracaoRepository, logger *zap.Logger) *ConfiguracaoHandler {
+	return &ConfiguracaoHandler{repo: repo, logger: logger}
+}
+
+// Routes monta as rotas do recurso /configuracoes
+func (h *ConfiguracaoHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/{usuarioID}", h.Get)
+	r.Put("/{usuarioID}", h.Put)
+
+	return r
+}
+
+// configuracaoRequest usa ponteiros para diferenciar "não enviado" de valor zero.
+// Campos omitidos mantêm o valor atual (ou o default de NewConfiguracao na criação).
+type configuracaoRequest struct {
+	DiasAntesLembrete    *int                  `json:"dias_antes_lembrete"`
+	TemplateLembrete     *string               `json:"template_lembrete"`
+	TemplateCobranca     *string               `json:"template_cobranca"`
+	TemplateConfirmacao  *string               `json:"template_confirmacao"` // Vazio = comprovante padrão
+	WhatsAppFinanceiro   *string               `json:"whatsapp_financeiro"`
+	EnvioAutomaticoAtivo *bool                 `json:"envio_automatico_ativo"`
+	HorarioInicioEnvio   *string               `json:"horario_inicio_envio"`
+	HorarioFimEnvio      *string               `json:"horario_fim_envio"`
+	NumeracaoPrefixo     *string               `json:"numeracao_prefixo"`
+	NumeracaoIncluirAno  *bool                 `json:"numeracao_incluir_ano"`
+	NumeracaoDigitos     *int                  `json:"numeracao_digitos"`
+	Multa                *entity.Percentual    `json:"multa"`        // Percentual: 2 = 2%
+	JurosAoMes           *entity.Percentual    `json:"juros_ao_mes"` // Percentual ao mês, cobrado pro rata die
+	DiasCarencia         *int                  `json:"dias_carencia"`
+	PixChave             *string               `json:"pix_chave"`
+	PixNomeRecebedor     *string               `json:"pix_nome_recebedor"`
+	PixCidade            *string               `json:"pix_cidade"`
+	BoletoBanco          *string               `json:"boleto_banco"`
+	BoletoConvenio       *string               `json:"boleto_convenio"`
+	BoletoCarteira       *string               `json:"boleto_carteira"`
+	ReguaCobranca        *entity.ReguaCobranca `json:"regua_cobranca"` // Lista completa: substitui a atual; [] volta ao lembrete único
+}
+
+type configuracaoResponse struct {
+	ID                   string               `json:"id"`
+	UsuarioID            string               `json:"usuario_id"`
+	DiasAntesLembrete    int                  `json:"dias_antes_lembrete"`
+	TemplateLembrete     string               `json:"template_lembrete"`
+	TemplateCobranca     string               `json:"template_cobranca"`
+	TemplateConfirmacao  string               `json:"template_confirmacao"`
+	WhatsAppFinanceiro   string               `json:"whatsapp_financeiro"`
+	EnvioAutomaticoAtivo bool                 `json:"envio_automatico_ativo"`
+	HorarioInicioEnvio   string               `json:"horario_inicio_envio"`
+	HorarioFimEnvio      string               `json:"horario_fim_envio"`
+	NumeracaoPrefixo     string               `json:"numeracao_prefixo"`
+	NumeracaoIncluirAno  bool                 `json:"numeracao_incluir_ano"`
+	NumeracaoDigitos     int                  `json:"numeracao_digitos"`
+	Multa                entity.Percentual    `json:"multa"`
+	JurosAoMes           entity.Percentual    `json:"juros_ao_mes"`
+	DiasCarencia         int                  `json:"dias_carencia"`
+	PixChave             string               `json:"pix_chave"`
+	PixNomeRecebedor     string               `json:"pix_nome_recebedor"`
+	PixCidade            string               `json:"pix_cidade"`
+	BoletoBanco          string               `json:"boleto_banco"`
+	BoletoConvenio       string               `json:"boleto_convenio"`
+	BoletoCarteira       string               `json:"boleto_carteira"`
+	ReguaCobranca        entity.ReguaCobranca `json:"regua_cobranca"`
+	CreatedAt            time.Time            `json:"created_at"`
+	UpdatedAt            time.Time            `json:"updated_at"`
+}
+
+func toResponse(c *entity.Configuracao) configuracaoResponse {
+	regua := c.Regua
+	if regua == nil {
+		regua = entity.ReguaCobranca{}
+	}
+
+	return configuracaoResponse{
+		ID:                   c.ID,
+		UsuarioID:            c.UsuarioID,
+		DiasAntesLembrete:    c.DiasAntesLembrete,
+		TemplateLembrete:     c.TemplateLembrete,
+		TemplateCobranca:     c.TemplateCobranca,
+		TemplateConfirmacao:  c.TemplateConfirmacao,
+		WhatsAppFinanceiro:   c.WhatsAppFinanceiro,
+		EnvioAutomaticoAtivo: c.EnvioAutomaticoAtivo,
+		HorarioInicioEnvio:   c.HorarioInicioEnvio,
+		HorarioFimEnvio:      c.HorarioFimEnvio,
+		NumeracaoPrefixo:     c.Numeracao.Prefixo,
+		NumeracaoIncluirAno:  c.Numeracao.IncluirAno,
+		NumeracaoDigitos:     c.Numeracao.Digitos,
+		Multa:                c.Encargos.Multa,
+		JurosAoMes:           c.Encargos.JurosAoMes,
+		DiasCarencia:         c.Encargos.DiasCarencia,
+		PixChave:             c.Pix.Chave,
+		PixNomeRecebedor:     c.Pix.NomeRecebedor,
+		PixCidade:            c.Pix.Cidade,
+		BoletoBanco:          c.Boleto.Banco,
+		BoletoConvenio:       c.Boleto.Convenio,
+		BoletoCarteira:       c.Boleto.Carteira,
+		ReguaCobranca:        regua,
+		CreatedAt:            c.CreatedAt,
+		UpdatedAt:            c.UpdatedAt,
+	}
+}
+
+func (req configuracaoRequest) applyTo(c *entity.Configuracao) {
+	if req.DiasAntesLembrete != nil {
+		c.DiasAntesLembrete = *req.DiasAntesLembrete
+	}
+	if req.TemplateLembrete != nil {
+		c.TemplateLembrete = *req.TemplateLembrete
+	}
+	if req.TemplateCobranca != nil {
+		c.TemplateCobranca = *req.TemplateCobranca
+	}
+	if req.TemplateConfirmacao != nil {
+		c.TemplateConfirmacao = *req.TemplateConfirmacao
+	}
+	if req.WhatsAppFinanceiro != nil {
+		c.WhatsAppFinanceiro = *req.WhatsAppFinanceiro
+	}
+	if req.EnvioAutomaticoAtivo != nil {
+		c.EnvioAutomaticoAtivo = *req.EnvioAutomaticoAtivo
+	}
+	if req.HorarioInicioEnvio != nil {
+		c.HorarioInicioEnvio = *req.HorarioInicioEnvio
+	}
+	if req.HorarioFimEnvio != nil {
+		c.HorarioFimEnvio = *req.HorarioFimEnvio
+	}
+	if req.NumeracaoPrefixo != nil {
+		c.Numeracao.Prefixo = *req.NumeracaoPrefixo
+	}
+	if req.NumeracaoIncluirAno != nil {
+		c.Numeracao.IncluirAno = *req.NumeracaoIncluirAno
+	}
+	if req.NumeracaoDigitos != nil {
+		c.Numeracao.Digitos = *req.NumeracaoDigitos
+	}
+	if req.Multa != nil {
+		c.Encargos.Multa = *req.Multa
+	}
+	if req.JurosAoMes != nil {
+		c.Encargos.JurosAoMes = *req.JurosAoMes
+	}
+	if req.DiasCarencia != nil {
+		c.Encargos.DiasCarencia = *req.DiasCarencia
+	}
+	if req.PixChave != nil {
+		c.Pix.Chave = *req.PixChave
+	}
+	if req.PixNomeRecebedor != nil {
+		c.Pix.NomeRecebedor = *req.PixNomeRecebedor
+	}
+	if req.PixCidade != nil {
+		c.Pix.Cidade = *req.PixCidade
+	}
+	if req.BoletoBanco != nil {
+		c.Boleto.Banco = *req.BoletoBanco
+	}
+	if req.BoletoConvenio != nil {
+		c.Boleto.Convenio = *req.BoletoConvenio
+	}
+	if req.BoletoCarteira != nil {
+		c.Boleto.Carteira = *req.BoletoCarteira
+	}
+	if req.ReguaCobranca != nil {
+		c.Regua = *req.ReguaCobranca
+	}
+}
+
+func (h *ConfiguracaoHandler) Get(w http.ResponseWriter, r *http.Request) {
+	config, err := h.repo.FindByUsuarioID(chi.URLParam(r, "usuarioID"))
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+	if config == nil {
+		shared.WriteError(w, http.StatusNotFound, "configuracao_nao_encontrada", "configuracao nao encontrada")
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toResponse(config))
+}
+
+// Put cria a configuração do usuário (com defaults) ou atualiza a existente
+func (h *ConfiguracaoHandler) Put(w http.ResponseWriter, r *http.Request) {
+	usuarioID := chi.URLParam(r, "usuarioID")
+
+	var req configuracaoRequest
+	if err := shared.DecodeJSON(r, &req); err != nil {
+		shared.WriteError(w, http.StatusBadRequest, "json_invalido", err.Error())
+		return
+	}
+
+	config, err := h.repo.FindByUsuarioID(usuarioID)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	if config == nil {
+		config, err = entity.NewConfiguracao(usuarioID)
+		if err != nil {
+			shared.HandleError(w, h.logger, err)
+			return
+		}
+
+		req.applyTo(config)
+		if err := validar(config); err != nil {
+			shared.HandleError(w, h.logger, err)
+			return
+		}
+
+		if err := h.repo.Save(config); err != nil {
+			shared.HandleError(w, h.logger, err)
+			return
+		}
+
+		shared.WriteJSON(w, http.StatusCreated, toResponse(config))
+		return
+	}
+
+	req.applyTo(config)
+	if err := validar(config); err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	config.Touch()
+	if err := h.repo.Update(config); err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toResponse(config))
+}
+
+// validar aplica as regras da entidade e confere as variáveis dos templates,
+// para que um template inválido seja recusado ao salvar e não na hora do envio
+func validar(c *entity.Configuracao) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	templates := []string{c.TemplateLembrete, c.TemplateCobranca, c.TemplateConfirmacao}
+	for _, etapa := range c.Regua {
+		templates = append(templates, etapa.Template)
+	}
+	for _, t := range templates {
+		if err := template.Validar(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
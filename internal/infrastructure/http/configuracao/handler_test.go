@@ -0,0 +1,154 @@
+package configuracao
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/http/shared"
+)
+
+// memRepo é um ConfiguracaoRepository em memória para testar os handlers sem banco
+type memRepo struct {
+	configs map[string]*entity.Configuracao
+}
+
+func (m *memRepo) Save(c *entity.Configuracao) error {
+	m.configs[c.UsuarioID] = c
+	return nil
+}
+
+func (m *memRepo) FindByUsuarioID(usuarioID string) (*entity.Configuracao, error) {
+	return m.configs[usuarioID], nil
+}
+
+func (m *memRepo) FindAll() ([]*entity.Configuracao, error) {
+	var all []*entity.Configuracao
+	for _, c := range m.configs {
+		all = append(all, c)
+	}
+	return all, nil
+}
+
+func (m *memRepo) Update(c *entity.Configuracao) error {
+	m.configs[c.UsuarioID] = c
+	return nil
+}
+
+func do(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestConfiguracaoHandler_GetPut(t *testing.T) {
+	repo := &memRepo{configs: map[string]*entity.Configuracao{}}
+	h := NewConfiguracaoHandler(repo, zap.NewNop()).Routes()
+
+	// 1. Ainda não existe
+	rec := do(h, http.MethodGet, "/user1", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// 2. PUT cria com defaults + campos enviados
+	rec = do(h, http.MethodPut, "/user1", `{"dias_antes_lembrete":5,"whatsapp_financeiro":"5511977776666","envio_automatico_ativo":false}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var created configuracaoResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, 5, created.DiasAntesLembrete)
+	assert.Equal(t, "5511977776666", created.WhatsAppFinanceiro)
+	assert.False(t, created.EnvioAutomaticoAtivo)
+	assert.Equal(t, "08:00", created.HorarioInicioEnvio)
+
+	// 3. PUT atualiza mantendo campos omitidos
+	rec = do(h, http.MethodPut, "/user1", `{"horario_fim_envio":"20:00"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "20:00", repo.configs["user1"].HorarioFimEnvio)
+	assert.Equal(t, 5, repo.configs["user1"].DiasAntesLembrete)
+	assert.Equal(t, created.ID, repo.configs["user1"].ID)
+
+	// 4. GET
+	rec = do(h, http.MethodGet, "/user1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestConfiguracaoHandler_Validacao(t *testing.T) {
+	repo := &memRepo{configs: map[string]*entity.Configuracao{}}
+	h := NewConfiguracaoHandler(repo, zap.NewNop()).Routes()
+
+	rec := do(h, http.MethodPut, "/user1", `{"dias_antes_lembrete":31}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	var e shared.ErrorResponse
+	json.Unmarshal(rec.Body.Bytes(), &e)
+	assert.Equal(t, "dias_invalidos", e.Code)
+	assert.Empty(t, repo.configs)
+
+	rec = do(h, http.MethodPut, "/user1", `{"horario_inicio_envio":"25:00"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = do(h, http.MethodPut, "/user1", `{"regua_cobranca":[{"dias":3,"tipo":"cobranca"},{"dias":3,"tipo":"lembrete"}]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	json.Unmarshal(rec.Body.Bytes(), &e)
+	assert.Equal(t, "etapa_duplicada", e.Code)
+}
+
+func TestConfiguracaoHandler_Templates(t *testing.T) {
+	repo := &memRepo{configs: map[string]*entity.Configuracao{}}
+	h := NewConfiguracaoHandler(repo, zap.NewNop()).Routes()
+
+	rec := do(h, http.MethodPut, "/user1", `{"template_lembrete":"Ola {{cliente}}, a fatura {{numero}} vence em {{vencimento}}"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var e shared.ErrorResponse
+	rec = do(h, http.MethodPut, "/user1", `{"template_cobranca":"Ola {{nome_cliente}}"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	json.Unmarshal(rec.Body.Bytes(), &e)
+	assert.Equal(t, "variavel_desconhecida", e.Code)
+	assert.Contains(t, e.Message, "{{nome_cliente}}")
+
+	rec = do(h, http.MethodPut, "/user2", `{"regua_cobranca":[{"dias":1,"tipo":"cobranca","template":"Pague {{valor"}]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	json.Unmarshal(rec.Body.Bytes(), &e)
+	assert.Equal(t, "template_mal_formado", e.Code)
+
+	rec = do(h, http.MethodPut, "/user3", `{"template_confirmacao":"Recebemos {{valor_recebido}}"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	json.Unmarshal(rec.Body.Bytes(), &e)
+	assert.Equal(t, "variavel_desconhecida", e.Code)
+
+	rec = do(h, http.MethodPut, "/user3", `{"template_confirmacao":"Recebemos {{valor_pago}} em {{data_pagamento}}"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resp configuracaoResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.Equal(t, "Recebemos {{valor_pago}} em {{data_pagamento}}", resp.TemplateConfirmacao)
+}
+
+func TestConfiguracaoHandler_Regua(t *testing.T) {
+	repo := &memRepo{configs: map[string]*entity.Configuracao{}}
+	h := NewConfiguracaoHandler(repo, zap.NewNop()).Routes()
+
+	rec := do(h, http.MethodPut, "/user1", `{"dias_antes_lembrete":2}`)
+	var resp configuracaoResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.NotNil(t, resp.ReguaCobranca) // Serializada como [] e não null
+
+	body := `{"regua_cobranca":[{"dias":-5,"tipo":"lembrete"},{"dias":0,"tipo":"lembrete","template":"Vence hoje"},{"dias":10,"tipo":"cobranca"}]}`
+	rec = do(h, http.MethodPut, "/user1", body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, repo.configs["user1"].Regua, 3)
+	assert.Equal(t, "Vence hoje", repo.configs["user1"].Regua[1].Template)
+
+	// Campos omitidos mantêm a régua; [] a remove
+	do(h, http.MethodPut, "/user1", `{"horario_fim_envio":"20:00"}`)
+	assert.Len(t, repo.configs["user1"].Regua, 3)
+	do(h, http.MethodPut, "/user1", `{"regua_cobranca":[]}`)
+	assert.Empty(t, repo.configs["user1"].Regua)
+}
@@ -0,0 +1,757 @@
+package fatura
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/codigobarras"
+	"github.com/teusf/billing-system/internal/infrastructure/http/shared"
+	"github.com/teusf/billing-system/internal/infrastructure/qrcode"
+	"github.com/teusf/billing-system/internal/usecase/boleto"
+	"github.com/teusf/billing-system/internal/usecase/confirmacao"
+	"github.com/teusf/billing-system/internal/usecase/encargos"
+	"github.com/teusf/billing-system/internal/usecase/numeracao"
+	"github.com/teusf/billing-system/internal/usecase/pix"
+	"github.com/teusf/billing-system/internal/usecase/template"
+)
+
+type FaturaHandler struct {
+	repo        repository.FaturaRepository
+	clienteRepo repository.ClienteRepository
+	eventos     repository.EventStore
+	uow         repository.UnitOfWork // Escritas gravam a fatura e os eventos juntos
+	urlPublica  string                // Endereço externo da API, para o {{link_pagamento}} da prévia
+	logger      *zap.Logger
+}
+
+func NewFaturaHandler(repo repository.FaturaRepository, clienteRepo repository.ClienteRepository, eventos repository.EventStore, uow repository.UnitOfWork, urlPublica string, logger *zap.Logger) *FaturaHandler {
+	return &FaturaHandler{repo: repo, clienteRepo: clienteRepo, eventos: eventos, uow: uow, urlPublica: urlPublica, logger: logger}
+}
+
+// Routes monta as rotas do recurso /faturas
+func (h *FaturaHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/", h.Create)
+	r.Get("/", h.ListByCliente)
+	r.Get("/pendentes", h.ListPendentes)
+	r.Get("/{id}", h.Get)
+	r.Get("/{id}/historico", h.Historico)
+	r.Get("/{id}/valor-devido", h.ValorDevido)
+	r.Get("/{id}/pix", h.Pix)
+	r.Get("/{id}/pix/qrcode", h.PixQRCode)
+	r.Put("/{id}/pix/cobranca", h.RegistrarCobrancaPix)
+	r.Get("/{id}/boleto", h.Boleto)
+	r.Get("/{id}/boleto/codigo-barras", h.BoletoCodigoBarras)
+	r.Post("/{id}/mensagens/preview", h.PreviewMensagem)
+	r.Post("/{id}/pagar", h.Pagar)
+	r.Get("/{id}/pagamentos", h.ListPagamentos)
+	r.Post("/{id}/pagamentos", h.RegistrarPagamento)
+	r.Post("/{id}/pagamentos/{pagamentoID}/estornar", h.EstornarPagamento)
+	r.Post("/{id}/cancelar", h.Cancelar)
+
+	return r
+}
+
+type faturaRequest struct {
+	ClienteID      string              `json:"cliente_id"`
+	Valor          entity.Dinheiro     `json:"valor"` // 150.5 ou "1.234,56"; com itens, pode ser omitido
+	DataVencimento time.Time           `json:"data_vencimento"`
+	Descricao      string              `json:"descricao"`
+	Itens          []entity.ItemFatura `json:"itens"`
+}
+
+type faturaResponse struct {
+	ID              string              `json:"id"`
+	ClienteID       string              `json:"cliente_id"`
+	Numero          string              `json:"numero"`
+	Descricao       string              `json:"descricao"`
+	Valor           entity.Dinheiro     `json:"valor"`
+	Moeda           entity.Moeda        `json:"moeda"`
+	Subtotal        entity.Dinheiro     `json:"subtotal"`
+	Descontos       entity.Dinheiro     `json:"descontos"`
+	Impostos        entity.Dinheiro     `json:"impostos"`
+	Itens           []itemResponse      `json:"itens"`
+	ValorPago       entity.Dinheiro     `json:"valor_pago"`
+	Pagamentos      []pagamentoResponse `json:"pagamentos"`
+	DataVencimento  time.Time           `json:"data_vencimento"`
+	DataPagamento   *time.Time          `json:"data_pagamento,omitempty"`
+	Status          entity.StatusFatura `json:"status"`
+	LembreteEnviado bool                `json:"lembrete_enviado"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+}
+
+type itemResponse struct {
+	entity.ItemFatura
+	Imposto entity.Dinheiro `json:"imposto"`
+	Total   entity.Dinheiro `json:"total"`
+}
+
+type pagamentoResponse struct {
+	ID                string                 `json:"id"`
+	Valor             entity.Dinheiro        `json:"valor"`
+	Metodo            entity.MetodoPagamento `json:"metodo"`
+	PagoEm            time.Time              `json:"pago_em"`
+	ReferenciaExterna string                 `json:"referencia_externa,omitempty"`
+	EstornadoEm       *time.Time             `json:"estornado_em,omitempty"`
+	MotivoEstorno     string                 `json:"motivo_estorno,omitempty"`
+}
+
+func toPagamentosResponse(pagamentos []entity.Pagamento) []pagamentoResponse {
+	resp := make([]pagamentoResponse, 0, len(pagamentos))
+	for _, p := range pagamentos {
+		resp = append(resp, pagamentoResponse{
+			ID:                p.ID,
+			Valor:             p.Valor,
+			Metodo:            p.Metodo,
+			PagoEm:            p.PagoEm,
+			ReferenciaExterna: p.ReferenciaExterna,
+			EstornadoEm:       p.EstornadoEm,
+			MotivoEstorno:     p.MotivoEstorno,
+		})
+	}
+	return resp
+}
+
+func toResponse(f *entity.Fatura) faturaResponse {
+	// Os totais já foram validados na criação da fatura
+	totais, _ := f.Totais()
+
+	itens := make([]itemResponse, 0, len(f.Itens))
+	for _, item := range f.Itens {
+		t, _ := item.Totais()
+		itens = append(itens, itemResponse{ItemFatura: item, Imposto: t.Impostos, Total: t.Total})
+	}
+
+	return faturaResponse{
+		ID:              f.ID,
+		ClienteID:       f.ClienteID,
+		Numero:          f.Numero,
+		Descricao:       f.Descricao,
+		Valor:           f.Valor,
+		Moeda:           f.Valor.Moeda(),
+		Subtotal:        totais.Subtotal,
+		Descontos:       totais.Descontos,
+		Impostos:        totais.Impostos,
+		Itens:           itens,
+		ValorPago:       f.TotalPago(),
+		Pagamentos:      toPagamentosResponse(f.Pagamentos),
+		DataVencimento:  f.DataVencimento,
+		DataPagamento:   f.DataPagamento,
+		Status:          f.Status,
+		LembreteEnviado: f.LembreteEnviado,
+		CreatedAt:       f.CreatedAt,
+		UpdatedAt:       f.UpdatedAt,
+	}
+}
+
+func toListResponse(faturas []*entity.Fatura) []faturaResponse {
+	resp := make([]faturaResponse, 0, len(faturas))
+	for _, f := range faturas {
+		resp = append(resp, toResponse(f))
+	}
+	return resp
+}
+
+func (h *FaturaHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req faturaRequest
+	if err := shared.DecodeJSON(r, &req); err != nil {
+		shared.WriteError(w, http.StatusBadRequest, "json_invalido", err.Error())
+		return
+	}
+
+	if _, err := uuid.Parse(req.ClienteID); err != nil {
+		shared.WriteError(w, http.StatusUnprocessableEntity, "cliente_nao_encontrado", "cliente nao encontrado")
+		return
+	}
+
+	// Sem valor informado, a fatura com itens vale o total deles;
+	// com valor informado, NewFatura recusa se ele divergir dos itens
+	valor := req.Valor
+	if valor.IsZero() && len(req.Itens) > 0 {
+		rascunho := entity.Fatura{Itens: req.Itens}
+		totais, err := rascunho.Totais()
+		if err != nil {
+			shared.HandleError(w, h.logger, err)
+			return
+		}
+		valor = totais.Total
+	}
+
+	// O número é reservado na transação do insert: se a gravação falhar, ele não é consumido.
+	// O cliente é lido na mesma transação, e o tenant dele define tanto a série quanto o
+	// usuario_id gravado na fatura.
+	var fatura *entity.Fatura
+	err := h.uow.Executar(func(repos repository.Repositorios) error {
+		cliente, err := repos.Clientes.FindByID(req.ClienteID)
+		if err != nil {
+			return err
+		}
+		if cliente == nil {
+			return entity.ErrClienteNaoEncontrado
+		}
+
+		numero, err := numeracao.Proximo(repos, cliente.UsuarioID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		fatura, err = entity.NewFatura(req.ClienteID, numero, valor, req.DataVencimento, req.Descricao, req.Itens...)
+		if err != nil {
+			return err
+		}
+		fatura.UsuarioID = cliente.UsuarioID
+
+		return repos.Faturas.Save(fatura)
+	})
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusCreated, toResponse(fatura))
+}
+
+// ListByCliente lista as faturas de um cliente (?cliente_id=)
+func (h *FaturaHandler) ListByCliente(w http.ResponseWriter, r *http.Request) {
+	clienteID := r.URL.Query().Get("cliente_id")
+	if clienteID == "" {
+		shared.WriteError(w, http.StatusBadRequest, "cliente_id_obrigatorio", "informe o parametro cliente_id")
+		return
+	}
+	if _, err := uuid.Parse(clienteID); err != nil {
+		shared.WriteJSON(w, http.StatusOK, []faturaResponse{})
+		return
+	}
+
+	faturas, err := h.repo.FindByClienteID(clienteID)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toListResponse(faturas))
+}
+
+func (h *FaturaHandler) ListPendentes(w http.ResponseWriter, r *http.Request) {
+	faturas, err := h.repo.FindPendentes()
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toListResponse(faturas))
+}
+
+// Get retorna a fatura atual ou, com ?em=<RFC3339>, como ela estava naquele instante
+// (reconstruída a partir do stream de eventos)
+func (h *FaturaHandler) Get(w http.ResponseWriter, r *http.Request) {
+	fatura, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	if em := r.URL.Query().Get("em"); em != "" {
+		instante, err := time.Parse(time.RFC3339, em)
+		if err != nil {
+			shared.WriteError(w, http.StatusBadRequest, "data_invalida", "parametro em deve estar no formato RFC3339")
+			return
+		}
+
+		stream, err := h.eventos.LoadStream(fatura.ID, 1)
+		if err != nil {
+			shared.HandleError(w, h.logger, err)
+			return
+		}
+
+		fatura, err = entity.ReconstruirFaturaEm(stream, instante)
+		if errors.Is(err, entity.ErrStreamVazio) {
+			shared.WriteError(w, http.StatusNotFound, "fatura_nao_encontrada", "fatura nao existia na data informada")
+			return
+		}
+		if err != nil {
+			shared.HandleError(w, h.logger, err)
+			return
+		}
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toResponse(fatura))
+}
+
+type eventoResponse struct {
+	ID        string          `json:"id"`
+	Tipo      string          `json:"tipo"`
+	Versao    int             `json:"versao"`
+	Dados     json.RawMessage `json:"dados"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// Historico lista todas as transições da fatura, na ordem do stream
+func (h *FaturaHandler) Historico(w http.ResponseWriter, r *http.Request) {
+	fatura, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	stream, err := h.eventos.LoadStream(fatura.ID, 1)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	resp := make([]eventoResponse, 0, len(stream))
+	for _, e := range stream {
+		resp = append(resp, eventoResponse{ID: e.ID, Tipo: e.EventType, Versao: e.Version, Dados: e.EventData, Timestamp: e.Timestamp})
+	}
+
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+type valorDevidoResponse struct {
+	FaturaID   string          `json:"fatura_id"`
+	Data       string          `json:"data"`
+	DiasAtraso int             `json:"dias_atraso"`
+	Principal  entity.Dinheiro `json:"principal"`
+	Multa      entity.Dinheiro `json:"multa"`
+	Juros      entity.Dinheiro `json:"juros"`
+	Total      entity.Dinheiro `json:"total"`
+	Pago       entity.Dinheiro `json:"pago"`
+	Saldo      entity.Dinheiro `json:"saldo"`
+}
+
+// ValorDevido retorna o valor atualizado com multa e juros para pagamento em ?data=AAAA-MM-DD (padrão: hoje)
+func (h *FaturaHandler) ValorDevido(w http.ResponseWriter, r *http.Request) {
+	fatura, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	em := time.Now()
+	if data := r.URL.Query().Get("data"); data != "" {
+		dia, err := time.ParseInLocation("2006-01-02", data, time.Local)
+		if err != nil {
+			shared.WriteError(w, http.StatusBadRequest, "data_invalida", "use o formato AAAA-MM-DD")
+			return
+		}
+		em = dia
+	}
+
+	var devido entity.ValorDevido
+	err := h.uow.Executar(func(repos repository.Repositorios) error {
+		regras, err := encargos.DoCliente(repos, fatura.ClienteID)
+		if err != nil {
+			return err
+		}
+		devido, err = fatura.ValorDevido(em, regras)
+		return err
+	})
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, valorDevidoResponse{
+		FaturaID:   fatura.ID,
+		Data:       em.Format("2006-01-02"),
+		DiasAtraso: devido.DiasAtraso,
+		Principal:  devido.Principal,
+		Multa:      devido.Multa,
+		Juros:      devido.Juros,
+		Total:      devido.Total,
+		Pago:       devido.Pago,
+		Saldo:      devido.Saldo,
+	})
+}
+
+type pixResponse struct {
+	FaturaID   string          `json:"fatura_id"`
+	TxID       string          `json:"txid"`
+	Tipo       entity.TipoPix  `json:"tipo"`
+	Valor      entity.Dinheiro `json:"valor"`
+	CopiaECola string          `json:"copia_e_cola"`
+}
+
+// Pix retorna o BR Code "copia e cola" do saldo da fatura (?tipo=estatico|dinamico; o padrão é
+// o dinâmico quando há cobrança registrada no PSP)
+func (h *FaturaHandler) Pix(w http.ResponseWriter, r *http.Request) {
+	fatura, cobranca, ok := h.gerarPix(w, r)
+	if !ok {
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, pixResponse{
+		FaturaID:   fatura.ID,
+		TxID:       cobranca.TxID,
+		Tipo:       cobranca.Tipo,
+		Valor:      cobranca.Valor,
+		CopiaECola: cobranca.CopiaECola,
+	})
+}
+
+type cobrancaPixRequest struct {
+	Location string `json:"location"` // Devolvida pelo PSP na criação da cobrança, sem o https://
+}
+
+// RegistrarCobrancaPix guarda a cobrança criada no PSP do tenant para a fatura e retorna o
+// Pix dinâmico que aponta para ela. Uma nova chamada substitui a cobrança anterior.
+func (h *FaturaHandler) RegistrarCobrancaPix(w http.ResponseWriter, r *http.Request) {
+	var req cobrancaPixRequest
+	if err := shared.DecodeJSON(r, &req); err != nil {
+		shared.WriteError(w, http.StatusBadRequest, "json_invalido", err.Error())
+		return
+	}
+
+	fatura, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	var cobranca *pix.Cobranca
+	err := h.uow.Executar(func(repos repository.Repositorios) error {
+		var err error
+		cobranca, err = pix.RegistrarCobranca(repos, fatura, req.Location, time.Now())
+		return err
+	})
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, pixResponse{
+		FaturaID:   fatura.ID,
+		TxID:       cobranca.TxID,
+		Tipo:       cobranca.Tipo,
+		Valor:      cobranca.Valor,
+		CopiaECola: cobranca.CopiaECola,
+	})
+}
+
+// PixQRCode retorna o QR code do Pix em PNG (?tamanho= em pixels, padrão 256)
+func (h *FaturaHandler) PixQRCode(w http.ResponseWriter, r *http.Request) {
+	tamanho := qrcode.TamanhoPadrao
+	if t := r.URL.Query().Get("tamanho"); t != "" {
+		n, err := strconv.Atoi(t)
+		if err != nil || n < qrcode.TamanhoMinimo || n > qrcode.TamanhoMaximo {
+			shared.WriteError(w, http.StatusBadRequest, "tamanho_invalido",
+				fmt.Sprintf("tamanho deve estar entre %d e %d", qrcode.TamanhoMinimo, qrcode.TamanhoMaximo))
+			return
+		}
+		tamanho = n
+	}
+
+	_, cobranca, ok := h.gerarPix(w, r)
+	if !ok {
+		return
+	}
+
+	png, err := qrcode.PNG(cobranca.CopiaECola, tamanho)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store") // O valor muda com pagamentos e encargos
+	w.WriteHeader(http.StatusOK)
+	w.Write(png)
+}
+
+func (h *FaturaHandler) gerarPix(w http.ResponseWriter, r *http.Request) (*entity.Fatura, *pix.Cobranca, bool) {
+	fatura, ok := h.load(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+
+	var cobranca *pix.Cobranca
+	err := h.uow.Executar(func(repos repository.Repositorios) error {
+		var err error
+		cobranca, err = pix.Gerar(repos, fatura, entity.TipoPix(r.URL.Query().Get("tipo")), time.Now())
+		return err
+	})
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return nil, nil, false
+	}
+
+	return fatura, cobranca, true
+}
+
+type previewRequest struct {
+	Template string `json:"template"`
+}
+
+type previewResponse struct {
+	FaturaID string `json:"fatura_id"`
+	Conteudo string `json:"conteudo"`
+}
+
+// PreviewMensagem renderiza um template com os dados atuais da fatura, sem enviar nada.
+// Serve para o tenant conferir o texto antes de salvá-lo na configuração.
+func (h *FaturaHandler) PreviewMensagem(w http.ResponseWriter, r *http.Request) {
+	fatura, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	var req previewRequest
+	if err := shared.DecodeJSON(r, &req); err != nil {
+		shared.WriteError(w, http.StatusBadRequest, "json_invalido", err.Error())
+		return
+	}
+	if req.Template == "" {
+		shared.WriteError(w, http.StatusBadRequest, "template_obrigatorio", "informe o template")
+		return
+	}
+
+	var conteudo string
+	err := h.uow.Executar(func(repos repository.Repositorios) error {
+		cliente, err := repos.Clientes.FindByID(fatura.ClienteID)
+		if err != nil {
+			return err
+		}
+		if cliente == nil {
+			return fmt.Errorf("cliente %s da fatura nao encontrado", fatura.ClienteID)
+		}
+
+		// Sem configuração do tenant, a prévia sai sem encargos e sem Pix
+		config := &entity.Configuracao{}
+		if cliente.UsuarioID != "" {
+			encontrada, err := repos.Configuracoes.FindByUsuarioID(cliente.UsuarioID)
+			if err != nil {
+				return err
+			}
+			if encontrada != nil {
+				config = encontrada
+			}
+		}
+
+		conteudo, err = template.Montar(req.Template, config, cliente, fatura, time.Now(), h.urlPublica)
+		return err
+	})
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, previewResponse{FaturaID: fatura.ID, Conteudo: conteudo})
+}
+
+type boletoResponse struct {
+	FaturaID        string          `json:"fatura_id"`
+	Banco           string          `json:"banco"`
+	NossoNumero     string          `json:"nosso_numero"`
+	Valor           entity.Dinheiro `json:"valor"`
+	Vencimento      string          `json:"vencimento"` // AAAA-MM-DD
+	FatorVencimento string          `json:"fator_vencimento"`
+	CodigoBarras    string          `json:"codigo_barras"`
+	LinhaDigitavel  string          `json:"linha_digitavel"`
+}
+
+// Boleto retorna o código de barras e a linha digitável do saldo da fatura
+func (h *FaturaHandler) Boleto(w http.ResponseWriter, r *http.Request) {
+	fatura, b, ok := h.emitirBoleto(w, r)
+	if !ok {
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, boletoResponse{
+		FaturaID:        fatura.ID,
+		Banco:           b.Banco,
+		NossoNumero:     b.NossoNumero,
+		Valor:           b.Valor,
+		Vencimento:      b.Vencimento.Format("2006-01-02"),
+		FatorVencimento: b.FatorVencimento,
+		CodigoBarras:    b.CodigoBarras,
+		LinhaDigitavel:  b.LinhaDigitavel,
+	})
+}
+
+// BoletoCodigoBarras retorna o código de barras Interleaved 2 of 5 em PNG (?largura= em pixels, padrão 810)
+func (h *FaturaHandler) BoletoCodigoBarras(w http.ResponseWriter, r *http.Request) {
+	largura := codigobarras.LarguraPadrao
+	if l := r.URL.Query().Get("largura"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < codigobarras.LarguraMinima || n > codigobarras.LarguraMaxima {
+			shared.WriteError(w, http.StatusBadRequest, "largura_invalida",
+				fmt.Sprintf("largura deve estar entre %d e %d", codigobarras.LarguraMinima, codigobarras.LarguraMaxima))
+			return
+		}
+		largura = n
+	}
+
+	_, b, ok := h.emitirBoleto(w, r)
+	if !ok {
+		return
+	}
+
+	png, err := codigobarras.PNG(b.CodigoBarras, largura, codigobarras.AlturaPadrao)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store") // O valor muda com pagamentos e encargos
+	w.WriteHeader(http.StatusOK)
+	w.Write(png)
+}
+
+func (h *FaturaHandler) emitirBoleto(w http.ResponseWriter, r *http.Request) (*entity.Fatura, *entity.Boleto, bool) {
+	fatura, ok := h.load(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+
+	var b *entity.Boleto
+	err := h.uow.Executar(func(repos repository.Repositorios) error {
+		var err error
+		b, err = boleto.Emitir(repos, fatura, time.Now())
+		return err
+	})
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return nil, nil, false
+	}
+
+	return fatura, b, true
+}
+
+// Pagar liquida a fatura com um pagamento manual do saldo atualizado com os encargos do tenant
+func (h *FaturaHandler) Pagar(w http.ResponseWriter, r *http.Request) {
+	h.transicionar(w, r, func(repos repository.Repositorios, f *entity.Fatura) error {
+		regras, err := encargos.DoCliente(repos, f.ClienteID)
+		if err != nil {
+			return err
+		}
+		return f.MarcarComoPaga(regras)
+	})
+}
+
+type pagamentoRequest struct {
+	Valor             entity.Dinheiro        `json:"valor"`
+	Metodo            entity.MetodoPagamento `json:"metodo"`
+	PagoEm            *time.Time             `json:"pago_em"` // Opcional: agora
+	ReferenciaExterna string                 `json:"referencia_externa"`
+}
+
+func (h *FaturaHandler) ListPagamentos(w http.ResponseWriter, r *http.Request) {
+	fatura, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toPagamentosResponse(fatura.Pagamentos))
+}
+
+// RegistrarPagamento lança um pagamento, parcial ou do saldo inteiro; o segundo liquida a fatura
+func (h *FaturaHandler) RegistrarPagamento(w http.ResponseWriter, r *http.Request) {
+	var req pagamentoRequest
+	if err := shared.DecodeJSON(r, &req); err != nil {
+		shared.WriteError(w, http.StatusBadRequest, "json_invalido", err.Error())
+		return
+	}
+
+	var pagoEm time.Time
+	if req.PagoEm != nil {
+		pagoEm = *req.PagoEm
+	}
+
+	h.transicionar(w, r, func(repos repository.Repositorios, f *entity.Fatura) error {
+		regras, err := encargos.DoCliente(repos, f.ClienteID)
+		if err != nil {
+			return err
+		}
+		_, err = f.RegistrarPagamento(req.Valor, req.Metodo, pagoEm, req.ReferenciaExterna, regras)
+		return err
+	})
+}
+
+type estornoRequest struct {
+	Motivo string `json:"motivo"`
+}
+
+func (h *FaturaHandler) EstornarPagamento(w http.ResponseWriter, r *http.Request) {
+	// O corpo é opcional: sem ele, o estorno fica sem motivo
+	var req estornoRequest
+	if err := shared.DecodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		shared.WriteError(w, http.StatusBadRequest, "json_invalido", err.Error())
+		return
+	}
+
+	pagamentoID := chi.URLParam(r, "pagamentoID")
+	h.transicionar(w, r, func(_ repository.Repositorios, f *entity.Fatura) error {
+		return f.EstornarPagamento(pagamentoID, req.Motivo)
+	})
+}
+
+func (h *FaturaHandler) Cancelar(w http.ResponseWriter, r *http.Request) {
+	h.transicionar(w, r, func(_ repository.Repositorios, f *entity.Fatura) error {
+		return f.Cancelar()
+	})
+}
+
+// transicionar aplica uma transição da máquina de estados e persiste o resultado na mesma transação.
+// Quando a fatura passa a paga, a confirmação do pagamento ao cliente entra na mesma transação.
+// Transições inválidas são mapeadas para 409 pelo shared.HandleError.
+func (h *FaturaHandler) transicionar(w http.ResponseWriter, r *http.Request, acao func(repos repository.Repositorios, f *entity.Fatura) error) {
+	fatura, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	statusAnterior := fatura.Status
+	err := h.uow.Executar(func(repos repository.Repositorios) error {
+		if err := acao(repos, fatura); err != nil {
+			return err
+		}
+		if err := repos.Faturas.Update(fatura); err != nil {
+			return err
+		}
+
+		if statusAnterior != entity.StatusPaga && fatura.Status == entity.StatusPaga {
+			if _, err := confirmacao.Enfileirar(repos, fatura, time.Now()); err != nil {
+				return fmt.Errorf("erro ao enfileirar confirmacao de pagamento: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	shared.WriteJSON(w, http.StatusOK, toResponse(fatura))
+}
+
+// load busca a fatura do path e já responde 404 caso não exista
+func (h *FaturaHandler) load(w http.ResponseWriter, r *http.Request) (*entity.Fatura, bool) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		shared.WriteError(w, http.StatusNotFound, "fatura_nao_encontrada", "fatura nao encontrada")
+		return nil, false
+	}
+
+	fatura, err := h.repo.FindByID(id)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return nil, false
+	}
+	if fatura == nil {
+		shared.WriteError(w, http.StatusNotFound, "fatura_nao_encontrada", "fatura nao encontrada")
+		return nil, false
+	}
+
+	return fatura, true
+}
@@ -0,0 +1,551 @@
+package fatura
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/http/shared"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func do(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeError(rec *httptest.ResponseRecorder) shared.ErrorResponse {
+	var e shared.ErrorResponse
+	json.Unmarshal(rec.Body.Bytes(), &e)
+	return e
+}
+
+func setup(t *testing.T) (http.Handler, *memory.Store, *entity.Cliente) {
+	t.Helper()
+
+	store := memory.NewStore()
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "c1@test.com")
+	store.Clientes.Save(client)
+
+	h := NewFaturaHandler(store.Faturas, store.Clientes, store.Eventos, store.UnitOfWork(), "https://api.exemplo.com.br", zap.NewNop()).Routes()
+	return h, store, client
+}
+
+func status(store *memory.Store, id string) entity.StatusFatura {
+	f, _ := store.Faturas.FindByID(id)
+	return f.Status
+}
+
+func criarFatura(t *testing.T, h http.Handler, clienteID string) faturaResponse {
+	t.Helper()
+
+	vencimento := time.Now().AddDate(0, 0, 5).Format(time.RFC3339)
+	body := fmt.Sprintf(`{"cliente_id":"%s","valor":150.5,"data_vencimento":"%s","descricao":"Consultoria"}`, clienteID, vencimento)
+
+	rec := do(h, http.MethodPost, "/", body)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var f faturaResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &f))
+	return f
+}
+
+func TestFaturaHandler_Lifecycle(t *testing.T) {
+	h, store, client := setup(t)
+
+	// 1. Create
+	f := criarFatura(t, h, client.ID)
+	assert.Equal(t, entity.StatusPendente, f.Status)
+	assert.Equal(t, entity.FormatoNumeracaoPadrao().Formatar(1, time.Now()), f.Numero)
+	assert.Equal(t, entity.FormatoNumeracaoPadrao().Formatar(2, time.Now()), criarFatura(t, h, client.ID).Numero)
+
+	// 2. Listagens
+	rec := do(h, http.MethodGet, "/pendentes", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var pendentes []faturaResponse
+	json.Unmarshal(rec.Body.Bytes(), &pendentes)
+	assert.Len(t, pendentes, 2)
+
+	rec = do(h, http.MethodGet, "/?cliente_id="+client.ID, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var doCliente []faturaResponse
+	json.Unmarshal(rec.Body.Bytes(), &doCliente)
+	assert.Len(t, doCliente, 2)
+
+	// 3. Pagar
+	rec = do(h, http.MethodPost, "/"+f.ID+"/pagar", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, entity.StatusPaga, status(store, f.ID))
+
+	// 4. Transições inválidas viram 409
+	rec = do(h, http.MethodPost, "/"+f.ID+"/pagar", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "fatura_ja_paga", decodeError(rec).Code)
+
+	rec = do(h, http.MethodPost, "/"+f.ID+"/cancelar", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "cancelar_fatura_paga", decodeError(rec).Code)
+}
+
+func TestFaturaHandler_Pagamentos(t *testing.T) {
+	h, store, client := setup(t)
+	f := criarFatura(t, h, client.ID) // 150,50
+
+	t.Run("should record a partial payment", func(t *testing.T) {
+		rec := do(h, http.MethodPost, "/"+f.ID+"/pagamentos", `{"valor":"50,50","metodo":"pix","referencia_externa":"E1"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp faturaResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, entity.StatusParcialmentePaga, resp.Status)
+		assert.Equal(t, entity.BRL(5050), resp.ValorPago)
+		assert.Len(t, resp.Pagamentos, 1)
+	})
+
+	t.Run("should reject a replayed external reference", func(t *testing.T) {
+		rec := do(h, http.MethodPost, "/"+f.ID+"/pagamentos", `{"valor":10,"metodo":"pix","referencia_externa":"E1"}`)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "pagamento_duplicado", decodeError(rec).Code)
+	})
+
+	t.Run("should reject payments above the balance", func(t *testing.T) {
+		rec := do(h, http.MethodPost, "/"+f.ID+"/pagamentos", `{"valor":100.01,"metodo":"boleto"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "pagamento_excede_saldo", decodeError(rec).Code)
+	})
+
+	t.Run("should reject unknown methods", func(t *testing.T) {
+		rec := do(h, http.MethodPost, "/"+f.ID+"/pagamentos", `{"valor":10,"metodo":"cheque"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "metodo_pagamento_invalido", decodeError(rec).Code)
+	})
+
+	t.Run("should settle once payments cover the balance", func(t *testing.T) {
+		rec := do(h, http.MethodPost, "/"+f.ID+"/pagamentos", `{"valor":100,"metodo":"boleto"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, entity.StatusPaga, status(store, f.ID))
+
+		rec = do(h, http.MethodGet, "/"+f.ID+"/pagamentos", "")
+		var pagamentos []pagamentoResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &pagamentos))
+		assert.Len(t, pagamentos, 2)
+	})
+
+	t.Run("should reopen the fatura on refund", func(t *testing.T) {
+		found, _ := store.Faturas.FindByID(f.ID)
+		pagamentoID := found.Pagamentos[1].ID
+
+		rec := do(h, http.MethodPost, "/"+f.ID+"/pagamentos/"+pagamentoID+"/estornar", `{"motivo":"chargeback"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, entity.StatusParcialmentePaga, status(store, f.ID))
+
+		rec = do(h, http.MethodPost, "/"+f.ID+"/pagamentos/"+pagamentoID+"/estornar", "")
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "pagamento_ja_estornado", decodeError(rec).Code)
+
+		rec = do(h, http.MethodPost, "/"+f.ID+"/pagamentos/inexistente/estornar", "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should not cancel with active payments", func(t *testing.T) {
+		rec := do(h, http.MethodPost, "/"+f.ID+"/cancelar", "")
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "cancelar_fatura_com_pagamentos", decodeError(rec).Code)
+	})
+}
+
+func TestFaturaHandler_Pix(t *testing.T) {
+	h, store, client := setup(t)
+	client.UsuarioID = "user1"
+	store.Clientes.Update(client)
+
+	f := criarFatura(t, h, client.ID)
+
+	t.Run("should fail while the tenant has no pix", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/"+f.ID+"/pix", "")
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "pix_nao_configurado", decodeError(rec).Code)
+	})
+
+	config, _ := entity.NewConfiguracao("user1")
+	config.Pix = entity.ConfigPix{Chave: "financeiro@exemplo.com.br", NomeRecebedor: "Exemplo Ltda", Cidade: "Curitiba"}
+	store.Configuracoes.Save(config)
+
+	t.Run("should return the copia e cola payload", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/"+f.ID+"/pix", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp pixResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, entity.PixEstatico, resp.Tipo)
+		assert.Equal(t, entity.TxIDFatura(f.Numero), resp.TxID)
+		assert.Equal(t, entity.BRL(15050), resp.Valor)
+		assert.True(t, strings.HasPrefix(resp.CopiaECola, "000201"))
+	})
+
+	t.Run("should render the QR code as PNG", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/"+f.ID+"/pix/qrcode?tamanho=200", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "\x89PNG"))
+
+		rec = do(h, http.MethodGet, "/"+f.ID+"/pix/qrcode?tamanho=5000", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should reject dynamic codes without a PSP charge", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/"+f.ID+"/pix?tipo=dinamico", "")
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "pix_dinamico_sem_cobranca", decodeError(rec).Code)
+	})
+
+	t.Run("should register the PSP charge location", func(t *testing.T) {
+		rec := do(h, http.MethodPut, "/"+f.ID+"/pix/cobranca", `{"location":"https://pix.psp.com.br/qr/v2/abc"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "location_pix_invalida", decodeError(rec).Code)
+
+		rec = do(h, http.MethodPut, "/"+f.ID+"/pix/cobranca", `{"location":"pix.psp.com.br/qr/v2/abc"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = do(h, http.MethodGet, "/"+f.ID+"/pix", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp pixResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, entity.PixDinamico, resp.Tipo)
+		assert.Contains(t, resp.CopiaECola, "2524pix.psp.com.br/qr/v2/abc")
+	})
+}
+
+func TestFaturaHandler_Boleto(t *testing.T) {
+	h, store, client := setup(t)
+	client.UsuarioID = "user1"
+	store.Clientes.Update(client)
+
+	f := criarFatura(t, h, client.ID)
+
+	t.Run("should fail while the tenant has no boleto account", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/"+f.ID+"/boleto", "")
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "boleto_nao_configurado", decodeError(rec).Code)
+	})
+
+	config, _ := entity.NewConfiguracao("user1")
+	config.Boleto = entity.ConfigBoleto{Banco: "001", Convenio: "1234567", Carteira: "17"}
+	store.Configuracoes.Save(config)
+
+	t.Run("should return the barcode and the linha digitavel", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/"+f.ID+"/boleto", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp boletoResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, entity.BRL(15050), resp.Valor)
+		assert.Equal(t, f.DataVencimento.Format("2006-01-02"), resp.Vencimento)
+		assert.Len(t, resp.CodigoBarras, 44)
+		assert.Equal(t, entity.LinhaDigitavel(resp.CodigoBarras), resp.LinhaDigitavel)
+	})
+
+	t.Run("should render the barcode as PNG", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/"+f.ID+"/boleto/codigo-barras", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "\x89PNG"))
+
+		rec = do(h, http.MethodGet, "/"+f.ID+"/boleto/codigo-barras?largura=10", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestFaturaHandler_Valor(t *testing.T) {
+	h, _, client := setup(t)
+	vencimento := time.Now().AddDate(0, 0, 5).Format(time.RFC3339)
+
+	t.Run("should accept the brazilian format and answer with an exact number", func(t *testing.T) {
+		body := fmt.Sprintf(`{"cliente_id":"%s","valor":"1.234,56","data_vencimento":"%s"}`, client.ID, vencimento)
+		rec := do(h, http.MethodPost, "/", body)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"valor":1234.56`)
+		assert.Contains(t, rec.Body.String(), `"moeda":"BRL"`)
+	})
+
+	t.Run("should reject values with more than two decimal places", func(t *testing.T) {
+		body := fmt.Sprintf(`{"cliente_id":"%s","valor":10.005,"data_vencimento":"%s"}`, client.ID, vencimento)
+		rec := do(h, http.MethodPost, "/", body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestFaturaHandler_ValorDevido(t *testing.T) {
+	h, store, client := setup(t)
+	client.UsuarioID = "user1"
+	store.Clientes.Update(client)
+
+	config, _ := entity.NewConfiguracao("user1")
+	config.Encargos = entity.EncargosAtraso{Multa: 200, JurosAoMes: 100}
+	store.Configuracoes.Save(config)
+
+	f := criarFatura(t, h, client.ID)
+	data := f.DataVencimento.AddDate(0, 0, 30).Format("2006-01-02")
+
+	t.Run("should add fine and interest after the due date", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/"+f.ID+"/valor-devido?data="+data, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp valorDevidoResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, 30, resp.DiasAtraso)
+		assert.Equal(t, entity.BRL(15050), resp.Principal)
+		assert.Equal(t, entity.BRL(301), resp.Multa)
+		assert.Equal(t, entity.BRL(151), resp.Juros)
+		assert.Equal(t, entity.BRL(15502), resp.Total)
+	})
+
+	t.Run("should owe only the principal today", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/"+f.ID+"/valor-devido", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp valorDevidoResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, entity.BRL(15050), resp.Total)
+	})
+
+	t.Run("should reject an invalid date", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/"+f.ID+"/valor-devido?data=31/12/2026", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "data_invalida", decodeError(rec).Code)
+	})
+}
+
+func TestFaturaHandler_Itens(t *testing.T) {
+	h, _, client := setup(t)
+	vencimento := time.Now().AddDate(0, 0, 5).Format(time.RFC3339)
+	itens := `[{"descricao":"Consultoria","quantidade":2,"preco_unitario":"1.000,00","desconto":100,"aliquota_imposto":5},{"descricao":"Hospedagem","quantidade":1,"preco_unitario":49.9}]`
+
+	t.Run("should derive the valor from the items and return the breakdown", func(t *testing.T) {
+		body := fmt.Sprintf(`{"cliente_id":"%s","data_vencimento":"%s","itens":%s}`, client.ID, vencimento, itens)
+		rec := do(h, http.MethodPost, "/", body)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var f faturaResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &f))
+		assert.Equal(t, entity.BRL(204990), f.Subtotal)
+		assert.Equal(t, entity.BRL(10000), f.Descontos)
+		assert.Equal(t, entity.BRL(9500), f.Impostos)
+		assert.Equal(t, entity.BRL(204490), f.Valor)
+		assert.Len(t, f.Itens, 2)
+		assert.Equal(t, entity.BRL(199500), f.Itens[0].Total)
+	})
+
+	t.Run("should reject a valor that disagrees with the items", func(t *testing.T) {
+		body := fmt.Sprintf(`{"cliente_id":"%s","valor":100,"data_vencimento":"%s","itens":%s}`, client.ID, vencimento, itens)
+		rec := do(h, http.MethodPost, "/", body)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "total_divergente", decodeError(rec).Code)
+	})
+}
+
+func TestFaturaHandler_ConfirmacaoPagamento(t *testing.T) {
+	h, store, client := setup(t)
+	f := criarFatura(t, h, client.ID) // 150,50
+
+	confirmacoes := func() []*entity.Mensagem {
+		var msgs []*entity.Mensagem
+		for _, m := range store.Mensagens.All() {
+			if m.Tipo == entity.TipoMensagemConfirmacao {
+				msgs = append(msgs, m)
+			}
+		}
+		return msgs
+	}
+
+	t.Run("should not confirm partial payments", func(t *testing.T) {
+		rec := do(h, http.MethodPost, "/"+f.ID+"/pagamentos", `{"valor":50,"metodo":"pix"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, confirmacoes())
+	})
+
+	t.Run("should confirm once when the fatura is settled", func(t *testing.T) {
+		body := `{"valor":100.5,"metodo":"boleto","referencia_externa":"B1"}`
+		rec := do(h, http.MethodPost, "/"+f.ID+"/pagamentos", body)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		msgs := confirmacoes()
+		assert.Len(t, msgs, 1)
+		assert.Equal(t, client.WhatsApp, msgs[0].WhatsApp)
+		assert.Contains(t, msgs[0].Conteudo, "Valor pago: R$ 150,50")
+		assert.Contains(t, msgs[0].Conteudo, "Forma de pagamento: boleto")
+
+		// Repetir o pagamento ou a baixa não gera outra confirmação
+		rec = do(h, http.MethodPost, "/"+f.ID+"/pagamentos", body)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		rec = do(h, http.MethodPost, "/"+f.ID+"/pagar", "")
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Len(t, confirmacoes(), 1)
+	})
+
+	t.Run("should confirm the new payment after a refund", func(t *testing.T) {
+		found, _ := store.Faturas.FindByID(f.ID)
+		rec := do(h, http.MethodPost, "/"+f.ID+"/pagamentos/"+found.Pagamentos[1].ID+"/estornar", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, confirmacoes(), 1)
+
+		rec = do(h, http.MethodPost, "/"+f.ID+"/pagar", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, confirmacoes(), 2)
+
+		registradas, _ := store.Confirmacoes.FindByFaturaID(f.ID)
+		assert.Len(t, registradas, 2)
+		assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemEnfileirada), 2)
+	})
+
+	t.Run("should accept the payment and keep an unsendable confirmation in the DLQ", func(t *testing.T) {
+		outra := criarFatura(t, h, client.ID)
+
+		// Cadastro antigo sem WhatsApp válido: a mensagem não pode ser enviada
+		client.WhatsApp = ""
+		store.Clientes.Update(client)
+
+		rec := do(h, http.MethodPost, "/"+outra.ID+"/pagar", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		found, _ := store.Faturas.FindByID(outra.ID)
+		assert.Equal(t, entity.StatusPaga, found.Status)
+
+		registradas, _ := store.Confirmacoes.FindByFaturaID(outra.ID)
+		assert.Len(t, registradas, 1)
+		msg, _ := store.Mensagens.FindByID(registradas[0].MensagemID)
+		assert.Equal(t, entity.StatusMensagemDLQ, msg.Status)
+		assert.Equal(t, entity.ErrWhatsAppVazio.Error(), msg.ErroMensagem)
+	})
+}
+
+func TestFaturaHandler_Historico(t *testing.T) {
+	h, _, client := setup(t)
+
+	antesDaCriacao := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	f := criarFatura(t, h, client.ID)
+	antesDoPagamento := time.Now().Format(time.RFC3339Nano)
+	time.Sleep(time.Millisecond)
+	do(h, http.MethodPost, "/"+f.ID+"/pagar", "")
+
+	// Transição rejeitada não gera evento
+	do(h, http.MethodPost, "/"+f.ID+"/cancelar", "")
+
+	rec := do(h, http.MethodGet, "/"+f.ID+"/historico", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var historico []eventoResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &historico))
+	assert.Len(t, historico, 3)
+	assert.Equal(t, entity.EventFaturaCriada, historico[0].Tipo)
+	assert.Equal(t, entity.EventFaturaPagamentoRegistrado, historico[1].Tipo)
+	assert.Equal(t, entity.EventFaturaPaga, historico[2].Tipo)
+	assert.Equal(t, 3, historico[2].Versao)
+
+	t.Run("should return the fatura as of a past date", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/"+f.ID+"?em="+antesDoPagamento, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var passado faturaResponse
+		json.Unmarshal(rec.Body.Bytes(), &passado)
+		assert.Equal(t, entity.StatusPendente, passado.Status)
+
+		rec = do(h, http.MethodGet, "/"+f.ID+"?em="+antesDaCriacao, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = do(h, http.MethodGet, "/"+f.ID+"?em=ontem", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestFaturaHandler_Cancelamento(t *testing.T) {
+	h, _, client := setup(t)
+	f := criarFatura(t, h, client.ID)
+
+	rec := do(h, http.MethodPost, "/"+f.ID+"/cancelar", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = do(h, http.MethodPost, "/"+f.ID+"/pagar", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "pagar_fatura_cancelada", decodeError(rec).Code)
+}
+
+func TestFaturaHandler_Erros(t *testing.T) {
+	h, store, client := setup(t)
+
+	t.Run("should map validation errors to 422", func(t *testing.T) {
+		vencimento := time.Now().AddDate(0, 0, 5).Format(time.RFC3339)
+		rec := do(h, http.MethodPost, "/", fmt.Sprintf(`{"cliente_id":"%s","valor":0,"data_vencimento":"%s"}`, client.ID, vencimento))
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "valor_invalido", decodeError(rec).Code)
+	})
+
+	t.Run("should reject unknown cliente", func(t *testing.T) {
+		vencimento := time.Now().AddDate(0, 0, 5).Format(time.RFC3339)
+		rec := do(h, http.MethodPost, "/", fmt.Sprintf(`{"cliente_id":"00000000-0000-0000-0000-000000000000","valor":10,"data_vencimento":"%s"}`, vencimento))
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "cliente_nao_encontrado", decodeError(rec).Code)
+	})
+
+	t.Run("should number and store the fatura under the tenant of the client", func(t *testing.T) {
+		client.UsuarioID = "user1"
+		store.Clientes.Update(client)
+
+		f := criarFatura(t, h, client.ID)
+		assert.Equal(t, entity.FormatoNumeracaoPadrao().Formatar(1, time.Now()), f.Numero)
+
+		salva, _ := store.Faturas.FindByID(f.ID)
+		assert.Equal(t, "user1", salva.UsuarioID)
+	})
+
+	t.Run("should require cliente_id on list", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should return 404 for unknown fatura", func(t *testing.T) {
+		rec := do(h, http.MethodPost, "/00000000-0000-0000-0000-000000000000/pagar", "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "fatura_nao_encontrada", decodeError(rec).Code)
+	})
+}
+
+func TestFaturaHandler_PreviewMensagem(t *testing.T) {
+	h, store, client := setup(t)
+	client.UsuarioID = "user1"
+	store.Clientes.Update(client)
+
+	config, _ := entity.NewConfiguracao("user1")
+	config.Pix = entity.ConfigPix{Chave: "financeiro@exemplo.com.br", NomeRecebedor: "Exemplo Ltda", Cidade: "Curitiba"}
+	store.Configuracoes.Save(config)
+
+	f := criarFatura(t, h, client.ID)
+
+	rec := do(h, http.MethodPost, "/"+f.ID+"/mensagens/preview", `{"template":"Ola {{cliente}}, {{numero}} de {{valor}} vence em {{dias_para_vencer}} dias. {{link_pagamento}}"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp previewResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.Equal(t, fmt.Sprintf("Ola Cliente 1, %s de R$ 150,50 vence em 5 dias. https://api.exemplo.com.br/faturas/%s/pix/qrcode", f.Numero, f.ID), resp.Conteudo)
+
+	// Nada é enviado
+	assert.Empty(t, store.Mensagens.All())
+
+	rec = do(h, http.MethodPost, "/"+f.ID+"/mensagens/preview", `{"template":"Ola {{nome}}"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "variavel_desconhecida", decodeError(rec).Code)
+
+	rec = do(h, http.MethodPost, "/"+f.ID+"/mensagens/preview", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,131 @@
+package relatorio
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/http/shared"
+)
+
+// Período padrão do relatório de envios quando de/ate não são informados
+const diasPadraoEnvios = 30
+
+// RelatorioHandler expõe os read models mantidos pelas projeções.
+// Os dados são eventualmente consistentes: refletem os eventos já processados pelo projector.
+type RelatorioHandler struct {
+	recebiveis   repository.RecebiveisRepository
+	estatisticas repository.EstatisticasEnvioRepository
+	logger       *zap.Logger
+}
+
+func NewRelatorioHandler(recebiveis repository.RecebiveisRepository, estatisticas repository.EstatisticasEnvioRepository, logger *zap.Logger) *RelatorioHandler {
+	return &RelatorioHandler{recebiveis: recebiveis, estatisticas: estatisticas, logger: logger}
+}
+
+// Routes monta as rotas do recurso /relatorios
+func (h *RelatorioHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/recebiveis/{clienteID}", h.Recebiveis)
+	r.Get("/envios", h.Envios)
+
+	return r
+}
+
+type recebiveisResponse struct {
+	ClienteID          string          `json:"cliente_id"`
+	QuantidadeEmAberto int             `json:"quantidade_em_aberto"`
+	ValorEmAberto      entity.Dinheiro `json:"valor_em_aberto"`
+	QuantidadeVencida  int             `json:"quantidade_vencida"`
+	ValorVencido       entity.Dinheiro `json:"valor_vencido"`
+	QuantidadePaga     int             `json:"quantidade_paga"`
+	ValorRecebido      entity.Dinheiro `json:"valor_recebido"`
+	AtualizadoEm       time.Time       `json:"atualizado_em"`
+}
+
+type envioResponse struct {
+	Dia          string              `json:"dia"`
+	Tipo         entity.TipoMensagem `json:"tipo"`
+	Enfileiradas int                 `json:"enfileiradas"`
+	Enviadas     int                 `json:"enviadas"`
+	Falhas       int                 `json:"falhas"`
+	DLQ          int                 `json:"dlq"`
+}
+
+// Recebiveis retorna os totais do cliente; um cliente sem faturas projetadas tem tudo zerado
+func (h *RelatorioHandler) Recebiveis(w http.ResponseWriter, r *http.Request) {
+	clienteID := chi.URLParam(r, "clienteID")
+
+	rec, err := h.recebiveis.FindByClienteID(clienteID)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+	if rec == nil {
+		rec = &entity.RecebiveisCliente{ClienteID: clienteID}
+	}
+
+	shared.WriteJSON(w, http.StatusOK, recebiveisResponse{
+		ClienteID:          rec.ClienteID,
+		QuantidadeEmAberto: rec.QuantidadeEmAberto,
+		ValorEmAberto:      rec.ValorEmAberto,
+		QuantidadeVencida:  rec.QuantidadeVencida,
+		ValorVencido:       rec.ValorVencido,
+		QuantidadePaga:     rec.QuantidadePaga,
+		ValorRecebido:      rec.ValorRecebido,
+		AtualizadoEm:       rec.AtualizadoEm,
+	})
+}
+
+// Envios retorna as estatísticas por dia e tipo no período ?de=AAAA-MM-DD&ate=AAAA-MM-DD
+// (padrão: últimos 30 dias)
+func (h *RelatorioHandler) Envios(w http.ResponseWriter, r *http.Request) {
+	agora := time.Now()
+	hoje := time.Date(agora.Year(), agora.Month(), agora.Day(), 0, 0, 0, 0, time.UTC)
+
+	ate, ok := parseDia(w, r.URL.Query().Get("ate"), hoje)
+	if !ok {
+		return
+	}
+	de, ok := parseDia(w, r.URL.Query().Get("de"), ate.AddDate(0, 0, -diasPadraoEnvios))
+	if !ok {
+		return
+	}
+
+	stats, err := h.estatisticas.FindPeriodo(de, ate)
+	if err != nil {
+		shared.HandleError(w, h.logger, err)
+		return
+	}
+
+	resp := make([]envioResponse, 0, len(stats))
+	for _, e := range stats {
+		resp = append(resp, envioResponse{
+			Dia:          e.Dia.Format("2006-01-02"),
+			Tipo:         e.Tipo,
+			Enfileiradas: e.Enfileiradas,
+			Enviadas:     e.Enviadas,
+			Falhas:       e.Falhas,
+			DLQ:          e.DLQ,
+		})
+	}
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+func parseDia(w http.ResponseWriter, valor string, padrao time.Time) (time.Time, bool) {
+	if valor == "" {
+		return padrao, true
+	}
+
+	dia, err := time.Parse("2006-01-02", valor)
+	if err != nil {
+		shared.WriteError(w, http.StatusBadRequest, "data_invalida", "datas devem estar no formato AAAA-MM-DD")
+		return time.Time{}, false
+	}
+	return dia, true
+}
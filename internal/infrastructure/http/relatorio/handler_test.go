@@ -0,0 +1,75 @@
+package relatorio
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func do(h http.Handler, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRelatorioHandler_Recebiveis(t *testing.T) {
+	leitura := memory.NewLeituraStore()
+	h := NewRelatorioHandler(leitura.Recebiveis, leitura.EstatisticasEnvio, zap.NewNop()).Routes()
+
+	leitura.Recebiveis.SaveCliente(&entity.RecebiveisCliente{ClienteID: "cli-1", QuantidadeEmAberto: 2, ValorEmAberto: entity.BRL(15050)})
+
+	t.Run("should return the projected totals", func(t *testing.T) {
+		rec := do(h, "/recebiveis/cli-1")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp recebiveisResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, 2, resp.QuantidadeEmAberto)
+		assert.Equal(t, entity.BRL(15050), resp.ValorEmAberto)
+	})
+
+	t.Run("should return zeros for a cliente without faturas", func(t *testing.T) {
+		rec := do(h, "/recebiveis/cli-2")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp recebiveisResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "cli-2", resp.ClienteID)
+		assert.True(t, resp.ValorEmAberto.IsZero())
+	})
+}
+
+func TestRelatorioHandler_Envios(t *testing.T) {
+	leitura := memory.NewLeituraStore()
+	h := NewRelatorioHandler(leitura.Recebiveis, leitura.EstatisticasEnvio, zap.NewNop()).Routes()
+
+	dia := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	leitura.EstatisticasEnvio.Incrementar(&entity.EstatisticasEnvio{Dia: dia, Tipo: entity.TipoMensagemLembrete, Enfileiradas: 3, Enviadas: 2})
+	leitura.EstatisticasEnvio.Incrementar(&entity.EstatisticasEnvio{Dia: dia.AddDate(0, 0, 5), Tipo: entity.TipoMensagemCobranca, DLQ: 1})
+
+	t.Run("should filter by period", func(t *testing.T) {
+		rec := do(h, "/envios?de=2026-03-01&ate=2026-03-12")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp []envioResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Len(t, resp, 1)
+		assert.Equal(t, "2026-03-10", resp[0].Dia)
+		assert.Equal(t, 3, resp[0].Enfileiradas)
+		assert.Equal(t, 2, resp[0].Enviadas)
+	})
+
+	t.Run("should reject an invalid date", func(t *testing.T) {
+		rec := do(h, "/envios?de=10/03/2026")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
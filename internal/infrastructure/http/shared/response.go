@@ -0,0 +1,182 @@
+package shared
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/lib/pq"
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/usecase/template"
+	"go.uber.org/zap"
+)
+
+// ErrorResponse é o corpo padrão de erro da API.
+// O Code é estável e pode ser usado pelos clientes da API; a Message é apenas informativa.
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type apiError struct {
+	err    error
+	status int
+	code   string
+}
+
+// Tabela de erros de domínio conhecidos -> status HTTP + código estável
+var domainErrors = []apiError{
+	// Cliente
+	{entity.ErrNomeCurto, http.StatusUnprocessableEntity, "nome_curto"},
+	{entity.ErrWhatsAppInvalido, http.StatusUnprocessableEntity, "whatsapp_invalido"},
+	{entity.ErrClienteNaoEncontrado, http.StatusUnprocessableEntity, "cliente_nao_encontrado"},
+	{entity.ErrEmailInvalido, http.StatusUnprocessableEntity, "email_invalido"},
+
+	// Fatura
+	{entity.ErrValorInvalido, http.StatusUnprocessableEntity, "valor_invalido"},
+	{entity.ErrMoedaNaoSuportada, http.StatusUnprocessableEntity, "moeda_nao_suportada"},
+	{entity.ErrEstouroMonetario, http.StatusUnprocessableEntity, "valor_fora_do_limite"},
+	{entity.ErrNumeroObrigatorio, http.StatusUnprocessableEntity, "numero_obrigatorio"},
+	{entity.ErrDescricaoItemObrigatoria, http.StatusUnprocessableEntity, "descricao_item_obrigatoria"},
+	{entity.ErrQuantidadeInvalida, http.StatusUnprocessableEntity, "quantidade_invalida"},
+	{entity.ErrPrecoUnitarioInvalido, http.StatusUnprocessableEntity, "preco_unitario_invalido"},
+	{entity.ErrDescontoInvalido, http.StatusUnprocessableEntity, "desconto_invalido"},
+	{entity.ErrAliquotaInvalida, http.StatusUnprocessableEntity, "aliquota_invalida"},
+	{entity.ErrTotalDivergente, http.StatusUnprocessableEntity, "total_divergente"},
+	{entity.ErrVencimentoPassado, http.StatusUnprocessableEntity, "vencimento_passado"},
+	{entity.ErrFaturaJaPaga, http.StatusConflict, "fatura_ja_paga"},
+	{entity.ErrFaturaJaCancelada, http.StatusConflict, "fatura_ja_cancelada"},
+	{entity.ErrCancelarFaturaPaga, http.StatusConflict, "cancelar_fatura_paga"},
+	{entity.ErrPagarFaturaCancelada, http.StatusConflict, "pagar_fatura_cancelada"},
+	{entity.ErrCancelarFaturaComPagamentos, http.StatusConflict, "cancelar_fatura_com_pagamentos"},
+
+	// Pagamento
+	{entity.ErrValorPagamentoInvalido, http.StatusUnprocessableEntity, "valor_pagamento_invalido"},
+	{entity.ErrMetodoPagamentoInvalido, http.StatusUnprocessableEntity, "metodo_pagamento_invalido"},
+	{entity.ErrDataPagamentoFutura, http.StatusUnprocessableEntity, "data_pagamento_futura"},
+	{entity.ErrPagamentoExcedeSaldo, http.StatusUnprocessableEntity, "pagamento_excede_saldo"},
+	{entity.ErrPagamentoDuplicado, http.StatusConflict, "pagamento_duplicado"},
+	{entity.ErrPagamentoNaoEncontrado, http.StatusNotFound, "pagamento_nao_encontrado"},
+	{entity.ErrPagamentoJaEstornado, http.StatusConflict, "pagamento_ja_estornado"},
+
+	// Configuracao
+	{entity.ErrUsuarioIDObrigatorio, http.StatusUnprocessableEntity, "usuario_id_obrigatorio"},
+	{entity.ErrDiasInvalidos, http.StatusUnprocessableEntity, "dias_invalidos"},
+	{entity.ErrFormatoHoraInvalido, http.StatusUnprocessableEntity, "formato_hora_invalido"},
+	{entity.ErrPrefixoNumeracaoInvalido, http.StatusUnprocessableEntity, "prefixo_numeracao_invalido"},
+	{entity.ErrDigitosNumeracaoInvalido, http.StatusUnprocessableEntity, "digitos_numeracao_invalidos"},
+	{entity.ErrMultaInvalida, http.StatusUnprocessableEntity, "multa_invalida"},
+	{entity.ErrJurosInvalidos, http.StatusUnprocessableEntity, "juros_invalidos"},
+	{entity.ErrCarenciaInvalida, http.StatusUnprocessableEntity, "carencia_invalida"},
+	{entity.ErrChavePixInvalida, http.StatusUnprocessableEntity, "chave_pix_invalida"},
+	{entity.ErrNomeRecebedorPix, http.StatusUnprocessableEntity, "nome_recebedor_pix_invalido"},
+	{entity.ErrCidadePix, http.StatusUnprocessableEntity, "cidade_pix_invalida"},
+	{entity.ErrBancoBoletoInvalido, http.StatusUnprocessableEntity, "banco_boleto_invalido"},
+	{entity.ErrConvenioBoletoInvalido, http.StatusUnprocessableEntity, "convenio_boleto_invalido"},
+	{entity.ErrCarteiraBoletoInvalida, http.StatusUnprocessableEntity, "carteira_boleto_invalida"},
+	{entity.ErrReguaMuitasEtapas, http.StatusUnprocessableEntity, "regua_muitas_etapas"},
+	{entity.ErrDiasEtapaInvalidos, http.StatusUnprocessableEntity, "dias_etapa_invalidos"},
+	{entity.ErrEtapaDuplicada, http.StatusUnprocessableEntity, "etapa_duplicada"},
+	{entity.ErrTipoEtapaInvalido, http.StatusUnprocessableEntity, "tipo_etapa_invalido"},
+	{entity.ErrTemplateEtapaExtenso, http.StatusUnprocessableEntity, "template_etapa_extenso"},
+
+	// Pix
+	{entity.ErrPixNaoConfigurado, http.StatusUnprocessableEntity, "pix_nao_configurado"},
+	{entity.ErrPixDinamicoSemCob, http.StatusUnprocessableEntity, "pix_dinamico_sem_cobranca"},
+	{entity.ErrLocationPix, http.StatusUnprocessableEntity, "location_pix_invalida"},
+	{entity.ErrTipoPixInvalido, http.StatusBadRequest, "tipo_pix_invalido"},
+	{entity.ErrValorPixForaDoLimite, http.StatusUnprocessableEntity, "valor_pix_fora_do_limite"},
+
+	// Boleto
+	{entity.ErrBoletoNaoConfigurado, http.StatusUnprocessableEntity, "boleto_nao_configurado"},
+	{entity.ErrVencimentoBoletoForaDoLimite, http.StatusUnprocessableEntity, "vencimento_boleto_fora_do_limite"},
+	{entity.ErrValorBoletoForaDoLimite, http.StatusUnprocessableEntity, "valor_boleto_fora_do_limite"},
+
+	// Assinatura
+	{entity.ErrPeriodicidadeInvalida, http.StatusUnprocessableEntity, "periodicidade_invalida"},
+	{entity.ErrDiaVencimentoInvalido, http.StatusUnprocessableEntity, "dia_vencimento_invalido"},
+	{entity.ErrDescricaoObrigatoria, http.StatusUnprocessableEntity, "descricao_obrigatoria"},
+	{entity.ErrFimAntesDoInicio, http.StatusUnprocessableEntity, "fim_antes_do_inicio"},
+	{entity.ErrAssinaturaSemCiclosAtivos, http.StatusUnprocessableEntity, "assinatura_sem_ciclos"},
+	{entity.ErrAssinaturaNaoEncontrada, http.StatusNotFound, "assinatura_nao_encontrada"},
+	{entity.ErrAssinaturaNaoAtiva, http.StatusConflict, "assinatura_nao_ativa"},
+	{entity.ErrAssinaturaNaoPausada, http.StatusConflict, "assinatura_nao_pausada"},
+	{entity.ErrAssinaturaFinalizada, http.StatusConflict, "assinatura_finalizada"},
+
+	// Templates de mensagem
+	{template.ErrVariavelDesconhecida, http.StatusUnprocessableEntity, "variavel_desconhecida"},
+	{template.ErrTemplateMalFormado, http.StatusUnprocessableEntity, "template_mal_formado"},
+
+	// Event store: outra requisição alterou o agregado ao mesmo tempo
+	{repository.ErrConflitoVersao, http.StatusConflict, "conflito_versao"},
+}
+
+// WriteJSON serializa o payload como JSON com o status informado
+func WriteJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if payload != nil {
+		json.NewEncoder(w).Encode(payload)
+	}
+}
+
+// WriteError escreve um erro no formato padrão da API
+func WriteError(w http.ResponseWriter, status int, code, message string) {
+	WriteJSON(w, status, ErrorResponse{Code: code, Message: message})
+}
+
+// HandleError traduz erros de domínio e de banco para respostas HTTP.
+// Erros desconhecidos são logados e viram 500 sem expor detalhes internos.
+func HandleError(w http.ResponseWriter, logger *zap.Logger, err error) {
+	if writeDomainError(w, err) {
+		return
+	}
+
+	logger.Error("Erro inesperado ao processar requisicao", zap.Error(err))
+	WriteError(w, http.StatusInternalServerError, "erro_interno", "erro interno do servidor")
+}
+
+// Erros cuja mensagem completa é segura para o cliente da API e diz o que corrigir
+// (ex.: a variável desconhecida do template). Os demais expõem só a mensagem base.
+var errosComDetalhe = []error{template.ErrVariavelDesconhecida}
+
+func writeDomainError(w http.ResponseWriter, err error) bool {
+	for _, de := range domainErrors {
+		if errors.Is(err, de.err) {
+			WriteError(w, de.status, de.code, mensagem(err, de.err))
+			return true
+		}
+	}
+
+	// Violações de constraint do Postgres
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505": // unique_violation
+			WriteError(w, http.StatusConflict, "registro_duplicado", "registro ja existe")
+			return true
+		case "23503": // foreign_key_violation
+			WriteError(w, http.StatusConflict, "registro_referenciado", "registro possui dependencias")
+			return true
+		}
+	}
+
+	return false
+}
+
+func mensagem(err, base error) string {
+	for _, e := range errosComDetalhe {
+		if e == base {
+			return err.Error()
+		}
+	}
+	return base.Error()
+}
+
+// DecodeJSON lê o corpo da requisição para dst, rejeitando campos desconhecidos
+func DecodeJSON(r *http.Request, dst interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	return dec.Decode(dst)
+}
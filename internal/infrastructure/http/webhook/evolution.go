@@ -0,0 +1,221 @@
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/http/shared"
+	"github.com/teusf/billing-system/internal/usecase/entrega"
+)
+
+// Tamanho máximo aceito do corpo de um webhook
+const tamanhoMaximoCorpo = 1 << 20
+
+// EvolutionHandler recebe os webhooks de status de mensagem da Evolution API
+// (evento messages.update) e avança as mensagens para entregue ou lida.
+//
+// A chave compartilhada pode vir no header apikey (headers configurados no webhook)
+// ou no campo apikey do corpo, que a Evolution preenche com a chave da instância.
+// Chamadas sem a chave certa recebem 401; as demais recebem 200 mesmo quando nada
+// muda (status repetido), para que a Evolution não as reenvie. O status de uma mensagem
+// desconhecida fica guardado, pois o webhook pode chegar antes de o envio ser gravado.
+type EvolutionHandler struct {
+	entregas *entrega.Service
+	chave    string
+	logger   *zap.Logger
+}
+
+func NewEvolutionHandler(entregas *entrega.Service, chave string, logger *zap.Logger) *EvolutionHandler {
+	return &EvolutionHandler{entregas: entregas, chave: chave, logger: logger}
+}
+
+// Routes monta as rotas do recurso /webhooks/evolution. Com "webhook by events" ligado,
+// a Evolution acrescenta o nome do evento à URL (/webhooks/evolution/messages-update).
+func (h *EvolutionHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/", h.Receber)
+	r.Post("/{evento}", h.Receber)
+
+	return r
+}
+
+// evolutionWebhook é o envelope dos webhooks da Evolution (v1 e v2)
+type evolutionWebhook struct {
+	Event    string          `json:"event"`
+	Instance string          `json:"instance"`
+	Data     json.RawMessage `json:"data"` // Objeto na v2, lista na v1
+	DateTime string          `json:"date_time"`
+	APIKey   string          `json:"apikey"`
+}
+
+// statusEvolution é um item de messages.update. A v2 manda keyId/status na raiz;
+// a v1 manda key.id e update.status. O status pode vir como texto ou número.
+type statusEvolution struct {
+	KeyID  string          `json:"keyId"`
+	FromMe *bool           `json:"fromMe"`
+	Status json.RawMessage `json:"status"`
+	Key    struct {
+		ID     string `json:"id"`
+		FromMe *bool  `json:"fromMe"`
+	} `json:"key"`
+	Update struct {
+		Status json.RawMessage `json:"status"`
+	} `json:"update"`
+}
+
+type webhookResponse struct {
+	Aplicadas     int `json:"aplicadas"`
+	Ignoradas     int `json:"ignoradas"`
+	Desconhecidas int `json:"desconhecidas"`
+}
+
+func (h *EvolutionHandler) Receber(w http.ResponseWriter, r *http.Request) {
+	corpo, err := io.ReadAll(http.MaxBytesReader(w, r.Body, tamanhoMaximoCorpo))
+	if err != nil {
+		shared.WriteError(w, http.StatusBadRequest, "json_invalido", err.Error())
+		return
+	}
+
+	var webhook evolutionWebhook
+	if err := json.Unmarshal(corpo, &webhook); err != nil {
+		shared.WriteError(w, http.StatusBadRequest, "json_invalido", err.Error())
+		return
+	}
+
+	chave := r.Header.Get("apikey")
+	if chave == "" {
+		chave = webhook.APIKey
+	}
+	if h.chave == "" || subtle.ConstantTimeCompare([]byte(chave), []byte(h.chave)) != 1 {
+		shared.WriteError(w, http.StatusUnauthorized, "chave_invalida", "chave do webhook invalida")
+		return
+	}
+
+	var resp webhookResponse
+	if normalizarEvento(webhook.Event) != "messages.update" {
+		shared.WriteJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	itens, err := lerItens(webhook.Data)
+	if err != nil {
+		shared.WriteError(w, http.StatusBadRequest, "json_invalido", err.Error())
+		return
+	}
+
+	em := horario(webhook.DateTime)
+	for _, item := range itens {
+		atualizacao, ok := item.atualizacao(em)
+		if !ok {
+			resp.Ignoradas++
+			continue
+		}
+
+		resultado, err := h.entregas.Registrar(atualizacao)
+		if err != nil {
+			// 500 faz a Evolution reenviar; o reprocessamento dos itens já aplicados é inócuo
+			shared.HandleError(w, h.logger, err)
+			return
+		}
+
+		switch resultado {
+		case entrega.ResultadoAplicado:
+			resp.Aplicadas++
+		case entrega.ResultadoDesconhecida:
+			resp.Desconhecidas++
+			h.logger.Debug("Status de mensagem desconhecida recebido da Evolution", zap.String("id_provedor", atualizacao.IDProvedor))
+		default:
+			resp.Ignoradas++
+		}
+	}
+
+	shared.WriteJSON(w, http.StatusOK, resp)
+}
+
+// atualizacao traduz o item para o caso de uso. Itens de mensagens recebidas pela instância,
+// sem ID ou com status que não interessam (pendente, enviado ao servidor, erro) são ignorados.
+func (s statusEvolution) atualizacao(em time.Time) (entrega.Atualizacao, bool) {
+	id, fromMe, status := s.KeyID, s.FromMe, s.Status
+	if id == "" {
+		id, fromMe, status = s.Key.ID, s.Key.FromMe, s.Update.Status
+	}
+	if id == "" || (fromMe != nil && !*fromMe) {
+		return entrega.Atualizacao{}, false
+	}
+
+	st, ok := statusMensagem(status)
+	if !ok {
+		return entrega.Atualizacao{}, false
+	}
+
+	return entrega.Atualizacao{IDProvedor: id, Status: st, Em: em}, true
+}
+
+// statusMensagem mapeia os status do WhatsApp (Baileys): 3/DELIVERY_ACK é entregue,
+// 4/READ e 5/PLAYED (áudio ouvido) são lida
+func statusMensagem(raw json.RawMessage) (entity.StatusMensagem, bool) {
+	var texto string
+	if err := json.Unmarshal(raw, &texto); err != nil {
+		var numero int
+		if err := json.Unmarshal(raw, &numero); err != nil {
+			return "", false
+		}
+		switch numero {
+		case 3:
+			return entity.StatusMensagemEntregue, true
+		case 4, 5:
+			return entity.StatusMensagemLida, true
+		}
+		return "", false
+	}
+
+	switch strings.ToUpper(texto) {
+	case "DELIVERY_ACK":
+		return entity.StatusMensagemEntregue, true
+	case "READ", "PLAYED":
+		return entity.StatusMensagemLida, true
+	}
+	return "", false
+}
+
+func lerItens(data json.RawMessage) ([]statusEvolution, error) {
+	data = json.RawMessage(strings.TrimSpace(string(data)))
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		var itens []statusEvolution
+		err := json.Unmarshal(data, &itens)
+		return itens, err
+	}
+
+	var item statusEvolution
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	return []statusEvolution{item}, nil
+}
+
+// normalizarEvento aceita as grafias messages.update (v2) e MESSAGES_UPDATE (v1)
+func normalizarEvento(evento string) string {
+	return strings.ReplaceAll(strings.ToLower(evento), "_", ".")
+}
+
+// horario é o date_time do webhook; ausente ou inválido, o caso de uso usa a hora atual
+func horario(dateTime string) time.Time {
+	em, err := time.Parse(time.RFC3339Nano, dateTime)
+	if err != nil {
+		return time.Time{}
+	}
+	return em
+}
@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+	"github.com/teusf/billing-system/internal/usecase/entrega"
+)
+
+func setup(t *testing.T) (http.Handler, *memory.Store, *entity.Mensagem) {
+	t.Helper()
+
+	store := memory.NewStore()
+	msg, _ := entity.NewMensagem("fat-1", "cli-1", "5511999998888", "Ola", entity.TipoMensagemLembrete)
+	msg.MarcarComoEnviada("3EB0C767D26A")
+	store.Mensagens.Save(msg)
+
+	h := NewEvolutionHandler(entrega.NewService(store.UnitOfWork()), "segredo", zap.NewNop()).Routes()
+	return h, store, msg
+}
+
+func post(h http.Handler, path, chave, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if chave != "" {
+		req.Header.Set("apikey", chave)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) webhookResponse {
+	t.Helper()
+	var resp webhookResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+// Formato da Evolution v2
+const entregueV2 = `{"event":"messages.update","instance":"instance1","date_time":"2026-03-05T10:00:00.000Z",
+	"data":{"keyId":"3EB0C767D26A","remoteJid":"5511999998888@s.whatsapp.net","fromMe":true,"status":"DELIVERY_ACK"}}`
+
+func TestEvolutionHandler_Chave(t *testing.T) {
+	h, store, msg := setup(t)
+
+	t.Run("should reject a missing or wrong key", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, post(h, "/", "", entregueV2).Code)
+		assert.Equal(t, http.StatusUnauthorized, post(h, "/", "errada", entregueV2).Code)
+
+		found, _ := store.Mensagens.FindByID(msg.ID)
+		assert.Equal(t, entity.StatusMensagemEnviada, found.Status)
+	})
+
+	t.Run("should accept the key in the body", func(t *testing.T) {
+		body := strings.Replace(entregueV2, `"instance":"instance1"`, `"instance":"instance1","apikey":"segredo"`, 1)
+		rec := post(h, "/", "", body)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 1, decode(t, rec).Aplicadas)
+	})
+
+	t.Run("should reject everything without a configured key", func(t *testing.T) {
+		aberto := NewEvolutionHandler(entrega.NewService(store.UnitOfWork()), "", zap.NewNop()).Routes()
+		assert.Equal(t, http.StatusUnauthorized, post(aberto, "/", "", entregueV2).Code)
+	})
+}
+
+func TestEvolutionHandler_Status(t *testing.T) {
+	t.Run("should advance to delivered and read", func(t *testing.T) {
+		h, store, msg := setup(t)
+
+		rec := post(h, "/", "segredo", entregueV2)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, webhookResponse{Aplicadas: 1}, decode(t, rec))
+
+		// Formato da Evolution v1, com "webhook by events" (evento na URL)
+		lida := `{"event":"MESSAGES_UPDATE","date_time":"2026-03-05T11:30:00-03:00",
+			"data":[{"key":{"id":"3EB0C767D26A","fromMe":true},"update":{"status":4}}]}`
+		rec = post(h, "/messages-update", "segredo", lida)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, webhookResponse{Aplicadas: 1}, decode(t, rec))
+
+		found, _ := store.Mensagens.FindByID(msg.ID)
+		assert.Equal(t, entity.StatusMensagemLida, found.Status)
+		assert.True(t, time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC).Equal(*found.EntregueEm))
+		assert.True(t, time.Date(2026, 3, 5, 14, 30, 0, 0, time.UTC).Equal(*found.LidaEm))
+	})
+
+	t.Run("should be idempotent for replayed and unknown callbacks", func(t *testing.T) {
+		h, store, _ := setup(t)
+
+		post(h, "/", "segredo", entregueV2)
+		rec := post(h, "/", "segredo", entregueV2)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, webhookResponse{Ignoradas: 1}, decode(t, rec))
+
+		desconhecida := strings.Replace(entregueV2, "3EB0C767D26A", "OUTRA", 1)
+		rec = post(h, "/", "segredo", desconhecida)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, webhookResponse{Desconhecidas: 1}, decode(t, rec))
+
+		assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemEntregue), 1)
+	})
+
+	t.Run("should ignore other events and statuses", func(t *testing.T) {
+		h, store, msg := setup(t)
+
+		rec := post(h, "/", "segredo", `{"event":"connection.update","data":{"state":"open"}}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		servidor := strings.Replace(entregueV2, "DELIVERY_ACK", "SERVER_ACK", 1)
+		rec = post(h, "/", "segredo", servidor)
+		assert.Equal(t, webhookResponse{Ignoradas: 1}, decode(t, rec))
+
+		recebida := strings.Replace(entregueV2, `"fromMe":true`, `"fromMe":false`, 1)
+		rec = post(h, "/", "segredo", recebida)
+		assert.Equal(t, webhookResponse{Ignoradas: 1}, decode(t, rec))
+
+		found, _ := store.Mensagens.FindByID(msg.ID)
+		assert.Equal(t, entity.StatusMensagemEnviada, found.Status)
+	})
+
+	t.Run("should reject invalid JSON", func(t *testing.T) {
+		h, _, _ := setup(t)
+		assert.Equal(t, http.StatusBadRequest, post(h, "/", "segredo", `{"event":`).Code)
+	})
+}
@@ -0,0 +1,74 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrReprocessar pode ser retornado (ou embrulhado) por um Handler para devolver a
+// mensagem à fila em vez de mandá-la para a dead letter exchange
+var ErrReprocessar = errors.New("mensagem devolvida para reprocessamento")
+
+// Envelope é uma mensagem trafegando pelo broker
+type Envelope struct {
+	ID         string
+	RoutingKey string
+	Corpo      []byte
+	Headers    map[string]string
+	Expiracao  time.Duration // Zero = não expira. Ao expirar vai para a dead letter da fila
+}
+
+// Handler processa uma mensagem consumida.
+// nil confirma (ack); ErrReprocessar devolve para a fila; qualquer outro erro
+// rejeita sem requeue, o que envia a mensagem para a dead letter exchange da fila.
+type Handler func(ctx context.Context, env Envelope) error
+
+// Broker é a abstração sobre o RabbitMQ. O pacote memory fornece uma
+// implementação em memória com a mesma semântica para testes.
+type Broker interface {
+	// Declarar cria exchanges, filas e bindings (idempotente)
+	Declarar(t Topologia) error
+	// Publicar envia para uma exchange; exchange "" publica direto na fila com nome routingKey
+	Publicar(ctx context.Context, exchange, routingKey string, env Envelope) error
+	// Consumir entrega as mensagens da fila ao handler até o contexto ser cancelado
+	Consumir(ctx context.Context, fila string, handler Handler) error
+	Close() error
+}
+
+type TipoExchange string
+
+const (
+	ExchangeTopic  TipoExchange = "topic"
+	ExchangeDirect TipoExchange = "direct"
+)
+
+type Exchange struct {
+	Nome string
+	Tipo TipoExchange
+}
+
+type Binding struct {
+	Exchange   string
+	RoutingKey string
+}
+
+type Fila struct {
+	Nome     string
+	Bindings []Binding
+
+	// Destino de mensagens rejeitadas ou expiradas (x-dead-letter-exchange).
+	// DeadLetterExchange "" com DeadLetterRoutingKey devolve direto para outra fila.
+	DeadLetterExchange   string
+	DeadLetterRoutingKey string
+}
+
+// TemDeadLetter indica se a fila tem destino configurado para mensagens mortas
+func (f Fila) TemDeadLetter() bool {
+	return f.DeadLetterExchange != "" || f.DeadLetterRoutingKey != ""
+}
+
+type Topologia struct {
+	Exchanges []Exchange
+	Filas     []Fila
+}
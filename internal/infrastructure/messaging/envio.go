@@ -0,0 +1,93 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/usecase/envio"
+)
+
+// ConsumidorEnvio trata os MensagemEnfileirada da FilaEnvio.
+// Falhas temporárias voltam para a FilaEnvioRetry com o atraso do backoff;
+// falhas definitivas são rejeitadas e caem na FilaEnvioDLQ pela DLX.
+type ConsumidorEnvio struct {
+	broker      Broker
+	processador *envio.Processador
+	logger      *zap.Logger
+	agora       func() time.Time
+}
+
+func NewConsumidorEnvio(broker Broker, processador *envio.Processador, logger *zap.Logger) *ConsumidorEnvio {
+	return &ConsumidorEnvio{broker: broker, processador: processador, logger: logger, agora: time.Now}
+}
+
+// Run consome a FilaEnvio até o contexto ser cancelado
+func (c *ConsumidorEnvio) Run(ctx context.Context) error {
+	return c.broker.Consumir(ctx, FilaEnvio, c.Handle)
+}
+
+func (c *ConsumidorEnvio) Handle(ctx context.Context, env Envelope) error {
+	var event entity.Event
+	if err := json.Unmarshal(env.Corpo, &event); err != nil {
+		return fmt.Errorf("evento invalido: %w", err)
+	}
+	if event.EventType != entity.EventMensagemEnfileirada {
+		return fmt.Errorf("tipo de evento inesperado na fila de envio: %s", event.EventType)
+	}
+
+	// Mensagens publicadas antes de uma mudança de schema chegam na versão antiga
+	payload, err := entity.Eventos.Decodificar(&event)
+	if err != nil {
+		return fmt.Errorf("payload invalido: %w", err)
+	}
+	data := payload.(*entity.MensagemEnfileiradaData)
+
+	resultado, msg, err := c.processador.ProcessarPorID(ctx, data.MensagemID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrReprocessar, err)
+	}
+
+	switch resultado {
+	case envio.ResultadoRetentar:
+		return c.adiar(ctx, env, msg)
+	case envio.ResultadoDLQ:
+		return fmt.Errorf("envio da mensagem %s falhou definitivamente: %s", msg.ID, msg.ErroMensagem)
+	case envio.ResultadoIgnorada:
+		if msg == nil {
+			c.logger.Warn("Mensagem da fila nao encontrada", zap.String("mensagem_id", data.MensagemID))
+			return nil
+		}
+		// Em backoff ou reservada por outro worker: volta quando o prazo vencer.
+		// Se já tiver sido enviada até lá, a próxima entrega é simplesmente ignorada.
+		if aguardandoEnvio(msg) {
+			return c.adiar(ctx, env, msg)
+		}
+	}
+
+	return nil
+}
+
+// adiar publica o envelope na FilaEnvioRetry, que o devolve à FilaEnvio em ProximaTentativaEm
+func (c *ConsumidorEnvio) adiar(ctx context.Context, env Envelope, msg *entity.Mensagem) error {
+	env.Expiracao = time.Millisecond
+	if msg.ProximaTentativaEm != nil {
+		if atraso := msg.ProximaTentativaEm.Sub(c.agora()); atraso > env.Expiracao {
+			env.Expiracao = atraso
+		}
+	}
+
+	if err := c.broker.Publicar(ctx, "", FilaEnvioRetry, env); err != nil {
+		return fmt.Errorf("%w: %v", ErrReprocessar, err)
+	}
+	return nil
+}
+
+func aguardandoEnvio(m *entity.Mensagem) bool {
+	pendente := m.Status == entity.StatusMensagemPendente || m.Status == entity.StatusMensagemFalha
+	return pendente && m.TentativasEnvio < entity.MaxTentativasEnvio && m.ProximaTentativaEm != nil
+}
@@ -0,0 +1,109 @@
+package messaging_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/evolution"
+	"github.com/teusf/billing-system/internal/infrastructure/evolution/evolutiontest"
+	"github.com/teusf/billing-system/internal/infrastructure/messaging"
+	brokerMemory "github.com/teusf/billing-system/internal/infrastructure/messaging/memory"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+	"github.com/teusf/billing-system/internal/usecase/envio"
+)
+
+type ambiente struct {
+	broker     *brokerMemory.Broker
+	mensagens  *memory.MensagemMemory
+	evolution  *evolutiontest.Server
+	publicador *messaging.Publicador
+}
+
+// setup sobe o broker em memória com a topologia padrão e um consumidor rodando
+func setup(t *testing.T) *ambiente {
+	t.Helper()
+
+	srv := evolutiontest.NewServer("chave", "instance1")
+	t.Cleanup(srv.Close)
+
+	broker := brokerMemory.NewBroker()
+	assert.NoError(t, broker.Declarar(messaging.TopologiaPadrao()))
+
+	store := memory.NewStore()
+	mensagens := store.Mensagens
+	processador := envio.NewProcessador(mensagens, store.UnitOfWork(), evolution.NewClient(srv.URL, "chave", "instance1"), zap.NewNop(), envio.Opcoes{
+		BackoffBase: 10 * time.Millisecond,
+		BackoffMax:  50 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go messaging.NewConsumidorEnvio(broker, processador, zap.NewNop()).Run(ctx)
+
+	return &ambiente{broker: broker, mensagens: mensagens, evolution: srv, publicador: messaging.NewPublicador(broker)}
+}
+
+func (a *ambiente) enfileirar(t *testing.T, whatsapp string) *entity.Mensagem {
+	t.Helper()
+
+	msg, err := entity.NewMensagem("fat-1", "cli-1", whatsapp, "Ola", entity.TipoMensagemLembrete)
+	assert.NoError(t, err)
+	a.mensagens.Save(msg)
+
+	event, err := entity.NewMensagemEnfileiradaEvent(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, a.publicador.Publicar(context.Background(), event))
+
+	return msg
+}
+
+func (a *ambiente) status(id string) entity.StatusMensagem {
+	m, _ := a.mensagens.FindByID(id)
+	return m.Status
+}
+
+func TestConsumidorEnvio_Envia(t *testing.T) {
+	a := setup(t)
+
+	msg := a.enfileirar(t, "5511999998888")
+
+	assert.Eventually(t, func() bool { return a.status(msg.ID) == entity.StatusMensagemEnviada }, time.Second, 5*time.Millisecond)
+	assert.Len(t, a.evolution.Recebidas(), 1)
+
+	// Reentrega do mesmo evento é ignorada
+	event, _ := entity.NewMensagemEnfileiradaEvent(msg)
+	a.publicador.Publicar(context.Background(), event)
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, a.evolution.Recebidas(), 1)
+}
+
+func TestConsumidorEnvio_Retentativa(t *testing.T) {
+	a := setup(t)
+	a.evolution.FalharCom(evolutiontest.Resposta{Status: http.StatusServiceUnavailable})
+
+	msg := a.enfileirar(t, "5511999998888")
+
+	// A primeira falha vai para a fila de retry e volta após o backoff
+	assert.Eventually(t, func() bool { return a.status(msg.ID) == entity.StatusMensagemEnviada }, time.Second, 5*time.Millisecond)
+
+	m, _ := a.mensagens.FindByID(msg.ID)
+	assert.Equal(t, 2, m.TentativasEnvio)
+	assert.Empty(t, a.broker.Mensagens(messaging.FilaEnvioDLQ))
+}
+
+func TestConsumidorEnvio_DLQ(t *testing.T) {
+	a := setup(t)
+	a.evolution.FalharCom(evolutiontest.NumeroInexistente("5511900000000"))
+
+	msg := a.enfileirar(t, "5511900000000")
+
+	// Falha definitiva: rejeitada e roteada pela DLX
+	assert.Eventually(t, func() bool { return len(a.broker.Mensagens(messaging.FilaEnvioDLQ)) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, entity.StatusMensagemDLQ, a.status(msg.ID))
+}
@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teusf/billing-system/internal/infrastructure/messaging"
+)
+
+var _ messaging.Broker = (*Broker)(nil)
+
+// Broker implementa messaging.Broker em memória, com roteamento topic/direct,
+// dead letter e expiração de mensagens. Serve para testes sem RabbitMQ.
+//
+// Diferente do RabbitMQ, mensagens com Expiracao expiram pelo próprio timer
+// (e não só quando chegam à cabeça da fila).
+type Broker struct {
+	mu        sync.Mutex
+	exchanges map[string]messaging.TipoExchange
+	filas     map[string]*fila
+	ordem     []string // Ordem de declaração, usada no roteamento
+}
+
+type fila struct {
+	def      messaging.Fila
+	msgs     []messaging.Envelope
+	notifica chan struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		exchanges: map[string]messaging.TipoExchange{},
+		filas:     map[string]*fila{},
+	}
+}
+
+func (b *Broker) Declarar(t messaging.Topologia) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ex := range t.Exchanges {
+		b.exchanges[ex.Nome] = ex.Tipo
+	}
+	for _, f := range t.Filas {
+		if _, ok := b.filas[f.Nome]; ok {
+			continue
+		}
+		b.filas[f.Nome] = &fila{def: f, notifica: make(chan struct{}, 1)}
+		b.ordem = append(b.ordem, f.Nome)
+	}
+
+	return nil
+}
+
+func (b *Broker) Publicar(ctx context.Context, exchange, routingKey string, env messaging.Envelope) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if exchange != "" {
+		if _, ok := b.exchanges[exchange]; !ok {
+			return fmt.Errorf("exchange %s nao declarada", exchange)
+		}
+	}
+
+	env.RoutingKey = routingKey
+	b.rotear(exchange, env)
+	return nil
+}
+
+// rotear entrega nas filas que casam com exchange/routing key. Deve ser chamado com mu travado.
+func (b *Broker) rotear(exchange string, env messaging.Envelope) {
+	if exchange == "" {
+		if f, ok := b.filas[env.RoutingKey]; ok {
+			b.enfileirar(f, env)
+		}
+		return
+	}
+
+	tipo := b.exchanges[exchange]
+	for _, nome := range b.ordem {
+		f := b.filas[nome]
+		for _, bind := range f.def.Bindings {
+			if bind.Exchange != exchange {
+				continue
+			}
+			if tipo == messaging.ExchangeTopic && TopicoCasa(bind.RoutingKey, env.RoutingKey) ||
+				tipo != messaging.ExchangeTopic && bind.RoutingKey == env.RoutingKey {
+				b.enfileirar(f, env)
+				break
+			}
+		}
+	}
+}
+
+func (b *Broker) enfileirar(f *fila, env messaging.Envelope) {
+	if env.Expiracao > 0 {
+		expirada := env
+		expirada.Expiracao = 0
+		time.AfterFunc(env.Expiracao, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.deadLetter(f, expirada)
+		})
+		return
+	}
+
+	f.msgs = append(f.msgs, env)
+	select {
+	case f.notifica <- struct{}{}:
+	default:
+	}
+}
+
+// deadLetter encaminha para o destino configurado na fila (descarta se não houver)
+func (b *Broker) deadLetter(f *fila, env messaging.Envelope) {
+	if !f.def.TemDeadLetter() {
+		return
+	}
+	if f.def.DeadLetterRoutingKey != "" {
+		env.RoutingKey = f.def.DeadLetterRoutingKey
+	}
+	b.rotear(f.def.DeadLetterExchange, env)
+}
+
+func (b *Broker) Consumir(ctx context.Context, nome string, handler messaging.Handler) error {
+	b.mu.Lock()
+	f, ok := b.filas[nome]
+	b.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("fila %s nao declarada", nome)
+	}
+
+	for {
+		b.mu.Lock()
+		if len(f.msgs) == 0 {
+			b.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-f.notifica:
+				continue
+			}
+		}
+		env := f.msgs[0]
+		f.msgs = f.msgs[1:]
+		b.mu.Unlock()
+
+		err := handler(ctx, env)
+
+		b.mu.Lock()
+		switch {
+		case err == nil:
+		case errors.Is(err, messaging.ErrReprocessar):
+			f.msgs = append(f.msgs, env)
+		default:
+			b.deadLetter(f, env)
+		}
+		b.mu.Unlock()
+	}
+}
+
+// Mensagens retorna uma cópia das mensagens prontas na fila (não inclui as que aguardam expiração)
+func (b *Broker) Mensagens(nome string) []messaging.Envelope {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	f, ok := b.filas[nome]
+	if !ok {
+		return nil
+	}
+	return append([]messaging.Envelope(nil), f.msgs...)
+}
+
+func (b *Broker) Close() error {
+	return nil
+}
+
+// TopicoCasa aplica as regras de topic exchange do RabbitMQ:
+// "*" casa exatamente uma palavra e "#" casa zero ou mais.
+func TopicoCasa(padrao, chave string) bool {
+	return casa(strings.Split(padrao, "."), strings.Split(chave, "."))
+}
+
+func casa(padrao, chave []string) bool {
+	if len(padrao) == 0 {
+		return len(chave) == 0
+	}
+
+	switch padrao[0] {
+	case "#":
+		for i := 0; i <= len(chave); i++ {
+			if casa(padrao[1:], chave[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(chave) > 0 && casa(padrao[1:], chave[1:])
+	default:
+		return len(chave) > 0 && padrao[0] == chave[0] && casa(padrao[1:], chave[1:])
+	}
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teusf/billing-system/internal/infrastructure/messaging"
+)
+
+func TestTopicoCasa(t *testing.T) {
+	assert.True(t, TopicoCasa("fatura.*", "fatura.FaturaPaga"))
+	assert.True(t, TopicoCasa("#", "fatura.FaturaPaga"))
+	assert.True(t, TopicoCasa("fatura.#", "fatura"))
+	assert.True(t, TopicoCasa("*.FaturaPaga", "fatura.FaturaPaga"))
+	assert.False(t, TopicoCasa("fatura.*", "fatura.a.b"))
+	assert.False(t, TopicoCasa("mensagem.*", "fatura.FaturaPaga"))
+}
+
+func TestBroker_DeadLetter(t *testing.T) {
+	b := NewBroker()
+	b.Declarar(messaging.TopologiaPadrao())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handler que rejeita tudo: as mensagens devem cair na DLQ
+	go b.Consumir(ctx, messaging.FilaEnvio, func(ctx context.Context, env messaging.Envelope) error {
+		return errors.New("falha definitiva")
+	})
+
+	err := b.Publicar(ctx, messaging.ExchangeEventos, "mensagem.MensagemEnfileirada", messaging.Envelope{ID: "1"})
+	assert.NoError(t, err)
+
+	// Evento sem fila vinculada é descartado
+	err = b.Publicar(ctx, messaging.ExchangeEventos, "fatura.FaturaPaga", messaging.Envelope{ID: "2"})
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return len(b.Mensagens(messaging.FilaEnvioDLQ)) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "1", b.Mensagens(messaging.FilaEnvioDLQ)[0].ID)
+}
+
+func TestBroker_Expiracao(t *testing.T) {
+	b := NewBroker()
+	b.Declarar(messaging.TopologiaPadrao())
+
+	err := b.Publicar(context.Background(), "", messaging.FilaEnvioRetry, messaging.Envelope{ID: "1", Expiracao: 10 * time.Millisecond})
+	assert.NoError(t, err)
+	assert.Empty(t, b.Mensagens(messaging.FilaEnvioRetry))
+
+	// Ao expirar volta para a fila de envio
+	assert.Eventually(t, func() bool { return len(b.Mensagens(messaging.FilaEnvio)) == 1 }, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,49 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/gateway"
+)
+
+var _ gateway.PublicadorEventos = (*Publicador)(nil)
+
+// Publicador publica eventos de domínio na ExchangeEventos
+type Publicador struct {
+	broker Broker
+}
+
+func NewPublicador(broker Broker) *Publicador {
+	return &Publicador{broker: broker}
+}
+
+func (p *Publicador) Publicar(ctx context.Context, eventos ...*entity.Event) error {
+	for _, event := range eventos {
+		corpo, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar evento %s: %w", event.ID, err)
+		}
+
+		env := Envelope{
+			ID:         event.ID,
+			RoutingKey: RoutingKey(event),
+			Corpo:      corpo,
+			Headers: map[string]string{
+				"event_type":     event.EventType,
+				"aggregate_type": event.AggregateType,
+				"aggregate_id":   event.AggregateID,
+				"schema_version": strconv.Itoa(event.SchemaVersion),
+			},
+		}
+
+		if err := p.broker.Publicar(ctx, ExchangeEventos, env.RoutingKey, env); err != nil {
+			return fmt.Errorf("erro ao publicar evento %s: %w", event.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/infrastructure/messaging"
+)
+
+var _ messaging.Broker = (*Broker)(nil)
+
+// Broker implementa messaging.Broker sobre o RabbitMQ.
+// A publicação usa publisher confirms: Publicar só retorna depois que o broker aceitou a mensagem.
+// Se a conexão cair, Consumir retorna erro e o processo deve ser reiniciado.
+type Broker struct {
+	conn     *amqp.Connection
+	mu       sync.Mutex // Canais amqp não são seguros para uso concorrente
+	pubCh    *amqp.Channel
+	prefetch int
+	logger   *zap.Logger
+}
+
+// URL monta a URL de conexão a partir dos campos do config
+func URL(host, port, user, password string) string {
+	u := url.URL{
+		Scheme: "amqp",
+		User:   url.UserPassword(user, password),
+		Host:   host + ":" + port,
+		Path:   "/",
+	}
+	return u.String()
+}
+
+// Conectar abre a conexão e o canal de publicação.
+// prefetch limita quantas mensagens não confirmadas cada consumidor recebe.
+func Conectar(amqpURL string, prefetch int, logger *zap.Logger) (*Broker, error) {
+	conn, err := amqp.Dial(amqpURL)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar no rabbitmq: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("erro ao abrir canal: %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("erro ao habilitar publisher confirms: %w", err)
+	}
+
+	if prefetch <= 0 {
+		prefetch = 1
+	}
+
+	logger.Info("Conectado ao RabbitMQ")
+	return &Broker{conn: conn, pubCh: ch, prefetch: prefetch, logger: logger}, nil
+}
+
+func (b *Broker) Declarar(t messaging.Topologia) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ex := range t.Exchanges {
+		if err := b.pubCh.ExchangeDeclare(ex.Nome, string(ex.Tipo), true, false, false, false, nil); err != nil {
+			return fmt.Errorf("erro ao declarar exchange %s: %w", ex.Nome, err)
+		}
+	}
+
+	for _, f := range t.Filas {
+		args := amqp.Table{}
+		if f.TemDeadLetter() {
+			args["x-dead-letter-exchange"] = f.DeadLetterExchange
+			if f.DeadLetterRoutingKey != "" {
+				args["x-dead-letter-routing-key"] = f.DeadLetterRoutingKey
+			}
+		}
+
+		if _, err := b.pubCh.QueueDeclare(f.Nome, true, false, false, false, args); err != nil {
+			return fmt.Errorf("erro ao declarar fila %s: %w", f.Nome, err)
+		}
+
+		for _, bind := range f.Bindings {
+			if err := b.pubCh.QueueBind(f.Nome, bind.RoutingKey, bind.Exchange, false, nil); err != nil {
+				return fmt.Errorf("erro ao vincular fila %s: %w", f.Nome, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *Broker) Publicar(ctx context.Context, exchange, routingKey string, env messaging.Envelope) error {
+	headers := amqp.Table{}
+	for k, v := range env.Headers {
+		headers[k] = v
+	}
+
+	pub := amqp.Publishing{
+		MessageId:    env.ID,
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Headers:      headers,
+		Body:         env.Corpo,
+	}
+	if env.Expiracao > 0 {
+		pub.Expiration = strconv.FormatInt(env.Expiracao.Milliseconds(), 10)
+	}
+
+	b.mu.Lock()
+	confirmacao, err := b.pubCh.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, false, false, pub)
+	b.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("erro ao publicar: %w", err)
+	}
+
+	ok, err := confirmacao.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao aguardar confirmacao: %w", err)
+	}
+	if !ok {
+		return errors.New("publicacao recusada pelo rabbitmq")
+	}
+
+	return nil
+}
+
+func (b *Broker) Consumir(ctx context.Context, fila string, handler messaging.Handler) error {
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("erro ao abrir canal: %w", err)
+	}
+	defer ch.Close()
+
+	if err := ch.Qos(b.prefetch, 0, false); err != nil {
+		return fmt.Errorf("erro ao configurar prefetch: %w", err)
+	}
+
+	entregas, err := ch.Consume(fila, "", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao consumir fila %s: %w", fila, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-entregas:
+			if !ok {
+				return fmt.Errorf("canal da fila %s foi fechado", fila)
+			}
+			b.tratar(ctx, d, handler)
+		}
+	}
+}
+
+func (b *Broker) tratar(ctx context.Context, d amqp.Delivery, handler messaging.Handler) {
+	headers := map[string]string{}
+	for k, v := range d.Headers {
+		if s, ok := v.(string); ok {
+			headers[k] = s
+		}
+	}
+
+	err := handler(ctx, messaging.Envelope{
+		ID:         d.MessageId,
+		RoutingKey: d.RoutingKey,
+		Corpo:      d.Body,
+		Headers:    headers,
+	})
+
+	switch {
+	case err == nil:
+		err = d.Ack(false)
+	case errors.Is(err, messaging.ErrReprocessar):
+		b.logger.Warn("Mensagem devolvida para a fila", zap.String("message_id", d.MessageId), zap.Error(err))
+		err = d.Nack(false, true)
+	default:
+		b.logger.Warn("Mensagem rejeitada (dead letter)", zap.String("message_id", d.MessageId), zap.Error(err))
+		err = d.Nack(false, false)
+	}
+	if err != nil {
+		b.logger.Error("Falha ao confirmar mensagem", zap.String("message_id", d.MessageId), zap.Error(err))
+	}
+}
+
+func (b *Broker) Close() error {
+	return b.conn.Close()
+}
@@ -0,0 +1,56 @@
+package messaging
+
+import (
+	"strings"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+)
+
+const (
+	// ExchangeEventos recebe todos os eventos de domínio (routing key <agregado>.<EventType>)
+	ExchangeEventos = "billing.events"
+	// ExchangeDLX recebe as mensagens mortas; cada fila usa o próprio nome como routing key
+	ExchangeDLX = "billing.dlx"
+
+	// FilaEnvio recebe os MensagemEnfileirada e é consumida pelo worker de envio
+	FilaEnvio = "mensagens.enviar"
+	// FilaEnvioRetry segura as retentativas até o backoff expirar e então as devolve para FilaEnvio
+	FilaEnvioRetry = "mensagens.enviar.retry"
+	// FilaEnvioDLQ guarda as mensagens que falharam definitivamente
+	FilaEnvioDLQ = "mensagens.dlq"
+)
+
+// RoutingKey monta a routing key de um evento, ex: "fatura.FaturaPaga"
+func RoutingKey(event *entity.Event) string {
+	return strings.ToLower(event.AggregateType) + "." + event.EventType
+}
+
+// TopologiaPadrao declara tudo que o sistema usa no broker
+func TopologiaPadrao() Topologia {
+	return Topologia{
+		Exchanges: []Exchange{
+			{Nome: ExchangeEventos, Tipo: ExchangeTopic},
+			{Nome: ExchangeDLX, Tipo: ExchangeDirect},
+		},
+		Filas: []Fila{
+			{
+				Nome: FilaEnvio,
+				Bindings: []Binding{
+					{Exchange: ExchangeEventos, RoutingKey: "mensagem." + entity.EventMensagemEnfileirada},
+				},
+				DeadLetterExchange:   ExchangeDLX,
+				DeadLetterRoutingKey: FilaEnvio,
+			},
+			{
+				Nome:                 FilaEnvioRetry,
+				DeadLetterRoutingKey: FilaEnvio,
+			},
+			{
+				Nome: FilaEnvioDLQ,
+				Bindings: []Binding{
+					{Exchange: ExchangeDLX, RoutingKey: FilaEnvio},
+				},
+			},
+		},
+	}
+}
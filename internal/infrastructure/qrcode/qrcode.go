@@ -0,0 +1,28 @@
+package qrcode
+
+import (
+	"fmt"
+
+	goqrcode "github.com/skip2/go-qrcode"
+)
+
+// Tamanhos aceitos para a imagem, em pixels (lado do quadrado)
+const (
+	TamanhoPadrao = 256
+	TamanhoMinimo = 128
+	TamanhoMaximo = 1024
+)
+
+// PNG gera a imagem do QR code do conteúdo. A correção de erro média é a recomendada
+// para o BR Code: aguenta uma tela riscada sem deixar o código denso demais.
+func PNG(conteudo string, tamanho int) ([]byte, error) {
+	if tamanho < TamanhoMinimo || tamanho > TamanhoMaximo {
+		return nil, fmt.Errorf("tamanho do qr code deve estar entre %d e %d", TamanhoMinimo, TamanhoMaximo)
+	}
+
+	png, err := goqrcode.Encode(conteudo, goqrcode.Medium, tamanho)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar qr code: %w", err)
+	}
+	return png, nil
+}
@@ -0,0 +1,202 @@
+package assinatura
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/eventstore"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
+)
+
+var _ repository.AssinaturaRepository = (*AssinaturaPostgres)(nil)
+
+// AssinaturaPostgres grava a linha de assinaturas e os eventos pendentes no mesmo DBTX,
+// como o FaturaPostgres: Save e Update devem rodar dentro de uma transação.
+type AssinaturaPostgres struct {
+	db      shared.DBTX
+	eventos *eventstore.EventStorePostgres
+}
+
+func NewAssinaturaPostgres(db shared.DBTX) *AssinaturaPostgres {
+	return &AssinaturaPostgres{db: db, eventos: eventstore.NewEventStorePostgres(db)}
+}
+
+const colunas = `id, cliente_id, valor, descricao, periodicidade, dia_vencimento, data_inicio, data_fim, status, proximo_ciclo, versao, created_at, updated_at`
+
+func (r *AssinaturaPostgres) Save(a *entity.Assinatura) error {
+	_, err := r.db.Exec(`
+		INSERT INTO assinaturas (id, cliente_id, valor, descricao, periodicidade, dia_vencimento, data_inicio, data_fim, status, proximo_ciclo, proximo_vencimento, versao, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`,
+		a.ID,
+		a.ClienteID,
+		a.Valor,
+		a.Descricao,
+		a.Periodicidade,
+		a.DiaVencimento,
+		a.DataInicio,
+		a.DataFim,
+		a.Status,
+		a.ProximoCiclo,
+		a.ProximoVencimento(),
+		a.Versao+len(a.EventosPendentes()),
+		a.CreatedAt,
+		a.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar assinatura: %w", err)
+	}
+
+	return r.gravarEventos(a)
+}
+
+// FindByID trava a linha até o fim da transação: pausar, retomar e cancelar partem
+// do estado lido, e o gerador de faturas (SKIP LOCKED) pula a assinatura enquanto isso
+func (r *AssinaturaPostgres) FindByID(id string) (*entity.Assinatura, error) {
+	a, err := scan(r.db.QueryRow(`SELECT `+colunas+` FROM assinaturas WHERE id = $1 FOR UPDATE`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar assinatura: %w", err)
+	}
+	return a, nil
+}
+
+func (r *AssinaturaPostgres) FindByClienteID(clienteID string) ([]*entity.Assinatura, error) {
+	return r.query(`SELECT `+colunas+` FROM assinaturas WHERE cliente_id = $1 ORDER BY created_at`, clienteID)
+}
+
+// FindParaFaturar trava as linhas retornadas (SKIP LOCKED): dois geradores em paralelo
+// dividem as assinaturas em vez de disputar as mesmas
+func (r *AssinaturaPostgres) FindParaFaturar(ate time.Time, limite int) ([]*entity.Assinatura, error) {
+	return r.query(`
+		SELECT `+colunas+` FROM assinaturas
+		WHERE status = $1 AND proximo_vencimento <= $2
+		ORDER BY proximo_vencimento
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	`, entity.StatusAssinaturaAtiva, ate, limite)
+}
+
+func (r *AssinaturaPostgres) Update(a *entity.Assinatura) error {
+	res, err := r.db.Exec(`
+		UPDATE assinaturas
+		SET status = $1, proximo_ciclo = $2, proximo_vencimento = $3, updated_at = $4, versao = $5
+		WHERE id = $6 AND versao = $7
+	`,
+		a.Status,
+		a.ProximoCiclo,
+		a.ProximoVencimento(),
+		a.UpdatedAt,
+		a.Versao+len(a.EventosPendentes()),
+		a.ID,
+		a.Versao,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar assinatura: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar assinatura: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: assinatura %s", repository.ErrConflitoVersao, a.ID)
+	}
+
+	return r.gravarEventos(a)
+}
+
+func (r *AssinaturaPostgres) FaturaDoPeriodo(assinaturaID, periodo string) (string, error) {
+	var faturaID string
+	err := r.db.QueryRow(`
+		SELECT fatura_id FROM assinatura_periodos WHERE assinatura_id = $1 AND periodo = $2
+	`, assinaturaID, periodo).Scan(&faturaID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("erro ao buscar periodo da assinatura: %w", err)
+	}
+	return faturaID, nil
+}
+
+func (r *AssinaturaPostgres) RegistrarPeriodo(assinaturaID, periodo, faturaID string) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO assinatura_periodos (assinatura_id, periodo, fatura_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (assinatura_id, periodo) DO NOTHING
+	`, assinaturaID, periodo, faturaID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao registrar periodo da assinatura: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erro ao registrar periodo da assinatura: %w", err)
+	}
+	return n == 1, nil
+}
+
+// gravarEventos anexa os eventos pendentes ao stream da assinatura
+func (r *AssinaturaPostgres) gravarEventos(a *entity.Assinatura) error {
+	if len(a.EventosPendentes()) == 0 {
+		return nil
+	}
+
+	if err := r.eventos.Append(a.ID, a.Versao, a.EventosPendentes()...); err != nil {
+		return err
+	}
+
+	a.ConfirmarEventos()
+	return nil
+}
+
+func (r *AssinaturaPostgres) query(query string, args ...interface{}) ([]*entity.Assinatura, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar assinaturas: %w", err)
+	}
+	defer rows.Close()
+
+	var assinaturas []*entity.Assinatura
+	for rows.Next() {
+		a, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao scanear assinatura: %w", err)
+		}
+		assinaturas = append(assinaturas, a)
+	}
+	return assinaturas, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(s scanner) (*entity.Assinatura, error) {
+	var a entity.Assinatura
+	err := s.Scan(
+		&a.ID,
+		&a.ClienteID,
+		&a.Valor,
+		&a.Descricao,
+		&a.Periodicidade,
+		&a.DiaVencimento,
+		&a.DataInicio,
+		&a.DataFim,
+		&a.Status,
+		&a.ProximoCiclo,
+		&a.Versao,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
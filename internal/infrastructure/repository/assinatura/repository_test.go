@@ -0,0 +1,104 @@
+package assinatura
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/cliente"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/eventstore"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/fatura"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/testutils"
+)
+
+var testDB *sql.DB
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = testutils.SetupTestDB()
+	if err != nil {
+		log.Fatalf("Falha ao configurar banco de teste: %v", err)
+	}
+	defer testDB.Close()
+
+	code := m.Run()
+	testDB.Close()
+	os.Exit(code)
+}
+
+func TestAssinaturaPostgres(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "c1@test.com")
+	cliente.NewClientePostgres(tx).Save(client)
+
+	repo := NewAssinaturaPostgres(tx)
+
+	a, err := entity.NewAssinatura(client.ID, entity.BRL(50000), "Retainer mensal", entity.PeriodicidadeMensal, 10, time.Now(), nil)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Save(a))
+	assert.Equal(t, 1, a.Versao)
+
+	t.Run("should read back the subscription", func(t *testing.T) {
+		found, err := repo.FindByID(a.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, a.Valor, found.Valor)
+		assert.Equal(t, a.ProximoVencimento().Format("2006-01-02"), found.ProximoVencimento().Format("2006-01-02"))
+
+		lista, err := repo.FindByClienteID(client.ID)
+		assert.NoError(t, err)
+		assert.Len(t, lista, 1)
+	})
+
+	t.Run("should list only active subscriptions due until the date", func(t *testing.T) {
+		devidas, err := repo.FindParaFaturar(a.ProximoVencimento(), 10)
+		assert.NoError(t, err)
+		assert.Len(t, devidas, 1)
+
+		devidas, _ = repo.FindParaFaturar(a.ProximoVencimento().AddDate(0, 0, -1), 10)
+		assert.Len(t, devidas, 0)
+	})
+
+	t.Run("should register each period only once", func(t *testing.T) {
+		f, _ := entity.NewFatura(client.ID, "FAT-2026-000001", entity.BRL(50000), a.ProximoVencimento().Add(time.Hour), "Retainer mensal")
+		assert.NoError(t, fatura.NewFaturaPostgres(tx).Save(f))
+
+		ok, err := repo.RegistrarPeriodo(a.ID, a.Periodo(a.ProximoCiclo), f.ID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.RegistrarPeriodo(a.ID, a.Periodo(a.ProximoCiclo), f.ID)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		faturaID, err := repo.FaturaDoPeriodo(a.ID, a.Periodo(a.ProximoCiclo))
+		assert.NoError(t, err)
+		assert.Equal(t, f.ID, faturaID)
+
+		faturaID, _ = repo.FaturaDoPeriodo(a.ID, a.Periodo(a.ProximoCiclo+1))
+		assert.Empty(t, faturaID)
+	})
+
+	t.Run("should update and append the events", func(t *testing.T) {
+		assert.NoError(t, a.Pausar())
+		assert.NoError(t, repo.Update(a))
+
+		found, _ := repo.FindByID(a.ID)
+		assert.Equal(t, entity.StatusAssinaturaPausada, found.Status)
+		assert.Equal(t, 2, found.Versao)
+
+		stream, err := eventstore.NewEventStorePostgres(tx).LoadStream(a.ID, 1)
+		assert.NoError(t, err)
+		assert.Len(t, stream, 2)
+		assert.Equal(t, entity.EventAssinaturaPausada, stream[1].EventType)
+
+		// Cópia desatualizada é recusada
+		found.Versao = 1
+		assert.Error(t, repo.Update(found))
+	})
+}
@@ -18,10 +18,11 @@ func NewClientePostgres(db shared.DBTX) *ClientePostgres {
 
 func (r *ClientePostgres) Save(cliente *entity.Cliente) error {
 	_, err := r.db.Exec(`
-		INSERT INTO clientes (id, nome, whatsapp, email, ativo, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO clientes (id, usuario_id, nome, whatsapp, email, ativo, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8)
 	`,
 		cliente.ID,
+		cliente.UsuarioID,
 		cliente.Nome,
 		cliente.WhatsApp,
 		cliente.Email,
@@ -40,11 +41,12 @@ func (r *ClientePostgres) Save(cliente *entity.Cliente) error {
 func (r *ClientePostgres) FindByID(id string) (*entity.Cliente, error) {
 	var c entity.Cliente
 	err := r.db.QueryRow(`
-		SELECT id, nome, whatsapp, email, ativo, created_at, updated_at
+		SELECT id, COALESCE(usuario_id, ''), nome, whatsapp, email, ativo, created_at, updated_at
 		FROM clientes
 		WHERE id = $1
 	`, id).Scan(
 		&c.ID,
+		&c.UsuarioID,
 		&c.Nome,
 		&c.WhatsApp,
 		&c.Email,
@@ -66,11 +68,12 @@ func (r *ClientePostgres) FindByID(id string) (*entity.Cliente, error) {
 func (r *ClientePostgres) FindByWhatsApp(whatsapp string) (*entity.Cliente, error) {
 	var c entity.Cliente
 	err := r.db.QueryRow(`
-		SELECT id, nome, whatsapp, email, ativo, created_at, updated_at
+		SELECT id, COALESCE(usuario_id, ''), nome, whatsapp, email, ativo, created_at, updated_at
 		FROM clientes
 		WHERE whatsapp = $1
 	`, whatsapp).Scan(
 		&c.ID,
+		&c.UsuarioID,
 		&c.Nome,
 		&c.WhatsApp,
 		&c.Email,
@@ -91,7 +94,7 @@ func (r *ClientePostgres) FindByWhatsApp(whatsapp string) (*entity.Cliente, erro
 
 func (r *ClientePostgres) FindAll() ([]*entity.Cliente, error) {
 	rows, err := r.db.Query(`
-		SELECT id, nome, whatsapp, email, ativo, created_at, updated_at
+		SELECT id, COALESCE(usuario_id, ''), nome, whatsapp, email, ativo, created_at, updated_at
 		FROM clientes
 	`)
 	if err != nil {
@@ -102,7 +105,7 @@ func (r *ClientePostgres) FindAll() ([]*entity.Cliente, error) {
 	var clientes []*entity.Cliente
 	for rows.Next() {
 		var c entity.Cliente
-		if err := rows.Scan(&c.ID, &c.Nome, &c.WhatsApp, &c.Email, &c.Ativo, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.UsuarioID, &c.Nome, &c.WhatsApp, &c.Email, &c.Ativo, &c.CreatedAt, &c.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("erro ao scanear cliente: %w", err)
 		}
 		clientes = append(clientes, &c)
@@ -114,9 +117,10 @@ func (r *ClientePostgres) FindAll() ([]*entity.Cliente, error) {
 func (r *ClientePostgres) Update(cliente *entity.Cliente) error {
 	_, err := r.db.Exec(`
 		UPDATE clientes
-		SET nome = $1, whatsapp = $2, email = $3, ativo = $4, updated_at = $5
-		WHERE id = $6
+		SET usuario_id = NULLIF($1, ''), nome = $2, whatsapp = $3, email = $4, ativo = $5, updated_at = $6
+		WHERE id = $7
 	`,
+		cliente.UsuarioID,
 		cliente.Nome,
 		cliente.WhatsApp,
 		cliente.Email,
@@ -44,6 +44,7 @@ func TestClientePostgres_CRUD(t *testing.T) {
 	if client == nil {
 		t.Fatal("Cliente nil")
 	}
+	client.UsuarioID = "user1"
 
 	err = repo.Save(client)
 	assert.NoError(t, err)
@@ -54,6 +55,7 @@ func TestClientePostgres_CRUD(t *testing.T) {
 	assert.NotNil(t, found)
 	assert.Equal(t, client.Nome, found.Nome)
 	assert.Equal(t, client.WhatsApp, found.WhatsApp)
+	assert.Equal(t, "user1", found.UsuarioID)
 
 	foundZap, err := repo.FindByWhatsApp(client.WhatsApp)
 	assert.NoError(t, err)
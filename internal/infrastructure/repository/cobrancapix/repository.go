@@ -0,0 +1,52 @@
+package cobrancapix
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
+)
+
+var _ repository.CobrancaPixRepository = (*CobrancaPixPostgres)(nil)
+
+type CobrancaPixPostgres struct {
+	db shared.DBTX
+}
+
+func NewCobrancaPixPostgres(db shared.DBTX) *CobrancaPixPostgres {
+	return &CobrancaPixPostgres{db: db}
+}
+
+func (r *CobrancaPixPostgres) FindByFaturaID(faturaID string) (*entity.CobrancaPix, error) {
+	var c entity.CobrancaPix
+	err := r.db.QueryRow(`
+		SELECT fatura_id, location, registrada_em
+		FROM cobrancas_pix
+		WHERE fatura_id = $1
+	`, faturaID).Scan(&c.FaturaID, &c.Location, &c.RegistradaEm)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar cobranca pix: %w", err)
+	}
+
+	return &c, nil
+}
+
+func (r *CobrancaPixPostgres) Save(c *entity.CobrancaPix) error {
+	_, err := r.db.Exec(`
+		INSERT INTO cobrancas_pix (fatura_id, location, registrada_em)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (fatura_id) DO UPDATE SET
+			location = EXCLUDED.location,
+			registrada_em = EXCLUDED.registrada_em
+	`, c.FaturaID, c.Location, c.RegistradaEm)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar cobranca pix: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package cobrancapix
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/cliente"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/fatura"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/testutils"
+)
+
+var testDB *sql.DB
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = testutils.SetupTestDB()
+	if err != nil {
+		log.Fatalf("Falha ao configurar banco de teste: %v", err)
+	}
+	defer testDB.Close()
+
+	code := m.Run()
+	testDB.Close()
+	os.Exit(code)
+}
+
+func TestCobrancaPixPostgres(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "c1@test.com")
+	cliente.NewClientePostgres(tx).Save(client)
+
+	f, _ := entity.NewFatura(client.ID, "FAT-2026-000001", entity.BRL(10000), time.Now().AddDate(0, 0, 5), "F1")
+	fatura.NewFaturaPostgres(tx).Save(f)
+
+	repo := NewCobrancaPixPostgres(tx)
+
+	t.Run("should return nil when the fatura has no charge", func(t *testing.T) {
+		c, err := repo.FindByFaturaID(f.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, c)
+	})
+
+	t.Run("should replace the location when the charge is registered again", func(t *testing.T) {
+		c, _ := entity.NewCobrancaPix(f.ID, "pix.psp.com.br/qr/v2/abc")
+		assert.NoError(t, repo.Save(c))
+
+		c, _ = entity.NewCobrancaPix(f.ID, "pix.psp.com.br/qr/v2/def")
+		assert.NoError(t, repo.Save(c))
+
+		salva, err := repo.FindByFaturaID(f.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "pix.psp.com.br/qr/v2/def", salva.Location)
+	})
+}
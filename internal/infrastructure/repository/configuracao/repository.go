@@ -5,9 +5,13 @@ import (
 	"fmt"
 
 	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
 	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
 )
 
+// Garante em tempo de compilação que o repositório implementa o contrato do domínio
+var _ repository.ConfiguracaoRepository = (*ConfiguracaoPostgres)(nil)
+
 type ConfiguracaoPostgres struct {
 	db shared.DBTX
 }
@@ -18,14 +22,30 @@ func NewConfiguracaoPostgres(db shared.DBTX) *ConfiguracaoPostgres {
 
 func (r *ConfiguracaoPostgres) Save(config *entity.Configuracao) error {
 	_, err := r.db.Exec(`
-		INSERT INTO configuracoes (id, usuario_id, dias_antes_lembrete, template_lembrete, template_cobranca, horario_inicio_envio, horario_fim_envio, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO configuracoes (id, usuario_id, dias_antes_lembrete, template_lembrete, template_cobranca, whatsapp_financeiro, envio_automatico_ativo, horario_inicio_envio, horario_fim_envio, numeracao_prefixo, numeracao_incluir_ano, numeracao_digitos, multa, juros_ao_mes, dias_carencia, pix_chave, pix_nome_recebedor, pix_cidade, boleto_banco, boleto_convenio, boleto_carteira, regua_cobranca, template_confirmacao, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25)
 		ON CONFLICT (usuario_id) DO UPDATE SET
 			dias_antes_lembrete = EXCLUDED.dias_antes_lembrete,
 			template_lembrete = EXCLUDED.template_lembrete,
 			template_cobranca = EXCLUDED.template_cobranca,
+			whatsapp_financeiro = EXCLUDED.whatsapp_financeiro,
+			envio_automatico_ativo = EXCLUDED.envio_automatico_ativo,
 			horario_inicio_envio = EXCLUDED.horario_inicio_envio,
 			horario_fim_envio = EXCLUDED.horario_fim_envio,
+			numeracao_prefixo = EXCLUDED.numeracao_prefixo,
+			numeracao_incluir_ano = EXCLUDED.numeracao_incluir_ano,
+			numeracao_digitos = EXCLUDED.numeracao_digitos,
+			multa = EXCLUDED.multa,
+			juros_ao_mes = EXCLUDED.juros_ao_mes,
+			dias_carencia = EXCLUDED.dias_carencia,
+			pix_chave = EXCLUDED.pix_chave,
+			pix_nome_recebedor = EXCLUDED.pix_nome_recebedor,
+			pix_cidade = EXCLUDED.pix_cidade,
+			boleto_banco = EXCLUDED.boleto_banco,
+			boleto_convenio = EXCLUDED.boleto_convenio,
+			boleto_carteira = EXCLUDED.boleto_carteira,
+			regua_cobranca = EXCLUDED.regua_cobranca,
+			template_confirmacao = EXCLUDED.template_confirmacao,
 			updated_at = EXCLUDED.updated_at
 	`,
 		config.ID,
@@ -33,8 +53,24 @@ func (r *ConfiguracaoPostgres) Save(config *entity.Configuracao) error {
 		config.DiasAntesLembrete,
 		config.TemplateLembrete,
 		config.TemplateCobranca,
+		config.WhatsAppFinanceiro,
+		config.EnvioAutomaticoAtivo,
 		config.HorarioInicioEnvio,
 		config.HorarioFimEnvio,
+		config.Numeracao.Prefixo,
+		config.Numeracao.IncluirAno,
+		config.Numeracao.Digitos,
+		config.Encargos.Multa,
+		config.Encargos.JurosAoMes,
+		config.Encargos.DiasCarencia,
+		config.Pix.Chave,
+		config.Pix.NomeRecebedor,
+		config.Pix.Cidade,
+		config.Boleto.Banco,
+		config.Boleto.Convenio,
+		config.Boleto.Carteira,
+		config.Regua,
+		config.TemplateConfirmacao,
 		config.CreatedAt,
 		config.UpdatedAt,
 	)
@@ -48,12 +84,13 @@ func (r *ConfiguracaoPostgres) Save(config *entity.Configuracao) error {
 
 func (r *ConfiguracaoPostgres) FindByUsuarioID(usuarioID string) (*entity.Configuracao, error) {
 	var c entity.Configuracao
+	// COALESCE nas colunas opcionais para não quebrar o Scan em registros antigos com NULL
 	err := r.db.QueryRow(`
-		SELECT id, usuario_id, dias_antes_lembrete, template_lembrete, template_cobranca, horario_inicio_envio, horario_fim_envio, created_at, updated_at
+		SELECT id, usuario_id, dias_antes_lembrete, COALESCE(template_lembrete, ''), COALESCE(template_cobranca, ''), COALESCE(whatsapp_financeiro, ''), envio_automatico_ativo, horario_inicio_envio, horario_fim_envio, numeracao_prefixo, numeracao_incluir_ano, numeracao_digitos, multa, juros_ao_mes, dias_carencia, pix_chave, pix_nome_recebedor, pix_cidade, boleto_banco, boleto_convenio, boleto_carteira, regua_cobranca, template_confirmacao, created_at, updated_at
 		FROM configuracoes
 		WHERE usuario_id = $1
 	`, usuarioID).Scan(
-		&c.ID, &c.UsuarioID, &c.DiasAntesLembrete, &c.TemplateLembrete, &c.TemplateCobranca, &c.HorarioInicioEnvio, &c.HorarioFimEnvio, &c.CreatedAt, &c.UpdatedAt,
+		&c.ID, &c.UsuarioID, &c.DiasAntesLembrete, &c.TemplateLembrete, &c.TemplateCobranca, &c.WhatsAppFinanceiro, &c.EnvioAutomaticoAtivo, &c.HorarioInicioEnvio, &c.HorarioFimEnvio, &c.Numeracao.Prefixo, &c.Numeracao.IncluirAno, &c.Numeracao.Digitos, &c.Encargos.Multa, &c.Encargos.JurosAoMes, &c.Encargos.DiasCarencia, &c.Pix.Chave, &c.Pix.NomeRecebedor, &c.Pix.Cidade, &c.Boleto.Banco, &c.Boleto.Convenio, &c.Boleto.Carteira, &c.Regua, &c.TemplateConfirmacao, &c.CreatedAt, &c.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -65,3 +102,68 @@ func (r *ConfiguracaoPostgres) FindByUsuarioID(usuarioID string) (*entity.Config
 
 	return &c, nil
 }
+
+func (r *ConfiguracaoPostgres) FindAll() ([]*entity.Configuracao, error) {
+	rows, err := r.db.Query(`
+		SELECT id, usuario_id, dias_antes_lembrete, COALESCE(template_lembrete, ''), COALESCE(template_cobranca, ''), COALESCE(whatsapp_financeiro, ''), envio_automatico_ativo, horario_inicio_envio, horario_fim_envio, numeracao_prefixo, numeracao_incluir_ano, numeracao_digitos, multa, juros_ao_mes, dias_carencia, pix_chave, pix_nome_recebedor, pix_cidade, boleto_banco, boleto_convenio, boleto_carteira, regua_cobranca, template_confirmacao, created_at, updated_at
+		FROM configuracoes
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar configuracoes: %w", err)
+	}
+	defer rows.Close()
+
+	var configs []*entity.Configuracao
+	for rows.Next() {
+		var c entity.Configuracao
+		if err := rows.Scan(
+			&c.ID, &c.UsuarioID, &c.DiasAntesLembrete, &c.TemplateLembrete, &c.TemplateCobranca, &c.WhatsAppFinanceiro, &c.EnvioAutomaticoAtivo, &c.HorarioInicioEnvio, &c.HorarioFimEnvio, &c.Numeracao.Prefixo, &c.Numeracao.IncluirAno, &c.Numeracao.Digitos, &c.Encargos.Multa, &c.Encargos.JurosAoMes, &c.Encargos.DiasCarencia, &c.Pix.Chave, &c.Pix.NomeRecebedor, &c.Pix.Cidade, &c.Boleto.Banco, &c.Boleto.Convenio, &c.Boleto.Carteira, &c.Regua, &c.TemplateConfirmacao, &c.CreatedAt, &c.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("erro ao scanear configuracao: %w", err)
+		}
+		configs = append(configs, &c)
+	}
+
+	return configs, nil
+}
+
+func (r *ConfiguracaoPostgres) Update(config *entity.Configuracao) error {
+	_, err := r.db.Exec(`
+		UPDATE configuracoes
+		SET dias_antes_lembrete = $1, template_lembrete = $2, template_cobranca = $3, whatsapp_financeiro = $4, envio_automatico_ativo = $5, horario_inicio_envio = $6, horario_fim_envio = $7,
+			numeracao_prefixo = $8, numeracao_incluir_ano = $9, numeracao_digitos = $10, multa = $11, juros_ao_mes = $12, dias_carencia = $13,
+			pix_chave = $14, pix_nome_recebedor = $15, pix_cidade = $16,
+			boleto_banco = $17, boleto_convenio = $18, boleto_carteira = $19, regua_cobranca = $20, template_confirmacao = $21, updated_at = $22
+		WHERE id = $23
+	`,
+		config.DiasAntesLembrete,
+		config.TemplateLembrete,
+		config.TemplateCobranca,
+		config.WhatsAppFinanceiro,
+		config.EnvioAutomaticoAtivo,
+		config.HorarioInicioEnvio,
+		config.HorarioFimEnvio,
+		config.Numeracao.Prefixo,
+		config.Numeracao.IncluirAno,
+		config.Numeracao.Digitos,
+		config.Encargos.Multa,
+		config.Encargos.JurosAoMes,
+		config.Encargos.DiasCarencia,
+		config.Pix.Chave,
+		config.Pix.NomeRecebedor,
+		config.Pix.Cidade,
+		config.Boleto.Banco,
+		config.Boleto.Convenio,
+		config.Boleto.Carteira,
+		config.Regua,
+		config.TemplateConfirmacao,
+		config.UpdatedAt,
+		config.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar configuracao: %w", err)
+	}
+
+	return nil
+}
@@ -62,3 +62,54 @@ func TestConfiguracaoPostgres_Upsert(t *testing.T) {
 	assert.Equal(t, "Novo template", found2.TemplateLembrete)
 	assert.Equal(t, c1.ID, found2.ID) // O ID deve ser o original (c1), não o do c2
 }
+
+func TestConfiguracaoPostgres_TodosOsCampos(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	repo := NewConfiguracaoPostgres(tx)
+
+	// 1. Create com todos os campos preenchidos
+	c, _ := entity.NewConfiguracao("user2")
+	c.TemplateLembrete = "Lembrete"
+	c.TemplateCobranca = "Cobranca"
+	c.TemplateConfirmacao = "Recebemos {{valor_pago}}"
+	c.WhatsAppFinanceiro = "5511977776666"
+	c.EnvioAutomaticoAtivo = false
+	c.HorarioInicioEnvio = "09:00"
+	c.HorarioFimEnvio = "17:30"
+	c.Regua = entity.ReguaCobranca{{Dias: -5, Tipo: entity.TipoMensagemLembrete}, {Dias: 3, Tipo: entity.TipoMensagemCobranca, Template: "Atrasou"}}
+
+	err := repo.Save(c)
+	assert.NoError(t, err)
+
+	found, err := repo.FindByUsuarioID("user2")
+	assert.NoError(t, err)
+	assert.NotNil(t, found)
+	assert.Equal(t, "Cobranca", found.TemplateCobranca)
+	assert.Equal(t, "Recebemos {{valor_pago}}", found.TemplateConfirmacao)
+	assert.Equal(t, "5511977776666", found.WhatsAppFinanceiro)
+	assert.False(t, found.EnvioAutomaticoAtivo)
+	assert.Equal(t, "09:00", found.HorarioInicioEnvio)
+	assert.Equal(t, "17:30", found.HorarioFimEnvio)
+	assert.Equal(t, c.Regua, found.Regua)
+
+	// 2. Update
+	found.DiasAntesLembrete = 10
+	found.WhatsAppFinanceiro = ""
+	found.EnvioAutomaticoAtivo = true
+	found.Regua = nil
+	found.TemplateConfirmacao = ""
+	found.Touch()
+
+	err = repo.Update(found)
+	assert.NoError(t, err)
+
+	found2, _ := repo.FindByUsuarioID("user2")
+	assert.Equal(t, 10, found2.DiasAntesLembrete)
+	assert.Equal(t, "", found2.WhatsAppFinanceiro)
+	assert.True(t, found2.EnvioAutomaticoAtivo)
+	assert.Empty(t, found2.Regua)
+	assert.Empty(t, found2.TemplateConfirmacao)
+	assert.Equal(t, c.ID, found2.ID)
+}
@@ -0,0 +1,60 @@
+package confirmacao
+
+import (
+	"fmt"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
+)
+
+var _ repository.ConfirmacaoRepository = (*ConfirmacaoPostgres)(nil)
+
+type ConfirmacaoPostgres struct {
+	db shared.DBTX
+}
+
+func NewConfirmacaoPostgres(db shared.DBTX) *ConfirmacaoPostgres {
+	return &ConfirmacaoPostgres{db: db}
+}
+
+func (r *ConfirmacaoPostgres) FindByFaturaID(faturaID string) ([]*entity.ConfirmacaoPagamento, error) {
+	rows, err := r.db.Query(`
+		SELECT pagamento_id, fatura_id, mensagem_id, enviada_em
+		FROM confirmacoes_pagamento
+		WHERE fatura_id = $1
+		ORDER BY enviada_em
+	`, faturaID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar confirmacoes da fatura: %w", err)
+	}
+	defer rows.Close()
+
+	var confirmacoes []*entity.ConfirmacaoPagamento
+	for rows.Next() {
+		var c entity.ConfirmacaoPagamento
+		if err := rows.Scan(&c.PagamentoID, &c.FaturaID, &c.MensagemID, &c.EnviadaEm); err != nil {
+			return nil, fmt.Errorf("erro ao scanear confirmacao de pagamento: %w", err)
+		}
+		confirmacoes = append(confirmacoes, &c)
+	}
+
+	return confirmacoes, rows.Err()
+}
+
+func (r *ConfirmacaoPostgres) Registrar(c *entity.ConfirmacaoPagamento) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO confirmacoes_pagamento (pagamento_id, fatura_id, mensagem_id, enviada_em)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (pagamento_id) DO NOTHING
+	`, c.PagamentoID, c.FaturaID, c.MensagemID, c.EnviadaEm)
+	if err != nil {
+		return false, fmt.Errorf("erro ao registrar confirmacao de pagamento: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erro ao registrar confirmacao de pagamento: %w", err)
+	}
+	return n == 1, nil
+}
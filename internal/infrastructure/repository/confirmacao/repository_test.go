@@ -0,0 +1,70 @@
+package confirmacao
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/cliente"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/fatura"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/mensagem"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/testutils"
+)
+
+var testDB *sql.DB
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = testutils.SetupTestDB()
+	if err != nil {
+		log.Fatalf("Falha ao configurar banco de teste: %v", err)
+	}
+	defer testDB.Close()
+
+	code := m.Run()
+	testDB.Close()
+	os.Exit(code)
+}
+
+func TestConfirmacaoPostgres(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "c1@test.com")
+	cliente.NewClientePostgres(tx).Save(client)
+
+	faturas := fatura.NewFaturaPostgres(tx)
+	f, _ := entity.NewFatura(client.ID, "FAT-2026-000001", entity.BRL(10000), time.Now().AddDate(0, 0, 5), "F1")
+	faturas.Save(f)
+	f.MarcarComoPaga(entity.EncargosAtraso{})
+	assert.NoError(t, faturas.Update(f))
+
+	msg, _ := entity.NewMensagem(f.ID, client.ID, client.WhatsApp, "Pagamento recebido", entity.TipoMensagemConfirmacao)
+	mensagem.NewMensagemPostgres(tx).Save(msg)
+
+	repo := NewConfirmacaoPostgres(tx)
+
+	t.Run("should register each payment only once", func(t *testing.T) {
+		c := &entity.ConfirmacaoPagamento{PagamentoID: f.Pagamentos[0].ID, FaturaID: f.ID, MensagemID: msg.ID, EnviadaEm: time.Now()}
+
+		ok, err := repo.Registrar(c)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.Registrar(c)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("should list the confirmations of the fatura", func(t *testing.T) {
+		confirmacoes, err := repo.FindByFaturaID(f.ID)
+		assert.NoError(t, err)
+		assert.Len(t, confirmacoes, 1)
+		assert.Equal(t, f.Pagamentos[0].ID, confirmacoes[0].PagamentoID)
+		assert.Equal(t, msg.ID, confirmacoes[0].MensagemID)
+	})
+}
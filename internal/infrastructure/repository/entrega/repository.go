@@ -0,0 +1,85 @@
+package entrega
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
+)
+
+var _ repository.EntregaPendenteRepository = (*EntregaPendentePostgres)(nil)
+
+// Namespace dos advisory locks por ID do provedor (a segunda chave é o hash do ID)
+const lockEntregas = 7_302_004
+
+type EntregaPendentePostgres struct {
+	db shared.DBTX
+}
+
+func NewEntregaPendentePostgres(db shared.DBTX) *EntregaPendentePostgres {
+	return &EntregaPendentePostgres{db: db}
+}
+
+// Travar usa um advisory lock de transação: o ID ainda não está em nenhuma linha
+// de mensagens que o FOR UPDATE de FindByIDProvedor possa travar
+func (r *EntregaPendentePostgres) Travar(idProvedor string) error {
+	if _, err := r.db.Exec(`SELECT pg_advisory_xact_lock($1, hashtext($2))`, lockEntregas, idProvedor); err != nil {
+		return fmt.Errorf("erro ao travar id do provedor: %w", err)
+	}
+	return nil
+}
+
+func (r *EntregaPendentePostgres) Guardar(e *entity.EntregaPendente) error {
+	_, err := r.db.Exec(`
+		INSERT INTO entregas_pendentes (id_provedor, status, em, recebido_em)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id_provedor, status) DO NOTHING
+	`, e.IDProvedor, e.Status, e.Em, e.RecebidoEm)
+	if err != nil {
+		return fmt.Errorf("erro ao guardar status de entrega: %w", err)
+	}
+	return nil
+}
+
+func (r *EntregaPendentePostgres) Retirar(idProvedor string) ([]*entity.EntregaPendente, error) {
+	rows, err := r.db.Query(`
+		WITH retiradas AS (
+			DELETE FROM entregas_pendentes
+			WHERE id_provedor = $1
+			RETURNING id_provedor, status, em, recebido_em
+		)
+		SELECT id_provedor, status, em, recebido_em
+		FROM retiradas
+		ORDER BY em, status
+	`, idProvedor)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao retirar status de entrega: %w", err)
+	}
+	defer rows.Close()
+
+	var entregas []*entity.EntregaPendente
+	for rows.Next() {
+		var e entity.EntregaPendente
+		if err := rows.Scan(&e.IDProvedor, &e.Status, &e.Em, &e.RecebidoEm); err != nil {
+			return nil, fmt.Errorf("erro ao scanear status de entrega: %w", err)
+		}
+		entregas = append(entregas, &e)
+	}
+
+	return entregas, rows.Err()
+}
+
+func (r *EntregaPendentePostgres) ExpurgarAntesDe(limite time.Time) (int, error) {
+	res, err := r.db.Exec(`DELETE FROM entregas_pendentes WHERE recebido_em < $1`, limite)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao expurgar status de entrega: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao expurgar status de entrega: %w", err)
+	}
+	return int(n), nil
+}
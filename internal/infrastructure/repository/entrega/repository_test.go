@@ -0,0 +1,68 @@
+package entrega
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/testutils"
+)
+
+var testDB *sql.DB
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = testutils.SetupTestDB()
+	if err != nil {
+		log.Fatalf("Falha ao configurar banco de teste: %v", err)
+	}
+	defer testDB.Close()
+
+	code := m.Run()
+	testDB.Close()
+	os.Exit(code)
+}
+
+func TestEntregaPendentePostgres(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	repo := NewEntregaPendentePostgres(tx)
+	agora := time.Now().Truncate(time.Microsecond)
+
+	t.Run("should keep each status of an ID once and take them in order", func(t *testing.T) {
+		assert.NoError(t, repo.Travar("3EB0C767D26A"))
+
+		lida := &entity.EntregaPendente{IDProvedor: "3EB0C767D26A", Status: entity.StatusMensagemLida, Em: agora.Add(time.Second), RecebidoEm: agora}
+		entregue := &entity.EntregaPendente{IDProvedor: "3EB0C767D26A", Status: entity.StatusMensagemEntregue, Em: agora, RecebidoEm: agora}
+		assert.NoError(t, repo.Guardar(lida))
+		assert.NoError(t, repo.Guardar(entregue))
+		assert.NoError(t, repo.Guardar(entregue))
+
+		entregas, err := repo.Retirar("3EB0C767D26A")
+		assert.NoError(t, err)
+		assert.Len(t, entregas, 2)
+		assert.Equal(t, entity.StatusMensagemEntregue, entregas[0].Status)
+		assert.Equal(t, entity.StatusMensagemLida, entregas[1].Status)
+
+		entregas, err = repo.Retirar("3EB0C767D26A")
+		assert.NoError(t, err)
+		assert.Empty(t, entregas)
+	})
+
+	t.Run("should discard statuses received before the limit", func(t *testing.T) {
+		assert.NoError(t, repo.Guardar(&entity.EntregaPendente{IDProvedor: "ANTIGA", Status: entity.StatusMensagemEntregue, Em: agora, RecebidoEm: agora.Add(-2 * time.Hour)}))
+		assert.NoError(t, repo.Guardar(&entity.EntregaPendente{IDProvedor: "RECENTE", Status: entity.StatusMensagemEntregue, Em: agora, RecebidoEm: agora}))
+
+		n, err := repo.ExpurgarAntesDe(agora.Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		entregas, _ := repo.Retirar("RECENTE")
+		assert.Len(t, entregas, 1)
+	})
+}
@@ -0,0 +1,86 @@
+package eventstore
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var _ repository.Outbox = (*OutboxPostgres)(nil)
+
+// Chave do advisory lock que garante um único relay publicando por vez
+const lockOutbox = 7_302_001
+
+// OutboxPostgres lê os eventos não publicados da tabela events.
+// Precisa de *sql.DB (e não shared.DBTX) porque controla a própria transação.
+type OutboxPostgres struct {
+	db *sql.DB
+}
+
+func NewOutboxPostgres(db *sql.DB) *OutboxPostgres {
+	return &OutboxPostgres{db: db}
+}
+
+// ProcessarPendentes roda em uma transação com advisory lock: réplicas do relay não publicam
+// em paralelo, o que preservaria a ordem só por sorte. Só entram eventos de transações
+// anteriores ao snapshot mais antigo em aberto, para que uma transação lenta com position
+// menor não seja ultrapassada. A marcação é gravada mesmo quando fn falha no meio do lote,
+// e se o processo cair antes do commit o lote é republicado (entrega at-least-once).
+func (o *OutboxPostgres) ProcessarPendentes(limite int, fn func(eventos []*entity.Event) (int, error)) error {
+	tx, err := o.db.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transacao: %w", err)
+	}
+	defer tx.Rollback()
+
+	var obtido bool
+	if err := tx.QueryRow(`SELECT pg_try_advisory_xact_lock($1)`, lockOutbox).Scan(&obtido); err != nil {
+		return fmt.Errorf("erro ao obter lock do outbox: %w", err)
+	}
+	if !obtido {
+		return nil
+	}
+
+	rows, err := tx.Query(selectEvents+`
+		WHERE published_at IS NULL
+		AND transacao_id < pg_snapshot_xmin(pg_current_snapshot())
+		ORDER BY position
+		LIMIT $1
+	`, limite)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar eventos pendentes: %w", err)
+	}
+
+	eventos, err := scanEvents(rows)
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	if len(eventos) == 0 {
+		return nil
+	}
+
+	n, fnErr := fn(eventos)
+
+	if n > 0 {
+		ids := make([]string, 0, n)
+		for _, e := range eventos[:n] {
+			ids = append(ids, e.ID)
+		}
+
+		if _, err := tx.Exec(`UPDATE events SET published_at = NOW() WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+			return fmt.Errorf("erro ao marcar eventos como publicados: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao commitar transacao: %w", err)
+	}
+
+	return fnErr
+}
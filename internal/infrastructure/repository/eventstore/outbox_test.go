@@ -0,0 +1,104 @@
+package eventstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/testutils"
+)
+
+// O banco de testes é compartilhado: o outbox também entrega eventos de outros testes,
+// então as verificações olham só para o agregado criado aqui
+func TestOutboxPostgres_ProcessarPendentes(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Falha ao configurar banco de teste: %v", err)
+	}
+	defer db.Close()
+
+	// Eventos gravados (e commitados) pelo fluxo normal
+	aggregateID := uuid.New().String()
+	defer db.Exec("DELETE FROM events WHERE aggregate_id = $1", aggregateID)
+
+	store := NewEventStorePostgres(db)
+	var gravados []*entity.Event
+	for i := 1; i <= 3; i++ {
+		e := entity.NewEvent(entity.EventFaturaCriada, aggregateID, entity.AggregateFatura, []byte(`{}`), nil, i)
+		assert.NoError(t, store.Append(aggregateID, i-1, e))
+		gravados = append(gravados, e)
+	}
+
+	doAgregado := func(eventos []*entity.Event) []*entity.Event {
+		var nossos []*entity.Event
+		for _, e := range eventos {
+			if e.AggregateID == aggregateID {
+				nossos = append(nossos, e)
+			}
+		}
+		return nossos
+	}
+	pendentes := func() int {
+		var n int
+		db.QueryRow("SELECT COUNT(*) FROM events WHERE aggregate_id = $1 AND published_at IS NULL", aggregateID).Scan(&n)
+		return n
+	}
+
+	outbox := NewOutboxPostgres(db)
+	const limite = 1000
+
+	t.Run("should mark only what was published", func(t *testing.T) {
+		err := outbox.ProcessarPendentes(limite, func(eventos []*entity.Event) (int, error) {
+			nossos := doAgregado(eventos)
+			assert.Len(t, nossos, 3)
+			for i, e := range nossos {
+				assert.Equal(t, gravados[i].ID, e.ID)
+			}
+
+			// Publica até o primeiro evento deste teste e falha no seguinte
+			for i, e := range eventos {
+				if e.ID == gravados[0].ID {
+					return i + 1, errors.New("broker indisponivel")
+				}
+			}
+			return 0, errors.New("broker indisponivel")
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 2, pendentes())
+	})
+
+	t.Run("should resume from the first unpublished event", func(t *testing.T) {
+		err := outbox.ProcessarPendentes(limite, func(eventos []*entity.Event) (int, error) {
+			nossos := doAgregado(eventos)
+			assert.Len(t, nossos, 2)
+			assert.Equal(t, gravados[1].ID, nossos[0].ID)
+			return len(eventos), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, pendentes())
+
+		err = outbox.ProcessarPendentes(limite, func(eventos []*entity.Event) (int, error) {
+			assert.Empty(t, doAgregado(eventos))
+			return len(eventos), nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should skip events from open transactions", func(t *testing.T) {
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		e := entity.NewEvent(entity.EventFaturaPaga, aggregateID, entity.AggregateFatura, []byte(`{}`), nil, 4)
+		assert.NoError(t, NewEventStorePostgres(tx).Append(aggregateID, 3, e))
+
+		err = outbox.ProcessarPendentes(limite, func(eventos []*entity.Event) (int, error) {
+			assert.Empty(t, doAgregado(eventos))
+			return 0, nil
+		})
+		assert.NoError(t, err)
+	})
+}
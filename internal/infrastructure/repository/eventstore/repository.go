@@ -1,13 +1,20 @@
 package eventstore
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/lib/pq"
+
 	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
 	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
 )
 
+var _ repository.EventStore = (*EventStorePostgres)(nil)
+
 type EventStorePostgres struct {
 	DB shared.DBTX
 }
@@ -16,33 +23,163 @@ func NewEventStorePostgres(db shared.DBTX) *EventStorePostgres {
 	return &EventStorePostgres{DB: db}
 }
 
-func (r *EventStorePostgres) Save(event *entity.Event) error {
+const selectEvents = `
+	SELECT id, event_type, aggregate_id, aggregate_type, event_data, COALESCE(metadata, '{}'), timestamp, version, position, schema_version
+	FROM events
+`
+
+// marcadorAppend identifica os advisory locks de appends em andamento.
+// A chave é marcadorAppend + um limite inferior da position que a transação vai receber;
+// como o lock só é solto no fim da transação, o menor deles mostra até onde ReadAll pode ler.
+const marcadorAppend int64 = 1 << 62
+
+// Append confere a versão atual do stream e insere os eventos em sequência.
+// Duas gravações concorrentes que passem pela checagem esbarram na constraint
+// (aggregate_id, version), que também vira ErrConflitoVersao.
+func (r *EventStorePostgres) Append(aggregateID string, expectedVersion int, events ...*entity.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	// Anuncia o append antes de reservar qualquer position (ver ReadAll)
+	if _, err := r.DB.Exec(`SELECT pg_advisory_xact_lock_shared($1 + last_value) FROM events_position_seq`, marcadorAppend); err != nil {
+		return fmt.Errorf("falha ao anunciar append: %w", err)
+	}
+
+	var atual int
+	err := r.DB.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM events WHERE aggregate_id = $1`, aggregateID).Scan(&atual)
+	if err != nil {
+		return fmt.Errorf("falha ao ler versao do stream: %w", err)
+	}
+	if expectedVersion != repository.ExpectedVersionAny && expectedVersion != atual {
+		return fmt.Errorf("%w: esperada %d, atual %d", repository.ErrConflitoVersao, expectedVersion, atual)
+	}
+
 	query := `
-		INSERT INTO events (id, event_type, aggregate_id, aggregate_type, event_data, metadata, timestamp, version)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO events (id, event_type, aggregate_id, aggregate_type, event_data, metadata, timestamp, version, schema_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING position
 	`
 
-	// Garante que Metadata seja um JSON válido se for nil ou vazio
-	metadata := event.Metadata
-	if len(metadata) == 0 {
-		metadata = json.RawMessage("{}")
+	for i, event := range events {
+		event.AggregateID = aggregateID
+		event.Version = atual + i + 1
+		if event.SchemaVersion == 0 {
+			event.SchemaVersion = entity.Eventos.VersaoAtual(event.EventType)
+		}
+
+		// Garante que Metadata seja um JSON válido se for nil ou vazio
+		metadata := event.Metadata
+		if len(metadata) == 0 {
+			metadata = json.RawMessage("{}")
+		}
+
+		err := r.DB.QueryRow(
+			query,
+			event.ID,
+			event.EventType,
+			event.AggregateID,
+			event.AggregateType,
+			event.EventData,
+			metadata,
+			event.Timestamp,
+			event.Version,
+			event.SchemaVersion,
+		).Scan(&event.Position)
+
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Constraint == "events_aggregate_version_unique" {
+				return fmt.Errorf("%w: versao %d ja existe", repository.ErrConflitoVersao, event.Version)
+			}
+			return fmt.Errorf("falha ao salvar evento: %w", err)
+		}
 	}
 
-	_, err := r.DB.Exec(
-		query,
-		event.ID,
-		event.EventType,
-		event.AggregateID,
-		event.AggregateType,
-		event.EventData,
-		metadata,
-		event.Timestamp,
-		event.Version,
-	)
+	return nil
+}
 
+func (r *EventStorePostgres) LoadStream(aggregateID string, fromVersion int) ([]*entity.Event, error) {
+	rows, err := r.DB.Query(selectEvents+`
+		WHERE aggregate_id = $1 AND version >= $2
+		ORDER BY version
+	`, aggregateID, fromVersion)
 	if err != nil {
-		return fmt.Errorf("falha ao salvar evento: %w", err)
+		return nil, fmt.Errorf("falha ao carregar stream: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
+	return scanEvents(rows)
+}
+
+// ReadAll só devolve eventos abaixo do horizonte: uma transação ainda aberta pode ter
+// reservado uma position menor que a de eventos já commitados, e quem lê em ordem
+// (projeções) avançaria o checkpoint por cima dela.
+func (r *EventStorePostgres) ReadAll(fromPosition int64, limite int) ([]*entity.Event, error) {
+	ate, err := r.horizonte()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.DB.Query(selectEvents+`
+		WHERE position > $1 AND position < $2
+		ORDER BY position
+		LIMIT $3
+	`, fromPosition, ate, limite)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler eventos: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// horizonte retorna a menor position que ainda pode aparecer depois desta leitura.
+// A ordem das consultas importa: a sequência é lida antes dos locks, e os eventos depois
+// dos dois (em outro statement, com snapshot novo). Assim, quem reservou uma position
+// abaixo do horizonte já terminou ou está com o lock visível em pg_locks.
+func (r *EventStorePostgres) horizonte() (int64, error) {
+	var ultima int64
+	var chamada bool
+	if err := r.DB.QueryRow(`SELECT last_value, is_called FROM events_position_seq`).Scan(&ultima, &chamada); err != nil {
+		return 0, fmt.Errorf("falha ao ler sequencia de eventos: %w", err)
+	}
+
+	ate := ultima
+	if chamada {
+		ate = ultima + 1
+	}
+
+	var emAndamento sql.NullInt64
+	err := r.DB.QueryRow(`
+		SELECT MIN((classid::bigint << 32) | objid::bigint) - $1
+		FROM pg_locks
+		WHERE locktype = 'advisory' AND objsubid = 1
+		AND classid::bigint >= $1::bigint >> 32
+		AND database = (SELECT oid FROM pg_database WHERE datname = current_database())
+	`, marcadorAppend).Scan(&emAndamento)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao ler appends em andamento: %w", err)
+	}
+
+	if emAndamento.Valid && emAndamento.Int64 < ate {
+		ate = emAndamento.Int64
+	}
+	return ate, nil
+}
+
+// scanEvents lê os eventos já convertidos para a versão atual do schema
+func scanEvents(rows *sql.Rows) ([]*entity.Event, error) {
+	var events []*entity.Event
+	for rows.Next() {
+		var e entity.Event
+		if err := rows.Scan(&e.ID, &e.EventType, &e.AggregateID, &e.AggregateType, &e.EventData, &e.Metadata, &e.Timestamp, &e.Version, &e.Position, &e.SchemaVersion); err != nil {
+			return nil, fmt.Errorf("falha ao ler evento: %w", err)
+		}
+		if err := entity.Eventos.Atualizar(&e); err != nil {
+			return nil, fmt.Errorf("falha ao converter evento %s: %w", e.ID, err)
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
 }
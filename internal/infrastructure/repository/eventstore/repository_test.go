@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
 	"github.com/teusf/billing-system/internal/infrastructure/repository/testutils"
 
 	"github.com/google/uuid"
@@ -34,16 +35,16 @@ func TestEventStore(t *testing.T) {
 		event := &entity.Event{
 			ID:            eventID,
 			EventType:     eventType,
-			AggregateID:   aggregateID,
 			AggregateType: aggregateType,
 			EventData:     payload,
 			Metadata:      metadata,
 			Timestamp:     time.Now(),
-			Version:       1,
 		}
 
-		err = repoWithTx.Save(event)
+		err = repoWithTx.Append(aggregateID, 0, event)
 		assert.NoError(t, err)
+		assert.Equal(t, 1, event.Version)
+		assert.NotZero(t, event.Position)
 
 		// Verifica inserção
 		var count int
@@ -57,4 +58,121 @@ func TestEventStore(t *testing.T) {
 		assert.NoError(t, err)
 		assert.JSONEq(t, string(payload), string(savedPayload))
 	})
+
+	t.Run("should reject version conflicts", func(t *testing.T) {
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		repo := NewEventStorePostgres(tx)
+		aggregateID := uuid.New().String()
+
+		novo := func() *entity.Event {
+			return entity.NewEvent(entity.EventFaturaCriada, aggregateID, entity.AggregateFatura, []byte(`{}`), nil, 0)
+		}
+
+		assert.NoError(t, repo.Append(aggregateID, 0, novo(), novo()))
+
+		// Quem leu o stream na versão 1 não pode gravar por cima da versão 2
+		err = repo.Append(aggregateID, 1, novo())
+		assert.ErrorIs(t, err, repository.ErrConflitoVersao)
+
+		assert.NoError(t, repo.Append(aggregateID, 2, novo()))
+		assert.NoError(t, repo.Append(aggregateID, repository.ExpectedVersionAny, novo()))
+
+		stream, err := repo.LoadStream(aggregateID, 1)
+		assert.NoError(t, err)
+		assert.Len(t, stream, 4)
+		for i, e := range stream {
+			assert.Equal(t, i+1, e.Version)
+		}
+
+		stream, err = repo.LoadStream(aggregateID, 3)
+		assert.NoError(t, err)
+		assert.Len(t, stream, 2)
+	})
+
+	t.Run("should read all events in global order", func(t *testing.T) {
+		_, err := db.Exec("DELETE FROM events")
+		assert.NoError(t, err)
+
+		repo := NewEventStorePostgres(db)
+		for i := 0; i < 3; i++ {
+			e := entity.NewEvent(entity.EventFaturaCriada, "", entity.AggregateFatura, []byte(`{}`), nil, 0)
+			assert.NoError(t, repo.Append(uuid.New().String(), 0, e))
+		}
+
+		todos, err := repo.ReadAll(0, 10)
+		assert.NoError(t, err)
+		assert.Len(t, todos, 3)
+
+		resto, err := repo.ReadAll(todos[0].Position, 10)
+		assert.NoError(t, err)
+		assert.Len(t, resto, 2)
+		assert.Equal(t, todos[1].ID, resto[0].ID)
+	})
+
+	t.Run("should not read past an append still in progress", func(t *testing.T) {
+		_, err := db.Exec("DELETE FROM events")
+		assert.NoError(t, err)
+
+		repo := NewEventStorePostgres(db)
+		novo := func() *entity.Event {
+			return entity.NewEvent(entity.EventFaturaCriada, "", entity.AggregateFatura, []byte(`{}`), nil, 0)
+		}
+
+		assert.NoError(t, repo.Append(uuid.New().String(), 0, novo()))
+
+		// Transação lenta reserva a próxima position e fica aberta
+		lenta, err := db.Begin()
+		assert.NoError(t, err)
+		defer lenta.Rollback()
+		pendente := novo()
+		assert.NoError(t, NewEventStorePostgres(lenta).Append(uuid.New().String(), 0, pendente))
+
+		// Um evento posterior é commitado antes dela
+		depois := novo()
+		assert.NoError(t, repo.Append(uuid.New().String(), 0, depois))
+		assert.Greater(t, depois.Position, pendente.Position)
+
+		// Só o primeiro é lido: passar do pendente faria a projeção perdê-lo
+		lidos, err := repo.ReadAll(0, 10)
+		assert.NoError(t, err)
+		assert.Len(t, lidos, 1)
+
+		assert.NoError(t, lenta.Commit())
+
+		lidos, err = repo.ReadAll(0, 10)
+		assert.NoError(t, err)
+		assert.Len(t, lidos, 3)
+	})
+
+	t.Run("should upcast old payloads on read without rewriting them", func(t *testing.T) {
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		repo := NewEventStorePostgres(tx)
+		aggregateID := uuid.New().String()
+
+		antigo := entity.NewEvent(entity.EventFaturaPaga, "", entity.AggregateFatura, []byte(`{"fatura_id":"f1","valor":150.5}`), nil, 0)
+		antigo.SchemaVersion = 1
+		assert.NoError(t, repo.Append(aggregateID, 0, antigo))
+
+		stream, err := repo.LoadStream(aggregateID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, stream[0].SchemaVersion)
+
+		var d entity.FaturaPagaData
+		assert.NoError(t, json.Unmarshal(stream[0].EventData, &d))
+		assert.Equal(t, entity.BRL(15050), d.ValorPago)
+
+		// O histórico continua gravado na versão original
+		var versao int
+		var valor float64
+		err = tx.QueryRow(`SELECT schema_version, (event_data->>'valor')::numeric FROM events WHERE id = $1`, antigo.ID).Scan(&versao, &valor)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, versao)
+		assert.Equal(t, 150.5, valor)
+	})
 }
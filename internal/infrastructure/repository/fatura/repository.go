@@ -5,22 +5,33 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/eventstore"
 	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
 )
 
+var _ repository.FaturaRepository = (*FaturaPostgres)(nil)
+
+// FaturaPostgres grava a linha de faturas (estado atual, usado nas consultas)
+// e os eventos pendentes da fatura no mesmo DBTX. Para que os dois fiquem
+// consistentes, Save e Update devem ser chamados dentro de uma transação.
 type FaturaPostgres struct {
-	db shared.DBTX
+	db      shared.DBTX
+	eventos *eventstore.EventStorePostgres
 }
 
 func NewFaturaPostgres(db shared.DBTX) *FaturaPostgres {
-	return &FaturaPostgres{db: db}
+	return &FaturaPostgres{db: db, eventos: eventstore.NewEventStorePostgres(db)}
 }
 
+// Save grava a fatura com o tenant em usuario_id: o número é único por tenant,
+// já que cada tenant tem a sua própria sequência.
 func (r *FaturaPostgres) Save(fatura *entity.Fatura) error {
 	_, err := r.db.Exec(`
-		INSERT INTO faturas (id, cliente_id, numero, descricao, valor, data_vencimento, data_pagamento, status, lembrete_enviado, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		INSERT INTO faturas (id, cliente_id, numero, descricao, valor, data_vencimento, data_pagamento, status, lembrete_enviado, created_at, updated_at, versao, usuario_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`,
 		fatura.ID,
 		fatura.ClienteID,
@@ -33,19 +44,133 @@ func (r *FaturaPostgres) Save(fatura *entity.Fatura) error {
 		fatura.LembreteEnviado,
 		fatura.CreatedAt,
 		fatura.UpdatedAt,
+		fatura.Versao+len(fatura.EventosPendentes()),
+		fatura.UsuarioID,
 	)
 
 	if err != nil {
 		return fmt.Errorf("erro ao salvar fatura: %w", err)
 	}
 
+	if err := r.salvarItens(fatura); err != nil {
+		return err
+	}
+
+	if err := r.salvarPagamentos(fatura); err != nil {
+		return err
+	}
+
+	return r.gravarEventos(fatura)
+}
+
+// salvarItens grava os itens na ordem da fatura. Os itens só existem na criação:
+// Update não os altera.
+func (r *FaturaPostgres) salvarItens(fatura *entity.Fatura) error {
+	for i, item := range fatura.Itens {
+		_, err := r.db.Exec(`
+			INSERT INTO fatura_itens (fatura_id, posicao, descricao, quantidade, preco_unitario, desconto, aliquota_imposto)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, fatura.ID, i+1, item.Descricao, item.Quantidade, item.PrecoUnitario, item.Desconto, item.AliquotaImposto)
+		if err != nil {
+			return fmt.Errorf("erro ao salvar item da fatura: %w", err)
+		}
+	}
 	return nil
 }
 
+// carregarItens preenche os itens das faturas com uma única consulta
+func (r *FaturaPostgres) carregarItens(faturas []*entity.Fatura) error {
+	if len(faturas) == 0 {
+		return nil
+	}
+
+	porID := make(map[string]*entity.Fatura, len(faturas))
+	ids := make([]string, 0, len(faturas))
+	for _, f := range faturas {
+		porID[f.ID] = f
+		ids = append(ids, f.ID)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT fatura_id, descricao, quantidade, preco_unitario, desconto, aliquota_imposto
+		FROM fatura_itens
+		WHERE fatura_id = ANY($1::uuid[])
+		ORDER BY fatura_id, posicao
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("erro ao buscar itens das faturas: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var faturaID string
+		var item entity.ItemFatura
+		if err := rows.Scan(&faturaID, &item.Descricao, &item.Quantidade, &item.PrecoUnitario, &item.Desconto, &item.AliquotaImposto); err != nil {
+			return fmt.Errorf("erro ao scanear item da fatura: %w", err)
+		}
+		f := porID[faturaID]
+		f.Itens = append(f.Itens, item)
+	}
+
+	return rows.Err()
+}
+
+// salvarPagamentos grava os pagamentos novos e os estornos. Pagamentos nunca são apagados,
+// e de um já gravado só o estorno muda.
+func (r *FaturaPostgres) salvarPagamentos(fatura *entity.Fatura) error {
+	for _, p := range fatura.Pagamentos {
+		_, err := r.db.Exec(`
+			INSERT INTO pagamentos (id, fatura_id, valor, metodo, pago_em, referencia_externa, estornado_em, motivo_estorno)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (id) DO UPDATE SET estornado_em = EXCLUDED.estornado_em, motivo_estorno = EXCLUDED.motivo_estorno
+		`, p.ID, fatura.ID, p.Valor, p.Metodo, p.PagoEm, p.ReferenciaExterna, p.EstornadoEm, p.MotivoEstorno)
+		if err != nil {
+			return fmt.Errorf("erro ao salvar pagamento: %w", err)
+		}
+	}
+	return nil
+}
+
+// carregarPagamentos preenche os pagamentos das faturas com uma única consulta
+func (r *FaturaPostgres) carregarPagamentos(faturas []*entity.Fatura) error {
+	if len(faturas) == 0 {
+		return nil
+	}
+
+	porID := make(map[string]*entity.Fatura, len(faturas))
+	ids := make([]string, 0, len(faturas))
+	for _, f := range faturas {
+		porID[f.ID] = f
+		ids = append(ids, f.ID)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, fatura_id, valor, metodo, pago_em, referencia_externa, estornado_em, motivo_estorno
+		FROM pagamentos
+		WHERE fatura_id = ANY($1::uuid[])
+		ORDER BY fatura_id, pago_em, id
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("erro ao buscar pagamentos das faturas: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p entity.Pagamento
+		if err := rows.Scan(&p.ID, &p.FaturaID, &p.Valor, &p.Metodo, &p.PagoEm, &p.ReferenciaExterna, &p.EstornadoEm, &p.MotivoEstorno); err != nil {
+			return fmt.Errorf("erro ao scanear pagamento: %w", err)
+		}
+		f := porID[p.FaturaID]
+		f.Pagamentos = append(f.Pagamentos, p)
+	}
+
+	return rows.Err()
+}
+
 func (r *FaturaPostgres) FindByID(id string) (*entity.Fatura, error) {
 	var f entity.Fatura
 	err := r.db.QueryRow(`
-		SELECT id, cliente_id, numero, descricao, valor, data_vencimento, data_pagamento, status, lembrete_enviado, created_at, updated_at
+		SELECT id, cliente_id, numero, descricao, valor, data_vencimento, data_pagamento, status, lembrete_enviado, created_at, updated_at, versao, usuario_id
 		FROM faturas
 		WHERE id = $1
 	`, id).Scan(
@@ -60,6 +185,8 @@ func (r *FaturaPostgres) FindByID(id string) (*entity.Fatura, error) {
 		&f.LembreteEnviado,
 		&f.CreatedAt,
 		&f.UpdatedAt,
+		&f.Versao,
+		&f.UsuarioID,
 	)
 
 	if err == sql.ErrNoRows {
@@ -69,12 +196,19 @@ func (r *FaturaPostgres) FindByID(id string) (*entity.Fatura, error) {
 		return nil, fmt.Errorf("erro ao buscar fatura: %w", err)
 	}
 
+	if err := r.carregarItens([]*entity.Fatura{&f}); err != nil {
+		return nil, err
+	}
+	if err := r.carregarPagamentos([]*entity.Fatura{&f}); err != nil {
+		return nil, err
+	}
+
 	return &f, nil
 }
 
 func (r *FaturaPostgres) FindByClienteID(clienteID string) ([]*entity.Fatura, error) {
 	rows, err := r.db.Query(`
-		SELECT id, cliente_id, numero, descricao, valor, data_vencimento, data_pagamento, status, lembrete_enviado, created_at, updated_at
+		SELECT id, cliente_id, numero, descricao, valor, data_vencimento, data_pagamento, status, lembrete_enviado, created_at, updated_at, versao, usuario_id
 		FROM faturas
 		WHERE cliente_id = $1
 	`, clienteID)
@@ -83,23 +217,12 @@ func (r *FaturaPostgres) FindByClienteID(clienteID string) ([]*entity.Fatura, er
 	}
 	defer rows.Close()
 
-	var faturas []*entity.Fatura
-	for rows.Next() {
-		var f entity.Fatura
-		if err := rows.Scan(
-			&f.ID, &f.ClienteID, &f.Numero, &f.Descricao, &f.Valor, &f.DataVencimento, &f.DataPagamento, &f.Status, &f.LembreteEnviado, &f.CreatedAt, &f.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("erro ao scanear fatura: %w", err)
-		}
-		faturas = append(faturas, &f)
-	}
-
-	return faturas, nil
+	return r.scanRows(rows)
 }
 
 func (r *FaturaPostgres) FindPendentes() ([]*entity.Fatura, error) {
 	rows, err := r.db.Query(`
-		SELECT id, cliente_id, numero, descricao, valor, data_vencimento, data_pagamento, status, lembrete_enviado, created_at, updated_at
+		SELECT id, cliente_id, numero, descricao, valor, data_vencimento, data_pagamento, status, lembrete_enviado, created_at, updated_at, versao, usuario_id
 		FROM faturas
 		WHERE status = $1
 	`, entity.StatusPendente)
@@ -112,6 +235,38 @@ func (r *FaturaPostgres) FindPendentes() ([]*entity.Fatura, error) {
 	return r.scanRows(rows)
 }
 
+// FindPendentesByUsuarioID busca as faturas pendentes dos clientes de um tenant
+func (r *FaturaPostgres) FindPendentesByUsuarioID(usuarioID string) ([]*entity.Fatura, error) {
+	rows, err := r.db.Query(`
+		SELECT f.id, f.cliente_id, f.numero, f.descricao, f.valor, f.data_vencimento, f.data_pagamento, f.status, f.lembrete_enviado, f.created_at, f.updated_at, f.versao, f.usuario_id
+		FROM faturas f
+		JOIN clientes c ON c.id = f.cliente_id
+		WHERE f.status = $1 AND c.usuario_id = $2
+	`, entity.StatusPendente, usuarioID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar faturas pendentes do usuario: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanRows(rows)
+}
+
+// FindEmAbertoByUsuarioID busca as faturas com saldo a receber (pendentes, vencidas e parcialmente pagas) dos clientes de um tenant
+func (r *FaturaPostgres) FindEmAbertoByUsuarioID(usuarioID string) ([]*entity.Fatura, error) {
+	rows, err := r.db.Query(`
+		SELECT f.id, f.cliente_id, f.numero, f.descricao, f.valor, f.data_vencimento, f.data_pagamento, f.status, f.lembrete_enviado, f.created_at, f.updated_at, f.versao, f.usuario_id
+		FROM faturas f
+		JOIN clientes c ON c.id = f.cliente_id
+		WHERE f.status IN ($1, $2, $3) AND c.usuario_id = $4
+	`, entity.StatusPendente, entity.StatusVencida, entity.StatusParcialmentePaga, usuarioID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar faturas em aberto do usuario: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanRows(rows)
+}
+
 func (r *FaturaPostgres) FindVencendoEm(dias int) ([]*entity.Fatura, error) {
 	// A lógica de data pode ser complexa dependendo do banco.
 	// PostgreSQL: NOW() + interval 'X days'
@@ -122,7 +277,7 @@ func (r *FaturaPostgres) FindVencendoEm(dias int) ([]*entity.Fatura, error) {
 	targetDate := time.Now().AddDate(0, 0, dias).Format("2006-01-02")
 
 	rows, err := r.db.Query(`
-		SELECT id, cliente_id, numero, descricao, valor, data_vencimento, data_pagamento, status, lembrete_enviado, created_at, updated_at
+		SELECT id, cliente_id, numero, descricao, valor, data_vencimento, data_pagamento, status, lembrete_enviado, created_at, updated_at, versao, usuario_id
 		FROM faturas
 		WHERE status = $1 
 		AND DATE(data_vencimento) = $2
@@ -135,23 +290,73 @@ func (r *FaturaPostgres) FindVencendoEm(dias int) ([]*entity.Fatura, error) {
 	return r.scanRows(rows)
 }
 
+// FindPendentesVencidas busca um lote de faturas pendentes ou parcialmente pagas com vencimento anterior a 'ate'.
+// As linhas ficam travadas (FOR UPDATE SKIP LOCKED) até o fim da transação, então
+// duas instâncias do job nunca processam a mesma fatura. Deve ser chamado dentro de uma transação.
+func (r *FaturaPostgres) FindPendentesVencidas(ate time.Time, limite int) ([]*entity.Fatura, error) {
+	rows, err := r.db.Query(`
+		SELECT id, cliente_id, numero, descricao, valor, data_vencimento, data_pagamento, status, lembrete_enviado, created_at, updated_at, versao, usuario_id
+		FROM faturas
+		WHERE status IN ($1, $2) AND data_vencimento < $3
+		ORDER BY data_vencimento
+		LIMIT $4
+		FOR UPDATE SKIP LOCKED
+	`, entity.StatusPendente, entity.StatusParcialmentePaga, ate, limite)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar faturas vencidas: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanRows(rows)
+}
+
+// Update só grava se a linha ainda estiver na versão que foi lida (controle otimista);
+// caso contrário retorna repository.ErrConflitoVersao.
 func (r *FaturaPostgres) Update(fatura *entity.Fatura) error {
-	_, err := r.db.Exec(`
+	res, err := r.db.Exec(`
 		UPDATE faturas
-		SET status = $1, data_pagamento = $2, lembrete_enviado = $3, updated_at = $4
-		WHERE id = $5
+		SET status = $1, data_pagamento = $2, lembrete_enviado = $3, updated_at = $4, versao = $5
+		WHERE id = $6 AND versao = $7
 	`,
 		fatura.Status,
 		fatura.DataPagamento,
 		fatura.LembreteEnviado,
 		fatura.UpdatedAt,
+		fatura.Versao+len(fatura.EventosPendentes()),
 		fatura.ID,
+		fatura.Versao,
 	)
 
 	if err != nil {
 		return fmt.Errorf("erro ao atualizar fatura: %w", err)
 	}
 
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar fatura: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: fatura %s", repository.ErrConflitoVersao, fatura.ID)
+	}
+
+	if err := r.salvarPagamentos(fatura); err != nil {
+		return err
+	}
+
+	return r.gravarEventos(fatura)
+}
+
+// gravarEventos anexa os eventos pendentes ao stream da fatura
+func (r *FaturaPostgres) gravarEventos(fatura *entity.Fatura) error {
+	if len(fatura.EventosPendentes()) == 0 {
+		return nil
+	}
+
+	if err := r.eventos.Append(fatura.ID, fatura.Versao, fatura.EventosPendentes()...); err != nil {
+		return err
+	}
+
+	fatura.ConfirmarEventos()
 	return nil
 }
 
@@ -161,11 +366,22 @@ func (r *FaturaPostgres) scanRows(rows *sql.Rows) ([]*entity.Fatura, error) {
 	for rows.Next() {
 		var f entity.Fatura
 		if err := rows.Scan(
-			&f.ID, &f.ClienteID, &f.Numero, &f.Descricao, &f.Valor, &f.DataVencimento, &f.DataPagamento, &f.Status, &f.LembreteEnviado, &f.CreatedAt, &f.UpdatedAt,
+			&f.ID, &f.ClienteID, &f.Numero, &f.Descricao, &f.Valor, &f.DataVencimento, &f.DataPagamento, &f.Status, &f.LembreteEnviado, &f.CreatedAt, &f.UpdatedAt, &f.Versao, &f.UsuarioID,
 		); err != nil {
 			return nil, fmt.Errorf("erro ao scanear fatura: %w", err)
 		}
 		faturas = append(faturas, &f)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao scanear fatura: %w", err)
+	}
+
+	// Com as linhas já consumidas a conexão está livre (importante dentro de uma tx)
+	if err := r.carregarItens(faturas); err != nil {
+		return nil, err
+	}
+	if err := r.carregarPagamentos(faturas); err != nil {
+		return nil, err
+	}
 	return faturas, nil
 }
@@ -2,6 +2,7 @@ package fatura
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
 	"os"
 	"testing"
@@ -9,7 +10,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
 	"github.com/teusf/billing-system/internal/infrastructure/repository/cliente"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/eventstore"
 	"github.com/teusf/billing-system/internal/infrastructure/repository/testutils"
 )
 
@@ -41,7 +44,7 @@ func TestFaturaPostgres_CRUD(t *testing.T) {
 
 	// 1. Create
 	vencimento := time.Now().AddDate(0, 0, 5)
-	f, _ := entity.NewFatura(client.ID, 150.00, vencimento, "Consultoria")
+	f, _ := entity.NewFatura(client.ID, "FAT-2026-000001", entity.BRL(15000), vencimento, "Consultoria")
 	err := repo.Save(f)
 	assert.NoError(t, err)
 
@@ -53,7 +56,7 @@ func TestFaturaPostgres_CRUD(t *testing.T) {
 	assert.Equal(t, f.Valor, found.Valor)
 
 	// 3. Update (Pagar)
-	f.MarcarComoPaga()
+	f.MarcarComoPaga(entity.EncargosAtraso{})
 	err = repo.Update(f)
 	assert.NoError(t, err)
 
@@ -67,6 +70,97 @@ func TestFaturaPostgres_CRUD(t *testing.T) {
 	assert.Len(t, list, 1) // Deve ter 1 fatura
 }
 
+func TestFaturaPostgres_Tenant(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "c1@test.com")
+	client.UsuarioID = "user1"
+	cliente.NewClientePostgres(tx).Save(client)
+
+	repo := NewFaturaPostgres(tx)
+
+	t.Run("should store the tenant of the fatura, not re-read the client", func(t *testing.T) {
+		f, _ := entity.NewFatura(client.ID, "FAT-2026-000001", entity.BRL(15000), time.Now().AddDate(0, 0, 5), "Consultoria")
+		f.UsuarioID = "user1"
+		assert.NoError(t, repo.Save(f))
+
+		// O cliente muda de tenant depois: a fatura continua na série em que foi numerada
+		client.UsuarioID = "user2"
+		assert.NoError(t, cliente.NewClientePostgres(tx).Update(client))
+
+		found, err := repo.FindByID(f.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "user1", found.UsuarioID)
+	})
+}
+
+func TestFaturaPostgres_Itens(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	cRepo := cliente.NewClientePostgres(tx)
+	client, _ := entity.NewCliente("Cliente 1", "5511999997777", "c1@test.com")
+	cRepo.Save(client)
+
+	repo := NewFaturaPostgres(tx)
+
+	itens := []entity.ItemFatura{
+		{Descricao: "Consultoria", Quantidade: 2, PrecoUnitario: entity.BRL(100000), Desconto: entity.BRL(10000), AliquotaImposto: 500},
+		{Descricao: "Hospedagem", Quantidade: 1, PrecoUnitario: entity.BRL(4990)},
+	}
+	f, err := entity.NewFatura(client.ID, "FAT-2026-000201", entity.BRL(204490), time.Now().AddDate(0, 0, 5), "Marco", itens...)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Save(f))
+
+	found, err := repo.FindByID(f.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, f.Itens, found.Itens)
+
+	totais, err := found.Totais()
+	assert.NoError(t, err)
+	assert.Equal(t, entity.BRL(204490), totais.Total)
+
+	list, err := repo.FindByClienteID(client.ID)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Len(t, list[0].Itens, 2)
+}
+
+func TestFaturaPostgres_Pagamentos(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	cRepo := cliente.NewClientePostgres(tx)
+	client, _ := entity.NewCliente("Cliente 1", "5511999996666", "c1@test.com")
+	cRepo.Save(client)
+
+	repo := NewFaturaPostgres(tx)
+
+	f, _ := entity.NewFatura(client.ID, "FAT-2026-000301", entity.BRL(10000), time.Now().AddDate(0, 0, 5), "Parcelada")
+	_, err := f.RegistrarPagamento(entity.BRL(4000), entity.MetodoPix, time.Now(), "E123", entity.EncargosAtraso{})
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Save(f))
+
+	found, err := repo.FindByID(f.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusParcialmentePaga, found.Status)
+	assert.Len(t, found.Pagamentos, 1)
+	assert.Equal(t, entity.BRL(4000), found.TotalPago())
+	assert.Equal(t, "E123", found.Pagamentos[0].ReferenciaExterna)
+
+	// Estorno grava na mesma linha do pagamento
+	assert.NoError(t, found.EstornarPagamento(found.Pagamentos[0].ID, "contestado"))
+	assert.NoError(t, repo.Update(found))
+
+	found, err = repo.FindByID(f.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusPendente, found.Status)
+	assert.True(t, found.Pagamentos[0].Estornado())
+	assert.Equal(t, "contestado", found.Pagamentos[0].MotivoEstorno)
+	assert.True(t, found.TotalPago().IsZero())
+}
+
 func TestFaturaPostgres_Filtros(t *testing.T) {
 	tx, cleanup := testutils.NewTestTx(t, testDB)
 	defer cleanup()
@@ -81,7 +175,7 @@ func TestFaturaPostgres_Filtros(t *testing.T) {
 	// Fatura 1: Vence hoje (pendente)
 	// Criamos com data futura para passar na validação do NewFatura, depois forçamos para "Agora"
 	// para garantir que o teste de "Vencendo Hoje" funcione mesmo se rodar às 23:59
-	f1, err := entity.NewFatura(client.ID, 100, time.Now().Add(24*time.Hour), "Hoje")
+	f1, err := entity.NewFatura(client.ID, "FAT-2026-000002", entity.BRL(10000), time.Now().Add(24*time.Hour), "Hoje")
 	assert.NoError(t, err)
 	if f1 == nil {
 		t.Fatal("Falha ao criar f1: nil")
@@ -90,7 +184,7 @@ func TestFaturaPostgres_Filtros(t *testing.T) {
 	repo.Save(f1)
 
 	// Fatura 2: Vence em 3 dias (pendente)
-	f2, err := entity.NewFatura(client.ID, 200, time.Now().AddDate(0, 0, 3), "Futuro")
+	f2, err := entity.NewFatura(client.ID, "FAT-2026-000003", entity.BRL(20000), time.Now().AddDate(0, 0, 3), "Futuro")
 	assert.NoError(t, err)
 	if f2 == nil {
 		t.Fatal("Falha ao criar f2: nil")
@@ -98,12 +192,12 @@ func TestFaturaPostgres_Filtros(t *testing.T) {
 	repo.Save(f2)
 
 	// Fatura 3: Paga
-	f3, err := entity.NewFatura(client.ID, 300, time.Now().AddDate(0, 0, 5), "Paga")
+	f3, err := entity.NewFatura(client.ID, "FAT-2026-000004", entity.BRL(30000), time.Now().AddDate(0, 0, 5), "Paga")
 	assert.NoError(t, err)
 	if f3 == nil {
 		t.Fatal("Falha ao criar f3: nil")
 	}
-	f3.MarcarComoPaga()
+	f3.MarcarComoPaga(entity.EncargosAtraso{})
 	repo.Save(f3)
 
 	// Test FindPendentes
@@ -129,3 +223,76 @@ func TestFaturaPostgres_Filtros(t *testing.T) {
 		assert.Equal(t, f2.ID, tresDias[0].ID)
 	}
 }
+
+func TestFaturaPostgres_FindPendentesVencidas(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	cRepo := cliente.NewClientePostgres(tx)
+	client, _ := entity.NewCliente("Cliente 1", "5511777777777", "")
+	cRepo.Save(client)
+
+	repo := NewFaturaPostgres(tx)
+
+	// Duas vencidas e uma em dia
+	for i := 1; i <= 2; i++ {
+		f, _ := entity.NewFatura(client.ID, fmt.Sprintf("FAT-2026-%06d", 100+i), entity.BRL(10000), time.Now().Add(24*time.Hour), "Vencida")
+		f.DataVencimento = time.Now().AddDate(0, 0, -i)
+		repo.Save(f)
+	}
+	emDia, _ := entity.NewFatura(client.ID, "FAT-2026-000006", entity.BRL(10000), time.Now().AddDate(0, 0, 5), "Em dia")
+	repo.Save(emDia)
+
+	// Parcialmente paga também vence
+	parcial, _ := entity.NewFatura(client.ID, "FAT-2026-000103", entity.BRL(10000), time.Now().Add(24*time.Hour), "Parcial")
+	parcial.DataVencimento = time.Now().AddDate(0, 0, -3)
+	repo.Save(parcial)
+	_, err := parcial.RegistrarPagamento(entity.BRL(4000), entity.MetodoPix, time.Time{}, "", entity.EncargosAtraso{})
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Update(parcial))
+
+	vencidas, err := repo.FindPendentesVencidas(time.Now(), 10)
+	assert.NoError(t, err)
+	assert.Len(t, vencidas, 3)
+
+	// Respeita o limite do lote
+	lote, err := repo.FindPendentesVencidas(time.Now(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, lote, 1)
+}
+
+func TestFaturaPostgres_EventSourcing(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	cRepo := cliente.NewClientePostgres(tx)
+	client, _ := entity.NewCliente("Cliente 1", "5511777777777", "c1@test.com")
+	cRepo.Save(client)
+
+	repo := NewFaturaPostgres(tx)
+
+	f, _ := entity.NewFatura(client.ID, "FAT-2026-000007", entity.BRL(8000), time.Now().AddDate(0, 0, 5), "Mensalidade")
+	assert.NoError(t, repo.Save(f))
+	assert.Equal(t, 1, f.Versao)
+
+	// Duas cópias lidas na mesma versão: a segunda gravação perde
+	copia1, _ := repo.FindByID(f.ID)
+	copia2, _ := repo.FindByID(f.ID)
+
+	copia1.MarcarComoPaga(entity.EncargosAtraso{}) // Pagamento registrado + FaturaPaga
+	assert.NoError(t, repo.Update(copia1))
+	assert.Equal(t, 3, copia1.Versao)
+
+	copia2.Cancelar()
+	assert.ErrorIs(t, repo.Update(copia2), repository.ErrConflitoVersao)
+
+	// O stream reconstrói o mesmo estado da linha
+	stream, err := eventstore.NewEventStorePostgres(tx).LoadStream(f.ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, stream, 3)
+
+	reconstruida, err := entity.ReconstruirFatura(stream)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusPaga, reconstruida.Status)
+	assert.Equal(t, 3, reconstruida.Versao)
+}
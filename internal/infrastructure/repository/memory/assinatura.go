@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var _ repository.AssinaturaRepository = (*AssinaturaMemory)(nil)
+
+type chavePeriodo struct {
+	assinaturaID string
+	periodo      string
+}
+
+// AssinaturaMemory não participa de rollback (ver UnitOfWorkMemory)
+type AssinaturaMemory struct {
+	mu          sync.RWMutex
+	assinaturas map[string]entity.Assinatura
+	periodos    map[chavePeriodo]string // Fatura de cada período
+	eventos     *EventStoreMemory
+}
+
+func NewAssinaturaMemory(eventos *EventStoreMemory) *AssinaturaMemory {
+	return &AssinaturaMemory{
+		assinaturas: map[string]entity.Assinatura{},
+		periodos:    map[chavePeriodo]string{},
+		eventos:     eventos,
+	}
+}
+
+func (r *AssinaturaMemory) Save(a *entity.Assinatura) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.gravar(a)
+}
+
+func (r *AssinaturaMemory) FindByID(id string) (*entity.Assinatura, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.assinaturas[id]
+	if !ok {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+func (r *AssinaturaMemory) FindByClienteID(clienteID string) ([]*entity.Assinatura, error) {
+	assinaturas := r.filter(func(a entity.Assinatura) bool { return a.ClienteID == clienteID })
+	sort.Slice(assinaturas, func(i, j int) bool { return assinaturas[i].CreatedAt.Before(assinaturas[j].CreatedAt) })
+	return assinaturas, nil
+}
+
+func (r *AssinaturaMemory) FindParaFaturar(ate time.Time, limite int) ([]*entity.Assinatura, error) {
+	assinaturas := r.filter(func(a entity.Assinatura) bool {
+		return a.Status == entity.StatusAssinaturaAtiva && !a.ProximoVencimento().After(ate)
+	})
+	sort.Slice(assinaturas, func(i, j int) bool {
+		return assinaturas[i].ProximoVencimento().Before(assinaturas[j].ProximoVencimento())
+	})
+	if len(assinaturas) > limite {
+		assinaturas = assinaturas[:limite]
+	}
+	return assinaturas, nil
+}
+
+func (r *AssinaturaMemory) Update(a *entity.Assinatura) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if atual, ok := r.assinaturas[a.ID]; !ok || atual.Versao != a.Versao {
+		return fmt.Errorf("%w: assinatura %s", repository.ErrConflitoVersao, a.ID)
+	}
+	return r.gravar(a)
+}
+
+func (r *AssinaturaMemory) FaturaDoPeriodo(assinaturaID, periodo string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.periodos[chavePeriodo{assinaturaID: assinaturaID, periodo: periodo}], nil
+}
+
+func (r *AssinaturaMemory) RegistrarPeriodo(assinaturaID, periodo, faturaID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chave := chavePeriodo{assinaturaID: assinaturaID, periodo: periodo}
+	if _, ok := r.periodos[chave]; ok {
+		return false, nil
+	}
+	r.periodos[chave] = faturaID
+	return true, nil
+}
+
+// gravar anexa os eventos pendentes e guarda uma cópia já confirmada. Deve ser chamado com mu travado.
+func (r *AssinaturaMemory) gravar(a *entity.Assinatura) error {
+	if pendentes := a.EventosPendentes(); len(pendentes) > 0 {
+		if err := r.eventos.Append(a.ID, a.Versao, pendentes...); err != nil {
+			return err
+		}
+		a.ConfirmarEventos()
+	}
+
+	r.assinaturas[a.ID] = *a
+	return nil
+}
+
+func (r *AssinaturaMemory) filter(match func(a entity.Assinatura) bool) []*entity.Assinatura {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var assinaturas []*entity.Assinatura
+	for _, a := range r.assinaturas {
+		if match(a) {
+			a := a
+			assinaturas = append(assinaturas, &a)
+		}
+	}
+	return assinaturas
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var _ repository.ClienteRepository = (*ClienteMemory)(nil)
+
+// ClienteMemory é uma implementação em memória usada nos testes de casos de uso.
+// Guarda cópias das entidades para imitar a semântica de um banco (alterações só valem após Save/Update).
+type ClienteMemory struct {
+	mu       sync.RWMutex
+	clientes map[string]entity.Cliente
+}
+
+func NewClienteMemory() *ClienteMemory {
+	return &ClienteMemory{clientes: map[string]entity.Cliente{}}
+}
+
+func (r *ClienteMemory) Save(cliente *entity.Cliente) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clientes[cliente.ID] = *cliente
+	return nil
+}
+
+func (r *ClienteMemory) FindByID(id string) (*entity.Cliente, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.clientes[id]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (r *ClienteMemory) FindByWhatsApp(whatsapp string) (*entity.Cliente, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, c := range r.clientes {
+		if c.WhatsApp == whatsapp {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *ClienteMemory) FindAll() ([]*entity.Cliente, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var clientes []*entity.Cliente
+	for _, c := range r.clientes {
+		c := c
+		clientes = append(clientes, &c)
+	}
+	return clientes, nil
+}
+
+func (r *ClienteMemory) Update(cliente *entity.Cliente) error {
+	return r.Save(cliente)
+}
+
+func (r *ClienteMemory) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clientes, id)
+	return nil
+}
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var _ repository.CobrancaPixRepository = (*CobrancaPixMemory)(nil)
+
+type CobrancaPixMemory struct {
+	mu        sync.RWMutex
+	cobrancas map[string]entity.CobrancaPix // Por FaturaID
+}
+
+func NewCobrancaPixMemory() *CobrancaPixMemory {
+	return &CobrancaPixMemory{cobrancas: map[string]entity.CobrancaPix{}}
+}
+
+func (r *CobrancaPixMemory) FindByFaturaID(faturaID string) (*entity.CobrancaPix, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.cobrancas[faturaID]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (r *CobrancaPixMemory) Save(c *entity.CobrancaPix) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cobrancas[c.FaturaID] = *c
+	return nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var _ repository.ConfiguracaoRepository = (*ConfiguracaoMemory)(nil)
+
+type ConfiguracaoMemory struct {
+	mu      sync.RWMutex
+	configs map[string]entity.Configuracao // por usuario_id
+}
+
+func NewConfiguracaoMemory() *ConfiguracaoMemory {
+	return &ConfiguracaoMemory{configs: map[string]entity.Configuracao{}}
+}
+
+func (r *ConfiguracaoMemory) Save(config *entity.Configuracao) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs[config.UsuarioID] = *config
+	return nil
+}
+
+func (r *ConfiguracaoMemory) FindByUsuarioID(usuarioID string) (*entity.Configuracao, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.configs[usuarioID]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (r *ConfiguracaoMemory) FindAll() ([]*entity.Configuracao, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var configs []*entity.Configuracao
+	for _, c := range r.configs {
+		c := c
+		configs = append(configs, &c)
+	}
+	return configs, nil
+}
+
+func (r *ConfiguracaoMemory) Update(config *entity.Configuracao) error {
+	return r.Save(config)
+}
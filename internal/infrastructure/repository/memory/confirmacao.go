@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var _ repository.ConfirmacaoRepository = (*ConfirmacaoMemory)(nil)
+
+type ConfirmacaoMemory struct {
+	mu           sync.RWMutex
+	confirmacoes map[string]entity.ConfirmacaoPagamento // Por PagamentoID
+}
+
+func NewConfirmacaoMemory() *ConfirmacaoMemory {
+	return &ConfirmacaoMemory{confirmacoes: map[string]entity.ConfirmacaoPagamento{}}
+}
+
+func (r *ConfirmacaoMemory) FindByFaturaID(faturaID string) ([]*entity.ConfirmacaoPagamento, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var confirmacoes []*entity.ConfirmacaoPagamento
+	for _, c := range r.confirmacoes {
+		if c.FaturaID == faturaID {
+			c := c
+			confirmacoes = append(confirmacoes, &c)
+		}
+	}
+	sort.Slice(confirmacoes, func(i, j int) bool {
+		return confirmacoes[i].EnviadaEm.Before(confirmacoes[j].EnviadaEm)
+	})
+	return confirmacoes, nil
+}
+
+func (r *ConfirmacaoMemory) Registrar(c *entity.ConfirmacaoPagamento) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.confirmacoes[c.PagamentoID]; ok {
+		return false, nil
+	}
+	r.confirmacoes[c.PagamentoID] = *c
+	return true, nil
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var _ repository.EntregaPendenteRepository = (*EntregaPendenteMemory)(nil)
+
+type EntregaPendenteMemory struct {
+	mu       sync.RWMutex
+	entregas map[string]map[entity.StatusMensagem]entity.EntregaPendente // Por IDProvedor e status
+}
+
+func NewEntregaPendenteMemory() *EntregaPendenteMemory {
+	return &EntregaPendenteMemory{entregas: map[string]map[entity.StatusMensagem]entity.EntregaPendente{}}
+}
+
+// Travar não faz nada: o UnitOfWorkMemory já serializa as transações
+func (r *EntregaPendenteMemory) Travar(idProvedor string) error {
+	return nil
+}
+
+func (r *EntregaPendenteMemory) Guardar(e *entity.EntregaPendente) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	porStatus, ok := r.entregas[e.IDProvedor]
+	if !ok {
+		porStatus = map[entity.StatusMensagem]entity.EntregaPendente{}
+		r.entregas[e.IDProvedor] = porStatus
+	}
+	if _, ok := porStatus[e.Status]; !ok {
+		porStatus[e.Status] = *e
+	}
+	return nil
+}
+
+func (r *EntregaPendenteMemory) Retirar(idProvedor string) ([]*entity.EntregaPendente, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var entregas []*entity.EntregaPendente
+	for _, e := range r.entregas[idProvedor] {
+		e := e
+		entregas = append(entregas, &e)
+	}
+	delete(r.entregas, idProvedor)
+
+	sort.Slice(entregas, func(i, j int) bool {
+		if !entregas[i].Em.Equal(entregas[j].Em) {
+			return entregas[i].Em.Before(entregas[j].Em)
+		}
+		return entregas[i].Status < entregas[j].Status
+	})
+	return entregas, nil
+}
+
+func (r *EntregaPendenteMemory) ExpurgarAntesDe(limite time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for id, porStatus := range r.entregas {
+		for status, e := range porStatus {
+			if e.RecebidoEm.Before(limite) {
+				delete(porStatus, status)
+				n++
+			}
+		}
+		if len(porStatus) == 0 {
+			delete(r.entregas, id)
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var (
+	_ repository.EventStore = (*EventStoreMemory)(nil)
+	_ repository.Outbox     = (*EventStoreMemory)(nil)
+)
+
+type EventStoreMemory struct {
+	mu         sync.RWMutex
+	events     []entity.Event // Position = índice + 1
+	publicados int            // Os eventos são publicados em ordem: basta guardar quantos já foram
+}
+
+func NewEventStoreMemory() *EventStoreMemory {
+	return &EventStoreMemory{}
+}
+
+func (r *EventStoreMemory) Append(aggregateID string, expectedVersion int, events ...*entity.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	atual := 0
+	for _, e := range r.events {
+		if e.AggregateID == aggregateID {
+			atual = e.Version
+		}
+	}
+	if expectedVersion != repository.ExpectedVersionAny && expectedVersion != atual {
+		return fmt.Errorf("%w: esperada %d, atual %d", repository.ErrConflitoVersao, expectedVersion, atual)
+	}
+
+	for i, event := range events {
+		event.AggregateID = aggregateID
+		event.Version = atual + i + 1
+		event.Position = int64(len(r.events) + 1)
+		if event.SchemaVersion == 0 {
+			event.SchemaVersion = entity.Eventos.VersaoAtual(event.EventType)
+		}
+		r.events = append(r.events, *event)
+	}
+	return nil
+}
+
+// ler devolve uma cópia do evento convertida para a versão atual do schema, como no Postgres
+func ler(e entity.Event) (*entity.Event, error) {
+	if err := entity.Eventos.Atualizar(&e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *EventStoreMemory) LoadStream(aggregateID string, fromVersion int) ([]*entity.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var stream []*entity.Event
+	for _, e := range r.events {
+		if e.AggregateID == aggregateID && e.Version >= fromVersion {
+			lido, err := ler(e)
+			if err != nil {
+				return nil, err
+			}
+			stream = append(stream, lido)
+		}
+	}
+	return stream, nil
+}
+
+func (r *EventStoreMemory) ReadAll(fromPosition int64, limite int) ([]*entity.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []*entity.Event
+	for i := int(fromPosition); i < len(r.events) && len(events) < limite; i++ {
+		lido, err := ler(r.events[i])
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, lido)
+	}
+	return events, nil
+}
+
+// All retorna os eventos na ordem em que foram gravados
+func (r *EventStoreMemory) All() []entity.Event {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]entity.Event(nil), r.events...)
+}
+
+// PorTipo retorna, em ordem, os eventos do tipo informado
+func (r *EventStoreMemory) PorTipo(eventType string) []entity.Event {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []entity.Event
+	for _, e := range r.events {
+		if e.EventType == eventType {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func (r *EventStoreMemory) ProcessarPendentes(limite int, fn func(eventos []*entity.Event) (int, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pendentes []*entity.Event
+	for i := r.publicados; i < len(r.events) && len(pendentes) < limite; i++ {
+		lido, err := ler(r.events[i])
+		if err != nil {
+			return err
+		}
+		pendentes = append(pendentes, lido)
+	}
+	if len(pendentes) == 0 {
+		return nil
+	}
+
+	n, err := fn(pendentes)
+	r.publicados += n
+	return err
+}
+
+// Pendentes retorna quantos eventos ainda não foram publicados
+func (r *EventStoreMemory) Pendentes() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.events) - r.publicados
+}
@@ -0,0 +1,29 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+func TestEventStoreMemory_Append(t *testing.T) {
+	store := NewEventStoreMemory()
+	novo := func() *entity.Event {
+		return entity.NewEvent(entity.EventFaturaCriada, "", entity.AggregateFatura, []byte(`{}`), nil, 0)
+	}
+
+	assert.NoError(t, store.Append("fat-1", 0, novo(), novo()))
+	assert.NoError(t, store.Append("fat-2", 0, novo()))
+	assert.ErrorIs(t, store.Append("fat-1", 1, novo()), repository.ErrConflitoVersao)
+
+	stream, _ := store.LoadStream("fat-1", 2)
+	assert.Len(t, stream, 1)
+	assert.Equal(t, 2, stream[0].Version)
+
+	todos, _ := store.ReadAll(1, 10)
+	assert.Len(t, todos, 2)
+	assert.Equal(t, int64(2), todos[0].Position)
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var _ repository.FaturaRepository = (*FaturaMemory)(nil)
+
+type FaturaMemory struct {
+	mu       sync.RWMutex
+	faturas  map[string]entity.Fatura
+	clientes *ClienteMemory    // Necessário para filtrar por tenant
+	eventos  *EventStoreMemory // Recebe os eventos pendentes, como no FaturaPostgres
+}
+
+func NewFaturaMemory(clientes *ClienteMemory, eventos *EventStoreMemory) *FaturaMemory {
+	return &FaturaMemory{faturas: map[string]entity.Fatura{}, clientes: clientes, eventos: eventos}
+}
+
+func (r *FaturaMemory) Save(fatura *entity.Fatura) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.gravar(fatura)
+}
+
+func (r *FaturaMemory) FindByID(id string) (*entity.Fatura, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.faturas[id]
+	if !ok {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+func (r *FaturaMemory) FindByClienteID(clienteID string) ([]*entity.Fatura, error) {
+	return r.filter(func(f entity.Fatura) bool { return f.ClienteID == clienteID }), nil
+}
+
+func (r *FaturaMemory) FindPendentes() ([]*entity.Fatura, error) {
+	return r.filter(func(f entity.Fatura) bool { return f.Status == entity.StatusPendente }), nil
+}
+
+func (r *FaturaMemory) FindPendentesByUsuarioID(usuarioID string) ([]*entity.Fatura, error) {
+	return r.filter(func(f entity.Fatura) bool {
+		if f.Status != entity.StatusPendente {
+			return false
+		}
+		c, _ := r.clientes.FindByID(f.ClienteID)
+		return c != nil && c.UsuarioID == usuarioID
+	}), nil
+}
+
+func (r *FaturaMemory) FindEmAbertoByUsuarioID(usuarioID string) ([]*entity.Fatura, error) {
+	return r.filter(func(f entity.Fatura) bool {
+		if f.Status != entity.StatusPendente && f.Status != entity.StatusVencida && f.Status != entity.StatusParcialmentePaga {
+			return false
+		}
+		c, _ := r.clientes.FindByID(f.ClienteID)
+		return c != nil && c.UsuarioID == usuarioID
+	}), nil
+}
+
+func (r *FaturaMemory) FindVencendoEm(dias int) ([]*entity.Fatura, error) {
+	target := time.Now().AddDate(0, 0, dias).Format("2006-01-02")
+	return r.filter(func(f entity.Fatura) bool {
+		return f.Status == entity.StatusPendente && f.DataVencimento.Format("2006-01-02") == target
+	}), nil
+}
+
+func (r *FaturaMemory) FindPendentesVencidas(ate time.Time, limite int) ([]*entity.Fatura, error) {
+	faturas := r.filter(func(f entity.Fatura) bool {
+		return (f.Status == entity.StatusPendente || f.Status == entity.StatusParcialmentePaga) && f.DataVencimento.Before(ate)
+	})
+	sort.Slice(faturas, func(i, j int) bool {
+		return faturas[i].DataVencimento.Before(faturas[j].DataVencimento)
+	})
+	if len(faturas) > limite {
+		faturas = faturas[:limite]
+	}
+	return faturas, nil
+}
+
+func (r *FaturaMemory) Update(fatura *entity.Fatura) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if atual, ok := r.faturas[fatura.ID]; !ok || atual.Versao != fatura.Versao {
+		return fmt.Errorf("%w: fatura %s", repository.ErrConflitoVersao, fatura.ID)
+	}
+	return r.gravar(fatura)
+}
+
+// gravar anexa os eventos pendentes e guarda uma cópia já confirmada. Deve ser chamado com mu travado.
+func (r *FaturaMemory) gravar(fatura *entity.Fatura) error {
+	if pendentes := fatura.EventosPendentes(); len(pendentes) > 0 {
+		if err := r.eventos.Append(fatura.ID, fatura.Versao, pendentes...); err != nil {
+			return err
+		}
+		fatura.ConfirmarEventos()
+	}
+
+	r.faturas[fatura.ID] = *fatura
+	return nil
+}
+
+func (r *FaturaMemory) filter(match func(f entity.Fatura) bool) []*entity.Fatura {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var faturas []*entity.Fatura
+	for _, f := range r.faturas {
+		if match(f) {
+			f := f
+			faturas = append(faturas, &f)
+		}
+	}
+	return faturas
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var _ repository.MensagemRepository = (*MensagemMemory)(nil)
+
+type MensagemMemory struct {
+	mu        sync.RWMutex
+	mensagens map[string]entity.Mensagem
+}
+
+func NewMensagemMemory() *MensagemMemory {
+	return &MensagemMemory{mensagens: map[string]entity.Mensagem{}}
+}
+
+func (r *MensagemMemory) Save(msg *entity.Mensagem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mensagens[msg.ID] = *msg
+	return nil
+}
+
+func (r *MensagemMemory) FindByID(id string) (*entity.Mensagem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, ok := r.mensagens[id]
+	if !ok {
+		return nil, nil
+	}
+	return &m, nil
+}
+
+func (r *MensagemMemory) FindByIDProvedor(idProvedor string) (*entity.Mensagem, error) {
+	if idProvedor == "" {
+		return nil, nil
+	}
+	msgs := r.filter(func(m entity.Mensagem) bool { return m.IDProvedor == idProvedor })
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+	return msgs[0], nil
+}
+
+func (r *MensagemMemory) FindByStatus(status entity.StatusMensagem) ([]*entity.Mensagem, error) {
+	return r.filter(func(m entity.Mensagem) bool { return m.Status == status }), nil
+}
+
+func (r *MensagemMemory) FindParaDLQ() ([]*entity.Mensagem, error) {
+	return r.filter(func(m entity.Mensagem) bool {
+		return m.Status == entity.StatusMensagemFalha && m.TentativasEnvio >= 5
+	}), nil
+}
+
+func (r *MensagemMemory) ReivindicarParaEnvio(agora time.Time, lease time.Duration, limite int) ([]*entity.Mensagem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var prontas []entity.Mensagem
+	for _, m := range r.mensagens {
+		if disponivelParaEnvio(m, agora) {
+			prontas = append(prontas, m)
+		}
+	}
+
+	sort.Slice(prontas, func(i, j int) bool {
+		return prontas[i].CreatedAt.Before(prontas[j].CreatedAt)
+	})
+	if len(prontas) > limite {
+		prontas = prontas[:limite]
+	}
+
+	fimLease := agora.Add(lease)
+	msgs := make([]*entity.Mensagem, 0, len(prontas))
+	for _, m := range prontas {
+		m.ProximaTentativaEm = &fimLease
+		r.mensagens[m.ID] = m
+		m := m
+		msgs = append(msgs, &m)
+	}
+	return msgs, nil
+}
+
+func (r *MensagemMemory) ReivindicarPorID(id string, agora time.Time, lease time.Duration) (*entity.Mensagem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.mensagens[id]
+	if !ok || !disponivelParaEnvio(m, agora) {
+		return nil, nil
+	}
+
+	fimLease := agora.Add(lease)
+	m.ProximaTentativaEm = &fimLease
+	r.mensagens[id] = m
+	return &m, nil
+}
+
+func disponivelParaEnvio(m entity.Mensagem, agora time.Time) bool {
+	if m.Status != entity.StatusMensagemPendente && m.Status != entity.StatusMensagemFalha {
+		return false
+	}
+	if m.TentativasEnvio >= entity.MaxTentativasEnvio {
+		return false
+	}
+	return m.ProximaTentativaEm == nil || !m.ProximaTentativaEm.After(agora)
+}
+
+func (r *MensagemMemory) Update(msg *entity.Mensagem) error {
+	return r.Save(msg)
+}
+
+// All retorna todas as mensagens salvas (útil para asserções nos testes)
+func (r *MensagemMemory) All() []*entity.Mensagem {
+	return r.filter(func(entity.Mensagem) bool { return true })
+}
+
+func (r *MensagemMemory) filter(match func(m entity.Mensagem) bool) []*entity.Mensagem {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var msgs []*entity.Mensagem
+	for _, m := range r.mensagens {
+		if match(m) {
+			m := m
+			msgs = append(msgs, &m)
+		}
+	}
+	return msgs
+}
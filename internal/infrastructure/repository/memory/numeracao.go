@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var _ repository.NumeracaoRepository = (*NumeracaoMemory)(nil)
+
+type chaveNumeracao struct {
+	usuarioID string
+	serie     string
+	periodo   int
+}
+
+// NumeracaoMemory não participa de rollback (ver UnitOfWorkMemory)
+type NumeracaoMemory struct {
+	mu      sync.Mutex
+	ultimos map[chaveNumeracao]int64
+}
+
+func NewNumeracaoMemory() *NumeracaoMemory {
+	return &NumeracaoMemory{ultimos: map[chaveNumeracao]int64{}}
+}
+
+func (r *NumeracaoMemory) Proximo(usuarioID, serie string, periodo int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chave := chaveNumeracao{usuarioID: usuarioID, serie: serie, periodo: periodo}
+	r.ultimos[chave]++
+	return r.ultimos[chave], nil
+}
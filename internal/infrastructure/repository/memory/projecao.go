@@ -0,0 +1,219 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var (
+	_ repository.CheckpointRepository        = (*CheckpointMemory)(nil)
+	_ repository.RecebiveisRepository        = (*RecebiveisMemory)(nil)
+	_ repository.EstatisticasEnvioRepository = (*EstatisticasEnvioMemory)(nil)
+	_ repository.ProjecaoUnitOfWork          = (*ProjecaoUnitOfWorkMemory)(nil)
+)
+
+type CheckpointMemory struct {
+	mu          sync.RWMutex
+	checkpoints map[string]int64
+}
+
+func NewCheckpointMemory() *CheckpointMemory {
+	return &CheckpointMemory{checkpoints: map[string]int64{}}
+}
+
+func (r *CheckpointMemory) Carregar(projecao string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.checkpoints[projecao], nil
+}
+
+func (r *CheckpointMemory) Salvar(projecao string, position int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkpoints[projecao] = position
+	return nil
+}
+
+type RecebiveisMemory struct {
+	mu       sync.RWMutex
+	faturas  map[string]entity.RecebivelFatura
+	clientes map[string]entity.RecebiveisCliente
+}
+
+func NewRecebiveisMemory() *RecebiveisMemory {
+	return &RecebiveisMemory{faturas: map[string]entity.RecebivelFatura{}, clientes: map[string]entity.RecebiveisCliente{}}
+}
+
+func (r *RecebiveisMemory) FindFatura(faturaID string) (*entity.RecebivelFatura, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.faturas[faturaID]
+	if !ok {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+func (r *RecebiveisMemory) SaveFatura(fatura *entity.RecebivelFatura) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.faturas[fatura.FaturaID] = *fatura
+	return nil
+}
+
+func (r *RecebiveisMemory) FindByClienteID(clienteID string) (*entity.RecebiveisCliente, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.clientes[clienteID]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (r *RecebiveisMemory) SaveCliente(recebiveis *entity.RecebiveisCliente) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clientes[recebiveis.ClienteID] = *recebiveis
+	return nil
+}
+
+func (r *RecebiveisMemory) Limpar() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.faturas = map[string]entity.RecebivelFatura{}
+	r.clientes = map[string]entity.RecebiveisCliente{}
+	return nil
+}
+
+type chaveEstatisticas struct {
+	dia  time.Time
+	tipo entity.TipoMensagem
+}
+
+type EstatisticasEnvioMemory struct {
+	mu           sync.RWMutex
+	estatisticas map[chaveEstatisticas]entity.EstatisticasEnvio
+}
+
+func NewEstatisticasEnvioMemory() *EstatisticasEnvioMemory {
+	return &EstatisticasEnvioMemory{estatisticas: map[chaveEstatisticas]entity.EstatisticasEnvio{}}
+}
+
+func (r *EstatisticasEnvioMemory) Incrementar(delta *entity.EstatisticasEnvio) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chave := chaveEstatisticas{dia: delta.Dia, tipo: delta.Tipo}
+	e, ok := r.estatisticas[chave]
+	if !ok {
+		e = entity.EstatisticasEnvio{Dia: delta.Dia, Tipo: delta.Tipo}
+	}
+	e.Enfileiradas += delta.Enfileiradas
+	e.Enviadas += delta.Enviadas
+	e.Falhas += delta.Falhas
+	e.DLQ += delta.DLQ
+	r.estatisticas[chave] = e
+	return nil
+}
+
+func (r *EstatisticasEnvioMemory) FindPeriodo(de, ate time.Time) ([]*entity.EstatisticasEnvio, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var periodo []*entity.EstatisticasEnvio
+	for _, e := range r.estatisticas {
+		if !e.Dia.Before(de) && !e.Dia.After(ate) {
+			e := e
+			periodo = append(periodo, &e)
+		}
+	}
+
+	sort.Slice(periodo, func(i, j int) bool {
+		if !periodo[i].Dia.Equal(periodo[j].Dia) {
+			return periodo[i].Dia.Before(periodo[j].Dia)
+		}
+		return periodo[i].Tipo < periodo[j].Tipo
+	})
+	return periodo, nil
+}
+
+func (r *EstatisticasEnvioMemory) Limpar() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.estatisticas = map[chaveEstatisticas]entity.EstatisticasEnvio{}
+	return nil
+}
+
+// ProjecaoUnitOfWorkMemory reserva uma projeção por vez e, como o UnitOfWorkMemory,
+// não faz rollback se fn falhar no meio.
+type ProjecaoUnitOfWorkMemory struct {
+	mu       sync.Mutex
+	ocupadas map[string]bool
+	Repos    repository.LeituraRepositorios
+}
+
+func NewProjecaoUnitOfWorkMemory(repos repository.LeituraRepositorios) *ProjecaoUnitOfWorkMemory {
+	return &ProjecaoUnitOfWorkMemory{ocupadas: map[string]bool{}, Repos: repos}
+}
+
+func (u *ProjecaoUnitOfWorkMemory) Executar(projecao string, fn func(repos repository.LeituraRepositorios) error) error {
+	if !u.Reservar(projecao) {
+		return repository.ErrProjecaoOcupada
+	}
+	defer u.Liberar(projecao)
+
+	return fn(u.Repos)
+}
+
+// Reservar marca a projeção como em processamento, como faria outra instância.
+// Retorna false se ela já estiver reservada.
+func (u *ProjecaoUnitOfWorkMemory) Reservar(projecao string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.ocupadas[projecao] {
+		return false
+	}
+	u.ocupadas[projecao] = true
+	return true
+}
+
+func (u *ProjecaoUnitOfWorkMemory) Liberar(projecao string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.ocupadas, projecao)
+}
+
+// LeituraStore agrupa os read models em memória
+type LeituraStore struct {
+	Checkpoints       *CheckpointMemory
+	Recebiveis        *RecebiveisMemory
+	EstatisticasEnvio *EstatisticasEnvioMemory
+}
+
+func NewLeituraStore() *LeituraStore {
+	return &LeituraStore{
+		Checkpoints:       NewCheckpointMemory(),
+		Recebiveis:        NewRecebiveisMemory(),
+		EstatisticasEnvio: NewEstatisticasEnvioMemory(),
+	}
+}
+
+// Repositorios expõe o LeituraStore no formato usado pelas projeções
+func (s *LeituraStore) Repositorios() repository.LeituraRepositorios {
+	return repository.LeituraRepositorios{
+		Checkpoints:       s.Checkpoints,
+		Recebiveis:        s.Recebiveis,
+		EstatisticasEnvio: s.EstatisticasEnvio,
+	}
+}
+
+// UnitOfWork cria um ProjecaoUnitOfWorkMemory sobre os repositórios do LeituraStore
+func (s *LeituraStore) UnitOfWork() *ProjecaoUnitOfWorkMemory {
+	return NewProjecaoUnitOfWorkMemory(s.Repositorios())
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var _ repository.ReguaRepository = (*ReguaMemory)(nil)
+
+type chaveEtapa struct {
+	faturaID string
+	dias     int
+}
+
+type ReguaMemory struct {
+	mu     sync.RWMutex
+	etapas map[chaveEtapa]entity.EtapaExecutada
+}
+
+func NewReguaMemory() *ReguaMemory {
+	return &ReguaMemory{etapas: map[chaveEtapa]entity.EtapaExecutada{}}
+}
+
+func (r *ReguaMemory) FindByFaturaID(faturaID string) ([]*entity.EtapaExecutada, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var etapas []*entity.EtapaExecutada
+	for k, e := range r.etapas {
+		if k.faturaID == faturaID {
+			e := e
+			etapas = append(etapas, &e)
+		}
+	}
+	sort.Slice(etapas, func(i, j int) bool {
+		if !etapas[i].ExecutadaEm.Equal(etapas[j].ExecutadaEm) {
+			return etapas[i].ExecutadaEm.Before(etapas[j].ExecutadaEm)
+		}
+		return etapas[i].Dias < etapas[j].Dias
+	})
+	return etapas, nil
+}
+
+func (r *ReguaMemory) Executada(faturaID string, dias int) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.etapas[chaveEtapa{faturaID, dias}]
+	return ok, nil
+}
+
+func (r *ReguaMemory) Registrar(etapa *entity.EtapaExecutada) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := chaveEtapa{etapa.FaturaID, etapa.Dias}
+	if _, ok := r.etapas[k]; ok {
+		return false, nil
+	}
+	r.etapas[k] = *etapa
+	return true, nil
+}
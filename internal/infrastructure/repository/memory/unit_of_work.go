@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var _ repository.UnitOfWork = (*UnitOfWorkMemory)(nil)
+
+// UnitOfWorkMemory serializa as execuções mas não faz rollback:
+// serve apenas para exercitar casos de uso sem banco.
+type UnitOfWorkMemory struct {
+	mu    sync.Mutex
+	Repos repository.Repositorios
+}
+
+func NewUnitOfWorkMemory(repos repository.Repositorios) *UnitOfWorkMemory {
+	return &UnitOfWorkMemory{Repos: repos}
+}
+
+func (u *UnitOfWorkMemory) Executar(fn func(repos repository.Repositorios) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return fn(u.Repos)
+}
+
+// Store agrupa um conjunto completo de repositórios em memória
+type Store struct {
+	Clientes          *ClienteMemory
+	Faturas           *FaturaMemory
+	Mensagens         *MensagemMemory
+	Configuracoes     *ConfiguracaoMemory
+	Eventos           *EventStoreMemory
+	Numeracao         *NumeracaoMemory
+	Assinaturas       *AssinaturaMemory
+	Regua             *ReguaMemory
+	Confirmacoes      *ConfirmacaoMemory
+	EntregasPendentes *EntregaPendenteMemory
+	CobrancasPix      *CobrancaPixMemory
+}
+
+func NewStore() *Store {
+	clientes := NewClienteMemory()
+	eventos := NewEventStoreMemory()
+	return &Store{
+		Clientes:          clientes,
+		Faturas:           NewFaturaMemory(clientes, eventos),
+		Mensagens:         NewMensagemMemory(),
+		Configuracoes:     NewConfiguracaoMemory(),
+		Eventos:           eventos,
+		Numeracao:         NewNumeracaoMemory(),
+		Assinaturas:       NewAssinaturaMemory(eventos),
+		Regua:             NewReguaMemory(),
+		Confirmacoes:      NewConfirmacaoMemory(),
+		EntregasPendentes: NewEntregaPendenteMemory(),
+		CobrancasPix:      NewCobrancaPixMemory(),
+	}
+}
+
+// Repositorios expõe o Store no formato usado pelos casos de uso
+func (s *Store) Repositorios() repository.Repositorios {
+	return repository.Repositorios{
+		Clientes:          s.Clientes,
+		Faturas:           s.Faturas,
+		Mensagens:         s.Mensagens,
+		Configuracoes:     s.Configuracoes,
+		Eventos:           s.Eventos,
+		Numeracao:         s.Numeracao,
+		Assinaturas:       s.Assinaturas,
+		Regua:             s.Regua,
+		Confirmacoes:      s.Confirmacoes,
+		EntregasPendentes: s.EntregasPendentes,
+		CobrancasPix:      s.CobrancasPix,
+	}
+}
+
+// UnitOfWork cria um UnitOfWorkMemory sobre os repositórios do Store
+func (s *Store) UnitOfWork() *UnitOfWorkMemory {
+	return NewUnitOfWorkMemory(s.Repositorios())
+}
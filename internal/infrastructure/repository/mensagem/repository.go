@@ -3,6 +3,7 @@ package mensagem
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/teusf/billing-system/internal/domain/entity"
 	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
@@ -18,8 +19,8 @@ func NewMensagemPostgres(db shared.DBTX) *MensagemPostgres {
 
 func (r *MensagemPostgres) Save(msg *entity.Mensagem) error {
 	_, err := r.db.Exec(`
-		INSERT INTO mensagens (id, fatura_id, cliente_id, whatsapp, tipo, conteudo, status, tentativas_envio, erro_mensagem, enviado_em, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO mensagens (id, fatura_id, cliente_id, whatsapp, tipo, conteudo, status, tentativas_envio, erro_mensagem, enviado_em, proxima_tentativa_em, id_provedor, entregue_em, lida_em, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	`,
 		msg.ID,
 		msg.FaturaID,
@@ -31,6 +32,10 @@ func (r *MensagemPostgres) Save(msg *entity.Mensagem) error {
 		msg.TentativasEnvio,
 		msg.ErroMensagem,
 		msg.EnviadoEm,
+		msg.ProximaTentativaEm,
+		msg.IDProvedor,
+		msg.EntregueEm,
+		msg.LidaEm,
 		msg.CreatedAt,
 		msg.UpdatedAt,
 	)
@@ -45,11 +50,11 @@ func (r *MensagemPostgres) Save(msg *entity.Mensagem) error {
 func (r *MensagemPostgres) FindByID(id string) (*entity.Mensagem, error) {
 	var m entity.Mensagem
 	err := r.db.QueryRow(`
-		SELECT id, fatura_id, cliente_id, whatsapp, tipo, conteudo, status, tentativas_envio, erro_mensagem, enviado_em, created_at, updated_at
+		SELECT id, fatura_id, cliente_id, whatsapp, tipo, conteudo, status, tentativas_envio, COALESCE(erro_mensagem, ''), enviado_em, proxima_tentativa_em, id_provedor, entregue_em, lida_em, created_at, updated_at
 		FROM mensagens
 		WHERE id = $1
 	`, id).Scan(
-		&m.ID, &m.FaturaID, &m.ClienteID, &m.WhatsApp, &m.Tipo, &m.Conteudo, &m.Status, &m.TentativasEnvio, &m.ErroMensagem, &m.EnviadoEm, &m.CreatedAt, &m.UpdatedAt,
+		&m.ID, &m.FaturaID, &m.ClienteID, &m.WhatsApp, &m.Tipo, &m.Conteudo, &m.Status, &m.TentativasEnvio, &m.ErroMensagem, &m.EnviadoEm, &m.ProximaTentativaEm, &m.IDProvedor, &m.EntregueEm, &m.LidaEm, &m.CreatedAt, &m.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -62,9 +67,34 @@ func (r *MensagemPostgres) FindByID(id string) (*entity.Mensagem, error) {
 	return &m, nil
 }
 
+func (r *MensagemPostgres) FindByIDProvedor(idProvedor string) (*entity.Mensagem, error) {
+	if idProvedor == "" {
+		return nil, nil
+	}
+
+	var m entity.Mensagem
+	err := r.db.QueryRow(`
+		SELECT id, fatura_id, cliente_id, whatsapp, tipo, conteudo, status, tentativas_envio, COALESCE(erro_mensagem, ''), enviado_em, proxima_tentativa_em, id_provedor, entregue_em, lida_em, created_at, updated_at
+		FROM mensagens
+		WHERE id_provedor = $1
+		FOR UPDATE
+	`, idProvedor).Scan(
+		&m.ID, &m.FaturaID, &m.ClienteID, &m.WhatsApp, &m.Tipo, &m.Conteudo, &m.Status, &m.TentativasEnvio, &m.ErroMensagem, &m.EnviadoEm, &m.ProximaTentativaEm, &m.IDProvedor, &m.EntregueEm, &m.LidaEm, &m.CreatedAt, &m.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar mensagem pelo id do provedor: %w", err)
+	}
+
+	return &m, nil
+}
+
 func (r *MensagemPostgres) FindByStatus(status entity.StatusMensagem) ([]*entity.Mensagem, error) {
 	rows, err := r.db.Query(`
-		SELECT id, fatura_id, cliente_id, whatsapp, tipo, conteudo, status, tentativas_envio, erro_mensagem, enviado_em, created_at, updated_at
+		SELECT id, fatura_id, cliente_id, whatsapp, tipo, conteudo, status, tentativas_envio, COALESCE(erro_mensagem, ''), enviado_em, proxima_tentativa_em, id_provedor, entregue_em, lida_em, created_at, updated_at
 		FROM mensagens
 		WHERE status = $1
 	`, status)
@@ -82,7 +112,7 @@ func (r *MensagemPostgres) FindParaDLQ() ([]*entity.Mensagem, error) {
 	// Ou somente para listar as que morreram?
 	// Vamos assumir que buscamos as que estao com status FALHA e tentativas >= 5
 	rows, err := r.db.Query(`
-		SELECT id, fatura_id, cliente_id, whatsapp, tipo, conteudo, status, tentativas_envio, erro_mensagem, enviado_em, created_at, updated_at
+		SELECT id, fatura_id, cliente_id, whatsapp, tipo, conteudo, status, tentativas_envio, COALESCE(erro_mensagem, ''), enviado_em, proxima_tentativa_em, id_provedor, entregue_em, lida_em, created_at, updated_at
 		FROM mensagens
 		WHERE status = $1 AND tentativas_envio >= 5
 	`, entity.StatusMensagemFalha)
@@ -94,16 +124,73 @@ func (r *MensagemPostgres) FindParaDLQ() ([]*entity.Mensagem, error) {
 	return r.scanRows(rows)
 }
 
+// ReivindicarParaEnvio reserva um lote de mensagens prontas para envio (pendentes ou com falha
+// retentável cujo backoff já expirou). A reserva empurra proxima_tentativa_em para agora+lease,
+// então outras réplicas não pegam as mesmas mensagens; se o worker morrer no meio do envio,
+// a mensagem volta a ficar disponível quando o lease expirar.
+func (r *MensagemPostgres) ReivindicarParaEnvio(agora time.Time, lease time.Duration, limite int) ([]*entity.Mensagem, error) {
+	rows, err := r.db.Query(`
+		UPDATE mensagens
+		SET proxima_tentativa_em = $1
+		WHERE id IN (
+			SELECT id
+			FROM mensagens
+			WHERE status IN ($2, $3)
+			AND tentativas_envio < $4
+			AND (proxima_tentativa_em IS NULL OR proxima_tentativa_em <= $5)
+			ORDER BY created_at
+			LIMIT $6
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, fatura_id, cliente_id, whatsapp, tipo, conteudo, status, tentativas_envio, COALESCE(erro_mensagem, ''), enviado_em, proxima_tentativa_em, id_provedor, entregue_em, lida_em, created_at, updated_at
+	`, agora.Add(lease), entity.StatusMensagemPendente, entity.StatusMensagemFalha, entity.MaxTentativasEnvio, agora, limite)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao reivindicar mensagens para envio: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanRows(rows)
+}
+
+// ReivindicarPorID reserva uma mensagem específica (usado pelo consumidor da fila).
+// Retorna nil se ela não estiver disponível: já enviada, na DLQ, em backoff ou reservada por outro worker.
+func (r *MensagemPostgres) ReivindicarPorID(id string, agora time.Time, lease time.Duration) (*entity.Mensagem, error) {
+	rows, err := r.db.Query(`
+		UPDATE mensagens
+		SET proxima_tentativa_em = $1
+		WHERE id = $2
+		AND status IN ($3, $4)
+		AND tentativas_envio < $5
+		AND (proxima_tentativa_em IS NULL OR proxima_tentativa_em <= $6)
+		RETURNING id, fatura_id, cliente_id, whatsapp, tipo, conteudo, status, tentativas_envio, COALESCE(erro_mensagem, ''), enviado_em, proxima_tentativa_em, id_provedor, entregue_em, lida_em, created_at, updated_at
+	`, agora.Add(lease), id, entity.StatusMensagemPendente, entity.StatusMensagemFalha, entity.MaxTentativasEnvio, agora)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao reivindicar mensagem: %w", err)
+	}
+	defer rows.Close()
+
+	msgs, err := r.scanRows(rows)
+	if err != nil || len(msgs) == 0 {
+		return nil, err
+	}
+	return msgs[0], nil
+}
+
 func (r *MensagemPostgres) Update(msg *entity.Mensagem) error {
 	_, err := r.db.Exec(`
 		UPDATE mensagens
-		SET status = $1, tentativas_envio = $2, erro_mensagem = $3, enviado_em = $4, updated_at = $5
-		WHERE id = $6
+		SET status = $1, tentativas_envio = $2, erro_mensagem = $3, enviado_em = $4, proxima_tentativa_em = $5,
+			id_provedor = $6, entregue_em = $7, lida_em = $8, updated_at = $9
+		WHERE id = $10
 	`,
 		msg.Status,
 		msg.TentativasEnvio,
 		msg.ErroMensagem,
 		msg.EnviadoEm,
+		msg.ProximaTentativaEm,
+		msg.IDProvedor,
+		msg.EntregueEm,
+		msg.LidaEm,
 		msg.UpdatedAt,
 		msg.ID,
 	)
@@ -120,7 +207,7 @@ func (r *MensagemPostgres) scanRows(rows *sql.Rows) ([]*entity.Mensagem, error)
 	for rows.Next() {
 		var m entity.Mensagem
 		if err := rows.Scan(
-			&m.ID, &m.FaturaID, &m.ClienteID, &m.WhatsApp, &m.Tipo, &m.Conteudo, &m.Status, &m.TentativasEnvio, &m.ErroMensagem, &m.EnviadoEm, &m.CreatedAt, &m.UpdatedAt,
+			&m.ID, &m.FaturaID, &m.ClienteID, &m.WhatsApp, &m.Tipo, &m.Conteudo, &m.Status, &m.TentativasEnvio, &m.ErroMensagem, &m.EnviadoEm, &m.ProximaTentativaEm, &m.IDProvedor, &m.EntregueEm, &m.LidaEm, &m.CreatedAt, &m.UpdatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("erro ao scanear mensagem: %w", err)
 		}
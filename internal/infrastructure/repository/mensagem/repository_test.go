@@ -43,7 +43,7 @@ func TestMensagemPostgres_CRUD(t *testing.T) {
 	fRepo := fatura.NewFaturaPostgres(tx)
 	// Usa data fixa para teste consistente
 	vencimento := time.Now().AddDate(0, 0, 5)
-	fatura, _ := entity.NewFatura(client.ID, 100, vencimento, "F1")
+	fatura, _ := entity.NewFatura(client.ID, "FAT-2026-000001", entity.BRL(10000), vencimento, "F1")
 	if err := fRepo.Save(fatura); err != nil {
 		t.Fatalf("Failed to save fatura: %v", err)
 	}
@@ -64,7 +64,7 @@ func TestMensagemPostgres_CRUD(t *testing.T) {
 	assert.Equal(t, msg.Conteudo, found.Conteudo)
 
 	// 3. Update (Enviada)
-	msg.MarcarComoEnviada()
+	msg.MarcarComoEnviada("3EB0C767D26A")
 	err = repo.Update(msg)
 	assert.NoError(t, err)
 
@@ -72,8 +72,25 @@ func TestMensagemPostgres_CRUD(t *testing.T) {
 	assert.Equal(t, entity.StatusMensagemEnviada, found2.Status)
 	assert.NotNil(t, found2.EnviadoEm)
 
+	// 3b. Webhooks do provedor: busca pelo ID dele e grava entrega e leitura
+	porProvedor, err := repo.FindByIDProvedor("3EB0C767D26A")
+	assert.NoError(t, err)
+	assert.Equal(t, msg.ID, porProvedor.ID)
+
+	porProvedor.MarcarComoLida(time.Now())
+	assert.NoError(t, repo.Update(porProvedor))
+
+	found3, _ := repo.FindByID(msg.ID)
+	assert.Equal(t, entity.StatusMensagemLida, found3.Status)
+	assert.NotNil(t, found3.EntregueEm)
+	assert.NotNil(t, found3.LidaEm)
+
+	desconhecida, err := repo.FindByIDProvedor("inexistente")
+	assert.NoError(t, err)
+	assert.Nil(t, desconhecida)
+
 	// 4. FindByStatus
-	list, err := repo.FindByStatus(entity.StatusMensagemEnviada)
+	list, err := repo.FindByStatus(entity.StatusMensagemLida)
 	assert.NoError(t, err)
 	// Como limpamos o banco, deve ser 1. Se tiver sujeira, >= 1
 	assert.GreaterOrEqual(t, len(list), 1)
@@ -92,3 +109,50 @@ func TestMensagemPostgres_CRUD(t *testing.T) {
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(dlq), 1) // deve achar msgFalha
 }
+
+func TestMensagemPostgres_ReivindicarParaEnvio(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	cRepo := cliente.NewClientePostgres(tx)
+	client, _ := entity.NewCliente("Cliente 1", "5511999997777", "")
+	cRepo.Save(client)
+
+	fRepo := fatura.NewFaturaPostgres(tx)
+	f, _ := entity.NewFatura(client.ID, "FAT-2026-000002", entity.BRL(10000), time.Now().AddDate(0, 0, 5), "F1")
+	fRepo.Save(f)
+
+	repo := NewMensagemPostgres(tx)
+	agora := time.Now()
+
+	pronta, _ := entity.NewMensagem(f.ID, client.ID, client.WhatsApp, "Pronta", entity.TipoMensagemLembrete)
+	repo.Save(pronta)
+
+	// Falhou e ainda está em backoff
+	emBackoff, _ := entity.NewMensagem(f.ID, client.ID, client.WhatsApp, "Backoff", entity.TipoMensagemLembrete)
+	emBackoff.MarcarComoFalha("timeout")
+	emBackoff.AgendarNovaTentativa(agora, time.Hour, time.Hour)
+	repo.Save(emBackoff)
+
+	// Já enviada
+	enviada, _ := entity.NewMensagem(f.ID, client.ID, client.WhatsApp, "Enviada", entity.TipoMensagemLembrete)
+	enviada.MarcarComoEnviada("3EB0C767D26B")
+	repo.Save(enviada)
+
+	lote, err := repo.ReivindicarParaEnvio(agora, time.Minute, 10)
+	assert.NoError(t, err)
+	assert.Len(t, lote, 1)
+	if len(lote) > 0 {
+		assert.Equal(t, pronta.ID, lote[0].ID)
+		assert.NotNil(t, lote[0].ProximaTentativaEm)
+	}
+
+	// O lease impede que a mesma mensagem seja reivindicada de novo
+	lote2, err := repo.ReivindicarParaEnvio(agora, time.Minute, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, lote2)
+
+	// Status DLQ é aceito pelo schema
+	pronta.MoverParaDLQ()
+	assert.NoError(t, repo.Update(pronta))
+}
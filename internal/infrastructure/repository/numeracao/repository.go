@@ -0,0 +1,37 @@
+package numeracao
+
+import (
+	"fmt"
+
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
+)
+
+var _ repository.NumeracaoRepository = (*NumeracaoPostgres)(nil)
+
+type NumeracaoPostgres struct {
+	db shared.DBTX
+}
+
+func NewNumeracaoPostgres(db shared.DBTX) *NumeracaoPostgres {
+	return &NumeracaoPostgres{db: db}
+}
+
+// Proximo incrementa o contador da série com um upsert. O UPDATE trava a linha até o fim
+// da transação: faturas concorrentes do mesmo tenant esperam em fila, e um rollback
+// desfaz o incremento. Uma SEQUENCE não serviria, porque nextval não volta no rollback.
+func (r *NumeracaoPostgres) Proximo(usuarioID, serie string, periodo int) (int64, error) {
+	var ultimo int64
+	err := r.db.QueryRow(`
+		INSERT INTO numeracao_faturas (usuario_id, serie, periodo, ultimo, updated_at)
+		VALUES ($1, $2, $3, 1, NOW())
+		ON CONFLICT (usuario_id, serie, periodo) DO UPDATE
+		SET ultimo = numeracao_faturas.ultimo + 1, updated_at = NOW()
+		RETURNING ultimo
+	`, usuarioID, serie, periodo).Scan(&ultimo)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao reservar numero da fatura: %w", err)
+	}
+
+	return ultimo, nil
+}
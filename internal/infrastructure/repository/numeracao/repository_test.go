@@ -0,0 +1,49 @@
+package numeracao
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/testutils"
+)
+
+func TestNumeracaoPostgres_Proximo(t *testing.T) {
+	db, err := testutils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Falha ao configurar banco de teste: %v", err)
+	}
+	defer db.Close()
+
+	tenant := uuid.New().String()
+	defer db.Exec("DELETE FROM numeracao_faturas WHERE usuario_id = $1", tenant)
+
+	t.Run("should give back the number when the transaction rolls back", func(t *testing.T) {
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+		n, err := NewNumeracaoPostgres(tx).Proximo(tenant, "FAT", 2026)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		assert.NoError(t, tx.Rollback())
+
+		tx, err = db.Begin()
+		assert.NoError(t, err)
+		repo := NewNumeracaoPostgres(tx)
+		n, _ = repo.Proximo(tenant, "FAT", 2026)
+		assert.Equal(t, int64(1), n)
+		n, _ = repo.Proximo(tenant, "FAT", 2026)
+		assert.Equal(t, int64(2), n)
+		assert.NoError(t, tx.Commit())
+	})
+
+	t.Run("should keep separate sequences per serie and periodo", func(t *testing.T) {
+		repo := NewNumeracaoPostgres(db)
+
+		n, _ := repo.Proximo(tenant, "FAT", 2026)
+		assert.Equal(t, int64(3), n)
+		n, _ = repo.Proximo(tenant, "NF", 2026)
+		assert.Equal(t, int64(1), n)
+		n, _ = repo.Proximo(tenant, "FAT", 2027)
+		assert.Equal(t, int64(1), n)
+	})
+}
@@ -0,0 +1,43 @@
+package projecao
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
+)
+
+var _ repository.CheckpointRepository = (*CheckpointPostgres)(nil)
+
+type CheckpointPostgres struct {
+	db shared.DBTX
+}
+
+func NewCheckpointPostgres(db shared.DBTX) *CheckpointPostgres {
+	return &CheckpointPostgres{db: db}
+}
+
+func (r *CheckpointPostgres) Carregar(projecao string) (int64, error) {
+	var position int64
+	err := r.db.QueryRow(`SELECT position FROM projecao_checkpoints WHERE projecao = $1`, projecao).Scan(&position)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("erro ao carregar checkpoint: %w", err)
+	}
+	return position, nil
+}
+
+func (r *CheckpointPostgres) Salvar(projecao string, position int64) error {
+	_, err := r.db.Exec(`
+		INSERT INTO projecao_checkpoints (projecao, position, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (projecao) DO UPDATE SET position = EXCLUDED.position, updated_at = EXCLUDED.updated_at
+	`, projecao, position)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar checkpoint: %w", err)
+	}
+	return nil
+}
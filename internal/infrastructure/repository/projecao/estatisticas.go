@@ -0,0 +1,67 @@
+package projecao
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
+)
+
+var _ repository.EstatisticasEnvioRepository = (*EstatisticasEnvioPostgres)(nil)
+
+type EstatisticasEnvioPostgres struct {
+	db shared.DBTX
+}
+
+func NewEstatisticasEnvioPostgres(db shared.DBTX) *EstatisticasEnvioPostgres {
+	return &EstatisticasEnvioPostgres{db: db}
+}
+
+func (r *EstatisticasEnvioPostgres) Incrementar(d *entity.EstatisticasEnvio) error {
+	_, err := r.db.Exec(`
+		INSERT INTO proj_estatisticas_envio (dia, tipo, enfileiradas, enviadas, falhas, dlq)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (dia, tipo) DO UPDATE SET
+			enfileiradas = proj_estatisticas_envio.enfileiradas + EXCLUDED.enfileiradas,
+			enviadas = proj_estatisticas_envio.enviadas + EXCLUDED.enviadas,
+			falhas = proj_estatisticas_envio.falhas + EXCLUDED.falhas,
+			dlq = proj_estatisticas_envio.dlq + EXCLUDED.dlq
+	`, d.Dia, d.Tipo, d.Enfileiradas, d.Enviadas, d.Falhas, d.DLQ)
+	if err != nil {
+		return fmt.Errorf("erro ao incrementar estatisticas de envio: %w", err)
+	}
+	return nil
+}
+
+func (r *EstatisticasEnvioPostgres) FindPeriodo(de, ate time.Time) ([]*entity.EstatisticasEnvio, error) {
+	rows, err := r.db.Query(`
+		SELECT dia, tipo, enfileiradas, enviadas, falhas, dlq
+		FROM proj_estatisticas_envio
+		WHERE dia BETWEEN $1 AND $2
+		ORDER BY dia, tipo
+	`, de, ate)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar estatisticas de envio: %w", err)
+	}
+	defer rows.Close()
+
+	var estatisticas []*entity.EstatisticasEnvio
+	for rows.Next() {
+		var e entity.EstatisticasEnvio
+		if err := rows.Scan(&e.Dia, &e.Tipo, &e.Enfileiradas, &e.Enviadas, &e.Falhas, &e.DLQ); err != nil {
+			return nil, fmt.Errorf("erro ao scanear estatisticas de envio: %w", err)
+		}
+		estatisticas = append(estatisticas, &e)
+	}
+	return estatisticas, rows.Err()
+}
+
+// Limpar usa DELETE (e não TRUNCATE) para não bloquear as leituras durante a reconstrução
+func (r *EstatisticasEnvioPostgres) Limpar() error {
+	if _, err := r.db.Exec(`DELETE FROM proj_estatisticas_envio`); err != nil {
+		return fmt.Errorf("erro ao limpar estatisticas de envio: %w", err)
+	}
+	return nil
+}
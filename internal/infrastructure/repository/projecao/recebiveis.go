@@ -0,0 +1,114 @@
+package projecao
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
+)
+
+var _ repository.RecebiveisRepository = (*RecebiveisPostgres)(nil)
+
+type RecebiveisPostgres struct {
+	db shared.DBTX
+}
+
+func NewRecebiveisPostgres(db shared.DBTX) *RecebiveisPostgres {
+	return &RecebiveisPostgres{db: db}
+}
+
+func (r *RecebiveisPostgres) FindFatura(faturaID string) (*entity.RecebivelFatura, error) {
+	var f entity.RecebivelFatura
+	err := r.db.QueryRow(`
+		SELECT fatura_id, cliente_id, valor, recebido, status
+		FROM proj_recebiveis_faturas
+		WHERE fatura_id = $1
+	`, faturaID).Scan(&f.FaturaID, &f.ClienteID, &f.Valor, &f.Recebido, &f.Status)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar recebivel da fatura: %w", err)
+	}
+	return &f, nil
+}
+
+func (r *RecebiveisPostgres) SaveFatura(f *entity.RecebivelFatura) error {
+	_, err := r.db.Exec(`
+		INSERT INTO proj_recebiveis_faturas (fatura_id, cliente_id, valor, recebido, status)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (fatura_id) DO UPDATE SET cliente_id = EXCLUDED.cliente_id, valor = EXCLUDED.valor, recebido = EXCLUDED.recebido, status = EXCLUDED.status
+	`, f.FaturaID, f.ClienteID, f.Valor, f.Recebido, f.Status)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar recebivel da fatura: %w", err)
+	}
+	return nil
+}
+
+func (r *RecebiveisPostgres) FindByClienteID(clienteID string) (*entity.RecebiveisCliente, error) {
+	var c entity.RecebiveisCliente
+	err := r.db.QueryRow(`
+		SELECT cliente_id, quantidade_em_aberto, valor_em_aberto, quantidade_vencida, valor_vencido, quantidade_paga, valor_recebido, updated_at
+		FROM proj_recebiveis_clientes
+		WHERE cliente_id = $1
+	`, clienteID).Scan(
+		&c.ClienteID,
+		&c.QuantidadeEmAberto,
+		&c.ValorEmAberto,
+		&c.QuantidadeVencida,
+		&c.ValorVencido,
+		&c.QuantidadePaga,
+		&c.ValorRecebido,
+		&c.AtualizadoEm,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar recebiveis do cliente: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *RecebiveisPostgres) SaveCliente(c *entity.RecebiveisCliente) error {
+	_, err := r.db.Exec(`
+		INSERT INTO proj_recebiveis_clientes (cliente_id, quantidade_em_aberto, valor_em_aberto, quantidade_vencida, valor_vencido, quantidade_paga, valor_recebido, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (cliente_id) DO UPDATE SET
+			quantidade_em_aberto = EXCLUDED.quantidade_em_aberto,
+			valor_em_aberto = EXCLUDED.valor_em_aberto,
+			quantidade_vencida = EXCLUDED.quantidade_vencida,
+			valor_vencido = EXCLUDED.valor_vencido,
+			quantidade_paga = EXCLUDED.quantidade_paga,
+			valor_recebido = EXCLUDED.valor_recebido,
+			updated_at = EXCLUDED.updated_at
+	`,
+		c.ClienteID,
+		c.QuantidadeEmAberto,
+		c.ValorEmAberto,
+		c.QuantidadeVencida,
+		c.ValorVencido,
+		c.QuantidadePaga,
+		c.ValorRecebido,
+		c.AtualizadoEm,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar recebiveis do cliente: %w", err)
+	}
+	return nil
+}
+
+// Limpar usa DELETE (e não TRUNCATE) para não bloquear as leituras durante a reconstrução
+func (r *RecebiveisPostgres) Limpar() error {
+	if _, err := r.db.Exec(`DELETE FROM proj_recebiveis_faturas`); err != nil {
+		return fmt.Errorf("erro ao limpar recebiveis: %w", err)
+	}
+	if _, err := r.db.Exec(`DELETE FROM proj_recebiveis_clientes`); err != nil {
+		return fmt.Errorf("erro ao limpar recebiveis: %w", err)
+	}
+	return nil
+}
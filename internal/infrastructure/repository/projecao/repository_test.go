@@ -0,0 +1,103 @@
+package projecao
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/testutils"
+)
+
+var testDB *sql.DB
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = testutils.SetupTestDB()
+	if err != nil {
+		log.Fatalf("Falha ao configurar banco de teste: %v", err)
+	}
+	defer testDB.Close()
+
+	code := m.Run()
+	testDB.Close()
+	os.Exit(code)
+}
+
+func TestCheckpointPostgres(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	repo := NewCheckpointPostgres(tx)
+
+	// Projeção que nunca rodou começa do zero
+	cp, err := repo.Carregar("teste")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), cp)
+
+	assert.NoError(t, repo.Salvar("teste", 10))
+	assert.NoError(t, repo.Salvar("teste", 25))
+
+	cp, err = repo.Carregar("teste")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), cp)
+}
+
+func TestRecebiveisPostgres(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	repo := NewRecebiveisPostgres(tx)
+	clienteID := uuid.New().String()
+	faturaID := uuid.New().String()
+
+	f := &entity.RecebivelFatura{FaturaID: faturaID, ClienteID: clienteID, Valor: entity.BRL(15050), Status: entity.StatusPendente}
+	assert.NoError(t, repo.SaveFatura(f))
+	f.Status = entity.StatusPaga
+	assert.NoError(t, repo.SaveFatura(f))
+
+	found, err := repo.FindFatura(faturaID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusPaga, found.Status)
+	assert.Equal(t, entity.BRL(15050), found.Valor)
+
+	c := &entity.RecebiveisCliente{ClienteID: clienteID, QuantidadePaga: 1, ValorRecebido: entity.BRL(15050), AtualizadoEm: time.Now()}
+	assert.NoError(t, repo.SaveCliente(c))
+
+	resumo, err := repo.FindByClienteID(clienteID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resumo.QuantidadePaga)
+	assert.Equal(t, entity.BRL(15050), resumo.ValorRecebido)
+
+	assert.NoError(t, repo.Limpar())
+	resumo, err = repo.FindByClienteID(clienteID)
+	assert.NoError(t, err)
+	assert.Nil(t, resumo)
+}
+
+func TestEstatisticasEnvioPostgres(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	repo := NewEstatisticasEnvioPostgres(tx)
+	dia := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, repo.Incrementar(&entity.EstatisticasEnvio{Dia: dia, Tipo: entity.TipoMensagemLembrete, Enfileiradas: 1}))
+	assert.NoError(t, repo.Incrementar(&entity.EstatisticasEnvio{Dia: dia, Tipo: entity.TipoMensagemLembrete, Enviadas: 1}))
+	assert.NoError(t, repo.Incrementar(&entity.EstatisticasEnvio{Dia: dia.AddDate(0, 0, 1), Tipo: entity.TipoMensagemCobranca, DLQ: 1}))
+
+	stats, err := repo.FindPeriodo(dia, dia)
+	assert.NoError(t, err)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, 1, stats[0].Enfileiradas)
+	assert.Equal(t, 1, stats[0].Enviadas)
+
+	stats, err = repo.FindPeriodo(dia, dia.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+}
@@ -0,0 +1,71 @@
+package regua
+
+import (
+	"fmt"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/shared"
+)
+
+var _ repository.ReguaRepository = (*ReguaPostgres)(nil)
+
+type ReguaPostgres struct {
+	db shared.DBTX
+}
+
+func NewReguaPostgres(db shared.DBTX) *ReguaPostgres {
+	return &ReguaPostgres{db: db}
+}
+
+func (r *ReguaPostgres) FindByFaturaID(faturaID string) ([]*entity.EtapaExecutada, error) {
+	rows, err := r.db.Query(`
+		SELECT fatura_id, dias, mensagem_id, executada_em
+		FROM fatura_etapas_cobranca
+		WHERE fatura_id = $1
+		ORDER BY executada_em, dias
+	`, faturaID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar etapas da fatura: %w", err)
+	}
+	defer rows.Close()
+
+	var etapas []*entity.EtapaExecutada
+	for rows.Next() {
+		var e entity.EtapaExecutada
+		if err := rows.Scan(&e.FaturaID, &e.Dias, &e.MensagemID, &e.ExecutadaEm); err != nil {
+			return nil, fmt.Errorf("erro ao scanear etapa da fatura: %w", err)
+		}
+		etapas = append(etapas, &e)
+	}
+
+	return etapas, rows.Err()
+}
+
+func (r *ReguaPostgres) Executada(faturaID string, dias int) (bool, error) {
+	var existe bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM fatura_etapas_cobranca WHERE fatura_id = $1 AND dias = $2)
+	`, faturaID, dias).Scan(&existe)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar etapa da fatura: %w", err)
+	}
+	return existe, nil
+}
+
+func (r *ReguaPostgres) Registrar(etapa *entity.EtapaExecutada) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO fatura_etapas_cobranca (fatura_id, dias, mensagem_id, executada_em)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (fatura_id, dias) DO NOTHING
+	`, etapa.FaturaID, etapa.Dias, etapa.MensagemID, etapa.ExecutadaEm)
+	if err != nil {
+		return false, fmt.Errorf("erro ao registrar etapa da fatura: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erro ao registrar etapa da fatura: %w", err)
+	}
+	return n == 1, nil
+}
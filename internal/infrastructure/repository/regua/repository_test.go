@@ -0,0 +1,76 @@
+package regua
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/cliente"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/fatura"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/mensagem"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/testutils"
+)
+
+var testDB *sql.DB
+
+func TestMain(m *testing.M) {
+	var err error
+	testDB, err = testutils.SetupTestDB()
+	if err != nil {
+		log.Fatalf("Falha ao configurar banco de teste: %v", err)
+	}
+	defer testDB.Close()
+
+	code := m.Run()
+	testDB.Close()
+	os.Exit(code)
+}
+
+func TestReguaPostgres(t *testing.T) {
+	tx, cleanup := testutils.NewTestTx(t, testDB)
+	defer cleanup()
+
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "c1@test.com")
+	cliente.NewClientePostgres(tx).Save(client)
+
+	f, _ := entity.NewFatura(client.ID, "FAT-2026-000001", entity.BRL(10000), time.Now().AddDate(0, 0, 5), "F1")
+	fatura.NewFaturaPostgres(tx).Save(f)
+
+	msg, _ := entity.NewMensagem(f.ID, client.ID, client.WhatsApp, "Ola", entity.TipoMensagemLembrete)
+	mensagem.NewMensagemPostgres(tx).Save(msg)
+
+	repo := NewReguaPostgres(tx)
+
+	executada, err := repo.Executada(f.ID, -5)
+	assert.NoError(t, err)
+	assert.False(t, executada)
+
+	t.Run("should register each step only once", func(t *testing.T) {
+		etapa := &entity.EtapaExecutada{FaturaID: f.ID, Dias: -5, MensagemID: msg.ID, ExecutadaEm: time.Now()}
+
+		ok, err := repo.Registrar(etapa)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.Registrar(etapa)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		executada, _ := repo.Executada(f.ID, -5)
+		assert.True(t, executada)
+		executada, _ = repo.Executada(f.ID, 0)
+		assert.False(t, executada)
+	})
+
+	t.Run("should list the steps of the fatura", func(t *testing.T) {
+		etapas, err := repo.FindByFaturaID(f.ID)
+		assert.NoError(t, err)
+		assert.Len(t, etapas, 1)
+		assert.Equal(t, -5, etapas[0].Dias)
+		assert.Equal(t, msg.ID, etapas[0].MensagemID)
+	})
+}
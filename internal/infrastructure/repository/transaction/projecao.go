@@ -0,0 +1,55 @@
+package transaction
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/projecao"
+)
+
+var _ repository.ProjecaoUnitOfWork = (*ProjecaoUnitOfWorkPostgres)(nil)
+
+// Namespace dos advisory locks das projeções (a segunda chave é o hash do nome)
+const lockProjecoes = 7_302_003
+
+type ProjecaoUnitOfWorkPostgres struct {
+	db *sql.DB
+}
+
+func NewProjecaoUnitOfWorkPostgres(db *sql.DB) *ProjecaoUnitOfWorkPostgres {
+	return &ProjecaoUnitOfWorkPostgres{db: db}
+}
+
+// Executar reserva a projeção com um advisory lock de transação. Read model e checkpoint
+// são gravados no mesmo commit, então um lote que falha é reaplicado inteiro no próximo ciclo.
+func (u *ProjecaoUnitOfWorkPostgres) Executar(nome string, fn func(repos repository.LeituraRepositorios) error) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transacao: %w", err)
+	}
+	defer tx.Rollback()
+
+	var obtido bool
+	if err := tx.QueryRow(`SELECT pg_try_advisory_xact_lock($1, hashtext($2))`, lockProjecoes, nome).Scan(&obtido); err != nil {
+		return fmt.Errorf("erro ao reservar projecao: %w", err)
+	}
+	if !obtido {
+		return repository.ErrProjecaoOcupada
+	}
+
+	repos := repository.LeituraRepositorios{
+		Checkpoints:       projecao.NewCheckpointPostgres(tx),
+		Recebiveis:        projecao.NewRecebiveisPostgres(tx),
+		EstatisticasEnvio: projecao.NewEstatisticasEnvioPostgres(tx),
+	}
+
+	if err := fn(repos); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao commitar transacao: %w", err)
+	}
+	return nil
+}
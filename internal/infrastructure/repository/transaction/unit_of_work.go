@@ -0,0 +1,74 @@
+package transaction
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/assinatura"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/cliente"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/cobrancapix"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/configuracao"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/confirmacao"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/entrega"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/eventstore"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/fatura"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/mensagem"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/numeracao"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/regua"
+)
+
+var _ repository.UnitOfWork = (*UnitOfWorkPostgres)(nil)
+
+type UnitOfWorkPostgres struct {
+	db *sql.DB
+}
+
+func NewUnitOfWorkPostgres(db *sql.DB) *UnitOfWorkPostgres {
+	return &UnitOfWorkPostgres{db: db}
+}
+
+// Executar abre uma transação, monta os repositórios sobre ela (via shared.DBTX)
+// e faz commit apenas se fn terminar sem erro. Os eventos salvos em repos.Eventos
+// entram no outbox na mesma transação e são publicados depois pelo relay.
+func (u *UnitOfWorkPostgres) Executar(fn func(repos repository.Repositorios) error) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transacao: %w", err)
+	}
+
+	// Garante rollback mesmo em caso de panic dentro de fn
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	repos := repository.Repositorios{
+		Clientes:          cliente.NewClientePostgres(tx),
+		Faturas:           fatura.NewFaturaPostgres(tx),
+		Mensagens:         mensagem.NewMensagemPostgres(tx),
+		Configuracoes:     configuracao.NewConfiguracaoPostgres(tx),
+		Eventos:           eventstore.NewEventStorePostgres(tx),
+		Numeracao:         numeracao.NewNumeracaoPostgres(tx),
+		Assinaturas:       assinatura.NewAssinaturaPostgres(tx),
+		Regua:             regua.NewReguaPostgres(tx),
+		Confirmacoes:      confirmacao.NewConfirmacaoPostgres(tx),
+		EntregasPendentes: entrega.NewEntregaPendentePostgres(tx),
+		CobrancasPix:      cobrancapix.NewCobrancaPixPostgres(tx),
+	}
+
+	if err := fn(repos); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback falhou: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao commitar transacao: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"go.uber.org/zap"
+)
+
+// Scheduler encapsula o gocron para rodar jobs periódicos com log padronizado
+type Scheduler struct {
+	cron   *gocron.Scheduler
+	logger *zap.Logger
+}
+
+func NewScheduler(logger *zap.Logger) *Scheduler {
+	cron := gocron.NewScheduler(time.Local)
+	// Uma execução por vez de cada job: se um ciclo atrasar, o próximo espera
+	cron.SingletonModeAll()
+
+	return &Scheduler{cron: cron, logger: logger}
+}
+
+// Agendar registra um job que roda imediatamente e depois a cada intervalo
+func (s *Scheduler) Agendar(nome string, intervalo time.Duration, job func() error) error {
+	_, err := s.cron.Every(intervalo).Do(func() {
+		inicio := time.Now()
+		if err := job(); err != nil {
+			s.logger.Error("Job falhou", zap.String("job", nome), zap.Error(err))
+			return
+		}
+		s.logger.Debug("Job executado", zap.String("job", nome), zap.Duration("duracao", time.Since(inicio)))
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao agendar job %s: %w", nome, err)
+	}
+
+	return nil
+}
+
+func (s *Scheduler) Start() {
+	s.cron.StartAsync()
+}
+
+// Stop aguarda os jobs em execução terminarem
+func (s *Scheduler) Stop() {
+	s.cron.Stop()
+}
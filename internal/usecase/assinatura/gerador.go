@@ -0,0 +1,156 @@
+package assinatura
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/usecase/numeracao"
+)
+
+// Gerador emite as faturas das assinaturas ativas, uma por ciclo, alguns dias antes do vencimento
+type Gerador struct {
+	uow              repository.UnitOfWork
+	logger           *zap.Logger
+	diasAntecedencia int
+	agora            func() time.Time
+}
+
+// NewGerador cria o gerador. A fatura de cada ciclo é emitida diasAntecedencia dias
+// antes do vencimento, para que o lembrete do tenant ainda tenha tempo de sair.
+func NewGerador(uow repository.UnitOfWork, logger *zap.Logger, diasAntecedencia int) *Gerador {
+	return &Gerador{
+		uow:              uow,
+		logger:           logger,
+		diasAntecedencia: diasAntecedencia,
+		agora:            time.Now,
+	}
+}
+
+// Executar fatura ciclos até não restar nenhum e retorna quantas faturas foram emitidas.
+// Cada ciclo é uma transação: a fatura, o período faturado e o avanço da assinatura são gravados
+// juntos, então uma re-execução depois de uma queda retoma exatamente do ciclo seguinte.
+// Uma assinatura atrasada vários ciclos recebe um por vez, até alcançar a data.
+// Falhas em uma assinatura (cliente removido, numeração, validação da fatura) são logadas
+// e ela fica de fora do resto da execução, sem impedir as demais.
+func (g *Gerador) Executar() (int, error) {
+	total := 0
+	falhas := map[string]bool{}
+
+	for {
+		var atual *entity.Assinatura
+		emitida := false
+
+		err := g.uow.Executar(func(repos repository.Repositorios) error {
+			agora := g.agora()
+
+			// As que falharam vêm primeiro na ordem de vencimento: buscar uma a mais que elas
+			// garante achar a próxima ainda não tentada, se houver
+			assinaturas, err := repos.Assinaturas.FindParaFaturar(agora.AddDate(0, 0, g.diasAntecedencia), len(falhas)+1)
+			if err != nil {
+				return err
+			}
+			for _, a := range assinaturas {
+				if !falhas[a.ID] {
+					atual = a
+					break
+				}
+			}
+			if atual == nil {
+				return nil
+			}
+
+			emitida, err = g.faturarCiclo(repos, atual, agora)
+			return err
+		})
+		if err != nil && atual == nil {
+			return total, fmt.Errorf("erro ao gerar faturas de assinaturas: %w", err)
+		}
+		if err != nil {
+			g.logger.Error("Falha ao faturar ciclo da assinatura",
+				zap.String("assinatura_id", atual.ID), zap.Int("ciclo", atual.ProximoCiclo), zap.Error(err))
+			falhas[atual.ID] = true
+			continue
+		}
+		if atual == nil {
+			return total, nil
+		}
+
+		if emitida {
+			total++
+		}
+	}
+}
+
+// faturarCiclo emite a fatura do próximo ciclo da assinatura e a avança.
+// Retorna false quando o período já tinha fatura: a assinatura só avança.
+func (g *Gerador) faturarCiclo(repos repository.Repositorios, a *entity.Assinatura, agora time.Time) (bool, error) {
+	ciclo := a.ProximoCiclo
+	periodo := a.Periodo(ciclo)
+
+	existente, err := repos.Assinaturas.FaturaDoPeriodo(a.ID, periodo)
+	if err != nil {
+		return false, err
+	}
+	if existente != "" {
+		g.logger.Warn("Periodo da assinatura ja faturado",
+			zap.String("assinatura_id", a.ID), zap.String("periodo", periodo), zap.String("fatura_id", existente))
+		if err := a.RegistrarFatura(existente); err != nil {
+			return false, err
+		}
+		return false, repos.Assinaturas.Update(a)
+	}
+
+	cliente, err := repos.Clientes.FindByID(a.ClienteID)
+	if err != nil {
+		return false, err
+	}
+	if cliente == nil {
+		return false, fmt.Errorf("cliente %s nao encontrado", a.ClienteID)
+	}
+
+	// Um ciclo que venceu sem fatura (gerador parado por mais que a antecedência)
+	// ainda é cobrado, com vencimento no dia seguinte
+	vencimento := a.Vencimento(ciclo)
+	if !vencimento.After(agora) {
+		g.logger.Warn("Ciclo da assinatura faturado com atraso",
+			zap.String("assinatura_id", a.ID), zap.String("periodo", periodo))
+		vencimento = time.Date(agora.Year(), agora.Month(), agora.Day()+1, 0, 0, 0, 0, agora.Location())
+	}
+
+	numero, err := numeracao.Proximo(repos, cliente.UsuarioID, agora)
+	if err != nil {
+		return false, err
+	}
+
+	descricao := fmt.Sprintf("%s - %s", a.Descricao, a.Vencimento(ciclo).Format("01/2006"))
+	fatura, err := entity.NewFatura(a.ClienteID, numero, a.Valor, vencimento, descricao)
+	if err != nil {
+		return false, err
+	}
+	fatura.UsuarioID = cliente.UsuarioID
+	if err := repos.Faturas.Save(fatura); err != nil {
+		return false, err
+	}
+
+	// A chave (assinatura, período) é a garantia final contra duplicidade
+	registrado, err := repos.Assinaturas.RegistrarPeriodo(a.ID, periodo, fatura.ID)
+	if err != nil {
+		return false, err
+	}
+	if !registrado {
+		return false, fmt.Errorf("periodo %s faturado em paralelo", periodo)
+	}
+
+	if err := a.RegistrarFatura(fatura.ID); err != nil {
+		return false, err
+	}
+	if err := repos.Assinaturas.Update(a); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
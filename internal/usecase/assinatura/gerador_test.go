@@ -0,0 +1,153 @@
+package assinatura
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func setup(t *testing.T) (*Gerador, *memory.Store, *entity.Cliente) {
+	t.Helper()
+
+	store := memory.NewStore()
+
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "")
+	client.UsuarioID = "user1"
+	store.Clientes.Save(client)
+
+	return NewGerador(store.UnitOfWork(), zap.NewNop(), 10), store, client
+}
+
+func novaAssinatura(t *testing.T, store *memory.Store, clienteID string, dia int) *entity.Assinatura {
+	t.Helper()
+
+	a, err := entity.NewAssinatura(clienteID, entity.BRL(50000), "Retainer", entity.PeriodicidadeMensal, dia, time.Now(), nil)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Assinaturas.Save(a))
+	return a
+}
+
+func TestGerador_Executar(t *testing.T) {
+	t.Run("should emit one fatura per cycle inside the lead time", func(t *testing.T) {
+		g, store, client := setup(t)
+		a := novaAssinatura(t, store, client.ID, 28)
+		vencimento := a.ProximoVencimento()
+
+		// Faltando mais que a antecedência, nada é emitido
+		g.agora = func() time.Time { return vencimento.AddDate(0, 0, -11) }
+		n, err := g.Executar()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+
+		g.agora = func() time.Time { return vencimento.AddDate(0, 0, -10) }
+		n, err = g.Executar()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		faturas, _ := store.Faturas.FindByClienteID(client.ID)
+		assert.Len(t, faturas, 1)
+		assert.Equal(t, entity.BRL(50000), faturas[0].Valor)
+		assert.True(t, vencimento.Equal(faturas[0].DataVencimento))
+		assert.Equal(t, "Retainer - "+vencimento.Format("01/2006"), faturas[0].Descricao)
+
+		atual, _ := store.Assinaturas.FindByID(a.ID)
+		assert.Equal(t, a.ProximoCiclo+1, atual.ProximoCiclo)
+
+		faturaID, _ := store.Assinaturas.FaturaDoPeriodo(a.ID, a.Periodo(a.ProximoCiclo))
+		assert.Equal(t, faturas[0].ID, faturaID)
+	})
+
+	t.Run("should not duplicate faturas when run again", func(t *testing.T) {
+		g, store, client := setup(t)
+		a := novaAssinatura(t, store, client.ID, 28)
+		g.agora = func() time.Time { return a.ProximoVencimento().AddDate(0, 0, -5) }
+
+		g.Executar()
+		n, err := g.Executar()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+
+		faturas, _ := store.Faturas.FindByClienteID(client.ID)
+		assert.Len(t, faturas, 1)
+	})
+
+	t.Run("should skip a period that already has a fatura", func(t *testing.T) {
+		g, store, client := setup(t)
+		a := novaAssinatura(t, store, client.ID, 28)
+		g.agora = func() time.Time { return a.ProximoVencimento().AddDate(0, 0, -5) }
+
+		// Queda entre a gravação do período e o avanço da assinatura (sem rollback na memória)
+		store.Assinaturas.RegistrarPeriodo(a.ID, a.Periodo(a.ProximoCiclo), "fat-anterior")
+
+		n, err := g.Executar()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+
+		faturas, _ := store.Faturas.FindByClienteID(client.ID)
+		assert.Empty(t, faturas)
+		atual, _ := store.Assinaturas.FindByID(a.ID)
+		assert.Equal(t, a.ProximoCiclo+1, atual.ProximoCiclo)
+	})
+
+	t.Run("should catch up missed cycles with a due date in the future", func(t *testing.T) {
+		g, store, client := setup(t)
+		a := novaAssinatura(t, store, client.ID, 28)
+
+		// Gerador parado por três ciclos
+		agora := a.Vencimento(a.ProximoCiclo+2).AddDate(0, 0, 1)
+		g.agora = func() time.Time { return agora }
+
+		n, err := g.Executar()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n) // O ciclo seguinte ainda está fora da antecedência
+
+		faturas, _ := store.Faturas.FindByClienteID(client.ID)
+		assert.Len(t, faturas, 3)
+		for _, f := range faturas {
+			assert.True(t, f.DataVencimento.After(agora))
+		}
+	})
+
+	t.Run("should ignore paused subscriptions", func(t *testing.T) {
+		g, store, client := setup(t)
+		a := novaAssinatura(t, store, client.ID, 28)
+		a.Pausar()
+		store.Assinaturas.Update(a)
+		g.agora = func() time.Time { return a.ProximoVencimento().AddDate(0, 0, -1) }
+
+		n, err := g.Executar()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+	t.Run("should not let a broken subscription block the others", func(t *testing.T) {
+		g, store, client := setup(t)
+
+		// Assinatura de um cliente removido, com vencimento antes da saudável
+		removido, _ := entity.NewCliente("Removido", "5511999997777", "")
+		store.Clientes.Save(removido)
+		quebrada, err := entity.NewAssinatura(removido.ID, entity.BRL(50000), "Retainer", entity.PeriodicidadeMensal, 28, time.Now().AddDate(0, -1, 0), nil)
+		assert.NoError(t, err)
+		store.Assinaturas.Save(quebrada)
+		store.Clientes.Delete(removido.ID)
+
+		a := novaAssinatura(t, store, client.ID, 28)
+		g.agora = func() time.Time { return a.ProximoVencimento().AddDate(0, 0, -5) }
+
+		for i := 0; i < 2; i++ {
+			n, err := g.Executar()
+			assert.NoError(t, err)
+			assert.Equal(t, 1-i, n)
+		}
+
+		faturas, _ := store.Faturas.FindByClienteID(client.ID)
+		assert.Len(t, faturas, 1)
+
+		atual, _ := store.Assinaturas.FindByID(quebrada.ID)
+		assert.Equal(t, quebrada.ProximoCiclo, atual.ProximoCiclo)
+	})
+}
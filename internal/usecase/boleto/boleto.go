@@ -0,0 +1,61 @@
+package boleto
+
+import (
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+// Emitir monta o boleto da fatura com a conta de cobrança do tenant dono do cliente
+func Emitir(repos repository.Repositorios, fatura *entity.Fatura, agora time.Time) (*entity.Boleto, error) {
+	cliente, err := repos.Clientes.FindByID(fatura.ClienteID)
+	if err != nil {
+		return nil, err
+	}
+	if cliente == nil || cliente.UsuarioID == "" {
+		return nil, entity.ErrBoletoNaoConfigurado
+	}
+
+	config, err := repos.Configuracoes.FindByUsuarioID(cliente.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, entity.ErrBoletoNaoConfigurado
+	}
+
+	return ParaFatura(config, fatura, agora)
+}
+
+// ParaFatura é o Emitir para quem já tem a configuração do tenant em mãos.
+// Até o vencimento, o boleto cobra o saldo no vencimento da fatura. Depois dele, é reemitido
+// para hoje com o saldo atualizado (multa e juros), já que o banco não calcula nossos encargos.
+func ParaFatura(config *entity.Configuracao, fatura *entity.Fatura, agora time.Time) (*entity.Boleto, error) {
+	switch fatura.Status {
+	case entity.StatusPaga:
+		return nil, entity.ErrFaturaJaPaga
+	case entity.StatusCancelada:
+		return nil, entity.ErrPagarFaturaCancelada
+	}
+
+	vencimento := fatura.DataVencimento
+	if diaAnterior(vencimento, agora) {
+		vencimento = agora
+	}
+
+	devido, err := fatura.ValorDevido(vencimento, config.Encargos)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewBoleto(config.Boleto, fatura, devido.Saldo, vencimento)
+}
+
+// diaAnterior compara só as datas: a fatura que vence hoje ainda não está atrasada
+func diaAnterior(data, referencia time.Time) bool {
+	data = data.In(referencia.Location())
+	ad, am, add := data.Date()
+	rd, rm, rdd := referencia.Date()
+	return time.Date(ad, am, add, 0, 0, 0, 0, time.UTC).Before(time.Date(rd, rm, rdd, 0, 0, 0, 0, time.UTC))
+}
@@ -0,0 +1,78 @@
+package boleto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func setup(t *testing.T, boleto entity.ConfigBoleto, encargos entity.EncargosAtraso) (*memory.Store, *entity.Fatura) {
+	t.Helper()
+
+	store := memory.NewStore()
+
+	config, _ := entity.NewConfiguracao("user1")
+	config.Boleto = boleto
+	config.Encargos = encargos
+	store.Configuracoes.Save(config)
+
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "")
+	client.UsuarioID = "user1"
+	store.Clientes.Save(client)
+
+	f, err := entity.NewFatura(client.ID, "FAT-2026-000042", entity.BRL(15050), time.Now().AddDate(0, 0, 5), "Consultoria")
+	assert.NoError(t, err)
+	store.Faturas.Save(f)
+
+	return store, f
+}
+
+func TestEmitir(t *testing.T) {
+	configBoleto := entity.ConfigBoleto{Banco: "001", Convenio: "1234567", Carteira: "17"}
+
+	t.Run("should charge the balance on the fatura due date", func(t *testing.T) {
+		store, f := setup(t, configBoleto, entity.EncargosAtraso{})
+		_, err := f.RegistrarPagamento(entity.BRL(5050), entity.MetodoPix, time.Time{}, "", entity.EncargosAtraso{})
+		assert.NoError(t, err)
+
+		b, err := Emitir(store.Repositorios(), f, time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, entity.BRL(10000), b.Valor)
+		assert.True(t, f.DataVencimento.Equal(b.Vencimento))
+		assert.Equal(t, "0000010000", b.CodigoBarras[9:19])
+		assert.Equal(t, "12345672026000042", b.NossoNumero)
+	})
+
+	t.Run("should reissue an overdue boleto for today with the late charges", func(t *testing.T) {
+		store, f := setup(t, configBoleto, entity.EncargosAtraso{Multa: 200})
+		f.DataVencimento = time.Now().AddDate(0, 0, -10)
+
+		agora := time.Now()
+		b, err := Emitir(store.Repositorios(), f, agora)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.BRL(15351), b.Valor) // 150,50 + 2% de multa
+		assert.True(t, agora.Equal(b.Vencimento))
+
+		fator, _ := entity.FatorVencimento(agora)
+		assert.Equal(t, fator, b.FatorVencimento)
+	})
+
+	t.Run("should fail when the tenant has no boleto account", func(t *testing.T) {
+		store, f := setup(t, entity.ConfigBoleto{}, entity.EncargosAtraso{})
+
+		_, err := Emitir(store.Repositorios(), f, time.Now())
+		assert.Equal(t, entity.ErrBoletoNaoConfigurado, err)
+	})
+
+	t.Run("should refuse paid faturas", func(t *testing.T) {
+		store, f := setup(t, configBoleto, entity.EncargosAtraso{})
+		f.MarcarComoPaga(entity.EncargosAtraso{})
+
+		_, err := Emitir(store.Repositorios(), f, time.Now())
+		assert.Equal(t, entity.ErrFaturaJaPaga, err)
+	})
+}
@@ -0,0 +1,134 @@
+// Package confirmacao enfileira a mensagem que confirma ao cliente o pagamento da fatura.
+//
+// Enfileirar roda na transação que registrou o pagamento (baixa manual ou pagamento informado
+// pela API): a confirmação existe se e somente se o pagamento for gravado, e nunca sai duas
+// vezes para o mesmo pagamento. Um pagamento cuja confirmação não pode ser enviada (template
+// inválido, cliente sem WhatsApp) não é recusado: a mensagem é gravada direto na DLQ.
+package confirmacao
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/usecase/template"
+)
+
+// Comprovante usado quando o tenant não configurou TemplateConfirmacao
+const textoPadrao = "Ola, %s! Confirmamos o pagamento da fatura %s.\n\n" +
+	"Valor da fatura: %s\n" +
+	"Valor pago: %s\n" +
+	"Forma de pagamento: %s\n" +
+	"Data do pagamento: %s\n\n" +
+	"Obrigado!"
+
+// Enfileirar gera a confirmação do pagamento que liquidou a fatura, se ela acabou de ser paga.
+// Retorna false sem erro quando não há o que enviar: fatura não paga, pagamento já confirmado,
+// cliente inativo ou tenant com envio automático desligado. Tenants sem configuração recebem
+// o comprovante padrão. O horário de envio não é respeitado: a confirmação responde a uma
+// ação do cliente e sai assim que o worker a pegar.
+// Os erros retornados são só os dos repositórios, que devem desfazer a transação inteira.
+func Enfileirar(repos repository.Repositorios, fatura *entity.Fatura, agora time.Time) (bool, error) {
+	pagamento := fatura.PagamentoQueLiquidou()
+	if pagamento == nil {
+		return false, nil
+	}
+
+	cliente, err := repos.Clientes.FindByID(fatura.ClienteID)
+	if err != nil {
+		return false, err
+	}
+	if cliente == nil || !cliente.Ativo {
+		return false, nil
+	}
+
+	var config *entity.Configuracao
+	if cliente.UsuarioID != "" {
+		config, err = repos.Configuracoes.FindByUsuarioID(cliente.UsuarioID)
+		if err != nil {
+			return false, err
+		}
+	}
+	if config == nil {
+		config = &entity.Configuracao{EnvioAutomaticoAtivo: true}
+	}
+	if !config.EnvioAutomaticoAtivo {
+		return false, nil
+	}
+
+	msg := novaMensagem(config, cliente, fatura, agora)
+
+	// A chave no pagamento é gravada antes da mensagem: se ele já foi confirmado,
+	// nada é enfileirado e a transação do pagamento segue normalmente
+	registrada, err := repos.Confirmacoes.Registrar(&entity.ConfirmacaoPagamento{
+		PagamentoID: pagamento.ID,
+		FaturaID:    fatura.ID,
+		MensagemID:  msg.ID,
+		EnviadaEm:   agora,
+	})
+	if err != nil {
+		return false, err
+	}
+	if !registrada {
+		return false, nil
+	}
+
+	if err := repos.Mensagens.Save(msg); err != nil {
+		return false, err
+	}
+
+	eventos := make([]*entity.Event, 0, 2)
+	event, err := entity.NewMensagemEnfileiradaEvent(msg)
+	if err != nil {
+		return false, fmt.Errorf("erro ao serializar evento: %w", err)
+	}
+	eventos = append(eventos, event)
+	if msg.Status == entity.StatusMensagemDLQ {
+		event, err := entity.NewMensagemEnvioEvent(entity.EventMensagemMovidaParaDLQ, msg)
+		if err != nil {
+			return false, fmt.Errorf("erro ao serializar evento: %w", err)
+		}
+		eventos = append(eventos, event)
+	}
+	if err := repos.Eventos.Append(msg.ID, 0, eventos...); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// novaMensagem monta a confirmação. Se o template do tenant não renderiza, usa o comprovante
+// padrão; se ainda assim não há mensagem enviável, ela nasce na DLQ com o motivo.
+func novaMensagem(config *entity.Configuracao, cliente *entity.Cliente, fatura *entity.Fatura, agora time.Time) *entity.Mensagem {
+	conteudo, err := montarConteudo(config, cliente, fatura, agora)
+	if err != nil && config.TemplateConfirmacao != "" {
+		padrao := *config
+		padrao.TemplateConfirmacao = ""
+		conteudo, err = montarConteudo(&padrao, cliente, fatura, agora)
+	}
+	if err != nil {
+		return entity.NewMensagemNaDLQ(fatura.ID, cliente.ID, cliente.WhatsApp, "", entity.TipoMensagemConfirmacao, err.Error())
+	}
+
+	msg, err := entity.NewMensagem(fatura.ID, cliente.ID, cliente.WhatsApp, conteudo, entity.TipoMensagemConfirmacao)
+	if err != nil {
+		return entity.NewMensagemNaDLQ(fatura.ID, cliente.ID, cliente.WhatsApp, conteudo, entity.TipoMensagemConfirmacao, err.Error())
+	}
+	return msg
+}
+
+// montarConteudo renderiza o template do tenant se existir (variáveis em usecase/template)
+// ou o comprovante padrão, com o valor, a forma e a data do pagamento
+func montarConteudo(config *entity.Configuracao, cliente *entity.Cliente, fatura *entity.Fatura, agora time.Time) (string, error) {
+	if config.TemplateConfirmacao != "" {
+		return template.Montar(config.TemplateConfirmacao, config, cliente, fatura, agora, "")
+	}
+
+	dados, err := template.DadosDaFatura(config, cliente, fatura, agora, "")
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(textoPadrao, dados.Cliente, dados.Numero, dados.Valor, dados.ValorPago, dados.FormaPagamento, dados.DataPagamento), nil
+}
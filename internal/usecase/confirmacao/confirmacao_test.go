@@ -0,0 +1,146 @@
+package confirmacao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func setup(t *testing.T) (*memory.Store, *entity.Configuracao, *entity.Cliente, *entity.Fatura) {
+	t.Helper()
+
+	store := memory.NewStore()
+
+	config, _ := entity.NewConfiguracao("user1")
+	store.Configuracoes.Save(config)
+
+	client, _ := entity.NewCliente("Maria Silva", "5511999998888", "")
+	client.UsuarioID = "user1"
+	store.Clientes.Save(client)
+
+	f, _ := entity.NewFatura(client.ID, "FAT-2026-000001", entity.BRL(123456), time.Now().AddDate(0, 0, 5), "Consultoria")
+	store.Faturas.Save(f)
+
+	return store, config, client, f
+}
+
+func pagar(t *testing.T, f *entity.Fatura, metodo entity.MetodoPagamento) {
+	t.Helper()
+	_, err := f.RegistrarPagamento(entity.BRL(123456), metodo, time.Time{}, "", entity.EncargosAtraso{})
+	assert.NoError(t, err)
+}
+
+func TestEnfileirar(t *testing.T) {
+	t.Run("should send the receipt once per payment", func(t *testing.T) {
+		store, _, client, f := setup(t)
+		pagar(t, f, entity.MetodoPix)
+
+		ok, err := Enfileirar(store.Repositorios(), f, time.Now())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = Enfileirar(store.Repositorios(), f, time.Now())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		msgs := store.Mensagens.All()
+		assert.Len(t, msgs, 1)
+		assert.Equal(t, entity.TipoMensagemConfirmacao, msgs[0].Tipo)
+		assert.Equal(t, client.WhatsApp, msgs[0].WhatsApp)
+		assert.Contains(t, msgs[0].Conteudo, "Ola, Maria Silva! Confirmamos o pagamento da fatura FAT-2026-000001.")
+		assert.Contains(t, msgs[0].Conteudo, "Valor pago: R$ 1.234,56")
+		assert.Contains(t, msgs[0].Conteudo, "Forma de pagamento: Pix")
+		assert.Contains(t, msgs[0].Conteudo, "Data do pagamento: "+time.Now().Format("02/01/2006"))
+
+		assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemEnfileirada), 1)
+
+		registradas, _ := store.Confirmacoes.FindByFaturaID(f.ID)
+		assert.Len(t, registradas, 1)
+		assert.Equal(t, f.Pagamentos[0].ID, registradas[0].PagamentoID)
+		assert.Equal(t, msgs[0].ID, registradas[0].MensagemID)
+	})
+
+	t.Run("should render the tenant template", func(t *testing.T) {
+		store, config, _, f := setup(t)
+		config.TemplateConfirmacao = "{{cliente}}, recebemos {{valor_pago}} via {{forma_pagamento}} em {{data_pagamento}}."
+		store.Configuracoes.Update(config)
+		pagar(t, f, entity.MetodoBoleto)
+
+		ok, err := Enfileirar(store.Repositorios(), f, time.Now())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "Maria Silva, recebemos R$ 1.234,56 via boleto em "+time.Now().Format("02/01/2006")+".", store.Mensagens.All()[0].Conteudo)
+	})
+
+	t.Run("should skip unpaid faturas", func(t *testing.T) {
+		store, _, _, f := setup(t)
+
+		ok, err := Enfileirar(store.Repositorios(), f, time.Now())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.Empty(t, store.Mensagens.All())
+	})
+
+	t.Run("should skip inactive clientes and tenants with sending disabled", func(t *testing.T) {
+		store, config, client, f := setup(t)
+		pagar(t, f, entity.MetodoPix)
+
+		config.EnvioAutomaticoAtivo = false
+		store.Configuracoes.Update(config)
+		ok, err := Enfileirar(store.Repositorios(), f, time.Now())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		config.EnvioAutomaticoAtivo = true
+		store.Configuracoes.Update(config)
+		client.Ativo = false
+		store.Clientes.Update(client)
+		ok, err = Enfileirar(store.Repositorios(), f, time.Now())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		assert.Empty(t, store.Mensagens.All())
+	})
+
+	t.Run("should fall back to the receipt when the tenant template does not render", func(t *testing.T) {
+		store, config, _, f := setup(t)
+		config.TemplateConfirmacao = "Recebemos {{valor_pago}"
+		store.Configuracoes.Update(config)
+		pagar(t, f, entity.MetodoPix)
+
+		ok, err := Enfileirar(store.Repositorios(), f, time.Now())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		msgs := store.Mensagens.All()
+		assert.Len(t, msgs, 1)
+		assert.Equal(t, entity.StatusMensagemPendente, msgs[0].Status)
+		assert.Contains(t, msgs[0].Conteudo, "Confirmamos o pagamento da fatura FAT-2026-000001.")
+	})
+
+	t.Run("should record an unsendable confirmation in the DLQ", func(t *testing.T) {
+		store, _, client, f := setup(t)
+		client.WhatsApp = ""
+		store.Clientes.Update(client)
+		pagar(t, f, entity.MetodoPix)
+
+		ok, err := Enfileirar(store.Repositorios(), f, time.Now())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		msgs := store.Mensagens.All()
+		assert.Len(t, msgs, 1)
+		assert.Equal(t, entity.StatusMensagemDLQ, msgs[0].Status)
+		assert.Equal(t, entity.ErrWhatsAppVazio.Error(), msgs[0].ErroMensagem)
+		assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemMovidaParaDLQ), 1)
+
+		// A chave do pagamento também foi gravada: não há segunda tentativa
+		ok, err = Enfileirar(store.Repositorios(), f, time.Now())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,28 @@
+package encargos
+
+import (
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+// DoCliente retorna as regras de multa e juros do tenant dono do cliente.
+// Clientes sem tenant, ou tenants sem configuração, não pagam encargos.
+func DoCliente(repos repository.Repositorios, clienteID string) (entity.EncargosAtraso, error) {
+	cliente, err := repos.Clientes.FindByID(clienteID)
+	if err != nil {
+		return entity.EncargosAtraso{}, err
+	}
+	if cliente == nil || cliente.UsuarioID == "" {
+		return entity.EncargosAtraso{}, nil
+	}
+
+	config, err := repos.Configuracoes.FindByUsuarioID(cliente.UsuarioID)
+	if err != nil {
+		return entity.EncargosAtraso{}, err
+	}
+	if config == nil {
+		return entity.EncargosAtraso{}, nil
+	}
+
+	return config.Encargos, nil
+}
@@ -0,0 +1,58 @@
+package encargos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func TestDoCliente(t *testing.T) {
+	store := memory.NewStore()
+
+	regras := entity.EncargosAtraso{Multa: 200, JurosAoMes: 100}
+	config, _ := entity.NewConfiguracao("user1")
+	config.Encargos = regras
+	store.Configuracoes.Save(config)
+
+	novoCliente := func(whatsapp, usuarioID string) string {
+		c, _ := entity.NewCliente("Cliente", whatsapp, "")
+		c.UsuarioID = usuarioID
+		store.Clientes.Save(c)
+		return c.ID
+	}
+
+	casos := []struct {
+		nome      string
+		clienteID string
+		esperado  entity.EncargosAtraso
+	}{
+		{
+			nome:      "tenant com encargos",
+			clienteID: novoCliente("5511999990001", "user1"),
+			esperado:  regras,
+		},
+		{
+			nome:      "tenant sem configuracao",
+			clienteID: novoCliente("5511999990002", "user2"),
+		},
+		{
+			nome:      "cliente sem tenant",
+			clienteID: novoCliente("5511999990003", ""),
+		},
+		{
+			nome:      "cliente inexistente",
+			clienteID: "nao-existe",
+		},
+	}
+
+	for _, tc := range casos {
+		t.Run(tc.nome, func(t *testing.T) {
+			encargos, err := DoCliente(store.Repositorios(), tc.clienteID)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.esperado, encargos)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Package entrega aplica às mensagens os status que o provedor de WhatsApp informa
+// depois do envio: entregue no aparelho do cliente e lida.
+package entrega
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var ErrStatusNaoSuportado = errors.New("status de entrega deve ser entregue ou lida")
+
+// Atualizacao é um status informado pelo provedor para uma mensagem enviada
+type Atualizacao struct {
+	IDProvedor string                // ID devolvido pelo provedor no envio
+	Status     entity.StatusMensagem // StatusMensagemEntregue ou StatusMensagemLida
+	Em         time.Time             // Horário do status no provedor; zero = agora
+}
+
+// Resultado de uma atualização
+type Resultado int
+
+const (
+	ResultadoAplicado     Resultado = iota
+	ResultadoDesconhecida           // Nenhuma mensagem com o ID do provedor: o status fica guardado (ver AplicarPendentes)
+	ResultadoIgnorado               // Status repetido ou fora de ordem: a mensagem já estava nele ou além
+)
+
+// Por quanto tempo um status de mensagem desconhecida fica guardado. O webhook só chega antes
+// da gravação do envio por alguns segundos; passado isso, o ID é de uma mensagem enviada
+// por fora do sistema.
+const retencaoPendentes = time.Hour
+
+type Service struct {
+	uow   repository.UnitOfWork
+	agora func() time.Time
+}
+
+func NewService(uow repository.UnitOfWork) *Service {
+	return &Service{uow: uow, agora: time.Now}
+}
+
+// Registrar aplica a atualização e grava o evento MensagemEntregue ou MensagemLida na mesma
+// transação. Callbacks repetidos ou atrasados não alteram nada, então o provedor pode
+// reenviá-los à vontade. O status de um ID ainda desconhecido é guardado: o webhook pode
+// chegar antes de o envio ser gravado, e a gravação o aplica.
+func (s *Service) Registrar(a Atualizacao) (Resultado, error) {
+	if a.Status != entity.StatusMensagemEntregue && a.Status != entity.StatusMensagemLida {
+		return ResultadoIgnorado, ErrStatusNaoSuportado
+	}
+	agora := s.agora()
+	if a.Em.IsZero() {
+		a.Em = agora
+	}
+
+	resultado := ResultadoAplicado
+	err := s.uow.Executar(func(repos repository.Repositorios) error {
+		if err := repos.EntregasPendentes.Travar(a.IDProvedor); err != nil {
+			return err
+		}
+
+		// Trava a mensagem: dois callbacks simultâneos não geram dois eventos
+		msg, err := repos.Mensagens.FindByIDProvedor(a.IDProvedor)
+		if err != nil {
+			return err
+		}
+		if msg == nil {
+			resultado = ResultadoDesconhecida
+			if _, err := repos.EntregasPendentes.ExpurgarAntesDe(agora.Add(-retencaoPendentes)); err != nil {
+				return err
+			}
+			return repos.EntregasPendentes.Guardar(&entity.EntregaPendente{
+				IDProvedor: a.IDProvedor,
+				Status:     a.Status,
+				Em:         a.Em,
+				RecebidoEm: agora,
+			})
+		}
+
+		aplicado, err := aplicar(repos, msg, a.Status, a.Em)
+		if err != nil {
+			return err
+		}
+		if !aplicado {
+			resultado = ResultadoIgnorado
+		}
+		return nil
+	})
+	if err != nil {
+		return ResultadoIgnorado, err
+	}
+
+	return resultado, nil
+}
+
+// AplicarPendentes aplica à mensagem os status guardados para o IDProvedor dela.
+// Roda na transação que grava o envio, depois de EntregasPendentes.Travar.
+func AplicarPendentes(repos repository.Repositorios, msg *entity.Mensagem) error {
+	pendentes, err := repos.EntregasPendentes.Retirar(msg.IDProvedor)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pendentes {
+		if _, err := aplicar(repos, msg, p.Status, p.Em); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aplicar avança a mensagem para o status e grava o evento.
+// Retorna false se a mensagem já estava nele ou além.
+func aplicar(repos repository.Repositorios, msg *entity.Mensagem, status entity.StatusMensagem, em time.Time) (bool, error) {
+	var aplicado bool
+	if status == entity.StatusMensagemLida {
+		aplicado = msg.MarcarComoLida(em)
+	} else {
+		aplicado = msg.MarcarComoEntregue(em)
+	}
+	if !aplicado {
+		return false, nil
+	}
+
+	evento, err := entity.NewMensagemEntregaEvent(msg)
+	if err != nil {
+		return false, fmt.Errorf("erro ao serializar evento: %w", err)
+	}
+	if err := repos.Mensagens.Update(msg); err != nil {
+		return false, err
+	}
+	return true, repos.Eventos.Append(msg.ID, repository.ExpectedVersionAny, evento)
+}
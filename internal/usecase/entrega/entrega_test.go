@@ -0,0 +1,131 @@
+package entrega
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func setup(t *testing.T) (*Service, *memory.Store, *entity.Mensagem) {
+	t.Helper()
+
+	store := memory.NewStore()
+	msg, _ := entity.NewMensagem("fat-1", "cli-1", "5511999998888", "Ola", entity.TipoMensagemLembrete)
+	msg.MarcarComoEnviada("3EB0C767D26A")
+	store.Mensagens.Save(msg)
+
+	return NewService(store.UnitOfWork()), store, msg
+}
+
+func TestService_Registrar(t *testing.T) {
+	entregueEm := time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+	lidaEm := entregueEm.Add(time.Hour)
+
+	t.Run("should advance to delivered and read with the provider timestamps", func(t *testing.T) {
+		s, store, msg := setup(t)
+
+		r, err := s.Registrar(Atualizacao{IDProvedor: "3EB0C767D26A", Status: entity.StatusMensagemEntregue, Em: entregueEm})
+		assert.NoError(t, err)
+		assert.Equal(t, ResultadoAplicado, r)
+
+		r, err = s.Registrar(Atualizacao{IDProvedor: "3EB0C767D26A", Status: entity.StatusMensagemLida, Em: lidaEm})
+		assert.NoError(t, err)
+		assert.Equal(t, ResultadoAplicado, r)
+
+		found, _ := store.Mensagens.FindByID(msg.ID)
+		assert.Equal(t, entity.StatusMensagemLida, found.Status)
+		assert.Equal(t, entregueEm, *found.EntregueEm)
+		assert.Equal(t, lidaEm, *found.LidaEm)
+
+		assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemEntregue), 1)
+		lidas := store.Eventos.PorTipo(entity.EventMensagemLida)
+		assert.Len(t, lidas, 1)
+		assert.Contains(t, string(lidas[0].EventData), `"id_provedor":"3EB0C767D26A"`)
+	})
+
+	t.Run("should ignore replayed and out of order callbacks", func(t *testing.T) {
+		s, store, msg := setup(t)
+
+		r, _ := s.Registrar(Atualizacao{IDProvedor: "3EB0C767D26A", Status: entity.StatusMensagemLida, Em: lidaEm})
+		assert.Equal(t, ResultadoAplicado, r)
+
+		r, err := s.Registrar(Atualizacao{IDProvedor: "3EB0C767D26A", Status: entity.StatusMensagemLida, Em: lidaEm})
+		assert.NoError(t, err)
+		assert.Equal(t, ResultadoIgnorado, r)
+
+		r, err = s.Registrar(Atualizacao{IDProvedor: "3EB0C767D26A", Status: entity.StatusMensagemEntregue, Em: entregueEm})
+		assert.NoError(t, err)
+		assert.Equal(t, ResultadoIgnorado, r)
+
+		found, _ := store.Mensagens.FindByID(msg.ID)
+		assert.Equal(t, entity.StatusMensagemLida, found.Status)
+		assert.Equal(t, lidaEm, *found.EntregueEm)
+		assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemLida), 1)
+		assert.Empty(t, store.Eventos.PorTipo(entity.EventMensagemEntregue))
+	})
+
+	t.Run("should keep the status of unknown provider IDs without changes", func(t *testing.T) {
+		s, store, _ := setup(t)
+
+		r, err := s.Registrar(Atualizacao{IDProvedor: "OUTRA", Status: entity.StatusMensagemEntregue})
+		assert.NoError(t, err)
+		assert.Equal(t, ResultadoDesconhecida, r)
+		assert.Empty(t, store.Eventos.PorTipo(entity.EventMensagemEntregue))
+
+		guardadas, _ := store.EntregasPendentes.Retirar("OUTRA")
+		assert.Len(t, guardadas, 1)
+	})
+
+	t.Run("should apply statuses received before the send was saved", func(t *testing.T) {
+		s, store, _ := setup(t)
+
+		// Os callbacks chegam antes de o envio gravar o ID do provedor
+		s.Registrar(Atualizacao{IDProvedor: "NOVA", Status: entity.StatusMensagemLida, Em: lidaEm})
+		s.Registrar(Atualizacao{IDProvedor: "NOVA", Status: entity.StatusMensagemEntregue, Em: entregueEm})
+
+		msg, _ := entity.NewMensagem("fat-1", "cli-1", "5511999998888", "Ola", entity.TipoMensagemLembrete)
+		store.Mensagens.Save(msg)
+		msg.MarcarComoEnviada("NOVA")
+		store.Mensagens.Update(msg)
+
+		assert.NoError(t, AplicarPendentes(store.Repositorios(), msg))
+
+		found, _ := store.Mensagens.FindByID(msg.ID)
+		assert.Equal(t, entity.StatusMensagemLida, found.Status)
+		assert.Equal(t, entregueEm, *found.EntregueEm)
+		assert.Equal(t, lidaEm, *found.LidaEm)
+		assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemEntregue), 1)
+		assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemLida), 1)
+
+		// Retirados na aplicação, não são aplicados de novo
+		guardadas, _ := store.EntregasPendentes.Retirar("NOVA")
+		assert.Empty(t, guardadas)
+	})
+
+	t.Run("should discard old statuses of unknown provider IDs", func(t *testing.T) {
+		s, store, _ := setup(t)
+
+		agora := time.Now()
+		s.agora = func() time.Time { return agora.Add(-2 * retencaoPendentes) }
+		s.Registrar(Atualizacao{IDProvedor: "ANTIGA", Status: entity.StatusMensagemEntregue})
+
+		s.agora = func() time.Time { return agora }
+		s.Registrar(Atualizacao{IDProvedor: "OUTRA", Status: entity.StatusMensagemEntregue})
+
+		antigas, _ := store.EntregasPendentes.Retirar("ANTIGA")
+		assert.Empty(t, antigas)
+		guardadas, _ := store.EntregasPendentes.Retirar("OUTRA")
+		assert.Len(t, guardadas, 1)
+	})
+
+	t.Run("should reject other statuses", func(t *testing.T) {
+		s, _, _ := setup(t)
+
+		_, err := s.Registrar(Atualizacao{IDProvedor: "3EB0C767D26A", Status: entity.StatusMensagemFalha})
+		assert.ErrorIs(t, err, ErrStatusNaoSuportado)
+	})
+}
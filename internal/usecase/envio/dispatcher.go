@@ -0,0 +1,98 @@
+package envio
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/gateway"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+// Dispatcher envia as mensagens pendentes do banco pelo Mensageiro, com retentativa e DLQ
+type Dispatcher struct {
+	*Processador
+}
+
+func NewDispatcher(mensagens repository.MensagemRepository, uow repository.UnitOfWork, mensageiro gateway.Mensageiro, logger *zap.Logger, opcoes Opcoes) *Dispatcher {
+	return &Dispatcher{Processador: NewProcessador(mensagens, uow, mensageiro, logger, opcoes)}
+}
+
+// Run processa lotes até o contexto ser cancelado.
+// Quando um lote vem cheio, busca o próximo imediatamente; caso contrário espera o intervalo de poll.
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.logger.Info("Dispatcher de mensagens iniciado", zap.Int("workers", d.opcoes.Workers))
+
+	for {
+		n, err := d.ProcessarLote(ctx)
+		if err != nil {
+			d.logger.Error("Falha ao processar lote de mensagens", zap.Error(err))
+		}
+
+		espera := d.opcoes.IntervaloPoll
+		if err == nil && n == d.opcoes.TamanhoLote {
+			espera = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			d.logger.Info("Dispatcher de mensagens encerrado")
+			return
+		case <-time.After(espera):
+		}
+	}
+}
+
+// ProcessarLote reivindica um lote e o envia usando o pool de workers.
+// Retorna quantas mensagens foram reivindicadas.
+func (d *Dispatcher) ProcessarLote(ctx context.Context) (int, error) {
+	if err := d.moverEsgotadasParaDLQ(); err != nil {
+		return 0, err
+	}
+
+	msgs, err := d.mensagens.ReivindicarParaEnvio(d.agora(), d.opcoes.Lease, d.opcoes.TamanhoLote)
+	if err != nil {
+		return 0, err
+	}
+
+	fila := make(chan *entity.Mensagem)
+	var wg sync.WaitGroup
+
+	for i := 0; i < d.opcoes.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for msg := range fila {
+				d.Tentar(ctx, msg)
+			}
+		}()
+	}
+
+	for _, msg := range msgs {
+		fila <- msg
+	}
+	close(fila)
+	wg.Wait()
+
+	return len(msgs), nil
+}
+
+// moverEsgotadasParaDLQ fecha mensagens com falha que já passaram do limite de tentativas
+func (d *Dispatcher) moverEsgotadasParaDLQ() error {
+	esgotadas, err := d.mensagens.FindParaDLQ()
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range esgotadas {
+		msg.MoverParaDLQ()
+		if err := d.gravar(msg, entity.EventMensagemMovidaParaDLQ); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
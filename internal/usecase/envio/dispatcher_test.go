@@ -0,0 +1,196 @@
+package envio
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/evolution"
+	"github.com/teusf/billing-system/internal/infrastructure/evolution/evolutiontest"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+	"github.com/teusf/billing-system/internal/usecase/entrega"
+)
+
+func setup(t *testing.T) (*Dispatcher, *memory.Store, *evolutiontest.Server, *time.Time) {
+	t.Helper()
+
+	srv := evolutiontest.NewServer("chave", "instance1")
+	t.Cleanup(srv.Close)
+
+	store := memory.NewStore()
+	d := NewDispatcher(store.Mensagens, store.UnitOfWork(), evolution.NewClient(srv.URL, "chave", "instance1"), zap.NewNop(), Opcoes{
+		Workers:     2,
+		BackoffBase: time.Minute,
+		BackoffMax:  10 * time.Minute,
+	})
+
+	agora := time.Now()
+	d.agora = func() time.Time { return agora }
+
+	return d, store, srv, &agora
+}
+
+func novaMensagem(t *testing.T, store *memory.Store) *entity.Mensagem {
+	t.Helper()
+	msg, err := entity.NewMensagem("fat-1", "cli-1", "5511999998888", "Ola", entity.TipoMensagemLembrete)
+	assert.NoError(t, err)
+	store.Mensagens.Save(msg)
+	return msg
+}
+
+func TestDispatcher_EnviaPendentes(t *testing.T) {
+	d, store, srv, _ := setup(t)
+
+	for i := 0; i < 5; i++ {
+		novaMensagem(t, store)
+	}
+
+	n, err := d.ProcessarLote(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Len(t, srv.Recebidas(), 5)
+
+	enviadas, _ := store.Mensagens.FindByStatus(entity.StatusMensagemEnviada)
+	assert.Len(t, enviadas, 5)
+
+	// Um MensagemEnviada por mensagem, para as projeções
+	assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemEnviada), 5)
+
+	// Nada mais a enviar
+	n, err = d.ProcessarLote(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestDispatcher_RetentativaComBackoff(t *testing.T) {
+	d, store, srv, agora := setup(t)
+	msg := novaMensagem(t, store)
+
+	srv.FalharCom(evolutiontest.Resposta{Status: http.StatusServiceUnavailable, Body: `{}`})
+
+	d.ProcessarLote(context.Background())
+
+	found, _ := store.Mensagens.FindByID(msg.ID)
+	assert.Equal(t, entity.StatusMensagemFalha, found.Status)
+	assert.Equal(t, 1, found.TentativasEnvio)
+	assert.Equal(t, "evolution api indisponivel", found.ErroMensagem)
+	assert.Equal(t, agora.Add(time.Minute), *found.ProximaTentativaEm)
+
+	// Antes do backoff expirar não é reivindicada
+	n, _ := d.ProcessarLote(context.Background())
+	assert.Equal(t, 0, n)
+
+	// Depois do backoff é reenviada com sucesso
+	*agora = agora.Add(time.Minute)
+	n, _ = d.ProcessarLote(context.Background())
+	assert.Equal(t, 1, n)
+
+	found, _ = store.Mensagens.FindByID(msg.ID)
+	assert.Equal(t, entity.StatusMensagemEnviada, found.Status)
+	assert.Equal(t, 2, found.TentativasEnvio)
+	assert.Equal(t, srv.Recebidas()[0].ID, found.IDProvedor)
+}
+
+func TestDispatcher_DLQ(t *testing.T) {
+	t.Run("should move to DLQ after exhausting attempts", func(t *testing.T) {
+		d, store, srv, agora := setup(t)
+		msg := novaMensagem(t, store)
+
+		for i := 0; i < entity.MaxTentativasEnvio; i++ {
+			srv.FalharCom(evolutiontest.Resposta{Status: http.StatusInternalServerError, Body: `{}`})
+			d.ProcessarLote(context.Background())
+			*agora = agora.Add(time.Hour)
+		}
+
+		found, _ := store.Mensagens.FindByID(msg.ID)
+		assert.Equal(t, entity.StatusMensagemDLQ, found.Status)
+		assert.Equal(t, entity.MaxTentativasEnvio, found.TentativasEnvio)
+		assert.Empty(t, srv.Recebidas())
+
+		assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemFalhou), entity.MaxTentativasEnvio-1)
+		dlq := store.Eventos.PorTipo(entity.EventMensagemMovidaParaDLQ)
+		assert.Len(t, dlq, 1)
+		assert.Equal(t, msg.ID, dlq[0].AggregateID)
+	})
+
+	t.Run("should move to DLQ on permanent failure", func(t *testing.T) {
+		d, store, srv, _ := setup(t)
+		msg := novaMensagem(t, store)
+
+		srv.FalharCom(evolutiontest.NumeroInexistente(msg.WhatsApp))
+		d.ProcessarLote(context.Background())
+
+		found, _ := store.Mensagens.FindByID(msg.ID)
+		assert.Equal(t, entity.StatusMensagemDLQ, found.Status)
+		assert.Equal(t, 1, found.TentativasEnvio)
+		assert.Equal(t, "numero nao possui whatsapp", found.ErroMensagem)
+	})
+
+	t.Run("should close exhausted failures left behind", func(t *testing.T) {
+		d, store, _, _ := setup(t)
+		msg := novaMensagem(t, store)
+		for i := 0; i < entity.MaxTentativasEnvio; i++ {
+			msg.MarcarComoFalha("timeout")
+		}
+		store.Mensagens.Update(msg)
+
+		d.ProcessarLote(context.Background())
+
+		found, _ := store.Mensagens.FindByID(msg.ID)
+		assert.Equal(t, entity.StatusMensagemDLQ, found.Status)
+	})
+}
+
+func TestDispatcher_LeaseEvitaEnvioDuplicado(t *testing.T) {
+	_, store, _, _ := setup(t)
+	novaMensagem(t, store)
+
+	agora := time.Now()
+
+	// Primeira réplica reivindica
+	lote1, _ := store.Mensagens.ReivindicarParaEnvio(agora, time.Minute, 10)
+	assert.Len(t, lote1, 1)
+
+	// Segunda réplica não vê a mesma mensagem enquanto o lease vale
+	lote2, _ := store.Mensagens.ReivindicarParaEnvio(agora, time.Minute, 10)
+	assert.Empty(t, lote2)
+
+	// Lease expirado (worker morreu): volta a ficar disponível
+	lote3, _ := store.Mensagens.ReivindicarParaEnvio(agora.Add(2*time.Minute), time.Minute, 10)
+	assert.Len(t, lote3, 1)
+}
+
+// mensageiroComCallback simula o provedor chamando o webhook de status antes de
+// responder ao envio, ou seja, antes de o envio ser gravado
+type mensageiroComCallback struct {
+	entregas *entrega.Service
+}
+
+func (m mensageiroComCallback) EnviarTexto(ctx context.Context, whatsapp, texto string) (string, error) {
+	m.entregas.Registrar(entrega.Atualizacao{IDProvedor: "3EB0C767D26A", Status: entity.StatusMensagemEntregue})
+	return "3EB0C767D26A", nil
+}
+
+func TestProcessador_StatusAntesDoCommitDoEnvio(t *testing.T) {
+	store := memory.NewStore()
+	uow := store.UnitOfWork()
+	p := NewProcessador(store.Mensagens, uow, mensageiroComCallback{entregas: entrega.NewService(uow)}, zap.NewNop(), Opcoes{})
+
+	msg := novaMensagem(t, store)
+	r, _, err := p.ProcessarPorID(context.Background(), msg.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultadoEnviada, r)
+
+	found, _ := store.Mensagens.FindByID(msg.ID)
+	assert.Equal(t, entity.StatusMensagemEntregue, found.Status)
+	assert.Equal(t, "3EB0C767D26A", found.IDProvedor)
+	assert.NotNil(t, found.EntregueEm)
+
+	assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemEnviada), 1)
+	assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemEntregue), 1)
+}
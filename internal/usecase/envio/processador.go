@@ -0,0 +1,171 @@
+package envio
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/gateway"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/usecase/entrega"
+)
+
+// Resultado de uma tentativa de envio
+type Resultado int
+
+const (
+	ResultadoEnviada  Resultado = iota
+	ResultadoRetentar           // Falha temporária, nova tentativa agendada em ProximaTentativaEm
+	ResultadoDLQ                // Falha definitiva ou tentativas esgotadas
+	ResultadoIgnorada           // Mensagem indisponível (já enviada, na DLQ ou reservada por outro worker)
+)
+
+// Opcoes controla o envio. Valores zerados usam os defaults.
+type Opcoes struct {
+	Workers       int           // Envios simultâneos (Dispatcher)
+	TamanhoLote   int           // Mensagens reivindicadas por vez (Dispatcher)
+	IntervaloPoll time.Duration // Espera quando não há mensagens prontas (Dispatcher)
+	Lease         time.Duration // Tempo de reserva de uma mensagem em envio
+	BackoffBase   time.Duration // Espera após a 1ª falha (dobra a cada falha)
+	BackoffMax    time.Duration
+	TimeoutEnvio  time.Duration // Timeout de cada chamada ao provedor
+}
+
+func (o Opcoes) comDefaults() Opcoes {
+	if o.Workers <= 0 {
+		o.Workers = 4
+	}
+	if o.TamanhoLote <= 0 {
+		o.TamanhoLote = o.Workers * 5
+	}
+	if o.IntervaloPoll <= 0 {
+		o.IntervaloPoll = 5 * time.Second
+	}
+	if o.Lease <= 0 {
+		o.Lease = 2 * time.Minute
+	}
+	if o.BackoffBase <= 0 {
+		o.BackoffBase = 30 * time.Second
+	}
+	if o.BackoffMax <= 0 {
+		o.BackoffMax = 30 * time.Minute
+	}
+	if o.TimeoutEnvio <= 0 {
+		o.TimeoutEnvio = 20 * time.Second
+	}
+	return o
+}
+
+// Processador faz tentativas de envio de mensagens já reservadas.
+// É compartilhado pelo Dispatcher (polling no banco) e pelo consumidor da fila.
+type Processador struct {
+	mensagens  repository.MensagemRepository
+	uow        repository.UnitOfWork
+	mensageiro gateway.Mensageiro
+	logger     *zap.Logger
+	opcoes     Opcoes
+	agora      func() time.Time
+}
+
+func NewProcessador(mensagens repository.MensagemRepository, uow repository.UnitOfWork, mensageiro gateway.Mensageiro, logger *zap.Logger, opcoes Opcoes) *Processador {
+	return &Processador{
+		mensagens:  mensagens,
+		uow:        uow,
+		mensageiro: mensageiro,
+		logger:     logger,
+		opcoes:     opcoes.comDefaults(),
+		agora:      time.Now,
+	}
+}
+
+// ProcessarPorID reserva a mensagem e faz uma tentativa de envio.
+// Se a mensagem não puder ser reservada, retorna ResultadoIgnorada junto com o estado atual (ou nil).
+func (p *Processador) ProcessarPorID(ctx context.Context, id string) (Resultado, *entity.Mensagem, error) {
+	msg, err := p.mensagens.ReivindicarPorID(id, p.agora(), p.opcoes.Lease)
+	if err != nil {
+		return ResultadoIgnorada, nil, err
+	}
+	if msg == nil {
+		atual, err := p.mensagens.FindByID(id)
+		return ResultadoIgnorada, atual, err
+	}
+
+	return p.Tentar(ctx, msg), msg, nil
+}
+
+// Tentar faz uma tentativa e grava o resultado junto com o evento correspondente
+// (MensagemEnviada, MensagemFalhou ou MensagemMovidaParaDLQ). Falhas definitivas ou
+// tentativas esgotadas vão para a DLQ; as demais são reagendadas com backoff exponencial.
+func (p *Processador) Tentar(ctx context.Context, msg *entity.Mensagem) Resultado {
+	envioCtx, cancel := context.WithTimeout(ctx, p.opcoes.TimeoutEnvio)
+	defer cancel()
+
+	resultado := ResultadoEnviada
+	evento := entity.EventMensagemEnviada
+
+	idProvedor, err := p.mensageiro.EnviarTexto(envioCtx, msg.WhatsApp, msg.Conteudo)
+	if err == nil {
+		msg.MarcarComoEnviada(idProvedor)
+		msg.ProximaTentativaEm = nil
+	} else {
+		msg.MarcarComoFalha(gateway.MotivoFalha(err))
+
+		if !gateway.EhRetentavel(err) || msg.DeveIrParaDLQ() {
+			resultado = ResultadoDLQ
+			evento = entity.EventMensagemMovidaParaDLQ
+			msg.MoverParaDLQ()
+			p.logger.Warn("Mensagem movida para DLQ",
+				zap.String("mensagem_id", msg.ID),
+				zap.Int("tentativas", msg.TentativasEnvio),
+				zap.Error(err),
+			)
+		} else {
+			resultado = ResultadoRetentar
+			evento = entity.EventMensagemFalhou
+			msg.AgendarNovaTentativa(p.agora(), p.opcoes.BackoffBase, p.opcoes.BackoffMax)
+			p.logger.Warn("Falha no envio, nova tentativa agendada",
+				zap.String("mensagem_id", msg.ID),
+				zap.Int("tentativas", msg.TentativasEnvio),
+				zap.Timep("proxima_tentativa_em", msg.ProximaTentativaEm),
+				zap.Error(err),
+			)
+		}
+	}
+
+	// Se a gravação falhar o lease expira e a mensagem é retentada
+	if err := p.gravar(msg, evento); err != nil {
+		p.logger.Error("Falha ao atualizar mensagem apos envio", zap.String("mensagem_id", msg.ID), zap.Error(err))
+	}
+
+	return resultado
+}
+
+// gravar atualiza a mensagem e registra o evento na mesma transação. No envio, aplica também
+// os status de entrega que o provedor informou antes deste commit.
+func (p *Processador) gravar(msg *entity.Mensagem, eventType string) error {
+	evento, err := entity.NewMensagemEnvioEvent(eventType, msg)
+	if err != nil {
+		return err
+	}
+
+	enviada := eventType == entity.EventMensagemEnviada
+	return p.uow.Executar(func(repos repository.Repositorios) error {
+		if enviada {
+			if err := repos.EntregasPendentes.Travar(msg.IDProvedor); err != nil {
+				return err
+			}
+		}
+		if err := repos.Mensagens.Update(msg); err != nil {
+			return err
+		}
+		if err := repos.Eventos.Append(msg.ID, repository.ExpectedVersionAny, evento); err != nil {
+			return err
+		}
+		if enviada {
+			return entrega.AplicarPendentes(repos, msg)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,141 @@
+package lembrete
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/usecase/pix"
+	"github.com/teusf/billing-system/internal/usecase/template"
+)
+
+// Texto usado quando o tenant não configurou TemplateLembrete
+const templateLembretePadrao = "Ola, %s! Lembrete: a fatura %s no valor de %s vence em %s."
+
+// Service varre as faturas pendentes de cada tenant e enfileira os lembretes devidos
+type Service struct {
+	repos      repository.Repositorios
+	uow        repository.UnitOfWork
+	logger     *zap.Logger
+	urlPublica string // Endereço externo da API, para o link do QR code do Pix (opcional)
+	agora      func() time.Time
+}
+
+func NewService(repos repository.Repositorios, uow repository.UnitOfWork, logger *zap.Logger, urlPublica string) *Service {
+	return &Service{repos: repos, uow: uow, logger: logger, urlPublica: urlPublica, agora: time.Now}
+}
+
+// Executar roda um ciclo do job e retorna quantos lembretes foram enfileirados.
+// Falhas em uma fatura são logadas e não interrompem as demais.
+func (s *Service) Executar() (int, error) {
+	configs, err := s.repos.Configuracoes.FindAll()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao carregar configuracoes: %w", err)
+	}
+
+	agora := s.agora()
+	enfileirados := 0
+
+	for _, config := range configs {
+		// Tenants com régua de cobrança recebem os lembretes pelas etapas dela (usecase/regua)
+		if len(config.Regua) > 0 || !config.EnvioAutomaticoAtivo || !config.EstaDentroHorarioEnvio(agora) {
+			continue
+		}
+
+		faturas, err := s.repos.Faturas.FindPendentesByUsuarioID(config.UsuarioID)
+		if err != nil {
+			return enfileirados, fmt.Errorf("erro ao buscar faturas do usuario %s: %w", config.UsuarioID, err)
+		}
+
+		for _, fatura := range faturas {
+			if !fatura.DeveEnviarLembrete(config.DiasAntesLembrete) {
+				continue
+			}
+
+			ok, err := s.enfileirar(config, fatura)
+			if err != nil {
+				s.logger.Error("Falha ao enfileirar lembrete",
+					zap.String("fatura_id", fatura.ID),
+					zap.Error(err),
+				)
+				continue
+			}
+			if ok {
+				enfileirados++
+			}
+		}
+	}
+
+	return enfileirados, nil
+}
+
+// enfileirar cria a mensagem e marca o lembrete na mesma transação,
+// para que uma fatura nunca fique marcada sem mensagem (ou vice-versa).
+// Clientes inativos são ignorados (retorna false sem erro).
+func (s *Service) enfileirar(config *entity.Configuracao, fatura *entity.Fatura) (bool, error) {
+	cliente, err := s.repos.Clientes.FindByID(fatura.ClienteID)
+	if err != nil {
+		return false, err
+	}
+	if cliente == nil || !cliente.Ativo {
+		return false, nil
+	}
+
+	conteudo, err := montarConteudo(config, cliente, fatura, s.agora(), s.urlPublica)
+	if err != nil && config.TemplateLembrete != "" {
+		s.logger.Warn("Template de lembrete invalido, usando o texto padrao",
+			zap.String("usuario_id", config.UsuarioID), zap.String("fatura_id", fatura.ID), zap.Error(err))
+		padrao := *config
+		padrao.TemplateLembrete = ""
+		conteudo, err = montarConteudo(&padrao, cliente, fatura, s.agora(), s.urlPublica)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	msg, err := entity.NewMensagem(fatura.ID, cliente.ID, cliente.WhatsApp, conteudo, entity.TipoMensagemLembrete)
+	if err != nil {
+		return false, err
+	}
+
+	err = s.uow.Executar(func(repos repository.Repositorios) error {
+		if err := fatura.MarcarLembreteEnviado(); err != nil {
+			return err
+		}
+		if err := repos.Faturas.Update(fatura); err != nil {
+			return err
+		}
+		if err := repos.Mensagens.Save(msg); err != nil {
+			return err
+		}
+
+		event, err := entity.NewMensagemEnfileiradaEvent(msg)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar evento: %w", err)
+		}
+		return repos.Eventos.Append(msg.ID, 0, event)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// montarConteudo renderiza o template do tenant se existir (variáveis em usecase/template).
+// O texto padrão leva o Pix copia e cola quando o tenant recebe por Pix.
+func montarConteudo(config *entity.Configuracao, cliente *entity.Cliente, fatura *entity.Fatura, agora time.Time, urlPublica string) (string, error) {
+	if config.TemplateLembrete != "" {
+		return template.Montar(config.TemplateLembrete, config, cliente, fatura, agora, urlPublica)
+	}
+
+	textoPix, err := pix.TextoMensagem(config, fatura, agora, urlPublica)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(templateLembretePadrao, cliente.Nome, fatura.Numero, fatura.Valor, fatura.DataVencimento.Format("02/01/2006")) + textoPix, nil
+}
@@ -0,0 +1,178 @@
+package lembrete
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func setup(t *testing.T) (*Service, *memory.Store, *entity.Cliente) {
+	t.Helper()
+
+	store := memory.NewStore()
+
+	config, _ := entity.NewConfiguracao("user1")
+	config.HorarioInicioEnvio = "08:00"
+	config.HorarioFimEnvio = "18:00"
+	store.Configuracoes.Save(config)
+
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "")
+	client.UsuarioID = "user1"
+	store.Clientes.Save(client)
+
+	s := NewService(store.Repositorios(), store.UnitOfWork(), zap.NewNop(), "")
+	s.agora = func() time.Time { return time.Date(2026, 1, 10, 10, 0, 0, 0, time.Local) }
+
+	return s, store, client
+}
+
+func TestService_EnfileiraLembrete(t *testing.T) {
+	s, store, client := setup(t)
+
+	// Vence em 2 dias: dentro da janela de 3 dias
+	f1, _ := entity.NewFatura(client.ID, "FAT-2026-000001", entity.BRL(10000), time.Now().AddDate(0, 0, 2), "Dentro da janela")
+	store.Faturas.Save(f1)
+
+	// Vence em 10 dias: fora da janela
+	f2, _ := entity.NewFatura(client.ID, "FAT-2026-000002", entity.BRL(10000), time.Now().AddDate(0, 0, 10), "Fora da janela")
+	store.Faturas.Save(f2)
+
+	n, err := s.Executar()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	msgs := store.Mensagens.All()
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, f1.ID, msgs[0].FaturaID)
+	assert.Equal(t, entity.TipoMensagemLembrete, msgs[0].Tipo)
+	assert.Equal(t, client.WhatsApp, msgs[0].WhatsApp)
+
+	found, _ := store.Faturas.FindByID(f1.ID)
+	assert.True(t, found.LembreteEnviado)
+
+	assert.Len(t, store.Eventos.PorTipo(entity.EventFaturaLembreteEnviado), 1)
+	events := store.Eventos.PorTipo(entity.EventMensagemEnfileirada)
+	assert.Len(t, events, 1)
+	assert.Equal(t, msgs[0].ID, events[0].AggregateID)
+
+	// Segunda execução não reenvia
+	n, err = s.Executar()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Len(t, store.Mensagens.All(), 1)
+}
+
+func TestService_LembreteComPix(t *testing.T) {
+	s, store, client := setup(t)
+	s.urlPublica = "https://api.exemplo.com.br"
+
+	config, _ := store.Configuracoes.FindByUsuarioID("user1")
+	config.Pix = entity.ConfigPix{Chave: "financeiro@exemplo.com.br", NomeRecebedor: "Exemplo Ltda", Cidade: "Curitiba"}
+	store.Configuracoes.Update(config)
+
+	f, _ := entity.NewFatura(client.ID, "FAT-2026-000001", entity.BRL(10000), time.Now().AddDate(0, 0, 2), "Com Pix")
+	store.Faturas.Save(f)
+
+	n, err := s.Executar()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	conteudo := store.Mensagens.All()[0].Conteudo
+	assert.Contains(t, conteudo, "Pague com Pix copia e cola:\n000201")
+	assert.Contains(t, conteudo, "https://api.exemplo.com.br/faturas/"+f.ID+"/pix/qrcode")
+}
+
+func TestService_RespeitaConfiguracao(t *testing.T) {
+	t.Run("should not send outside sending window", func(t *testing.T) {
+		s, store, client := setup(t)
+		s.agora = func() time.Time { return time.Date(2026, 1, 10, 22, 0, 0, 0, time.Local) }
+
+		f, _ := entity.NewFatura(client.ID, "FAT-2026-000003", entity.BRL(10000), time.Now().AddDate(0, 0, 2), "Test")
+		store.Faturas.Save(f)
+
+		n, err := s.Executar()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("should not send when automatic sending is disabled", func(t *testing.T) {
+		s, store, client := setup(t)
+		config, _ := store.Configuracoes.FindByUsuarioID("user1")
+		config.EnvioAutomaticoAtivo = false
+		store.Configuracoes.Update(config)
+
+		f, _ := entity.NewFatura(client.ID, "FAT-2026-000004", entity.BRL(10000), time.Now().AddDate(0, 0, 2), "Test")
+		store.Faturas.Save(f)
+
+		n, err := s.Executar()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("should leave tenants with dunning steps to the regua job", func(t *testing.T) {
+		s, store, client := setup(t)
+		config, _ := store.Configuracoes.FindByUsuarioID("user1")
+		config.Regua = entity.ReguaCobranca{{Dias: -2, Tipo: entity.TipoMensagemLembrete}}
+		store.Configuracoes.Update(config)
+
+		f, _ := entity.NewFatura(client.ID, "FAT-2026-000006", entity.BRL(10000), time.Now().AddDate(0, 0, 2), "Test")
+		store.Faturas.Save(f)
+
+		n, err := s.Executar()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("should use tenant template", func(t *testing.T) {
+		s, store, client := setup(t)
+		config, _ := store.Configuracoes.FindByUsuarioID("user1")
+		config.TemplateLembrete = "Sua fatura vence em breve"
+		store.Configuracoes.Update(config)
+
+		f, _ := entity.NewFatura(client.ID, "FAT-2026-000005", entity.BRL(10000), time.Now().AddDate(0, 0, 2), "Test")
+		store.Faturas.Save(f)
+
+		_, err := s.Executar()
+		assert.NoError(t, err)
+		assert.Equal(t, "Sua fatura vence em breve", store.Mensagens.All()[0].Conteudo)
+	})
+
+	t.Run("should render the tenant template variables", func(t *testing.T) {
+		s, store, client := setup(t)
+		config, _ := store.Configuracoes.FindByUsuarioID("user1")
+		config.TemplateLembrete = "Oi {{cliente}}, a {{numero}} de {{valor}} vence em {{vencimento}}."
+		store.Configuracoes.Update(config)
+
+		vencimento := time.Now().AddDate(0, 0, 2)
+		f, _ := entity.NewFatura(client.ID, "FAT-2026-000007", entity.BRL(150050), vencimento, "Test")
+		store.Faturas.Save(f)
+
+		_, err := s.Executar()
+		assert.NoError(t, err)
+		assert.Equal(t, "Oi Cliente 1, a FAT-2026-000007 de R$ 1.500,50 vence em "+vencimento.Format("02/01/2006")+".", store.Mensagens.All()[0].Conteudo)
+	})
+
+	t.Run("should fall back to the default text when the stored template is invalid", func(t *testing.T) {
+		s, store, client := setup(t)
+		config, _ := store.Configuracoes.FindByUsuarioID("user1")
+		config.TemplateLembrete = "Pague {{valor_total}}" // Gravado antes da validação existir
+		store.Configuracoes.Update(config)
+
+		f, _ := entity.NewFatura(client.ID, "FAT-2026-000008", entity.BRL(10000), time.Now().AddDate(0, 0, 2), "Test")
+		store.Faturas.Save(f)
+
+		n, err := s.Executar()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Contains(t, store.Mensagens.All()[0].Conteudo, "FAT-2026-000008")
+
+		// O texto gravado pelo tenant continua intacto
+		config, _ = store.Configuracoes.FindByUsuarioID("user1")
+		assert.Equal(t, "Pague {{valor_total}}", config.TemplateLembrete)
+	})
+}
@@ -0,0 +1,33 @@
+package numeracao
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+// Proximo reserva o próximo número de fatura do tenant e o formata conforme a configuração
+// dele (ou o formato padrão, para tenants sem configuração e clientes sem tenant).
+// Deve ser chamado com os repositórios da UnitOfWork que grava a fatura, para que
+// o número só seja consumido se a fatura for de fato gravada.
+func Proximo(repos repository.Repositorios, usuarioID string, em time.Time) (string, error) {
+	formato := entity.FormatoNumeracaoPadrao()
+	if usuarioID != "" {
+		config, err := repos.Configuracoes.FindByUsuarioID(usuarioID)
+		if err != nil {
+			return "", err
+		}
+		if config != nil {
+			formato = config.Numeracao
+		}
+	}
+
+	sequencial, err := repos.Numeracao.Proximo(usuarioID, formato.Prefixo, formato.Periodo(em))
+	if err != nil {
+		return "", fmt.Errorf("erro ao numerar fatura: %w", err)
+	}
+
+	return formato.Formatar(sequencial, em), nil
+}
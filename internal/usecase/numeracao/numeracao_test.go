@@ -0,0 +1,46 @@
+package numeracao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func TestProximo(t *testing.T) {
+	em := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should hand out sequential numbers per tenant", func(t *testing.T) {
+		repos := memory.NewStore().Repositorios()
+
+		n1, err := Proximo(repos, "tenant-a", em)
+		assert.NoError(t, err)
+		n2, _ := Proximo(repos, "tenant-a", em)
+		outro, _ := Proximo(repos, "tenant-b", em)
+
+		assert.Equal(t, "FAT-2026-000001", n1)
+		assert.Equal(t, "FAT-2026-000002", n2)
+		assert.Equal(t, "FAT-2026-000001", outro)
+	})
+
+	t.Run("should restart the sequence every year when the year is part of the number", func(t *testing.T) {
+		repos := memory.NewStore().Repositorios()
+
+		Proximo(repos, "tenant-a", em)
+		n, _ := Proximo(repos, "tenant-a", em.AddDate(1, 0, 0))
+		assert.Equal(t, "FAT-2027-000001", n)
+	})
+
+	t.Run("should use the tenant format", func(t *testing.T) {
+		repos := memory.NewStore().Repositorios()
+		config, _ := entity.NewConfiguracao("tenant-a")
+		config.Numeracao = entity.FormatoNumeracao{Prefixo: "NF", Digitos: 3}
+		repos.Configuracoes.Save(config)
+
+		n, err := Proximo(repos, "tenant-a", em)
+		assert.NoError(t, err)
+		assert.Equal(t, "NF-001", n)
+	})
+}
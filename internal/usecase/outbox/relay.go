@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/gateway"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+// Opcoes controla o relay. Valores zerados usam os defaults.
+type Opcoes struct {
+	TamanhoLote   int           // Eventos publicados por transação
+	IntervaloPoll time.Duration // Espera quando não há eventos pendentes
+}
+
+func (o Opcoes) comDefaults() Opcoes {
+	if o.TamanhoLote <= 0 {
+		o.TamanhoLote = 100
+	}
+	if o.IntervaloPoll <= 0 {
+		o.IntervaloPoll = time.Second
+	}
+	return o
+}
+
+// Relay publica os eventos do outbox na ordem em que foram gravados.
+// Um evento só é marcado como publicado depois que o publicador confirma;
+// se a publicação falhar o lote para ali, e o próximo ciclo recomeça do mesmo evento.
+type Relay struct {
+	outbox     repository.Outbox
+	publicador gateway.PublicadorEventos
+	logger     *zap.Logger
+	opcoes     Opcoes
+}
+
+func NewRelay(outbox repository.Outbox, publicador gateway.PublicadorEventos, logger *zap.Logger, opcoes Opcoes) *Relay {
+	return &Relay{outbox: outbox, publicador: publicador, logger: logger, opcoes: opcoes.comDefaults()}
+}
+
+// Run processa lotes até o contexto ser cancelado
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Info("Relay do outbox iniciado")
+
+	for {
+		n, err := r.ProcessarLote(ctx)
+		if err != nil {
+			r.logger.Error("Falha ao publicar eventos do outbox", zap.Int("publicados", n), zap.Error(err))
+		}
+
+		espera := r.opcoes.IntervaloPoll
+		if err == nil && n == r.opcoes.TamanhoLote {
+			espera = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Relay do outbox encerrado")
+			return
+		case <-time.After(espera):
+		}
+	}
+}
+
+// ProcessarLote publica um lote e retorna quantos eventos foram publicados
+func (r *Relay) ProcessarLote(ctx context.Context) (int, error) {
+	publicados := 0
+
+	err := r.outbox.ProcessarPendentes(r.opcoes.TamanhoLote, func(eventos []*entity.Event) (int, error) {
+		for i, event := range eventos {
+			if err := r.publicador.Publicar(ctx, event); err != nil {
+				publicados = i
+				return i, err
+			}
+		}
+		publicados = len(eventos)
+		return publicados, nil
+	})
+
+	return publicados, err
+}
@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+// publicadorFake registra os eventos publicados e pode falhar a partir de um ponto
+type publicadorFake struct {
+	publicados []*entity.Event
+	falharEm   int // Falha ao publicar o evento de índice falharEm (-1 = nunca)
+}
+
+func (p *publicadorFake) Publicar(ctx context.Context, eventos ...*entity.Event) error {
+	for _, e := range eventos {
+		if len(p.publicados) == p.falharEm {
+			return errors.New("broker indisponivel")
+		}
+		p.publicados = append(p.publicados, e)
+	}
+	return nil
+}
+
+func gravarEventos(t *testing.T, store *memory.EventStoreMemory, n int) []*entity.Event {
+	t.Helper()
+
+	var events []*entity.Event
+	for i := 0; i < n; i++ {
+		e := entity.NewEvent(entity.EventFaturaCriada, "fat-1", entity.AggregateFatura, []byte(`{}`), nil, i+1)
+		assert.NoError(t, store.Append("fat-1", i, e))
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestRelay_PublicaEmOrdem(t *testing.T) {
+	store := memory.NewEventStoreMemory()
+	pub := &publicadorFake{falharEm: -1}
+	r := NewRelay(store, pub, zap.NewNop(), Opcoes{TamanhoLote: 2})
+
+	events := gravarEventos(t, store, 3)
+
+	n, err := r.ProcessarLote(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	n, err = r.ProcessarLote(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.Len(t, pub.publicados, 3)
+	for i, e := range events {
+		assert.Equal(t, e.ID, pub.publicados[i].ID)
+	}
+	assert.Equal(t, 0, store.Pendentes())
+
+	// Nada mais a publicar
+	n, err = r.ProcessarLote(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestRelay_RetomaAposFalha(t *testing.T) {
+	store := memory.NewEventStoreMemory()
+	pub := &publicadorFake{falharEm: 1}
+	r := NewRelay(store, pub, zap.NewNop(), Opcoes{})
+
+	events := gravarEventos(t, store, 3)
+
+	// O segundo evento falha: só o primeiro é marcado
+	n, err := r.ProcessarLote(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 2, store.Pendentes())
+
+	// Broker volta: o próximo ciclo continua do evento que falhou, sem pular nem reordenar
+	pub.falharEm = -1
+	n, err = r.ProcessarLote(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	assert.Len(t, pub.publicados, 3)
+	for i, e := range events {
+		assert.Equal(t, e.ID, pub.publicados[i].ID)
+	}
+}
@@ -0,0 +1,138 @@
+package pix
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+// Cobranca é o Pix de uma fatura, pronto para exibir ou enviar ao cliente
+type Cobranca struct {
+	TxID       string
+	Tipo       entity.TipoPix
+	Valor      entity.Dinheiro // Saldo devedor na data da geração, com multa e juros
+	CopiaECola string          // Payload do BR Code; é o conteúdo do QR code
+}
+
+// Gerar monta o Pix da fatura com os dados de recebimento do tenant dono do cliente.
+// Tipo vazio usa o dinâmico se houver cobrança registrada no PSP para a fatura.
+func Gerar(repos repository.Repositorios, fatura *entity.Fatura, tipo entity.TipoPix, agora time.Time) (*Cobranca, error) {
+	config, err := configDoTenant(repos, fatura)
+	if err != nil {
+		return nil, err
+	}
+
+	cob, err := repos.CobrancasPix.FindByFaturaID(fatura.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParaFatura(config, fatura, tipo, cob, agora)
+}
+
+// RegistrarCobranca guarda a cobrança que o PSP do tenant criou para a fatura, com a location
+// devolvida por ele, e retorna o Pix dinâmico que aponta para ela
+func RegistrarCobranca(repos repository.Repositorios, fatura *entity.Fatura, location string, agora time.Time) (*Cobranca, error) {
+	cob, err := entity.NewCobrancaPix(fatura.ID, location)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := configDoTenant(repos, fatura)
+	if err != nil {
+		return nil, err
+	}
+
+	// Monta o Pix antes de gravar: fatura quitada ou tenant sem Pix não registram cobrança
+	cobranca, err := ParaFatura(config, fatura, entity.PixDinamico, cob, agora)
+	if err != nil {
+		return nil, err
+	}
+	if err := repos.CobrancasPix.Save(cob); err != nil {
+		return nil, err
+	}
+
+	return cobranca, nil
+}
+
+func configDoTenant(repos repository.Repositorios, fatura *entity.Fatura) (*entity.Configuracao, error) {
+	cliente, err := repos.Clientes.FindByID(fatura.ClienteID)
+	if err != nil {
+		return nil, err
+	}
+	if cliente == nil || cliente.UsuarioID == "" {
+		return nil, entity.ErrPixNaoConfigurado
+	}
+
+	config, err := repos.Configuracoes.FindByUsuarioID(cliente.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, entity.ErrPixNaoConfigurado
+	}
+	return config, nil
+}
+
+// ParaFatura é o Gerar para quem já tem a configuração do tenant e a cobrança no PSP
+// (nil se não houver) em mãos
+func ParaFatura(config *entity.Configuracao, fatura *entity.Fatura, tipo entity.TipoPix, cob *entity.CobrancaPix, agora time.Time) (*Cobranca, error) {
+	switch fatura.Status {
+	case entity.StatusPaga:
+		return nil, entity.ErrFaturaJaPaga
+	case entity.StatusCancelada:
+		return nil, entity.ErrPagarFaturaCancelada
+	}
+
+	if tipo == "" {
+		tipo = entity.PixEstatico
+		if cob != nil {
+			tipo = entity.PixDinamico
+		}
+	}
+
+	devido, err := fatura.ValorDevido(agora, config.Encargos)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := entity.NewBRCode(config.Pix, tipo, fatura, devido.Saldo, cob)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := code.Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cobranca{TxID: code.TxID, Tipo: tipo, Valor: devido.Saldo, CopiaECola: payload}, nil
+}
+
+// TextoMensagem é o trecho com o Pix acrescentado aos lembretes e cobranças.
+// Retorna vazio se o tenant não recebe por Pix. Com urlPublica (endereço externo da API),
+// inclui o link para a imagem do QR code. O copia e cola é o estático, que não expira
+// junto com a cobrança do PSP enquanto a mensagem espera para ser lida.
+func TextoMensagem(config *entity.Configuracao, fatura *entity.Fatura, agora time.Time, urlPublica string) (string, error) {
+	if !config.Pix.Configurado() {
+		return "", nil
+	}
+
+	cobranca, err := ParaFatura(config, fatura, entity.PixEstatico, nil, agora)
+	if err != nil {
+		return "", err
+	}
+
+	texto := fmt.Sprintf("\n\nPague com Pix copia e cola:\n%s", cobranca.CopiaECola)
+	if urlPublica != "" {
+		texto += fmt.Sprintf("\n\nQR code: %s", URLQRCode(urlPublica, fatura.ID))
+	}
+	return texto, nil
+}
+
+// URLQRCode é o endereço da imagem do QR code da fatura na API
+func URLQRCode(urlPublica, faturaID string) string {
+	return fmt.Sprintf("%s/faturas/%s/pix/qrcode", strings.TrimSuffix(urlPublica, "/"), faturaID)
+}
@@ -0,0 +1,110 @@
+package pix
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func setup(t *testing.T, pix entity.ConfigPix) (*memory.Store, *entity.Fatura) {
+	t.Helper()
+
+	store := memory.NewStore()
+
+	config, _ := entity.NewConfiguracao("user1")
+	config.Pix = pix
+	store.Configuracoes.Save(config)
+
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "")
+	client.UsuarioID = "user1"
+	store.Clientes.Save(client)
+
+	f, err := entity.NewFatura(client.ID, "FAT-2026-000042", entity.BRL(15050), time.Now().AddDate(0, 0, 5), "Consultoria")
+	assert.NoError(t, err)
+	store.Faturas.Save(f)
+
+	return store, f
+}
+
+func TestGerar(t *testing.T) {
+	configPix := entity.ConfigPix{Chave: "financeiro@exemplo.com.br", NomeRecebedor: "Exemplo Ltda", Cidade: "Curitiba"}
+
+	t.Run("should build the code for the outstanding balance", func(t *testing.T) {
+		store, f := setup(t, configPix)
+		_, err := f.RegistrarPagamento(entity.BRL(5050), entity.MetodoPix, time.Time{}, "", entity.EncargosAtraso{})
+		assert.NoError(t, err)
+
+		cobranca, err := Gerar(store.Repositorios(), f, "", time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, entity.PixEstatico, cobranca.Tipo)
+		assert.Equal(t, "FAT2026000042", cobranca.TxID)
+		assert.Equal(t, entity.BRL(10000), cobranca.Valor)
+		assert.Contains(t, cobranca.CopiaECola, "5406100.00")
+	})
+
+	t.Run("should default to dynamic once the PSP charge is registered", func(t *testing.T) {
+		store, f := setup(t, configPix)
+
+		_, err := Gerar(store.Repositorios(), f, entity.PixDinamico, time.Now())
+		assert.Equal(t, entity.ErrPixDinamicoSemCob, err)
+
+		registrada, err := RegistrarCobranca(store.Repositorios(), f, "pix.psp.com.br/qr/v2/9d36b84fc70b478fb95c12729b90ca25", time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, entity.PixDinamico, registrada.Tipo)
+		assert.Contains(t, registrada.CopiaECola, "2553pix.psp.com.br/qr/v2/9d36b84fc70b478fb95c12729b90ca25")
+
+		cobranca, err := Gerar(store.Repositorios(), f, "", time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, registrada.CopiaECola, cobranca.CopiaECola)
+
+		estatico, err := Gerar(store.Repositorios(), f, entity.PixEstatico, time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, entity.PixEstatico, estatico.Tipo)
+	})
+
+	t.Run("should not register a charge for a paid fatura", func(t *testing.T) {
+		store, f := setup(t, configPix)
+		assert.NoError(t, f.MarcarComoPaga(entity.EncargosAtraso{}))
+
+		_, err := RegistrarCobranca(store.Repositorios(), f, "pix.psp.com.br/qr/v2/abc", time.Now())
+		assert.Equal(t, entity.ErrFaturaJaPaga, err)
+
+		cob, _ := store.CobrancasPix.FindByFaturaID(f.ID)
+		assert.Nil(t, cob)
+	})
+
+	t.Run("should fail when the tenant has no pix", func(t *testing.T) {
+		store, f := setup(t, entity.ConfigPix{})
+
+		_, err := Gerar(store.Repositorios(), f, "", time.Now())
+		assert.Equal(t, entity.ErrPixNaoConfigurado, err)
+	})
+
+	t.Run("should not charge a paid fatura", func(t *testing.T) {
+		store, f := setup(t, configPix)
+		assert.NoError(t, f.MarcarComoPaga(entity.EncargosAtraso{}))
+
+		_, err := Gerar(store.Repositorios(), f, "", time.Now())
+		assert.Equal(t, entity.ErrFaturaJaPaga, err)
+	})
+}
+
+func TestTextoMensagem(t *testing.T) {
+	config, _ := entity.NewConfiguracao("user1")
+	f, _ := entity.NewFatura("cli-1", "FAT-2026-000042", entity.BRL(15050), time.Now().AddDate(0, 0, 5), "Consultoria")
+
+	texto, err := TextoMensagem(config, f, time.Now(), "https://api.exemplo.com.br")
+	assert.NoError(t, err)
+	assert.Empty(t, texto)
+
+	config.Pix = entity.ConfigPix{Chave: "financeiro@exemplo.com.br", NomeRecebedor: "Exemplo Ltda", Cidade: "Curitiba"}
+	texto, err = TextoMensagem(config, f, time.Now(), "https://api.exemplo.com.br/")
+	assert.NoError(t, err)
+	assert.Contains(t, texto, "Pix copia e cola:\n000201")
+	assert.True(t, strings.HasSuffix(texto, "QR code: https://api.exemplo.com.br/faturas/"+f.ID+"/pix/qrcode"))
+}
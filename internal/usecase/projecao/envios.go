@@ -0,0 +1,70 @@
+package projecao
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+const NomeEstatisticasEnvio = "estatisticas_envio"
+
+// EstatisticasEnvio conta, por dia e tipo de mensagem, os enfileiramentos e os resultados de envio
+type EstatisticasEnvio struct{}
+
+func NewEstatisticasEnvio() *EstatisticasEnvio {
+	return &EstatisticasEnvio{}
+}
+
+func (p *EstatisticasEnvio) Nome() string {
+	return NomeEstatisticasEnvio
+}
+
+func (p *EstatisticasEnvio) Aplicar(repos repository.LeituraRepositorios, e *entity.Event) error {
+	if e.AggregateType != entity.AggregateMensagem {
+		return nil
+	}
+
+	delta := &entity.EstatisticasEnvio{Dia: Dia(e.Timestamp)}
+
+	switch e.EventType {
+	case entity.EventMensagemEnfileirada:
+		var d entity.MensagemEnfileiradaData
+		if err := json.Unmarshal(e.EventData, &d); err != nil {
+			return err
+		}
+		delta.Tipo = d.Tipo
+		delta.Enfileiradas = 1
+
+	case entity.EventMensagemEnviada, entity.EventMensagemFalhou, entity.EventMensagemMovidaParaDLQ:
+		var d entity.MensagemEnvioData
+		if err := json.Unmarshal(e.EventData, &d); err != nil {
+			return err
+		}
+		delta.Tipo = d.Tipo
+
+		switch e.EventType {
+		case entity.EventMensagemEnviada:
+			delta.Enviadas = 1
+		case entity.EventMensagemFalhou:
+			delta.Falhas = 1
+		default:
+			delta.DLQ = 1
+		}
+
+	default:
+		return nil
+	}
+
+	return repos.EstatisticasEnvio.Incrementar(delta)
+}
+
+func (p *EstatisticasEnvio) Limpar(repos repository.LeituraRepositorios) error {
+	return repos.EstatisticasEnvio.Limpar()
+}
+
+// Dia zera o horário, mantendo a data do calendário em que o evento ocorreu
+func Dia(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
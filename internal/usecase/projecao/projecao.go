@@ -0,0 +1,21 @@
+package projecao
+
+import (
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+// Projecao mantém um read model a partir dos eventos, na ordem global do event store.
+// Aplicar roda na mesma transação que avança o checkpoint, então cada evento
+// é aplicado exatamente uma vez; eventos que não interessam à projeção são ignorados.
+type Projecao interface {
+	Nome() string
+	Aplicar(repos repository.LeituraRepositorios, evento *entity.Event) error
+	// Limpar apaga o read model antes de uma reconstrução a partir do evento zero
+	Limpar(repos repository.LeituraRepositorios) error
+}
+
+// Padrao retorna as projeções mantidas pelo sistema
+func Padrao() []Projecao {
+	return []Projecao{NewRecebiveis(), NewEstatisticasEnvio()}
+}
@@ -0,0 +1,154 @@
+package projecao
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+const NomeRecebiveis = "recebiveis_cliente"
+
+// Recebiveis mantém os totais em aberto, vencidos e recebidos de cada cliente
+type Recebiveis struct{}
+
+func NewRecebiveis() *Recebiveis {
+	return &Recebiveis{}
+}
+
+func (p *Recebiveis) Nome() string {
+	return NomeRecebiveis
+}
+
+func (p *Recebiveis) Aplicar(repos repository.LeituraRepositorios, e *entity.Event) error {
+	if e.AggregateType != entity.AggregateFatura {
+		return nil
+	}
+
+	switch e.EventType {
+	case entity.EventFaturaCriada:
+		var d entity.FaturaCriadaData
+		if err := json.Unmarshal(e.EventData, &d); err != nil {
+			return err
+		}
+		return p.mover(repos, nil, &entity.RecebivelFatura{
+			FaturaID:  e.AggregateID,
+			ClienteID: d.ClienteID,
+			Valor:     d.Valor,
+			Status:    entity.StatusPendente,
+		}, e.Timestamp)
+
+	case entity.EventFaturaPagamentoRegistrado:
+		var d entity.FaturaPagamentoRegistradoData
+		if err := json.Unmarshal(e.EventData, &d); err != nil {
+			return err
+		}
+		return p.transicionar(repos, e, func(f *entity.RecebivelFatura) error {
+			recebido, err := f.Recebido.Somar(d.Valor)
+			if err != nil {
+				return err
+			}
+			f.Recebido = recebido
+			if f.Status == entity.StatusPendente {
+				f.Status = entity.StatusParcialmentePaga
+			}
+			return nil
+		})
+
+	case entity.EventFaturaPagamentoEstornado:
+		var d entity.FaturaPagamentoEstornadoData
+		if err := json.Unmarshal(e.EventData, &d); err != nil {
+			return err
+		}
+		return p.transicionar(repos, e, func(f *entity.RecebivelFatura) error {
+			recebido, err := f.Recebido.Subtrair(d.Valor)
+			if err != nil {
+				return err
+			}
+			f.Recebido = recebido
+			f.Status = d.Status
+			return nil
+		})
+
+	case entity.EventFaturaPaga:
+		var d entity.FaturaPagaData
+		if err := json.Unmarshal(e.EventData, &d); err != nil {
+			return err
+		}
+		return p.transicionar(repos, e, func(f *entity.RecebivelFatura) error {
+			f.Status = entity.StatusPaga
+			// Eventos anteriores aos pagamentos liquidavam sem FaturaPagamentoRegistrado,
+			// e os bem antigos nem traziam o valor pago
+			switch {
+			case !d.ValorPago.IsZero():
+				f.Recebido = d.ValorPago
+			case f.Recebido.IsZero():
+				f.Recebido = f.Valor
+			}
+			return nil
+		})
+
+	case entity.EventFaturaCancelada:
+		return p.transicionar(repos, e, status(entity.StatusCancelada))
+	case entity.EventFaturaVencida:
+		return p.transicionar(repos, e, status(entity.StatusVencida))
+	}
+
+	return nil
+}
+
+func (p *Recebiveis) Limpar(repos repository.LeituraRepositorios) error {
+	return repos.Recebiveis.Limpar()
+}
+
+// transicionar aplica a mudança na situação da fatura e move seus valores entre os totais
+func (p *Recebiveis) transicionar(repos repository.LeituraRepositorios, e *entity.Event, mudar func(f *entity.RecebivelFatura) error) error {
+	antes, err := repos.Recebiveis.FindFatura(e.AggregateID)
+	if err != nil {
+		return err
+	}
+	// Todo stream de fatura começa com FaturaCriada; sem ela não há de onde tirar o valor
+	if antes == nil {
+		return nil
+	}
+
+	depois := *antes
+	if err := mudar(&depois); err != nil {
+		return err
+	}
+	return p.mover(repos, antes, &depois, e.Timestamp)
+}
+
+func status(s entity.StatusFatura) func(f *entity.RecebivelFatura) error {
+	return func(f *entity.RecebivelFatura) error {
+		f.Status = s
+		return nil
+	}
+}
+
+// mover tira a fatura do total do status anterior e a soma no total do novo status
+func (p *Recebiveis) mover(repos repository.LeituraRepositorios, antes, depois *entity.RecebivelFatura, em time.Time) error {
+	resumo, err := repos.Recebiveis.FindByClienteID(depois.ClienteID)
+	if err != nil {
+		return err
+	}
+	if resumo == nil {
+		resumo = &entity.RecebiveisCliente{ClienteID: depois.ClienteID}
+	}
+
+	if antes != nil {
+		if err := resumo.Somar(*antes, -1); err != nil {
+			return err
+		}
+	}
+	if err := resumo.Somar(*depois, 1); err != nil {
+		return err
+	}
+	resumo.AtualizadoEm = em
+
+	if err := repos.Recebiveis.SaveFatura(depois); err != nil {
+		return err
+	}
+	return repos.Recebiveis.SaveCliente(resumo)
+}
@@ -0,0 +1,200 @@
+package projecao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+)
+
+var ErrProjecaoDesconhecida = errors.New("projecao desconhecida")
+
+// Opcoes controla o runner. Valores zerados usam os defaults.
+type Opcoes struct {
+	TamanhoLote   int           // Eventos aplicados por transação
+	IntervaloPoll time.Duration // Espera quando não há eventos novos
+}
+
+func (o Opcoes) comDefaults() Opcoes {
+	if o.TamanhoLote <= 0 {
+		o.TamanhoLote = 500
+	}
+	if o.IntervaloPoll <= 0 {
+		o.IntervaloPoll = time.Second
+	}
+	return o
+}
+
+// Runner acompanha o event store e aplica os eventos novos em cada projeção,
+// a partir do checkpoint dela. Um evento que falha ao ser aplicado trava apenas
+// a sua projeção, que tenta de novo no próximo ciclo.
+type Runner struct {
+	eventos   repository.EventStore
+	uow       repository.ProjecaoUnitOfWork
+	projecoes []Projecao
+	logger    *zap.Logger
+	opcoes    Opcoes
+}
+
+func NewRunner(eventos repository.EventStore, uow repository.ProjecaoUnitOfWork, projecoes []Projecao, logger *zap.Logger, opcoes Opcoes) *Runner {
+	return &Runner{eventos: eventos, uow: uow, projecoes: projecoes, logger: logger, opcoes: opcoes.comDefaults()}
+}
+
+// Run processa lotes até o contexto ser cancelado
+func (r *Runner) Run(ctx context.Context) {
+	r.logger.Info("Runner de projecoes iniciado", zap.Int("projecoes", len(r.projecoes)))
+
+	for {
+		n, err := r.ProcessarLote()
+		if err != nil {
+			r.logger.Error("Falha ao atualizar projecoes", zap.Error(err))
+		}
+
+		espera := r.opcoes.IntervaloPoll
+		if n >= r.opcoes.TamanhoLote {
+			espera = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Runner de projecoes encerrado")
+			return
+		case <-time.After(espera):
+		}
+	}
+}
+
+// ProcessarLote aplica até um lote de eventos novos em cada projeção.
+// Retorna quantos eventos foram aplicados no total; projeções reservadas
+// por outra instância (ou em reconstrução) são puladas.
+func (r *Runner) ProcessarLote() (int, error) {
+	total := 0
+	var errs []error
+
+	for _, p := range r.projecoes {
+		n, err := r.processar(p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		total += n
+	}
+
+	return total, errors.Join(errs...)
+}
+
+func (r *Runner) processar(p Projecao) (int, error) {
+	aplicados := 0
+
+	err := r.uow.Executar(p.Nome(), func(repos repository.LeituraRepositorios) error {
+		checkpoint, err := repos.Checkpoints.Carregar(p.Nome())
+		if err != nil {
+			return err
+		}
+
+		eventos, err := r.eventos.ReadAll(checkpoint, r.opcoes.TamanhoLote)
+		if err != nil {
+			return err
+		}
+		if len(eventos) == 0 {
+			return nil
+		}
+
+		if err := r.aplicar(p, repos, eventos); err != nil {
+			return err
+		}
+
+		aplicados = len(eventos)
+		return repos.Checkpoints.Salvar(p.Nome(), eventos[len(eventos)-1].Position)
+	})
+
+	if errors.Is(err, repository.ErrProjecaoOcupada) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return aplicados, nil
+}
+
+// Reconstruir apaga o read model da projeção e reaplica todos os eventos desde o início,
+// em uma única transação: até o commit, as consultas continuam vendo o read model anterior,
+// então a API não precisa parar. Se a projeção estiver reservada, espera ela ser liberada.
+// Retorna quantos eventos foram reaplicados.
+func (r *Runner) Reconstruir(ctx context.Context, nome string) (int, error) {
+	p := r.buscar(nome)
+	if p == nil {
+		return 0, fmt.Errorf("%w: %s", ErrProjecaoDesconhecida, nome)
+	}
+
+	for {
+		aplicados := 0
+
+		err := r.uow.Executar(nome, func(repos repository.LeituraRepositorios) error {
+			if err := p.Limpar(repos); err != nil {
+				return err
+			}
+
+			var position int64
+			for {
+				eventos, err := r.eventos.ReadAll(position, r.opcoes.TamanhoLote)
+				if err != nil {
+					return err
+				}
+				if len(eventos) == 0 {
+					break
+				}
+
+				if err := r.aplicar(p, repos, eventos); err != nil {
+					return err
+				}
+				aplicados += len(eventos)
+				position = eventos[len(eventos)-1].Position
+			}
+
+			return repos.Checkpoints.Salvar(nome, position)
+		})
+
+		if !errors.Is(err, repository.ErrProjecaoOcupada) {
+			return aplicados, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(r.opcoes.IntervaloPoll):
+		}
+	}
+}
+
+// Nomes retorna os nomes das projeções registradas
+func (r *Runner) Nomes() []string {
+	nomes := make([]string, 0, len(r.projecoes))
+	for _, p := range r.projecoes {
+		nomes = append(nomes, p.Nome())
+	}
+	return nomes
+}
+
+func (r *Runner) aplicar(p Projecao, repos repository.LeituraRepositorios, eventos []*entity.Event) error {
+	for _, e := range eventos {
+		if err := p.Aplicar(repos, e); err != nil {
+			return fmt.Errorf("projecao %s: erro ao aplicar evento %s (position %d): %w", p.Nome(), e.EventType, e.Position, err)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) buscar(nome string) Projecao {
+	for _, p := range r.projecoes {
+		if p.Nome() == nome {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package projecao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func setup(t *testing.T, opcoes Opcoes) (*Runner, *memory.Store, *memory.LeituraStore, *memory.ProjecaoUnitOfWorkMemory) {
+	t.Helper()
+
+	store := memory.NewStore()
+	leitura := memory.NewLeituraStore()
+	uow := leitura.UnitOfWork()
+
+	return NewRunner(store.Eventos, uow, Padrao(), zap.NewNop(), opcoes), store, leitura, uow
+}
+
+func novaFatura(t *testing.T, store *memory.Store, clienteID string, valor entity.Dinheiro) *entity.Fatura {
+	t.Helper()
+
+	f, err := entity.NewFatura(clienteID, "FAT-2026-000001", valor, time.Now().AddDate(0, 0, 5), "Servico")
+	assert.NoError(t, err)
+	assert.NoError(t, store.Faturas.Save(f))
+	return f
+}
+
+func TestRunner_Recebiveis(t *testing.T) {
+	r, store, leitura, _ := setup(t, Opcoes{})
+
+	paga := novaFatura(t, store, "cli-1", entity.BRL(10000))
+	cancelada := novaFatura(t, store, "cli-1", entity.BRL(5000))
+	vencida := novaFatura(t, store, "cli-1", entity.BRL(3000))
+	novaFatura(t, store, "cli-1", entity.BRL(2000))
+	novaFatura(t, store, "cli-2", entity.BRL(7000))
+
+	paga.MarcarComoPaga(entity.EncargosAtraso{})
+	store.Faturas.Update(paga)
+	cancelada.Cancelar()
+	store.Faturas.Update(cancelada)
+	vencida.DataVencimento = time.Now().AddDate(0, 0, -1)
+	assert.NoError(t, vencida.MarcarComoVencida(time.Now()))
+	store.Faturas.Update(vencida)
+
+	n, err := r.ProcessarLote()
+	assert.NoError(t, err)
+	assert.Equal(t, 2*9, n) // Cada projeção lê os 9 eventos (a baixa manual gera pagamento + FaturaPaga)
+
+	c1, _ := leitura.Recebiveis.FindByClienteID("cli-1")
+	assert.Equal(t, 1, c1.QuantidadeEmAberto)
+	assert.Equal(t, entity.BRL(2000), c1.ValorEmAberto)
+	assert.Equal(t, 1, c1.QuantidadeVencida)
+	assert.Equal(t, entity.BRL(3000), c1.ValorVencido)
+	assert.Equal(t, 1, c1.QuantidadePaga)
+	assert.Equal(t, entity.BRL(10000), c1.ValorRecebido)
+
+	c2, _ := leitura.Recebiveis.FindByClienteID("cli-2")
+	assert.Equal(t, 1, c2.QuantidadeEmAberto)
+	assert.Equal(t, entity.BRL(7000), c2.ValorEmAberto)
+
+	// Vencida paga depois sai do total vencido
+	vencida.MarcarComoPaga(entity.EncargosAtraso{})
+	store.Faturas.Update(vencida)
+
+	_, err = r.ProcessarLote()
+	assert.NoError(t, err)
+
+	c1, _ = leitura.Recebiveis.FindByClienteID("cli-1")
+	assert.Equal(t, 0, c1.QuantidadeVencida)
+	assert.Equal(t, entity.BRL(0), c1.ValorVencido)
+	assert.Equal(t, 2, c1.QuantidadePaga)
+	assert.Equal(t, entity.BRL(13000), c1.ValorRecebido)
+}
+
+func TestRunner_RecebiveisPagamentosParciais(t *testing.T) {
+	r, store, leitura, _ := setup(t, Opcoes{})
+
+	f := novaFatura(t, store, "cli-1", entity.BRL(10000))
+	pagamento, err := f.RegistrarPagamento(entity.BRL(3000), entity.MetodoPix, time.Now(), "", entity.EncargosAtraso{})
+	assert.NoError(t, err)
+	store.Faturas.Update(f)
+
+	_, err = r.ProcessarLote()
+	assert.NoError(t, err)
+
+	c1, _ := leitura.Recebiveis.FindByClienteID("cli-1")
+	assert.Equal(t, 1, c1.QuantidadeEmAberto)
+	assert.Equal(t, entity.BRL(7000), c1.ValorEmAberto)
+	assert.Equal(t, entity.BRL(3000), c1.ValorRecebido)
+
+	// O estorno devolve o valor ao saldo em aberto
+	assert.NoError(t, f.EstornarPagamento(pagamento.ID, ""))
+	store.Faturas.Update(f)
+
+	_, err = r.ProcessarLote()
+	assert.NoError(t, err)
+
+	c1, _ = leitura.Recebiveis.FindByClienteID("cli-1")
+	assert.Equal(t, 1, c1.QuantidadeEmAberto)
+	assert.Equal(t, entity.BRL(10000), c1.ValorEmAberto)
+	assert.True(t, c1.ValorRecebido.IsZero())
+
+	// Parcialmente paga que vence passa para o total vencido, só com o saldo
+	_, err = f.RegistrarPagamento(entity.BRL(4000), entity.MetodoPix, time.Now(), "", entity.EncargosAtraso{})
+	assert.NoError(t, err)
+	assert.NoError(t, f.MarcarComoVencida(f.DataVencimento.AddDate(0, 0, 1)))
+	store.Faturas.Update(f)
+
+	_, err = r.ProcessarLote()
+	assert.NoError(t, err)
+
+	c1, _ = leitura.Recebiveis.FindByClienteID("cli-1")
+	assert.Equal(t, 0, c1.QuantidadeEmAberto)
+	assert.Equal(t, 1, c1.QuantidadeVencida)
+	assert.Equal(t, entity.BRL(6000), c1.ValorVencido)
+	assert.Equal(t, entity.BRL(4000), c1.ValorRecebido)
+}
+
+func TestRunner_EstatisticasEnvio(t *testing.T) {
+	r, store, leitura, _ := setup(t, Opcoes{})
+
+	msg, _ := entity.NewMensagem("fat-1", "cli-1", "5511999998888", "Ola", entity.TipoMensagemLembrete)
+	enfileirada, _ := entity.NewMensagemEnfileiradaEvent(msg)
+	store.Eventos.Append(msg.ID, 0, enfileirada)
+
+	msg.MarcarComoFalha("timeout")
+	falhou, _ := entity.NewMensagemEnvioEvent(entity.EventMensagemFalhou, msg)
+	msg.MarcarComoEnviada("3EB0C767D26A")
+	enviada, _ := entity.NewMensagemEnvioEvent(entity.EventMensagemEnviada, msg)
+	store.Eventos.Append(msg.ID, 1, falhou, enviada)
+
+	_, err := r.ProcessarLote()
+	assert.NoError(t, err)
+
+	hoje := Dia(time.Now())
+	stats, _ := leitura.EstatisticasEnvio.FindPeriodo(hoje, hoje)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, entity.TipoMensagemLembrete, stats[0].Tipo)
+	assert.Equal(t, 1, stats[0].Enfileiradas)
+	assert.Equal(t, 1, stats[0].Falhas)
+	assert.Equal(t, 1, stats[0].Enviadas)
+	assert.Equal(t, 0, stats[0].DLQ)
+}
+
+func TestRunner_Checkpoint(t *testing.T) {
+	t.Run("should resume from the checkpoint in batches", func(t *testing.T) {
+		r, store, leitura, _ := setup(t, Opcoes{TamanhoLote: 2})
+
+		for i := 0; i < 3; i++ {
+			novaFatura(t, store, "cli-1", entity.BRL(1000))
+		}
+
+		n, _ := r.ProcessarLote()
+		assert.Equal(t, 4, n)
+		cp, _ := leitura.Checkpoints.Carregar(NomeRecebiveis)
+		assert.Equal(t, int64(2), cp)
+
+		n, _ = r.ProcessarLote()
+		assert.Equal(t, 2, n)
+		cp, _ = leitura.Checkpoints.Carregar(NomeRecebiveis)
+		assert.Equal(t, int64(3), cp)
+
+		// Nada novo: os eventos não são aplicados de novo
+		n, _ = r.ProcessarLote()
+		assert.Equal(t, 0, n)
+
+		c, _ := leitura.Recebiveis.FindByClienteID("cli-1")
+		assert.Equal(t, 3, c.QuantidadeEmAberto)
+	})
+
+	t.Run("should skip a projection reserved by another instance", func(t *testing.T) {
+		r, store, leitura, uow := setup(t, Opcoes{})
+		novaFatura(t, store, "cli-1", entity.BRL(1000))
+
+		uow.Reservar(NomeRecebiveis)
+		n, err := r.ProcessarLote()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n) // Só estatisticas_envio rodou
+
+		cp, _ := leitura.Checkpoints.Carregar(NomeRecebiveis)
+		assert.Equal(t, int64(0), cp)
+
+		uow.Liberar(NomeRecebiveis)
+		n, _ = r.ProcessarLote()
+		assert.Equal(t, 1, n)
+	})
+}
+
+func TestRunner_Reconstruir(t *testing.T) {
+	t.Run("should rebuild the read model from event zero", func(t *testing.T) {
+		r, store, leitura, _ := setup(t, Opcoes{TamanhoLote: 2})
+
+		f := novaFatura(t, store, "cli-1", entity.BRL(10000))
+		novaFatura(t, store, "cli-1", entity.BRL(4000))
+		f.MarcarComoPaga(entity.EncargosAtraso{})
+		store.Faturas.Update(f)
+
+		for n := 1; n > 0; {
+			n, _ = r.ProcessarLote()
+		}
+		antes, _ := leitura.Recebiveis.FindByClienteID("cli-1")
+
+		// Read model corrompido (ex.: bug corrigido no handler)
+		leitura.Recebiveis.SaveCliente(&entity.RecebiveisCliente{ClienteID: "cli-1", ValorEmAberto: entity.BRL(99900)})
+
+		n, err := r.Reconstruir(context.Background(), NomeRecebiveis)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, n)
+
+		depois, _ := leitura.Recebiveis.FindByClienteID("cli-1")
+		assert.Equal(t, antes, depois)
+
+		cp, _ := leitura.Checkpoints.Carregar(NomeRecebiveis)
+		assert.Equal(t, int64(4), cp)
+	})
+
+	t.Run("should wait while the projection is reserved", func(t *testing.T) {
+		r, _, _, uow := setup(t, Opcoes{IntervaloPoll: time.Millisecond})
+
+		uow.Reservar(NomeEstatisticasEnvio)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := r.Reconstruir(ctx, NomeEstatisticasEnvio)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should reject an unknown projection", func(t *testing.T) {
+		r, _, _, _ := setup(t, Opcoes{})
+
+		_, err := r.Reconstruir(context.Background(), "inexistente")
+		assert.ErrorIs(t, err, ErrProjecaoDesconhecida)
+	})
+}
@@ -0,0 +1,203 @@
+package regua
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/usecase/pix"
+	"github.com/teusf/billing-system/internal/usecase/template"
+)
+
+// Textos usados quando a etapa não tem template, conforme a posição dela em relação ao vencimento
+const (
+	textoAntesPadrao  = "Ola, %s! Lembrete: a fatura %s no valor de %s vence em %s."
+	textoNoDiaPadrao  = "Ola, %s! A fatura %s no valor de %s vence hoje, %s."
+	textoDepoisPadrao = "Ola, %s! A fatura %s no valor de %s venceu em %s. O valor atualizado para pagamento hoje e %s."
+)
+
+// Service executa a régua de cobrança dos tenants que a configuraram: a cada rodada,
+// cada fatura em aberto recebe a mensagem da etapa devida, se ela ainda não rodou.
+// Tenants sem régua continuam com o lembrete único e a cobrança no vencimento.
+type Service struct {
+	repos      repository.Repositorios
+	uow        repository.UnitOfWork
+	logger     *zap.Logger
+	urlPublica string // Endereço externo da API, para o link do QR code do Pix (opcional)
+	agora      func() time.Time
+}
+
+func NewService(repos repository.Repositorios, uow repository.UnitOfWork, logger *zap.Logger, urlPublica string) *Service {
+	return &Service{repos: repos, uow: uow, logger: logger, urlPublica: urlPublica, agora: time.Now}
+}
+
+// Executar roda um ciclo do job e retorna quantas mensagens foram enfileiradas.
+// Fora do horário de envio do tenant nada é feito: a etapa sai na primeira rodada dentro dele.
+// Falhas em uma fatura são logadas e não interrompem as demais.
+func (s *Service) Executar() (int, error) {
+	configs, err := s.repos.Configuracoes.FindAll()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao carregar configuracoes: %w", err)
+	}
+
+	agora := s.agora()
+	enfileiradas := 0
+
+	for _, config := range configs {
+		if len(config.Regua) == 0 || !config.EnvioAutomaticoAtivo || !config.EstaDentroHorarioEnvio(agora) {
+			continue
+		}
+
+		faturas, err := s.repos.Faturas.FindEmAbertoByUsuarioID(config.UsuarioID)
+		if err != nil {
+			return enfileiradas, fmt.Errorf("erro ao buscar faturas do usuario %s: %w", config.UsuarioID, err)
+		}
+
+		for _, fatura := range faturas {
+			etapa, ok := config.Regua.EtapaDevida(fatura.DataVencimento, agora)
+			if !ok {
+				continue
+			}
+
+			ok, err := s.executarEtapa(config, fatura, etapa, agora)
+			if err != nil {
+				s.logger.Error("Falha ao executar etapa da regua de cobranca",
+					zap.String("fatura_id", fatura.ID),
+					zap.String("etapa", etapa.Nome()),
+					zap.Error(err),
+				)
+				continue
+			}
+			if ok {
+				enfileiradas++
+			}
+		}
+	}
+
+	return enfileiradas, nil
+}
+
+// executarEtapa enfileira a mensagem da etapa e a registra como executada na mesma transação.
+// Retorna false sem erro quando não há o que enviar: etapa já executada, cliente inativo
+// ou fatura paga ou cancelada depois da listagem.
+func (s *Service) executarEtapa(config *entity.Configuracao, fatura *entity.Fatura, etapa entity.EtapaCobranca, agora time.Time) (bool, error) {
+	executada, err := s.repos.Regua.Executada(fatura.ID, etapa.Dias)
+	if err != nil {
+		return false, err
+	}
+	if executada {
+		return false, nil
+	}
+
+	cliente, err := s.repos.Clientes.FindByID(fatura.ClienteID)
+	if err != nil {
+		return false, err
+	}
+	if cliente == nil || !cliente.Ativo {
+		return false, nil
+	}
+
+	enviada := false
+	err = s.uow.Executar(func(repos repository.Repositorios) error {
+		// Relê a fatura na transação: um pagamento entre a listagem e o envio cancela a etapa
+		atual, err := repos.Faturas.FindByID(fatura.ID)
+		if err != nil {
+			return err
+		}
+		if atual == nil || atual.Status == entity.StatusPaga || atual.Status == entity.StatusCancelada {
+			return nil
+		}
+
+		conteudo, err := montarConteudo(config, cliente, atual, etapa, agora, s.urlPublica)
+		if err != nil && etapa.Template != "" {
+			s.logger.Warn("Template da etapa invalido, usando o texto padrao",
+				zap.String("usuario_id", config.UsuarioID), zap.String("etapa", etapa.Nome()), zap.Error(err))
+			padrao := etapa
+			padrao.Template = ""
+			conteudo, err = montarConteudo(config, cliente, atual, padrao, agora, s.urlPublica)
+		}
+		if err != nil {
+			return err
+		}
+
+		msg, err := entity.NewMensagem(atual.ID, cliente.ID, cliente.WhatsApp, conteudo, etapa.Tipo)
+		if err != nil {
+			return err
+		}
+		if err := repos.Mensagens.Save(msg); err != nil {
+			return err
+		}
+
+		event, err := entity.NewMensagemEnfileiradaEvent(msg)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar evento: %w", err)
+		}
+		if err := repos.Eventos.Append(msg.ID, 0, event); err != nil {
+			return err
+		}
+
+		// A chave (fatura, etapa) é a garantia final contra envio duplicado
+		registrada, err := repos.Regua.Registrar(&entity.EtapaExecutada{
+			FaturaID:    atual.ID,
+			Dias:        etapa.Dias,
+			MensagemID:  msg.ID,
+			ExecutadaEm: agora,
+		})
+		if err != nil {
+			return err
+		}
+		if !registrada {
+			return fmt.Errorf("etapa %s executada em paralelo", etapa.Nome())
+		}
+
+		// Mantém o indicador da fatura coerente com o lembrete único
+		if etapa.Tipo == entity.TipoMensagemLembrete && !atual.LembreteEnviado {
+			if err := atual.MarcarLembreteEnviado(); err != nil {
+				return err
+			}
+			if err := repos.Faturas.Update(atual); err != nil {
+				return err
+			}
+		}
+
+		enviada = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return enviada, nil
+}
+
+// montarConteudo renderiza o template da etapa se existir (variáveis em usecase/template).
+// Os textos padrão levam o Pix copia e cola quando o tenant recebe por Pix;
+// depois do vencimento, o valor informado inclui a multa e os juros do tenant.
+func montarConteudo(config *entity.Configuracao, cliente *entity.Cliente, fatura *entity.Fatura, etapa entity.EtapaCobranca, agora time.Time, urlPublica string) (string, error) {
+	if etapa.Template != "" {
+		return template.Montar(etapa.Template, config, cliente, fatura, agora, urlPublica)
+	}
+
+	textoPix, err := pix.TextoMensagem(config, fatura, agora, urlPublica)
+	if err != nil {
+		return "", err
+	}
+
+	vencimento := fatura.DataVencimento.Format("02/01/2006")
+
+	switch {
+	case etapa.Dias < 0:
+		return fmt.Sprintf(textoAntesPadrao, cliente.Nome, fatura.Numero, fatura.Valor, vencimento) + textoPix, nil
+	case etapa.Dias == 0:
+		return fmt.Sprintf(textoNoDiaPadrao, cliente.Nome, fatura.Numero, fatura.Valor, vencimento) + textoPix, nil
+	}
+
+	devido, err := fatura.ValorDevido(agora, config.Encargos)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(textoDepoisPadrao, cliente.Nome, fatura.Numero, fatura.Valor, vencimento, devido.Saldo) + textoPix, nil
+}
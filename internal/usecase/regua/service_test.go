@@ -0,0 +1,181 @@
+package regua
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+var reguaPadrao = entity.ReguaCobranca{
+	{Dias: -5, Tipo: entity.TipoMensagemLembrete},
+	{Dias: 0, Tipo: entity.TipoMensagemLembrete, Template: "Sua fatura vence hoje"},
+	{Dias: 3, Tipo: entity.TipoMensagemCobranca},
+}
+
+func setup(t *testing.T) (*Service, *memory.Store, *entity.Fatura) {
+	t.Helper()
+
+	store := memory.NewStore()
+
+	config, _ := entity.NewConfiguracao("user1")
+	config.HorarioInicioEnvio = "08:00"
+	config.HorarioFimEnvio = "18:00"
+	config.Regua = reguaPadrao
+	store.Configuracoes.Save(config)
+
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "")
+	client.UsuarioID = "user1"
+	store.Clientes.Save(client)
+
+	f, _ := entity.NewFatura(client.ID, "FAT-2026-000001", entity.BRL(10000), time.Now().AddDate(0, 0, 10), "Consultoria")
+	store.Faturas.Save(f)
+
+	return NewService(store.Repositorios(), store.UnitOfWork(), zap.NewNop(), ""), store, f
+}
+
+// em posiciona o relógio do serviço às 10h do dia D+dias da fatura
+func em(s *Service, f *entity.Fatura, dias int) {
+	d := f.DataVencimento.AddDate(0, 0, dias)
+	s.agora = func() time.Time { return time.Date(d.Year(), d.Month(), d.Day(), 10, 0, 0, 0, d.Location()) }
+}
+
+func executar(t *testing.T, s *Service) int {
+	t.Helper()
+	n, err := s.Executar()
+	assert.NoError(t, err)
+	return n
+}
+
+func TestService_ExecutaCadaEtapaUmaVez(t *testing.T) {
+	s, store, f := setup(t)
+
+	em(s, f, -6)
+	assert.Equal(t, 0, executar(t, s))
+
+	// D-5: lembrete com o texto padrão
+	em(s, f, -5)
+	assert.Equal(t, 1, executar(t, s))
+	assert.Equal(t, 0, executar(t, s))
+
+	msgs := store.Mensagens.All()
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, entity.TipoMensagemLembrete, msgs[0].Tipo)
+	assert.Contains(t, msgs[0].Conteudo, "vence em "+f.DataVencimento.Format("02/01/2006"))
+
+	found, _ := store.Faturas.FindByID(f.ID)
+	assert.True(t, found.LembreteEnviado)
+
+	// D-1: nenhuma etapa nova
+	em(s, f, -1)
+	assert.Equal(t, 0, executar(t, s))
+
+	// D0: template da etapa
+	em(s, f, 0)
+	assert.Equal(t, 1, executar(t, s))
+
+	// D+3: cobrança
+	em(s, f, 3)
+	assert.Equal(t, 1, executar(t, s))
+	em(s, f, 20)
+	assert.Equal(t, 0, executar(t, s))
+
+	etapas, _ := store.Regua.FindByFaturaID(f.ID)
+	assert.Len(t, etapas, 3)
+	assert.Equal(t, []int{-5, 0, 3}, []int{etapas[0].Dias, etapas[1].Dias, etapas[2].Dias})
+
+	conteudos := map[entity.TipoMensagem][]string{}
+	for _, m := range store.Mensagens.All() {
+		conteudos[m.Tipo] = append(conteudos[m.Tipo], m.Conteudo)
+	}
+	assert.Contains(t, conteudos[entity.TipoMensagemLembrete], "Sua fatura vence hoje")
+	assert.Len(t, conteudos[entity.TipoMensagemCobranca], 1)
+	assert.True(t, strings.Contains(conteudos[entity.TipoMensagemCobranca][0], "venceu em"))
+
+	assert.Len(t, store.Eventos.PorTipo(entity.EventMensagemEnfileirada), 3)
+}
+
+func TestService_EtapaComTemplateInvalido(t *testing.T) {
+	s, store, f := setup(t)
+
+	// Template gravado antes da validação existir
+	config, _ := store.Configuracoes.FindByUsuarioID("user1")
+	config.Regua = entity.ReguaCobranca{{Dias: 0, Tipo: entity.TipoMensagemLembrete, Template: "Pague {{valor_total}}"}}
+	store.Configuracoes.Update(config)
+
+	em(s, f, 0)
+	assert.Equal(t, 1, executar(t, s))
+
+	msgs := store.Mensagens.All()
+	assert.Len(t, msgs, 1)
+	assert.Contains(t, msgs[0].Conteudo, f.Numero)
+	assert.NotContains(t, msgs[0].Conteudo, "{{")
+}
+
+func TestService_PulaEtapasAtrasadas(t *testing.T) {
+	s, store, f := setup(t)
+
+	// Job parado até D+4: só a etapa mais recente é enviada
+	em(s, f, 4)
+	assert.Equal(t, 1, executar(t, s))
+
+	msgs := store.Mensagens.All()
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, entity.TipoMensagemCobranca, msgs[0].Tipo)
+}
+
+func TestService_IgnoraFaturasEncerradas(t *testing.T) {
+	t.Run("should skip paid faturas", func(t *testing.T) {
+		s, store, f := setup(t)
+		f.MarcarComoPaga(entity.EncargosAtraso{})
+		store.Faturas.Update(f)
+
+		em(s, f, 3)
+		assert.Equal(t, 0, executar(t, s))
+		assert.Empty(t, store.Mensagens.All())
+	})
+
+	t.Run("should skip cancelled faturas", func(t *testing.T) {
+		s, store, f := setup(t)
+		f.Cancelar()
+		store.Faturas.Update(f)
+
+		em(s, f, 0)
+		assert.Equal(t, 0, executar(t, s))
+		assert.Empty(t, store.Mensagens.All())
+	})
+}
+
+func TestService_RespeitaConfiguracao(t *testing.T) {
+	t.Run("should wait for the sending window", func(t *testing.T) {
+		s, store, f := setup(t)
+		d := f.DataVencimento
+		s.agora = func() time.Time { return time.Date(d.Year(), d.Month(), d.Day(), 20, 0, 0, 0, d.Location()) }
+
+		assert.Equal(t, 0, executar(t, s))
+
+		em(s, f, 0)
+		assert.Equal(t, 1, executar(t, s))
+		assert.Len(t, store.Mensagens.All(), 1)
+	})
+
+	t.Run("should ignore tenants without steps or with sending disabled", func(t *testing.T) {
+		s, store, f := setup(t)
+		config, _ := store.Configuracoes.FindByUsuarioID("user1")
+
+		config.EnvioAutomaticoAtivo = false
+		store.Configuracoes.Update(config)
+		em(s, f, 0)
+		assert.Equal(t, 0, executar(t, s))
+
+		config.EnvioAutomaticoAtivo = true
+		config.Regua = nil
+		store.Configuracoes.Update(config)
+		assert.Equal(t, 0, executar(t, s))
+	})
+}
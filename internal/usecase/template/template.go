@@ -0,0 +1,218 @@
+// Package template renderiza os textos configurados pelo tenant (TemplateLembrete,
+// TemplateCobranca, TemplateConfirmacao e os templates das etapas da régua)
+// com os dados de uma fatura.
+//
+// As variáveis são escritas entre chaves duplas, com ou sem espaços: {{cliente}} ou {{ cliente }}.
+// Variáveis disponíveis:
+//
+//	{{cliente}}           nome do cliente
+//	{{numero}}            número da fatura (ex.: FAT-2026-000123)
+//	{{valor}}             valor da fatura em reais (ex.: R$ 1.234,56)
+//	{{valor_atualizado}}  saldo a pagar hoje, com multa e juros do tenant
+//	{{vencimento}}        data de vencimento (ex.: 05/03/2026)
+//	{{dias_para_vencer}}  dias até o vencimento; 0 no dia e negativo depois dele
+//	{{pix_copia_e_cola}}  Pix copia e cola do saldo (vazio se o tenant não recebe por Pix)
+//	{{link_pagamento}}    link do QR code do Pix (vazio sem Pix ou sem API_URL_PUBLICA)
+//	{{valor_pago}}        total pago na fatura, sem os pagamentos estornados
+//	{{data_pagamento}}    data em que a fatura foi liquidada (vazio enquanto não é paga)
+//	{{forma_pagamento}}   meio do pagamento que liquidou a fatura ou do último (ex.: Pix, boleto)
+//
+// Qualquer outra variável é recusada por Validar, para que um erro de digitação
+// apareça ao salvar o template e não no WhatsApp do cliente.
+package template
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/usecase/pix"
+)
+
+var (
+	ErrVariavelDesconhecida = errors.New("template usa variavel desconhecida")
+	ErrTemplateMalFormado   = errors.New("template tem chaves sem par")
+)
+
+// Variaveis são os nomes aceitos nos templates, na ordem da documentação
+var Variaveis = []string{
+	"cliente",
+	"numero",
+	"valor",
+	"valor_atualizado",
+	"vencimento",
+	"dias_para_vencer",
+	"pix_copia_e_cola",
+	"link_pagamento",
+	"valor_pago",
+	"data_pagamento",
+	"forma_pagamento",
+}
+
+var variavel = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// Dados são os valores das variáveis, já formatados para a mensagem
+type Dados struct {
+	Cliente         string
+	Numero          string
+	Valor           string
+	ValorAtualizado string
+	Vencimento      string
+	DiasParaVencer  int
+	PixCopiaECola   string
+	LinkPagamento   string
+	ValorPago       string
+	DataPagamento   string
+	FormaPagamento  string
+}
+
+func (d Dados) valor(nome string) string {
+	switch nome {
+	case "cliente":
+		return d.Cliente
+	case "numero":
+		return d.Numero
+	case "valor":
+		return d.Valor
+	case "valor_atualizado":
+		return d.ValorAtualizado
+	case "vencimento":
+		return d.Vencimento
+	case "dias_para_vencer":
+		return strconv.Itoa(d.DiasParaVencer)
+	case "pix_copia_e_cola":
+		return d.PixCopiaECola
+	case "link_pagamento":
+		return d.LinkPagamento
+	case "valor_pago":
+		return d.ValorPago
+	case "data_pagamento":
+		return d.DataPagamento
+	case "forma_pagamento":
+		return d.FormaPagamento
+	}
+	return ""
+}
+
+// Validar confere que o template só usa variáveis conhecidas e não tem chaves soltas.
+// O erro informa a primeira variável ou trecho inválido.
+func Validar(texto string) error {
+	for _, m := range variavel.FindAllStringSubmatch(texto, -1) {
+		if !conhecida(m[1]) {
+			return fmt.Errorf("%w: {{%s}}", ErrVariavelDesconhecida, m[1])
+		}
+	}
+
+	resto := variavel.ReplaceAllString(texto, "")
+	if strings.Contains(resto, "{{") || strings.Contains(resto, "}}") {
+		return ErrTemplateMalFormado
+	}
+	return nil
+}
+
+// Renderizar substitui as variáveis do template pelos dados
+func Renderizar(texto string, dados Dados) (string, error) {
+	if err := Validar(texto); err != nil {
+		return "", err
+	}
+
+	return variavel.ReplaceAllStringFunc(texto, func(m string) string {
+		return dados.valor(variavel.FindStringSubmatch(m)[1])
+	}), nil
+}
+
+// DadosDaFatura monta as variáveis da fatura em agora. Faturas pagas ou canceladas
+// são aceitas (ex.: confirmação de pagamento), só ficam sem Pix e sem link.
+func DadosDaFatura(config *entity.Configuracao, cliente *entity.Cliente, fatura *entity.Fatura, agora time.Time, urlPublica string) (Dados, error) {
+	devido, err := fatura.ValorDevido(agora, config.Encargos)
+	if err != nil {
+		return Dados{}, err
+	}
+
+	dados := Dados{
+		Cliente:         cliente.Nome,
+		Numero:          fatura.Numero,
+		Valor:           fatura.Valor.String(),
+		ValorAtualizado: devido.Saldo.String(),
+		Vencimento:      fatura.DataVencimento.Format("02/01/2006"),
+		DiasParaVencer:  diasAte(agora, fatura.DataVencimento),
+		ValorPago:       fatura.TotalPago().String(),
+	}
+
+	if fatura.DataPagamento != nil {
+		dados.DataPagamento = fatura.DataPagamento.Format("02/01/2006")
+	}
+	if p := ultimoPagamento(fatura); p != nil {
+		dados.FormaPagamento = FormaPagamento(p.Metodo)
+	}
+
+	aberta := fatura.Status != entity.StatusPaga && fatura.Status != entity.StatusCancelada
+	if aberta && config.Pix.Configurado() {
+		cobranca, err := pix.ParaFatura(config, fatura, entity.PixEstatico, nil, agora)
+		if err != nil {
+			return Dados{}, err
+		}
+		dados.PixCopiaECola = cobranca.CopiaECola
+		if urlPublica != "" {
+			dados.LinkPagamento = pix.URLQRCode(urlPublica, fatura.ID)
+		}
+	}
+
+	return dados, nil
+}
+
+// Montar renderiza o template com os dados da fatura
+func Montar(texto string, config *entity.Configuracao, cliente *entity.Cliente, fatura *entity.Fatura, agora time.Time, urlPublica string) (string, error) {
+	dados, err := DadosDaFatura(config, cliente, fatura, agora, urlPublica)
+	if err != nil {
+		return "", err
+	}
+	return Renderizar(texto, dados)
+}
+
+// FormaPagamento descreve o meio de pagamento para o cliente
+func FormaPagamento(m entity.MetodoPagamento) string {
+	switch m {
+	case entity.MetodoPix:
+		return "Pix"
+	case entity.MetodoCartao:
+		return "cartao"
+	case entity.MetodoManual:
+		return "baixa manual"
+	}
+	return string(m)
+}
+
+// ultimoPagamento é o que liquidou a fatura, se ela está paga, ou o ativo mais recente
+func ultimoPagamento(fatura *entity.Fatura) *entity.Pagamento {
+	if p := fatura.PagamentoQueLiquidou(); p != nil {
+		return p
+	}
+	for i := len(fatura.Pagamentos) - 1; i >= 0; i-- {
+		if !fatura.Pagamentos[i].Estornado() {
+			return &fatura.Pagamentos[i]
+		}
+	}
+	return nil
+}
+
+func conhecida(nome string) bool {
+	for _, v := range Variaveis {
+		if v == nome {
+			return true
+		}
+	}
+	return false
+}
+
+// diasAte conta os dias de calendário de agora até o vencimento, no fuso de agora
+func diasAte(agora, vencimento time.Time) int {
+	vencimento = vencimento.In(agora.Location())
+	de := time.Date(agora.Year(), agora.Month(), agora.Day(), 0, 0, 0, 0, time.UTC)
+	ate := time.Date(vencimento.Year(), vencimento.Month(), vencimento.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ate.Sub(de).Hours() / 24)
+}
@@ -0,0 +1,119 @@
+package template
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+)
+
+func TestValidar(t *testing.T) {
+	t.Run("should accept the documented variables", func(t *testing.T) {
+		assert.NoError(t, Validar("Ola {{cliente}}, a fatura {{ numero }} de {{valor}} vence em {{vencimento}}."))
+		assert.NoError(t, Validar("Texto sem variaveis"))
+		assert.NoError(t, Validar(""))
+
+		todas := ""
+		for _, v := range Variaveis {
+			todas += "{{" + v + "}} "
+		}
+		assert.NoError(t, Validar(todas))
+	})
+
+	t.Run("should reject unknown variables naming them", func(t *testing.T) {
+		err := Validar("Ola {{nome}}, pague {{valor}}")
+		assert.ErrorIs(t, err, ErrVariavelDesconhecida)
+		assert.Contains(t, err.Error(), "{{nome}}")
+
+		assert.ErrorIs(t, Validar("{{}}"), ErrVariavelDesconhecida)
+	})
+
+	t.Run("should reject unbalanced braces", func(t *testing.T) {
+		assert.ErrorIs(t, Validar("Ola {{cliente"), ErrTemplateMalFormado)
+		assert.ErrorIs(t, Validar("Ola cliente}}"), ErrTemplateMalFormado)
+	})
+}
+
+func TestRenderizar(t *testing.T) {
+	dados := Dados{
+		Cliente:        "Maria",
+		Numero:         "FAT-2026-000001",
+		Valor:          "R$ 1.234,56",
+		Vencimento:     "05/03/2026",
+		DiasParaVencer: -2,
+	}
+
+	texto, err := Renderizar("Ola {{cliente}}! {{numero}}: {{ valor }} ({{vencimento}}, {{dias_para_vencer}} dias){{pix_copia_e_cola}}", dados)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ola Maria! FAT-2026-000001: R$ 1.234,56 (05/03/2026, -2 dias)", texto)
+
+	_, err = Renderizar("{{desconto}}", dados)
+	assert.ErrorIs(t, err, ErrVariavelDesconhecida)
+}
+
+func TestDadosDaFatura(t *testing.T) {
+	config, _ := entity.NewConfiguracao("user1")
+	config.Encargos = entity.EncargosAtraso{Multa: 200}
+	cliente, _ := entity.NewCliente("Maria Silva", "5511999998888", "")
+
+	f, _ := entity.NewFatura("cli-1", "FAT-2026-000001", entity.BRL(123456), time.Now().AddDate(0, 0, 1), "Consultoria")
+	f.DataVencimento = time.Date(2026, 3, 5, 0, 0, 0, 0, time.Local)
+
+	t.Run("should format values in pt-BR", func(t *testing.T) {
+		dados, err := DadosDaFatura(config, cliente, f, time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local), "")
+		assert.NoError(t, err)
+		assert.Equal(t, "Maria Silva", dados.Cliente)
+		assert.Equal(t, "R$ 1.234,56", dados.Valor)
+		assert.Equal(t, "R$ 1.234,56", dados.ValorAtualizado)
+		assert.Equal(t, "05/03/2026", dados.Vencimento)
+		assert.Equal(t, 4, dados.DiasParaVencer)
+		assert.Empty(t, dados.PixCopiaECola)
+		assert.Empty(t, dados.LinkPagamento)
+	})
+
+	t.Run("should count negative days and add fees after the due date", func(t *testing.T) {
+		dados, err := DadosDaFatura(config, cliente, f, time.Date(2026, 3, 8, 10, 0, 0, 0, time.Local), "")
+		assert.NoError(t, err)
+		assert.Equal(t, -3, dados.DiasParaVencer)
+		assert.Equal(t, "R$ 1.259,25", dados.ValorAtualizado)
+	})
+
+	t.Run("should include Pix and the payment link when configured", func(t *testing.T) {
+		comPix := *config
+		comPix.Pix = entity.ConfigPix{Chave: "financeiro@exemplo.com.br", NomeRecebedor: "Exemplo Ltda", Cidade: "Curitiba"}
+
+		texto, err := Montar("{{pix_copia_e_cola}} {{link_pagamento}}", &comPix, cliente, f, time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local), "https://api.exemplo.com.br/")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(texto, "000201"))
+		assert.True(t, strings.HasSuffix(texto, "https://api.exemplo.com.br/faturas/"+f.ID+"/pix/qrcode"))
+
+		// Fatura paga: sem Pix, sem erro
+		pago := *f
+		pago.Status = entity.StatusPaga
+		dados, err := DadosDaFatura(&comPix, cliente, &pago, time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local), "https://api.exemplo.com.br")
+		assert.NoError(t, err)
+		assert.Empty(t, dados.PixCopiaECola)
+	})
+
+	t.Run("should describe the payment of a paid fatura", func(t *testing.T) {
+		pago := *f
+		pagoEm := time.Now().Add(-time.Hour)
+		_, err := pago.RegistrarPagamento(entity.BRL(123456), entity.MetodoPix, pagoEm, "", entity.EncargosAtraso{})
+		assert.NoError(t, err)
+
+		dados, err := DadosDaFatura(config, cliente, &pago, time.Now(), "")
+		assert.NoError(t, err)
+		assert.Equal(t, "R$ 1.234,56", dados.ValorPago)
+		assert.Equal(t, pagoEm.Format("02/01/2006"), dados.DataPagamento)
+		assert.Equal(t, "Pix", dados.FormaPagamento)
+
+		dados, err = DadosDaFatura(config, cliente, f, time.Now(), "")
+		assert.NoError(t, err)
+		assert.Equal(t, "R$ 0,00", dados.ValorPago)
+		assert.Empty(t, dados.DataPagamento)
+		assert.Empty(t, dados.FormaPagamento)
+	})
+}
@@ -0,0 +1,177 @@
+package vencimento
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/domain/repository"
+	"github.com/teusf/billing-system/internal/usecase/pix"
+	"github.com/teusf/billing-system/internal/usecase/template"
+)
+
+const (
+	// Quantidade de faturas processadas por transação
+	tamanhoLotePadrao = 100
+
+	// Texto usado quando o tenant não configurou TemplateCobranca
+	templateCobrancaPadrao = "Ola, %s! A fatura %s no valor de %s venceu em %s. O valor atualizado para pagamento hoje e %s."
+)
+
+// Service marca como vencidas as faturas pendentes cujo vencimento já passou
+type Service struct {
+	repos          repository.Repositorios
+	uow            repository.UnitOfWork
+	logger         *zap.Logger
+	enviarCobranca bool
+	urlPublica     string // Endereço externo da API, para o link do QR code do Pix (opcional)
+	tamanhoLote    int
+	agora          func() time.Time
+}
+
+// NewService cria o serviço. Com enviarCobranca, cada fatura vencida também gera
+// uma mensagem de cobrança para tenants com envio automático ativo.
+func NewService(repos repository.Repositorios, uow repository.UnitOfWork, logger *zap.Logger, enviarCobranca bool, urlPublica string) *Service {
+	return &Service{
+		repos:          repos,
+		uow:            uow,
+		logger:         logger,
+		enviarCobranca: enviarCobranca,
+		urlPublica:     urlPublica,
+		tamanhoLote:    tamanhoLotePadrao,
+		agora:          time.Now,
+	}
+}
+
+// Executar processa lotes até não restarem faturas vencidas e retorna quantas foram marcadas.
+// Cada lote é uma transação: transição de status, evento e cobrança são gravados juntos.
+func (s *Service) Executar() (int, error) {
+	total := 0
+	configs := map[string]*entity.Configuracao{}
+
+	for {
+		processadas := 0
+
+		err := s.uow.Executar(func(repos repository.Repositorios) error {
+			agora := s.agora()
+			faturas, err := repos.Faturas.FindPendentesVencidas(agora, s.tamanhoLote)
+			if err != nil {
+				return err
+			}
+
+			for _, fatura := range faturas {
+				if err := fatura.MarcarComoVencida(agora); err != nil {
+					return err
+				}
+				if fatura.Status != entity.StatusVencida {
+					continue
+				}
+
+				// O Update grava também o evento FaturaVencida registrado pela fatura
+				if err := repos.Faturas.Update(fatura); err != nil {
+					return err
+				}
+				if err := s.enfileirarCobranca(repos, configs, fatura, agora); err != nil {
+					return err
+				}
+				processadas++
+			}
+
+			return nil
+		})
+		if err != nil {
+			return total, fmt.Errorf("erro ao processar lote de faturas vencidas: %w", err)
+		}
+
+		total += processadas
+
+		// Lote vazio (ou sem nenhuma transição) encerra o ciclo
+		if processadas == 0 {
+			return total, nil
+		}
+	}
+}
+
+// enfileirarCobranca cria a mensagem de cobrança se o job e o tenant permitirem.
+// As configurações são cacheadas por execução para não consultar o banco a cada fatura.
+// Só erros de gravação são retornados; uma mensagem que não dá para montar é logada e pulada.
+func (s *Service) enfileirarCobranca(repos repository.Repositorios, configs map[string]*entity.Configuracao, fatura *entity.Fatura, agora time.Time) error {
+	if !s.enviarCobranca {
+		return nil
+	}
+
+	cliente, err := repos.Clientes.FindByID(fatura.ClienteID)
+	if err != nil {
+		return err
+	}
+	if cliente == nil || !cliente.Ativo || cliente.UsuarioID == "" {
+		return nil
+	}
+
+	config, ok := configs[cliente.UsuarioID]
+	if !ok {
+		config, err = repos.Configuracoes.FindByUsuarioID(cliente.UsuarioID)
+		if err != nil {
+			return err
+		}
+		configs[cliente.UsuarioID] = config
+	}
+	// Com régua de cobrança, as mensagens depois do vencimento seguem as etapas dela (usecase/regua)
+	if config == nil || !config.EnvioAutomaticoAtivo || len(config.Regua) > 0 {
+		return nil
+	}
+
+	// Problemas no conteúdo da mensagem não desfazem o lote: a fatura vence mesmo assim
+	conteudo, err := montarConteudo(config, cliente, fatura, agora, s.urlPublica)
+	if err != nil && config.TemplateCobranca != "" {
+		s.logger.Warn("Template de cobranca invalido, usando o texto padrao",
+			zap.String("usuario_id", config.UsuarioID), zap.String("fatura_id", fatura.ID), zap.Error(err))
+		padrao := *config
+		padrao.TemplateCobranca = ""
+		conteudo, err = montarConteudo(&padrao, cliente, fatura, agora, s.urlPublica)
+	}
+	if err != nil {
+		s.logger.Error("Falha ao montar a cobranca, fatura vencida sem mensagem", zap.String("fatura_id", fatura.ID), zap.Error(err))
+		return nil
+	}
+
+	msg, err := entity.NewMensagem(fatura.ID, cliente.ID, cliente.WhatsApp, conteudo, entity.TipoMensagemCobranca)
+	if err != nil {
+		s.logger.Error("Cobranca invalida, fatura vencida sem mensagem", zap.String("fatura_id", fatura.ID), zap.Error(err))
+		return nil
+	}
+
+	if err := repos.Mensagens.Save(msg); err != nil {
+		return err
+	}
+
+	event, err := entity.NewMensagemEnfileiradaEvent(msg)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar evento: %w", err)
+	}
+
+	return repos.Eventos.Append(msg.ID, 0, event)
+}
+
+// montarConteudo renderiza o template do tenant se existir (variáveis em usecase/template).
+// O texto padrão informa o valor atualizado com a multa e os juros do tenant
+// e leva o Pix copia e cola desse valor quando o tenant recebe por Pix.
+func montarConteudo(config *entity.Configuracao, cliente *entity.Cliente, fatura *entity.Fatura, agora time.Time, urlPublica string) (string, error) {
+	if config.TemplateCobranca != "" {
+		return template.Montar(config.TemplateCobranca, config, cliente, fatura, agora, urlPublica)
+	}
+
+	devido, err := fatura.ValorDevido(agora, config.Encargos)
+	if err != nil {
+		return "", err
+	}
+
+	textoPix, err := pix.TextoMensagem(config, fatura, agora, urlPublica)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(templateCobrancaPadrao, cliente.Nome, fatura.Numero, fatura.Valor, fatura.DataVencimento.Format("02/01/2006"), devido.Saldo) + textoPix, nil
+}
@@ -0,0 +1,227 @@
+package vencimento
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/teusf/billing-system/internal/domain/entity"
+	"github.com/teusf/billing-system/internal/infrastructure/repository/memory"
+)
+
+func novaFaturaVencida(t *testing.T, store *memory.Store, clienteID string, diasAtraso int) *entity.Fatura {
+	t.Helper()
+
+	// Cria com vencimento futuro (exigido por NewFatura) e força para o passado
+	f, err := entity.NewFatura(clienteID, "FAT-2026-000001", entity.BRL(10000), time.Now().AddDate(0, 0, 1), "Vencida")
+	assert.NoError(t, err)
+	f.DataVencimento = time.Now().AddDate(0, 0, -diasAtraso)
+	store.Faturas.Save(f)
+	return f
+}
+
+func setup(t *testing.T, enviarCobranca bool) (*Service, *memory.Store, *entity.Cliente) {
+	t.Helper()
+
+	store := memory.NewStore()
+
+	config, _ := entity.NewConfiguracao("user1")
+	store.Configuracoes.Save(config)
+
+	client, _ := entity.NewCliente("Cliente 1", "5511999998888", "")
+	client.UsuarioID = "user1"
+	store.Clientes.Save(client)
+
+	return NewService(store.Repositorios(), store.UnitOfWork(), zap.NewNop(), enviarCobranca, ""), store, client
+}
+
+func TestService_MarcaVencidasEmLotes(t *testing.T) {
+	s, store, client := setup(t, false)
+	s.tamanhoLote = 2
+
+	vencidas := []*entity.Fatura{
+		novaFaturaVencida(t, store, client.ID, 1),
+		novaFaturaVencida(t, store, client.ID, 2),
+		novaFaturaVencida(t, store, client.ID, 3),
+	}
+
+	emDia, _ := entity.NewFatura(client.ID, "FAT-2026-000002", entity.BRL(10000), time.Now().AddDate(0, 0, 5), "Em dia")
+	store.Faturas.Save(emDia)
+
+	n, err := s.Executar()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	for _, f := range vencidas {
+		found, _ := store.Faturas.FindByID(f.ID)
+		assert.Equal(t, entity.StatusVencida, found.Status)
+	}
+
+	found, _ := store.Faturas.FindByID(emDia.ID)
+	assert.Equal(t, entity.StatusPendente, found.Status)
+
+	// Um evento FaturaVencida por fatura
+	events := store.Eventos.PorTipo(entity.EventFaturaVencida)
+	assert.Len(t, events, 3)
+	for _, e := range events {
+		assert.Equal(t, entity.EventFaturaVencida, e.EventType)
+		assert.Equal(t, entity.AggregateFatura, e.AggregateType)
+		assert.NotEmpty(t, e.ID)
+
+		var data entity.FaturaVencidaData
+		assert.NoError(t, json.Unmarshal(e.EventData, &data))
+		assert.Equal(t, e.AggregateID, data.FaturaID)
+	}
+
+	// Sem cobrança configurada
+	assert.Empty(t, store.Mensagens.All())
+
+	// Segunda execução não encontra mais nada
+	n, err = s.Executar()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestService_RelogioInjetado(t *testing.T) {
+	s, store, client := setup(t, false)
+
+	f, _ := entity.NewFatura(client.ID, "FAT-2026-000003", entity.BRL(10000), time.Now().AddDate(0, 0, 5), "Futura")
+	store.Faturas.Save(f)
+
+	// O relógio do serviço vale tanto para a busca quanto para a transição
+	s.agora = func() time.Time { return f.DataVencimento.AddDate(0, 0, 1) }
+
+	n, err := s.Executar()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	found, _ := store.Faturas.FindByID(f.ID)
+	assert.Equal(t, entity.StatusVencida, found.Status)
+}
+
+func TestService_VenceFaturaParcialmentePaga(t *testing.T) {
+	s, store, client := setup(t, true)
+
+	f, _ := entity.NewFatura(client.ID, "FAT-2026-000004", entity.BRL(10000), time.Now().AddDate(0, 0, 5), "Parcelada")
+	_, err := f.RegistrarPagamento(entity.BRL(4000), entity.MetodoPix, time.Now(), "", entity.EncargosAtraso{})
+	assert.NoError(t, err)
+	store.Faturas.Save(f)
+
+	s.agora = func() time.Time { return f.DataVencimento.AddDate(0, 0, 1) }
+
+	n, err := s.Executar()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	found, _ := store.Faturas.FindByID(f.ID)
+	assert.Equal(t, entity.StatusVencida, found.Status)
+
+	// A cobrança pede só o saldo
+	msgs := store.Mensagens.All()
+	assert.Len(t, msgs, 1)
+	assert.Contains(t, msgs[0].Conteudo, "valor atualizado para pagamento hoje e R$ 60,00")
+}
+
+func TestService_EnfileiraCobranca(t *testing.T) {
+	s, store, client := setup(t, true)
+
+	config, _ := store.Configuracoes.FindByUsuarioID("user1")
+	config.TemplateCobranca = "Sua fatura venceu"
+	store.Configuracoes.Update(config)
+
+	f := novaFaturaVencida(t, store, client.ID, 1)
+
+	_, err := s.Executar()
+	assert.NoError(t, err)
+
+	msgs := store.Mensagens.All()
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, f.ID, msgs[0].FaturaID)
+	assert.Equal(t, entity.TipoMensagemCobranca, msgs[0].Tipo)
+	assert.Equal(t, "Sua fatura venceu", msgs[0].Conteudo)
+
+	// FaturaVencida + MensagemEnfileirada na mesma transação
+	assert.Len(t, store.Eventos.PorTipo(entity.EventFaturaVencida), 1)
+	events := store.Eventos.PorTipo(entity.EventMensagemEnfileirada)
+	assert.Len(t, events, 1)
+	assert.Equal(t, msgs[0].ID, events[0].AggregateID)
+}
+
+func TestService_CobrancaComTemplateInvalido(t *testing.T) {
+	s, store, client := setup(t, true)
+
+	// Template gravado antes da validação existir
+	config, _ := store.Configuracoes.FindByUsuarioID("user1")
+	config.TemplateCobranca = "Pague {{valor_total}}"
+	store.Configuracoes.Update(config)
+
+	outro, _ := entity.NewCliente("Cliente 2", "5511999997777", "")
+	outro.UsuarioID = "user1"
+	store.Clientes.Save(outro)
+	outro.WhatsApp = "" // Cadastro antigo sem WhatsApp válido
+	store.Clientes.Update(outro)
+
+	novaFaturaVencida(t, store, client.ID, 1)
+	novaFaturaVencida(t, store, outro.ID, 2)
+
+	n, err := s.Executar()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Len(t, store.Eventos.PorTipo(entity.EventFaturaVencida), 2)
+
+	// Cai no texto padrão; a fatura sem mensagem válida vence mesmo assim
+	msgs := store.Mensagens.All()
+	assert.Len(t, msgs, 1)
+	assert.Contains(t, msgs[0].Conteudo, "Ola, Cliente 1! A fatura FAT-2026-000001")
+}
+
+func TestService_CobrancaPadraoComEncargos(t *testing.T) {
+	s, store, client := setup(t, true)
+
+	config, _ := store.Configuracoes.FindByUsuarioID("user1")
+	config.Encargos = entity.EncargosAtraso{Multa: 200, JurosAoMes: 100}
+	store.Configuracoes.Update(config)
+
+	novaFaturaVencida(t, store, client.ID, 10)
+
+	_, err := s.Executar()
+	assert.NoError(t, err)
+
+	msgs := store.Mensagens.All()
+	assert.Len(t, msgs, 1)
+	assert.Contains(t, msgs[0].Conteudo, "no valor de R$ 100,00")
+	assert.Contains(t, msgs[0].Conteudo, "valor atualizado para pagamento hoje e R$ 102,33")
+}
+
+func TestService_NaoEnviaCobrancaSemEnvioAutomatico(t *testing.T) {
+	s, store, client := setup(t, true)
+
+	config, _ := store.Configuracoes.FindByUsuarioID("user1")
+	config.EnvioAutomaticoAtivo = false
+	store.Configuracoes.Update(config)
+
+	novaFaturaVencida(t, store, client.ID, 1)
+
+	n, err := s.Executar()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Empty(t, store.Mensagens.All())
+}
+
+func TestService_NaoEnviaCobrancaComRegua(t *testing.T) {
+	s, store, client := setup(t, true)
+
+	config, _ := store.Configuracoes.FindByUsuarioID("user1")
+	config.Regua = entity.ReguaCobranca{{Dias: 3, Tipo: entity.TipoMensagemCobranca}}
+	store.Configuracoes.Update(config)
+
+	novaFaturaVencida(t, store, client.ID, 1)
+
+	n, err := s.Executar()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n) // A fatura é marcada como vencida; a mensagem fica com a régua
+	assert.Empty(t, store.Mensagens.All())
+}